@@ -56,6 +56,8 @@ type MaintainerSpec struct {
 	CompanyRef    *ResourceReference  `json:"companyRef,omitempty"`
 	RegisteredAt  *metav1.Time        `json:"registeredAt,omitempty"`
 	ExternalIDs   map[string]string   `json:"externalIDs,omitempty"`
+	// ArchivedAt is set while the maintainer is archived in maintainer-d.
+	ArchivedAt *metav1.Time `json:"archivedAt,omitempty"`
 }
 
 // MaintainerStatus surfaces derived information gathered by controllers.
@@ -178,6 +180,8 @@ type ProjectSpec struct {
 	CollaboratorRefs  []ResourceReference `json:"collaboratorRefs,omitempty"`
 	ServiceRefs       []ResourceReference `json:"serviceRefs,omitempty"`
 	Tags              map[string]string   `json:"tags,omitempty"`
+	// ArchivedAt is set while the project is archived in maintainer-d.
+	ArchivedAt *metav1.Time `json:"archivedAt,omitempty"`
 }
 
 // ProjectStatus reports reconciliation signals for a project.
@@ -217,6 +221,8 @@ type CompanySpec struct {
 	Website     string            `json:"website,omitempty"`
 	Notes       string            `json:"notes,omitempty"`
 	Tags        map[string]string `json:"tags,omitempty"`
+	// ArchivedAt is set while the company is archived in maintainer-d.
+	ArchivedAt *metav1.Time `json:"archivedAt,omitempty"`
 }
 
 // CompanyStatus provides aggregated company metrics.
@@ -291,6 +297,8 @@ type ProjectMembershipSpec struct {
 	Roles         []string          `json:"roles,omitempty"`
 	JoinedAt      *metav1.Time      `json:"joinedAt,omitempty"`
 	Notes         string            `json:"notes,omitempty"`
+	// ArchivedAt is set while the project or the maintainer is archived in maintainer-d.
+	ArchivedAt *metav1.Time `json:"archivedAt,omitempty"`
 }
 
 // ProjectMembershipStatus surfaces reconciliation metadata about a membership.
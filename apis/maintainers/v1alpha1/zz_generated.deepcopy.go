@@ -198,6 +198,10 @@ func (in *CompanySpec) DeepCopyInto(out *CompanySpec) {
 			(*out)[key] = val
 		}
 	}
+	if in.ArchivedAt != nil {
+		in, out := &in.ArchivedAt, &out.ArchivedAt
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CompanySpec.
@@ -310,6 +314,10 @@ func (in *MaintainerSpec) DeepCopyInto(out *MaintainerSpec) {
 			(*out)[key] = val
 		}
 	}
+	if in.ArchivedAt != nil {
+		in, out := &in.ArchivedAt, &out.ArchivedAt
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintainerSpec.
@@ -587,6 +595,10 @@ func (in *ProjectMembershipSpec) DeepCopyInto(out *ProjectMembershipSpec) {
 		in, out := &in.JoinedAt, &out.JoinedAt
 		*out = (*in).DeepCopy()
 	}
+	if in.ArchivedAt != nil {
+		in, out := &in.ArchivedAt, &out.ArchivedAt
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectMembershipSpec.
@@ -686,6 +698,10 @@ func (in *ProjectSpec) DeepCopyInto(out *ProjectSpec) {
 			(*out)[key] = val
 		}
 	}
+	if in.ArchivedAt != nil {
+		in, out := &in.ArchivedAt, &out.ArchivedAt
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectSpec.
@@ -0,0 +1,327 @@
+// Package backup takes consistent snapshots of the SQLite database, verifies them with a SHA-256 checksum and
+// PRAGMA integrity_check, and keeps them in a Target: a local directory or an S3-compatible bucket.
+package backup
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"maintainerd/db"
+)
+
+const (
+	// Ext is the extension of backups.
+	Ext = ".bak"
+	// ChecksumExt is appended to the name of a backup to name its checksum, stored in the format of sha256sum.
+	ChecksumExt = ".sha256"
+	// PreRestoreExt is appended to the database Restore replaces to name the copy it keeps.
+	PreRestoreExt = ".pre-restore"
+	// timeFormat stamps the names of backups; names sort in the order the backups were taken.
+	timeFormat = "20060102-150405"
+)
+
+var (
+	// ErrNoDatabase is returned when there is no database file to back up.
+	ErrNoDatabase = errors.New("no database to back up")
+	// ErrChecksumMismatch is returned when a backup does not match its checksum.
+	ErrChecksumMismatch = errors.New("backup does not match its checksum")
+	// ErrCorrupt is returned when a database fails PRAGMA integrity_check.
+	ErrCorrupt = errors.New("database failed its integrity check")
+)
+
+// rename is os.Rename; tests replace it to make a step of Restore fail.
+var rename = os.Rename
+
+// A Target stores backups by name.
+type Target interface {
+	// Put stores the content of r as name, replacing any object of that name.
+	Put(ctx context.Context, name string, r io.ReadSeeker) error
+	// Get opens the object name. It returns an error wrapping fs.ErrNotExist if there is none.
+	Get(ctx context.Context, name string) (io.ReadCloser, error)
+	// List returns the names of the stored objects that start with prefix, in no particular order.
+	List(ctx context.Context, prefix string) ([]string, error)
+	Delete(ctx context.Context, name string) error
+	// String describes the target in logs.
+	String() string
+}
+
+// A Backup is a verified snapshot stored in a Target.
+type Backup struct {
+	Name   string `json:"name"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// SQLitePath returns the file of the SQLite database dsn, see db.Driver.
+func SQLitePath(dsn string) (string, error) {
+	if db.Driver(dsn) != db.DriverSQLite {
+		return "", fmt.Errorf("%s is not a SQLite database; back up PostgreSQL with pg_dump", db.RedactDSN(dsn))
+	}
+	path := strings.TrimPrefix(strings.TrimSpace(dsn), "sqlite://")
+	if path == ":memory:" || strings.HasPrefix(path, "file:") {
+		return "", fmt.Errorf("%s is not a database file", path)
+	}
+	return path, nil
+}
+
+// Snapshot writes a copy of the SQLite database at dbPath to path with VACUUM INTO. The copy is read in one
+// transaction, so it is consistent even while the server writes to the database, unlike a copy of its files.
+func Snapshot(ctx context.Context, dbPath, path string) error {
+	if _, err := os.Stat(dbPath); errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("%w: %s does not exist", ErrNoDatabase, dbPath)
+	}
+	conn, err := db.OpenSilent(dbPath)
+	if err != nil {
+		return err
+	}
+	if sqlDB, err := conn.DB(); err == nil {
+		defer func() { _ = sqlDB.Close() }()
+	}
+	if err := conn.WithContext(ctx).Exec("VACUUM INTO ?", path).Error; err != nil {
+		return fmt.Errorf("snapshot %s: %w", dbPath, err)
+	}
+	return nil
+}
+
+// Verify runs PRAGMA integrity_check on the SQLite database at path.
+func Verify(ctx context.Context, path string) error {
+	conn, err := db.OpenSilent(path)
+	if err != nil {
+		return fmt.Errorf("%w: %s: %v", ErrCorrupt, path, err)
+	}
+	if sqlDB, err := conn.DB(); err == nil {
+		defer func() { _ = sqlDB.Close() }()
+	}
+	var problems []string
+	if err := conn.WithContext(ctx).Raw("PRAGMA integrity_check").Scan(&problems).Error; err != nil {
+		return fmt.Errorf("%w: %s: %v", ErrCorrupt, path, err)
+	}
+	if len(problems) != 1 || problems[0] != "ok" {
+		return fmt.Errorf("%w: %s: %s", ErrCorrupt, path, strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// Create snapshots the SQLite database at dbPath, verifies the snapshot and stores it in target with its checksum,
+// as <base name of dbPath>.<timestamp>.bak. When keep is positive, the oldest backups beyond it are then removed.
+func Create(ctx context.Context, dbPath string, target Target, keep int) (Backup, error) {
+	base := filepath.Base(dbPath)
+	name := fmt.Sprintf("%s.%s%s", base, time.Now().UTC().Format(timeFormat), Ext)
+	dir, err := os.MkdirTemp(filepath.Dir(dbPath), "."+base+"-backup-")
+	if err != nil {
+		return Backup{}, fmt.Errorf("create backup: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	snapshot := filepath.Join(dir, name)
+	if err := Snapshot(ctx, dbPath, snapshot); err != nil {
+		return Backup{}, err
+	}
+	if err := Verify(ctx, snapshot); err != nil {
+		return Backup{}, err
+	}
+	f, err := os.Open(snapshot)
+	if err != nil {
+		return Backup{}, fmt.Errorf("create backup: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+	sum, size, err := checksum(f)
+	if err != nil {
+		return Backup{}, err
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return Backup{}, err
+	}
+	b := Backup{Name: name, Size: size, SHA256: sum}
+
+	if err := target.Put(ctx, name, f); err != nil {
+		return b, fmt.Errorf("store %s in %s: %w", name, target, err)
+	}
+	line := fmt.Sprintf("%s  %s\n", sum, name)
+	if err := target.Put(ctx, name+ChecksumExt, strings.NewReader(line)); err != nil {
+		return b, fmt.Errorf("store the checksum of %s in %s: %w", name, target, err)
+	}
+	log.Printf("backup: INF, stored %s (%d bytes, sha256 %s) in %s", name, size, sum, target)
+	if keep > 0 {
+		if err := Prune(ctx, target, base, keep); err != nil {
+			log.Printf("backup: WRN, %v", err)
+		}
+	}
+	return b, nil
+}
+
+// List returns the names of the backups of the database named base in target, oldest first.
+func List(ctx context.Context, target Target, base string) ([]string, error) {
+	names, err := target.List(ctx, base+".")
+	if err != nil {
+		return nil, fmt.Errorf("list backups in %s: %w", target, err)
+	}
+	var backups []string
+	for _, name := range names {
+		if strings.HasSuffix(name, Ext) {
+			backups = append(backups, name)
+		}
+	}
+	sort.Strings(backups)
+	return backups, nil
+}
+
+// Prune removes the oldest backups of the database named base in target, and their checksums, beyond keep.
+func Prune(ctx context.Context, target Target, base string, keep int) error {
+	backups, err := List(ctx, target, base)
+	if err != nil || len(backups) <= keep {
+		return err
+	}
+	var errs []error
+	for _, name := range backups[:len(backups)-keep] {
+		if err := target.Delete(ctx, name); err != nil {
+			errs = append(errs, fmt.Errorf("remove old backup %s: %w", name, err))
+			continue
+		}
+		if err := target.Delete(ctx, name+ChecksumExt); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			errs = append(errs, fmt.Errorf("remove the checksum of %s: %w", name, err))
+		}
+		log.Printf("backup: INF, removed old backup %s", name)
+	}
+	return errors.Join(errs...)
+}
+
+// Restore replaces the SQLite database at dbPath with the backup name from target. The backup is downloaded next to
+// the database and must match its checksum and pass PRAGMA integrity_check before it replaces anything; backups taken
+// before checksums were stored are only integrity checked. The database being replaced, and its WAL files, are kept as
+// dbPath+PreRestoreExt; if the backup cannot be moved into place they are moved back. The server must not have the
+// database open.
+func Restore(ctx context.Context, target Target, name, dbPath string) error {
+	tmp, err := fetch(ctx, target, name, filepath.Dir(dbPath))
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(tmp) }()
+
+	// The database being replaced, which may be what is broken, is moved aside as it is, with its WAL files.
+	previous := dbPath + PreRestoreExt
+	var moved []string
+	putBack := func() error {
+		var errs []error
+		for _, suffix := range moved {
+			if err := rename(previous+suffix, dbPath+suffix); err != nil {
+				errs = append(errs, fmt.Errorf("put back %s: %w", dbPath+suffix, err))
+			}
+		}
+		return errors.Join(errs...)
+	}
+	for _, suffix := range []string{"", "-wal", "-shm"} {
+		_ = os.Remove(previous + suffix)
+		err := rename(dbPath+suffix, previous+suffix)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return errors.Join(fmt.Errorf("keep %s: %w", dbPath+suffix, err), putBack())
+		}
+		moved = append(moved, suffix)
+	}
+	if err := rename(tmp, dbPath); err != nil {
+		return errors.Join(fmt.Errorf("restore %s: %w", name, err), putBack())
+	}
+	log.Printf("backup: INF, restored %s from %s in %s, the database it replaced is %s", dbPath, name, target, previous)
+	return nil
+}
+
+// Check downloads the backup name from target to a temporary file in dir, checks it against its checksum and with
+// PRAGMA integrity_check, and removes it again.
+func Check(ctx context.Context, target Target, name, dir string) error {
+	tmp, err := fetch(ctx, target, name, dir)
+	if err != nil {
+		return err
+	}
+	return os.Remove(tmp)
+}
+
+// fetch downloads the backup name from target to a temporary file in dir and verifies it, returning the file.
+func fetch(ctx context.Context, target Target, name, dir string) (string, error) {
+	f, err := os.CreateTemp(dir, "."+name+"-")
+	if err != nil {
+		return "", fmt.Errorf("fetch %s: %w", name, err)
+	}
+	tmp := f.Name()
+	err = download(ctx, target, name, f)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = Verify(ctx, tmp)
+	}
+	if err != nil {
+		_ = os.Remove(tmp)
+		return "", err
+	}
+	return tmp, nil
+}
+
+// download copies the backup name from target to w, checking it against its stored checksum.
+func download(ctx context.Context, target Target, name string, w io.Writer) error {
+	want, err := storedChecksum(ctx, target, name)
+	if err != nil {
+		return err
+	}
+	r, err := target.Get(ctx, name)
+	if err != nil {
+		return fmt.Errorf("read %s from %s: %w", name, target, err)
+	}
+	defer func() { _ = r.Close() }()
+	h := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(w, h), r); err != nil {
+		return fmt.Errorf("read %s from %s: %w", name, target, err)
+	}
+	if got := hex.EncodeToString(h.Sum(nil)); want != "" && got != want {
+		return fmt.Errorf("%w: %s has sha256 %s, want %s", ErrChecksumMismatch, name, got, want)
+	}
+	if want == "" {
+		log.Printf("backup: WRN, %s has no checksum, only its integrity is checked", name)
+	}
+	return nil
+}
+
+// storedChecksum returns the checksum stored for the backup name, or "" if it has none.
+func storedChecksum(ctx context.Context, target Target, name string) (string, error) {
+	r, err := target.Get(ctx, name+ChecksumExt)
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("read the checksum of %s: %w", name, err)
+	}
+	defer func() { _ = r.Close() }()
+	line, err := bufio.NewReader(r).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", fmt.Errorf("read the checksum of %s: %w", name, err)
+	}
+	fields := strings.Fields(line)
+	if len(fields) != 2 || fields[1] != name {
+		return "", fmt.Errorf("malformed checksum of %s: %q", name, line)
+	}
+	return fields[0], nil
+}
+
+// checksum returns the hex SHA-256 and size of what r reads.
+func checksum(r io.Reader) (string, int64, error) {
+	h := sha256.New()
+	n, err := io.Copy(h, r)
+	if err != nil {
+		return "", 0, fmt.Errorf("checksum: %w", err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), n, nil
+}
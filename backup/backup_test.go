@@ -0,0 +1,131 @@
+package backup
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"maintainerd/db"
+	"maintainerd/model"
+)
+
+// openTestDB returns a migrated SQLite database in WAL mode at dir/maintainers.db, with one project.
+func openTestDB(t *testing.T, dir string) (*gorm.DB, string) {
+	t.Helper()
+	path := filepath.Join(dir, "maintainers.db")
+	conn, err := db.OpenSilent(path)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		if sqlDB, err := conn.DB(); err == nil {
+			_ = sqlDB.Close()
+		}
+	})
+	require.NoError(t, conn.Exec("PRAGMA journal_mode=WAL").Error)
+	require.NoError(t, db.Migrate(conn))
+	require.NoError(t, conn.Create(&model.Project{Name: "argo", Maturity: model.Graduated}).Error)
+	return conn, path
+}
+
+func projectNames(t *testing.T, path string) []string {
+	t.Helper()
+	conn, err := db.OpenSilent(path)
+	require.NoError(t, err)
+	defer func() {
+		if sqlDB, err := conn.DB(); err == nil {
+			_ = sqlDB.Close()
+		}
+	}()
+	var names []string
+	require.NoError(t, conn.Model(&model.Project{}).Order("name").Pluck("name", &names).Error)
+	return names
+}
+
+func TestCreateAndRestore(t *testing.T) {
+	ctx := t.Context()
+	dir := t.TempDir()
+	conn, dbPath := openTestDB(t, dir)
+	target := Dir(filepath.Join(dir, "backups"))
+
+	_, err := Create(ctx, filepath.Join(dir, "missing.db"), target, 0)
+	assert.ErrorIs(t, err, ErrNoDatabase)
+
+	// The server keeps the database open while it is backed up.
+	b, err := Create(ctx, dbPath, target, 0)
+	require.NoError(t, err)
+	assert.Len(t, b.SHA256, 64)
+	assert.Positive(t, b.Size)
+	backups, err := List(ctx, target, "maintainers.db")
+	require.NoError(t, err)
+	assert.Equal(t, []string{b.Name}, backups)
+	sum, err := os.ReadFile(filepath.Join(string(target), b.Name+ChecksumExt))
+	require.NoError(t, err)
+	assert.Equal(t, b.SHA256+"  "+b.Name+"\n", string(sum))
+
+	require.NoError(t, conn.Create(&model.Project{Name: "zot", Maturity: model.Sandbox}).Error)
+	sqlDB, err := conn.DB()
+	require.NoError(t, err)
+	require.NoError(t, sqlDB.Close())
+	require.NoError(t, Restore(ctx, target, b.Name, dbPath))
+	assert.Equal(t, []string{"argo"}, projectNames(t, dbPath))
+	assert.Equal(t, []string{"argo", "zot"}, projectNames(t, dbPath+PreRestoreExt), "the replaced database is kept")
+	require.NoError(t, Verify(ctx, dbPath))
+
+	// A backup that cannot be moved into place leaves the database where it was.
+	rename = func(from, to string) error {
+		if to == dbPath && strings.HasPrefix(filepath.Base(from), ".") {
+			return os.ErrPermission
+		}
+		return os.Rename(from, to)
+	}
+	t.Cleanup(func() { rename = os.Rename })
+	err = Restore(ctx, target, b.Name, dbPath)
+	assert.ErrorIs(t, err, os.ErrPermission)
+	rename = os.Rename
+	assert.Equal(t, []string{"argo"}, projectNames(t, dbPath), "the replaced database is moved back")
+	assert.NoFileExists(t, dbPath+PreRestoreExt)
+
+	// A backup that was changed after it was taken is not restored.
+	require.NoError(t, os.WriteFile(filepath.Join(string(target), b.Name), []byte("not a database"), 0o600))
+	err = Restore(ctx, target, b.Name, dbPath)
+	assert.ErrorIs(t, err, ErrChecksumMismatch)
+	assert.Equal(t, []string{"argo"}, projectNames(t, dbPath))
+
+	// Without a checksum, the integrity check still catches it.
+	require.NoError(t, os.Remove(filepath.Join(string(target), b.Name+ChecksumExt)))
+	err = Restore(ctx, target, b.Name, dbPath)
+	assert.ErrorIs(t, err, ErrCorrupt)
+}
+
+func TestPrune(t *testing.T) {
+	ctx := t.Context()
+	target := Dir(t.TempDir())
+	for _, name := range []string{"maintainers.db.20260101-000000.bak", "maintainers.db.20260102-000000.bak", "maintainers.db.20260103-000000.bak"} {
+		require.NoError(t, os.WriteFile(filepath.Join(string(target), name), nil, 0o600))
+		require.NoError(t, os.WriteFile(filepath.Join(string(target), name+ChecksumExt), nil, 0o600))
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(string(target), "other.db.20260101-000000.bak"), nil, 0o600))
+
+	require.NoError(t, Prune(ctx, target, "maintainers.db", 2))
+	backups, err := List(ctx, target, "maintainers.db")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"maintainers.db.20260102-000000.bak", "maintainers.db.20260103-000000.bak"}, backups)
+	_, err = os.Stat(filepath.Join(string(target), "maintainers.db.20260101-000000.bak"+ChecksumExt))
+	assert.ErrorIs(t, err, os.ErrNotExist, "checksums go with their backups")
+	others, err := List(ctx, target, "other.db")
+	require.NoError(t, err)
+	assert.Len(t, others, 1)
+}
+
+func TestSQLitePath(t *testing.T) {
+	path, err := SQLitePath("sqlite:///data/maintainers.db")
+	require.NoError(t, err)
+	assert.Equal(t, "/data/maintainers.db", path)
+	_, err = SQLitePath("postgres://md:secret@db/maintainerd")
+	assert.ErrorContains(t, err, "not a SQLite database")
+	assert.NotContains(t, err.Error(), "secret")
+}
@@ -0,0 +1,59 @@
+package backup
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Dir is a Target that keeps backups as files in a local directory, by default the one the database is in.
+type Dir string
+
+func (d Dir) Put(_ context.Context, name string, r io.ReadSeeker) error {
+	if err := os.MkdirAll(string(d), 0o750); err != nil {
+		return err
+	}
+	// Written to a temporary file first, so a backup that is listed is complete.
+	f, err := os.CreateTemp(string(d), "."+name+"-")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(f.Name()) }()
+	_, err = io.Copy(f, r)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), d.path(name))
+}
+
+func (d Dir) Get(_ context.Context, name string) (io.ReadCloser, error) {
+	return os.Open(d.path(name))
+}
+
+func (d Dir) List(_ context.Context, prefix string) ([]string, error) {
+	entries, err := os.ReadDir(string(d))
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, e := range entries {
+		if e.Type().IsRegular() && strings.HasPrefix(e.Name(), prefix) {
+			names = append(names, e.Name())
+		}
+	}
+	return names, nil
+}
+
+func (d Dir) Delete(_ context.Context, name string) error {
+	return os.Remove(d.path(name))
+}
+
+func (d Dir) String() string { return fmt.Sprintf("directory %s", string(d)) }
+
+func (d Dir) path(name string) string { return filepath.Join(string(d), filepath.Base(name)) }
@@ -0,0 +1,255 @@
+package backup
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// emptySHA256 is the SHA-256 of an empty payload.
+const emptySHA256 = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+// S3 is a Target that keeps backups in a bucket of Amazon S3 or of an S3-compatible store such as MinIO. Objects are
+// addressed path-style, Endpoint/Bucket/Prefix+name, which S3-compatible stores accept, and requests are signed with
+// AWS Signature Version 4.
+type S3 struct {
+	// Endpoint is the URL of the store, e.g. https://s3.us-east-1.amazonaws.com or http://localhost:9000.
+	Endpoint        string
+	Region          string
+	Bucket          string
+	Prefix          string
+	AccessKeyID     string
+	SecretAccessKey string
+	// SessionToken is the token of temporary credentials, e.g. those of an assumed role, and empty for long-term keys.
+	SessionToken string
+	// Client makes the requests, http.DefaultClient if nil.
+	Client *http.Client
+
+	// now is the signing time, time.Now if nil.
+	now func() time.Time
+}
+
+func (s *S3) Put(ctx context.Context, name string, r io.ReadSeeker) error {
+	sum, size, err := checksum(r)
+	if err != nil {
+		return err
+	}
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	req, err := s.request(ctx, http.MethodPut, s.Prefix+name, nil, io.NopCloser(r))
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	_, err = s.do(req, sum)
+	return err
+}
+
+func (s *S3) Get(ctx context.Context, name string) (io.ReadCloser, error) {
+	req, err := s.request(ctx, http.MethodGet, s.Prefix+name, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.do(req, emptySHA256)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+func (s *S3) List(ctx context.Context, prefix string) ([]string, error) {
+	var names []string
+	token := ""
+	for {
+		query := url.Values{"list-type": {"2"}, "prefix": {s.Prefix + prefix}}
+		if token != "" {
+			query.Set("continuation-token", token)
+		}
+		req, err := s.request(ctx, http.MethodGet, "", query, nil)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := s.do(req, emptySHA256)
+		if err != nil {
+			return nil, err
+		}
+		var page struct {
+			Contents []struct {
+				Key string
+			}
+			IsTruncated           bool
+			NextContinuationToken string
+		}
+		err = xml.NewDecoder(resp.Body).Decode(&page)
+		_ = resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("list %s: %w", s, err)
+		}
+		for _, c := range page.Contents {
+			if name := strings.TrimPrefix(c.Key, s.Prefix); !strings.Contains(name, "/") {
+				names = append(names, name)
+			}
+		}
+		if !page.IsTruncated || page.NextContinuationToken == "" {
+			return names, nil
+		}
+		token = page.NextContinuationToken
+	}
+}
+
+func (s *S3) Delete(ctx context.Context, name string) error {
+	req, err := s.request(ctx, http.MethodDelete, s.Prefix+name, nil, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := s.do(req, emptySHA256)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+func (s *S3) String() string {
+	return fmt.Sprintf("s3://%s/%s at %s", s.Bucket, s.Prefix, s.Endpoint)
+}
+
+// request returns a request for key in the bucket, or for the bucket itself if key is empty.
+func (s *S3) request(ctx context.Context, method, key string, query url.Values, body io.ReadCloser) (*http.Request, error) {
+	u, err := url.Parse(strings.TrimSuffix(s.Endpoint, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("bad S3 endpoint %q: %w", s.Endpoint, err)
+	}
+	u.Path += "/" + s.Bucket
+	if key != "" {
+		u.Path += "/" + key
+	}
+	u.RawPath = escapePath(u.Path)
+	u.RawQuery = canonicalQuery(query)
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Body = body
+	}
+	return req, nil
+}
+
+// do signs and sends req, whose payload has the hex SHA-256 payloadHash, and returns the response of a request that
+// succeeded. A missing object is reported as fs.ErrNotExist.
+func (s *S3) do(req *http.Request, payloadHash string) (*http.Response, error) {
+	s.sign(req, payloadHash)
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%s %s: %w", req.Method, req.URL.Path, err)
+	}
+	if resp.StatusCode/100 == 2 {
+		return resp, nil
+	}
+	defer func() { _ = resp.Body.Close() }()
+	var s3Err struct {
+		Code    string
+		Message string
+	}
+	_ = xml.NewDecoder(io.LimitReader(resp.Body, 1<<16)).Decode(&s3Err)
+	if resp.StatusCode == http.StatusNotFound && s3Err.Code != "NoSuchBucket" {
+		return nil, fmt.Errorf("%s %s: %w", req.Method, req.URL.Path, fs.ErrNotExist)
+	}
+	return nil, fmt.Errorf("%s %s: %s: %s %s", req.Method, req.URL.Path, resp.Status, s3Err.Code, s3Err.Message)
+}
+
+// sign adds the AWS Signature Version 4 headers to req, signing its host, method, path, query and payloadHash.
+func (s *S3) sign(req *http.Request, payloadHash string) {
+	now := time.Now
+	if s.now != nil {
+		now = s.now
+	}
+	t := now().UTC()
+	amzDate := t.Format("20060102T150405Z")
+	date := t.Format("20060102")
+	req.Header.Set("x-amz-date", amzDate)
+	req.Header.Set("x-amz-content-sha256", payloadHash)
+
+	signedHeaders := "host;x-amz-content-sha256;x-amz-date"
+	canonicalHeaders := "host:" + req.URL.Host + "\nx-amz-content-sha256:" + payloadHash + "\nx-amz-date:" + amzDate + "\n"
+	if s.SessionToken != "" {
+		req.Header.Set("x-amz-security-token", s.SessionToken)
+		signedHeaders += ";x-amz-security-token"
+		canonicalHeaders += "x-amz-security-token:" + s.SessionToken + "\n"
+	}
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		escapePath(req.URL.Path),
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders,
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	scope := date + "/" + s.Region + "/s3/aws4_request"
+	hashed := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(hashed[:])
+
+	key := []byte("AWS4" + s.SecretAccessKey)
+	for _, part := range []string{date, s.Region, "s3", "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.AccessKeyID, scope, signedHeaders, signature))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+// canonicalQuery encodes query with its keys and values sorted and escaped as Signature Version 4 requires.
+func canonicalQuery(query url.Values) string {
+	var pairs []string
+	for k, values := range query {
+		for _, v := range values {
+			pairs = append(pairs, escape(k)+"="+escape(v))
+		}
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, "&")
+}
+
+// escapePath escapes each segment of path, see escape.
+func escapePath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, s := range segments {
+		segments[i] = escape(s)
+	}
+	return strings.Join(segments, "/")
+}
+
+// escape percent-encodes every byte of s but the unreserved characters of RFC 3986.
+func escape(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' || strings.IndexByte("-_.~", c) >= 0 {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"maintainerd/db"
+)
+
+// archivable describes a kind of record the archive and restore commands operate on.
+type archivable struct {
+	kind    string
+	archive func(db.Store, context.Context, uint) error
+	restore func(db.Store, context.Context, uint) error
+}
+
+var archivables = []archivable{
+	{"maintainer", db.Store.ArchiveMaintainer, db.Store.RestoreMaintainer},
+	{"project", db.Store.ArchiveProject, db.Store.RestoreProject},
+	{"company", db.Store.ArchiveCompany, db.Store.RestoreCompany},
+}
+
+// newArchiveCmd returns the archive command. Archiving soft deletes a maintainer, project or company together with
+// its memberships and, for projects, service teams; nothing is removed from the database.
+func newArchiveCmd(dbPath *string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "archive",
+		Short: "Archive maintainers, projects and companies, or list archived ones",
+	}
+	for _, a := range archivables {
+		cmd.AddCommand(newArchiveKindCmd(dbPath, a.kind, "Archive", "archived", a.archive))
+	}
+	cmd.AddCommand(&cobra.Command{
+		Use:   "list",
+		Short: "List archived maintainers, projects and companies",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			conn, err := openDB(dbPath)
+			if err != nil {
+				return err
+			}
+			return printArchived(cmd, db.NewSQLStore(conn))
+		},
+	})
+	return cmd
+}
+
+// newRestoreCmd returns the restore command, which undoes archive including the memberships archived with a record,
+// or restores a backup of the database.
+func newRestoreCmd(dbPath *string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "restore",
+		Short: "Restore archived maintainers, projects and companies, or a database backup",
+	}
+	for _, a := range archivables {
+		cmd.AddCommand(newArchiveKindCmd(dbPath, a.kind, "Restore", "restored", a.restore))
+	}
+	cmd.AddCommand(newRestoreBackupCmd(dbPath))
+	return cmd
+}
+
+func newArchiveKindCmd(dbPath *string, kind, verb, done string, op func(db.Store, context.Context, uint) error) *cobra.Command {
+	return &cobra.Command{
+		Use:   kind + " ID",
+		Short: fmt.Sprintf("%s the %s with ID", verb, kind),
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := strconv.ParseUint(args[0], 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid %s ID %q", kind, args[0])
+			}
+			conn, err := openDB(dbPath)
+			if err != nil {
+				return err
+			}
+			if err := op(db.NewSQLStore(conn), cmd.Context(), uint(id)); err != nil {
+				return fmt.Errorf("%s %s %d: %w", strings.ToLower(verb), kind, id, err)
+			}
+			cmd.Printf("%s %s %d\n", done, kind, id)
+			return nil
+		},
+	}
+}
+
+func printArchived(cmd *cobra.Command, store db.Store) error {
+	ctx := cmd.Context()
+	maintainers, err := store.ListArchivedMaintainers(ctx)
+	if err != nil {
+		return err
+	}
+	projects, err := store.ListArchivedProjects(ctx)
+	if err != nil {
+		return err
+	}
+	companies, err := store.ListArchivedCompanies(ctx)
+	if err != nil {
+		return err
+	}
+
+	tw := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "KIND\tID\tNAME\tARCHIVED AT")
+	for _, m := range maintainers {
+		fmt.Fprintf(tw, "maintainer\t%d\t@%s\t%s\n", m.ID, m.GitHubAccount, m.DeletedAt.Time.Format(time.RFC3339))
+	}
+	for _, p := range projects {
+		fmt.Fprintf(tw, "project\t%d\t%s\t%s\n", p.ID, p.Name, p.DeletedAt.Time.Format(time.RFC3339))
+	}
+	for _, c := range companies {
+		fmt.Fprintf(tw, "company\t%d\t%s\t%s\n", c.ID, c.Name, c.DeletedAt.Time.Format(time.RFC3339))
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,204 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"maintainerd/backup"
+)
+
+// The environment variables an s3:// backup target reads, named as the AWS tools name them.
+const (
+	s3EndpointEnvVar  = "AWS_ENDPOINT_URL"
+	s3RegionEnvVar    = "AWS_REGION"
+	s3AccessKeyEnvVar = "AWS_ACCESS_KEY_ID"     //nolint:gosec
+	s3SecretKeyEnvVar = "AWS_SECRET_ACCESS_KEY" //nolint:gosec
+	s3TokenEnvVar     = "AWS_SESSION_TOKEN"     //nolint:gosec
+)
+
+// backupTargetUsage describes the --target flag of the backup and restore commands.
+const backupTargetUsage = "Where backups are kept: a directory, or s3://BUCKET/PREFIX (default: the directory of the database)"
+
+// newBackupTarget returns the backup.Target spec names: the directory of the database at dbPath if spec is empty, a
+// bucket if it is an s3:// URL, and otherwise a directory. An S3 target reads the endpoint, region and keys from
+// $AWS_ENDPOINT_URL, $AWS_REGION, $AWS_ACCESS_KEY_ID and $AWS_SECRET_ACCESS_KEY, and the token of temporary
+// credentials from $AWS_SESSION_TOKEN; set the endpoint for MinIO.
+func newBackupTarget(spec, dbPath string) (backup.Target, error) {
+	rest, ok := strings.CutPrefix(spec, "s3://")
+	if !ok {
+		if spec == "" {
+			spec = filepath.Dir(dbPath)
+		}
+		return backup.Dir(spec), nil
+	}
+	bucket, prefix, _ := strings.Cut(rest, "/")
+	if bucket == "" {
+		return nil, fmt.Errorf("no bucket in backup target %q", spec)
+	}
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+	target := &backup.S3{
+		Endpoint:        viper.GetString(s3EndpointEnvVar),
+		Region:          viper.GetString(s3RegionEnvVar),
+		Bucket:          bucket,
+		Prefix:          prefix,
+		AccessKeyID:     viper.GetString(s3AccessKeyEnvVar),
+		SecretAccessKey: viper.GetString(s3SecretKeyEnvVar),
+		SessionToken:    viper.GetString(s3TokenEnvVar),
+	}
+	if target.Region == "" {
+		target.Region = "us-east-1"
+	}
+	if target.Endpoint == "" {
+		target.Endpoint = "https://s3." + target.Region + ".amazonaws.com"
+	}
+	if target.AccessKeyID == "" || target.SecretAccessKey == "" {
+		return nil, fmt.Errorf("environment variables %s and %s must be set for %s", s3AccessKeyEnvVar, s3SecretKeyEnvVar, spec)
+	}
+	return target, nil
+}
+
+// newBackupCmd returns the backup command, which takes a verified snapshot of the SQLite database.
+func newBackupCmd(dbPath *string) *cobra.Command {
+	var targetSpec string
+	var maxBackups int
+	cmd := &cobra.Command{
+		Use:   "backup",
+		Short: "Take a consistent, verified backup of the SQLite database",
+		Long: "backup snapshots the SQLite database with VACUUM INTO, which is consistent while the server is " +
+			"writing, checks the snapshot with PRAGMA integrity_check and stores it in --target as " +
+			"<db>.<timestamp>" + backup.Ext + ", with its SHA-256 checksum as <db>.<timestamp>" + backup.Ext +
+			backup.ChecksumExt + ". The oldest backups beyond --max-backups are then removed. PostgreSQL databases " +
+			"are backed up with pg_dump instead.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, err := backup.SQLitePath(resolveDSN(dbPath))
+			if err != nil {
+				return err
+			}
+			target, err := newBackupTarget(targetSpec, path)
+			if err != nil {
+				return err
+			}
+			b, err := backup.Create(cmd.Context(), path, target, maxBackups)
+			if err != nil {
+				return err
+			}
+			cmd.Printf("backed up %s to %s in %s (%d bytes, sha256 %s)\n", path, b.Name, target, b.Size, b.SHA256)
+			return nil
+		},
+	}
+	cmd.PersistentFlags().StringVar(&targetSpec, "target", "", backupTargetUsage)
+	cmd.Flags().IntVar(&maxBackups, "max-backups", defaultMaxBackups, "Maximum number of backups to retain")
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "list",
+		Short: "List the backups of the database, oldest first",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, err := backup.SQLitePath(resolveDSN(dbPath))
+			if err != nil {
+				return err
+			}
+			target, err := newBackupTarget(targetSpec, path)
+			if err != nil {
+				return err
+			}
+			backups, err := backup.List(cmd.Context(), target, filepath.Base(path))
+			if err != nil {
+				return err
+			}
+			if len(backups) == 0 {
+				cmd.Printf("no backups of %s in %s\n", path, target)
+			}
+			for _, name := range backups {
+				cmd.Println(name)
+			}
+			return nil
+		},
+	})
+	return cmd
+}
+
+// newRestoreBackupCmd returns the restore backup command, which replaces the database with a verified backup.
+func newRestoreBackupCmd(dbPath *string) *cobra.Command {
+	var targetSpec string
+	var dryRun bool
+	cmd := &cobra.Command{
+		Use:   "backup [NAME]",
+		Short: "Replace the SQLite database with a backup, the newest one by default",
+		Long: "restore backup downloads the backup NAME, or the newest backup of the database, from --target and " +
+			"checks it against its checksum and with PRAGMA integrity_check before it replaces the database. The " +
+			"database being replaced is kept as <db>" + backup.PreRestoreExt + ". Stop the server before restoring. " +
+			"With --dry-run the backup is only downloaded and checked.",
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, err := backup.SQLitePath(resolveDSN(dbPath))
+			if err != nil {
+				return err
+			}
+			target, err := newBackupTarget(targetSpec, path)
+			if err != nil {
+				return err
+			}
+			var name string
+			if len(args) == 1 {
+				name = filepath.Base(args[0])
+			} else {
+				backups, err := backup.List(cmd.Context(), target, filepath.Base(path))
+				if err != nil {
+					return err
+				}
+				if len(backups) == 0 {
+					return fmt.Errorf("no backups of %s in %s", path, target)
+				}
+				name = backups[len(backups)-1]
+			}
+			if dryRun {
+				if err := backup.Check(cmd.Context(), target, name, filepath.Dir(path)); err != nil {
+					return err
+				}
+				cmd.Printf("%s in %s is intact, would restore %s from it\n", name, target, path)
+				return nil
+			}
+			if err := backup.Restore(cmd.Context(), target, name, path); err != nil {
+				return err
+			}
+			cmd.Printf("restored %s from %s in %s, the database it replaced is %s\n", path, name, target, path+backup.PreRestoreExt)
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&targetSpec, "target", "", backupTargetUsage)
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Download and check the backup without restoring it")
+	return cmd
+}
+
+// backupBeforeLoad backs up the database at dsn before bootstrap changes it, unless it is not a SQLite database or
+// does not exist yet.
+func backupBeforeLoad(cmd *cobra.Command, dsn, targetSpec string, maxBackups int) error {
+	path, err := backup.SQLitePath(dsn)
+	if err != nil {
+		log.Printf("skipping backup: %v", err)
+		return nil
+	}
+	target, err := newBackupTarget(targetSpec, path)
+	if err != nil {
+		return err
+	}
+	b, err := backup.Create(cmd.Context(), path, target, maxBackups)
+	if errors.Is(err, backup.ErrNoDatabase) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create DB backup: %w", err)
+	}
+	log.Printf("existing database backed up to %s in %s", b.Name, target)
+	return nil
+}
@@ -0,0 +1,233 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"maintainerd/db"
+	"maintainerd/model"
+)
+
+// newCompaniesCmd returns the companies command, which canonicalizes companies and reports maintainer affiliations.
+func newCompaniesCmd(dbPath *string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "companies",
+		Short: "Merge companies spelt differently and report who maintainers work for",
+		Long: "Companies are found by their aliases: spellings of the name that are lower-cased and stripped of " +
+			"punctuation and legal forms such as LLC or Inc., so \"Google\", \"Google LLC\" and \"google\" are one " +
+			"company. A maintainer's change of company closes their affiliation period and opens another, so reports " +
+			"can look at any date.",
+	}
+	cmd.AddCommand(newCompaniesNormalizeCmd(dbPath), newCompaniesMergeCmd(dbPath), newCompaniesAliasCmd(dbPath),
+		newCompaniesHistoryCmd(dbPath), newCompaniesReportCmd(dbPath))
+	return cmd
+}
+
+func newCompaniesNormalizeCmd(dbPath *string) *cobra.Command {
+	var dryRun bool
+	cmd := &cobra.Command{
+		Use:   "normalize",
+		Short: "Merge the companies whose names are spellings of the same name",
+		Long: "normalize merges the companies whose names only differ in case, punctuation or legal form into the " +
+			"one most maintainers are recorded with; see companies merge. Use --dry-run to print the merges without " +
+			"making them.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			conn, err := openDB(dbPath)
+			if err != nil {
+				return err
+			}
+			merges, err := db.NormalizeCompanies(cmd.Context(), conn, dryRun)
+			if err != nil {
+				return err
+			}
+			if len(merges) == 0 {
+				cmd.Println("no companies to merge")
+				return nil
+			}
+			if dryRun {
+				cmd.Println("dry run, nothing is written")
+			}
+			for _, m := range merges {
+				cmd.Println(m)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print the merges without making them")
+	return cmd
+}
+
+func newCompaniesMergeCmd(dbPath *string) *cobra.Command {
+	var into uint
+	var dryRun bool
+	cmd := &cobra.Command{
+		Use:   "merge ID --into COMPANY_ID",
+		Short: "Merge a duplicate company into the surviving company",
+		Long: "merge repoints the duplicate's maintainers, affiliation periods and aliases to the surviving company, " +
+			"which takes the duplicate's name as an alias, and archives the duplicate. The merge is audit logged. Use " +
+			"it for names that are not spellings of each other, e.g. after an acquisition. Use --dry-run to print the " +
+			"merge without making it.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := strconv.ParseUint(args[0], 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid company ID %q", args[0])
+			}
+			if into == 0 {
+				return fmt.Errorf("--into is required")
+			}
+			conn, err := openDB(dbPath)
+			if err != nil {
+				return err
+			}
+			merge, err := db.MergeCompanies(cmd.Context(), conn, into, uint(id), dryRun)
+			if err != nil {
+				return err
+			}
+			if dryRun {
+				cmd.Println("dry run, nothing is written")
+			}
+			cmd.Println(merge)
+			return nil
+		},
+	}
+	cmd.Flags().UintVar(&into, "into", 0, "ID of the company to keep")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print the merge without making it")
+	return cmd
+}
+
+func newCompaniesAliasCmd(dbPath *string) *cobra.Command {
+	return &cobra.Command{
+		Use:   "alias COMPANY ALIAS...",
+		Short: "Record other names a company is known by",
+		Long: "alias records each ALIAS as a name of COMPANY, given by ID or name, so sheet rows that use it are " +
+			"recorded with the company. An alias of another company is refused; merge the companies instead.",
+		Args: cobra.MinimumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			conn, err := openDB(dbPath)
+			if err != nil {
+				return err
+			}
+			store := db.NewSQLStore(conn)
+			var companyID uint
+			if id, err := strconv.ParseUint(args[0], 10, 64); err == nil {
+				companyID = uint(id)
+			} else {
+				company, err := store.FindCompany(cmd.Context(), args[0])
+				if err != nil {
+					return err
+				}
+				companyID = company.ID
+			}
+			for _, alias := range args[1:] {
+				if err := store.AddCompanyAlias(cmd.Context(), companyID, alias); err != nil {
+					return err
+				}
+				cmd.Printf("%q is an alias of company %d\n", alias, companyID)
+			}
+			return nil
+		},
+	}
+}
+
+func newCompaniesHistoryCmd(dbPath *string) *cobra.Command {
+	return &cobra.Command{
+		Use:   "history GITHUB_LOGIN",
+		Short: "List the companies a maintainer has worked for",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			conn, err := openDB(dbPath)
+			if err != nil {
+				return err
+			}
+			store := db.NewSQLStore(conn)
+			m, err := store.FindMaintainerByIdentity(cmd.Context(), model.MaintainerIdentity{Kind: model.IdentityGitHub, Value: args[0]})
+			if err != nil {
+				return err
+			}
+			periods, err := store.MaintainerAffiliations(cmd.Context(), m.ID)
+			if err != nil {
+				return err
+			}
+			if len(periods) == 0 {
+				cmd.Printf("no affiliations recorded for @%s\n", m.GitHubAccount)
+				return nil
+			}
+			tw := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 4, 2, ' ', 0)
+			fmt.Fprintln(tw, "COMPANY\tFROM\tUNTIL\tREASON")
+			for _, p := range periods {
+				until := "now"
+				if p.EndedAt != nil {
+					until = p.EndedAt.Format(time.DateOnly)
+				}
+				fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", p.Company.Name, p.StartedAt.Format(time.DateOnly), until, p.Reason)
+			}
+			return tw.Flush()
+		},
+	}
+}
+
+func newCompaniesReportCmd(dbPath *string) *cobra.Command {
+	var on string
+	var asJSON bool
+	cmd := &cobra.Command{
+		Use:   "report",
+		Short: "Report how many maintainers of each project work for each company",
+		Long: "report counts the maintainers of each project by the company they worked for on --on, today by " +
+			"default, for CNCF's maintainer diversity requirements: a graduated project needs maintainers from at " +
+			"least two organizations. Projects whose maintainers all work for one company are marked. The report " +
+			"names no maintainers.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			date := time.Now()
+			if on != "" {
+				var err error
+				if date, err = time.Parse(time.DateOnly, on); err != nil {
+					return fmt.Errorf("invalid --on date %q, want YYYY-MM-DD", on)
+				}
+			}
+			conn, err := openDB(dbPath)
+			if err != nil {
+				return err
+			}
+			report, err := db.CompanyReport(cmd.Context(), conn, date)
+			if err != nil {
+				return err
+			}
+			if asJSON {
+				enc := json.NewEncoder(cmd.OutOrStdout())
+				enc.SetIndent("", "  ")
+				return enc.Encode(report)
+			}
+			tw := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 4, 2, ' ', 0)
+			fmt.Fprintln(tw, "PROJECT\tMATURITY\tMAINTAINERS\tCOMPANIES\tLARGEST SHARE\tBY COMPANY")
+			for _, p := range report {
+				if p.Maintainers == 0 {
+					continue
+				}
+				var byCompany []string
+				for _, c := range p.Companies {
+					byCompany = append(byCompany, fmt.Sprintf("%s %d", c.Company, c.Maintainers))
+				}
+				companies := strconv.Itoa(p.CompanyCount())
+				if p.CompanyCount() < 2 {
+					companies += " !"
+				}
+				_, share := p.LargestShare()
+				fmt.Fprintf(tw, "%s\t%s\t%d\t%s\t%.0f%%\t%s\n", p.Project, p.Maturity, p.Maintainers, companies,
+					share*100, strings.Join(byCompany, ", "))
+			}
+			return tw.Flush()
+		},
+	}
+	cmd.Flags().StringVar(&on, "on", "", "Date to report on, YYYY-MM-DD (default today)")
+	cmd.Flags().BoolVar(&asJSON, "json", false, "Print the report as JSON")
+	return cmd
+}
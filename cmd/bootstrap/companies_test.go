@@ -0,0 +1,45 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"maintainerd/db"
+)
+
+func TestCompaniesCommands(t *testing.T) {
+	dir := t.TempDir()
+	dbPath := filepath.Join(dir, "maintainers.db")
+	sheet := db.ProjectHdr + "," + db.StatusHdr + "," + db.MaintainerNameHdr + "," + db.CompanyNameHdr + "," + db.EmailHdr + "," + db.GitHubHdr + "\n" +
+		"argo,Graduated,Alice,Google,alice@example.com,alice\n" +
+		"argo,Graduated,Bob,Google LLC,bob@example.com,bob\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "Active.csv"), []byte(sheet), 0o600))
+	_, err := execute(t, newSchemaCmd(&dbPath))
+	require.NoError(t, err)
+	_, err = execute(t, newSheetCmd(&dbPath), "--source", "csv", "--source-path", dir)
+	require.NoError(t, err)
+
+	out, err := execute(t, newCompaniesCmd(&dbPath), "report")
+	require.NoError(t, err)
+	assert.Regexp(t, `argo\s+Graduated\s+2\s+1 !\s+100%\s+Google 2\n`, out)
+	assert.NotContains(t, out, "example.com")
+	out, err = execute(t, newCompaniesCmd(&dbPath), "report", "--on", "2000-01-01")
+	require.NoError(t, err)
+	assert.NotContains(t, out, "argo", "nobody maintained argo in 2000")
+	_, err = execute(t, newCompaniesCmd(&dbPath), "report", "--on", "yesterday")
+	assert.ErrorContains(t, err, "YYYY-MM-DD")
+
+	out, err = execute(t, newCompaniesCmd(&dbPath), "alias", "google", "Alphabet Inc.")
+	require.NoError(t, err)
+	assert.Contains(t, out, `"Alphabet Inc." is an alias of company 1`)
+	out, err = execute(t, newCompaniesCmd(&dbPath), "history", "bob")
+	require.NoError(t, err)
+	assert.Regexp(t, `Google\s+\d{4}-\d{2}-\d{2}\s+now`, out)
+	out, err = execute(t, newCompaniesCmd(&dbPath), "normalize", "--dry-run")
+	require.NoError(t, err)
+	assert.Equal(t, "no companies to merge\n", out)
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"maintainerd/db"
+)
+
+// newDuplicatesCmd returns the duplicates command, which lists maintainers, collaborators and staff members that
+// likely describe the same person.
+func newDuplicatesCmd(dbPath *string) *cobra.Command {
+	return &cobra.Command{
+		Use:   "duplicates",
+		Short: "List people recorded more than once, by email, GitHub login or name",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			conn, err := openDB(dbPath)
+			if err != nil {
+				return err
+			}
+			groups, err := db.NewSQLStore(conn).FindDuplicatePeople(cmd.Context())
+			if err != nil {
+				return err
+			}
+			if len(groups) == 0 {
+				cmd.Println("no duplicates found")
+				return nil
+			}
+			tw := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 4, 2, ' ', 0)
+			fmt.Fprintln(tw, "GROUP\tKIND\tID\tNAME\tGITHUB\tMATCHED ON")
+			for i, g := range groups {
+				for j, p := range g.People {
+					matched := ""
+					if j == 0 {
+						matched = strings.Join(g.Reasons, ", ")
+					}
+					fmt.Fprintf(tw, "%d\t%s\t%d\t%s\t%s\t%s\n", i+1, p.Kind, p.ID, p.Name, strings.Join(p.GitHubAccounts, ","), matched)
+				}
+			}
+			return tw.Flush()
+		},
+	}
+}
+
+// newMergeCmd returns the merge command, which folds a duplicate maintainer or collaborator into a surviving
+// maintainer.
+func newMergeCmd(dbPath *string) *cobra.Command {
+	var into uint
+	var dryRun bool
+	cmd := &cobra.Command{
+		Use:   "merge maintainer|collaborator ID --into MAINTAINER_ID",
+		Short: "Merge a duplicate maintainer or collaborator into the surviving maintainer",
+		Long: "Merge repoints the duplicate's memberships, membership and affiliation history, identities, service " +
+			"team links and audit log entries to the surviving maintainer, records its emails and GitHub login as " +
+			"identities of the survivor and deletes it. The merge is audit logged. Use --dry-run to print the changes " +
+			"without making them.",
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			kind := db.PersonKind(args[0])
+			if kind != db.PersonMaintainer && kind != db.PersonCollaborator {
+				return fmt.Errorf("cannot merge a %q, only a maintainer or collaborator", args[0])
+			}
+			id, err := strconv.ParseUint(args[1], 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid %s ID %q", kind, args[1])
+			}
+			if into == 0 {
+				return fmt.Errorf("--into is required")
+			}
+			conn, err := openDB(dbPath)
+			if err != nil {
+				return err
+			}
+			duplicate := db.PersonRef{Kind: kind, ID: uint(id)}
+			report, err := db.NewSQLStore(conn).MergePeople(cmd.Context(), into, duplicate, dryRun)
+			if err != nil {
+				return fmt.Errorf("merge %s into maintainer %d: %w", duplicate, into, err)
+			}
+			verb := "merged"
+			if dryRun {
+				verb = "dry run, would merge"
+			}
+			cmd.Printf("%s %s into maintainer %d\n", verb, duplicate, into)
+			for _, c := range report.Changes {
+				cmd.Printf("  %s\n", c)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().UintVar(&into, "into", 0, "ID of the maintainer to keep")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print the changes the merge would make without making them")
+	return cmd
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"maintainerd/db"
+)
+
+// The values of the export --to flag.
+const (
+	exportCSV    = "csv"
+	exportSheets = "sheets"
+)
+
+// newExportCmd returns the export command, which writes the registry back out to CSV files or a Google Sheet.
+func newExportCmd(dbPath *string) *cobra.Command {
+	var to, dir, spreadsheetID, tab, layoutPath string
+	var opts db.ExportOptions
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export projects, maintainers, companies and service teams and users",
+		Long: "export writes the live projects, maintainers with their memberships, companies, service teams and " +
+			"service users to CSV files, one per table, or to a worksheet of a Google Sheet. Rows are sorted by name, so " +
+			"exports of unchanged data are identical and exports of changed data diff line by line. Emails are left out " +
+			"unless --include-emails is set. --to sheets writes to $" + spreadsheetEnvVar + " unless --spreadsheet is " +
+			"set, and needs $" + googleWorkspaceCredentials + " with edit access. The worksheets the sheet import " +
+			"reads, Active and Staff or those of --sheet-layout, are refused as --tab.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var exporter db.Exporter
+			switch to {
+			case exportCSV:
+				if dir == "" {
+					return fmt.Errorf("--to %s needs --dir", to)
+				}
+				exporter = db.CSVExporter{Dir: dir}
+			case exportSheets:
+				if spreadsheetID == "" {
+					spreadsheetID = viper.GetString(spreadsheetEnvVar)
+				}
+				if spreadsheetID == "" {
+					return fmt.Errorf("--to %s needs --spreadsheet or $%s", to, spreadsheetEnvVar)
+				}
+				credentialsPath := viper.GetString(googleWorkspaceCredentials)
+				if credentialsPath == "" {
+					return fmt.Errorf("environment variable %s is not set", googleWorkspaceCredentials)
+				}
+				sheets := db.SheetsExporter{SpreadsheetID: spreadsheetID, CredentialsPath: credentialsPath, Tab: tab}
+				if layoutPath != "" {
+					layout, err := db.LoadSheetLayout(layoutPath)
+					if err != nil {
+						return fmt.Errorf("sheet layout: %w", err)
+					}
+					sheets.Layout = &layout
+				}
+				exporter = sheets
+			default:
+				return fmt.Errorf("unknown --to %q, want %s or %s", to, exportCSV, exportSheets)
+			}
+
+			conn, err := openDB(dbPath)
+			if err != nil {
+				return err
+			}
+			tables, err := db.ExportTables(cmd.Context(), conn, opts)
+			if err != nil {
+				return err
+			}
+			if err := exporter.Export(cmd.Context(), tables); err != nil {
+				return err
+			}
+			for _, t := range tables {
+				cmd.Printf("exported %d %s rows to %s\n", len(t.Rows), t.Name, exporter)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&to, "to", exportCSV, "Where to export to: csv or sheets")
+	cmd.Flags().StringVar(&dir, "dir", "", "The directory to write the CSV files to with --to csv")
+	cmd.Flags().StringVar(&spreadsheetID, "spreadsheet", "", "The Google Sheet to write to with --to sheets, $"+spreadsheetEnvVar+" by default")
+	cmd.Flags().StringVar(&tab, "tab", "Registry Export", "The worksheet to write to with --to sheets; it is replaced")
+	cmd.Flags().StringVar(&layoutPath, "sheet-layout", "", "YAML file of the sheet layout whose worksheets --tab must not be")
+	cmd.Flags().BoolVar(&opts.IncludeEmails, "include-emails", false, "Include maintainer, service user and GitHub emails")
+	return cmd
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"maintainerd/db"
+)
+
+// newFossaCmd returns the fossa command, which runs the FOSSA stage of bootstrap on its own.
+func newFossaCmd(dbPath *string) *cobra.Command {
+	var opts db.StageOptions
+	cmd := &cobra.Command{
+		Use:   "fossa",
+		Short: "Load FOSSA users and teams into an existing database",
+		Long: "fossa loads the users of the FOSSA organization as service users, linked to the maintainers or " +
+			"collaborators they are and to the teams of their projects. Users that cannot be loaded are listed and " +
+			"the rest are loaded; the command then fails so the run can be repeated. With --resume, the users an " +
+			"earlier failed or interrupted run loaded before its first failure are skipped. Only $" + apiTokenEnvVar +
+			" is needed.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := fossaClient()
+			if err != nil {
+				return err
+			}
+			opts.Stages, opts.Fossa = []db.Stage{db.StageFossa}, client
+			run, err := runStages(cmd, dbPath, opts, sourceFlags{})
+			if err != nil {
+				return err
+			}
+			if n := len(run.Fossa.Errors); n > 0 && opts.DryRun {
+				return fmt.Errorf("%d FOSSA users could not be loaded", n)
+			} else if n > 0 {
+				return fmt.Errorf("%d FOSSA users could not be loaded, rerun with --resume", n)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&opts.FossaResume, "resume", false, "Skip the users loaded by an earlier run before it failed")
+	cmd.Flags().BoolVar(&opts.DryRun, "dry-run", false, "Report the users that would fail without writing anything")
+	return cmd
+}
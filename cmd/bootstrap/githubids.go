@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/google/go-github/v55/github"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"golang.org/x/oauth2"
+
+	"maintainerd/db"
+	"maintainerd/model"
+	"maintainerd/validate"
+)
+
+const gitHubTokenEnvVar = "GITHUB_API_TOKEN" //nolint:gosec
+
+// newGitHubIDsCmd returns the github-ids command, which backfills the GitHub user IDs authorization matches
+// maintainers and staff on.
+func newGitHubIDsCmd(dbPath *string) *cobra.Command {
+	var dryRun bool
+	cmd := &cobra.Command{
+		Use:   "github-ids",
+		Short: "Link maintainers and staff to their numeric GitHub user IDs",
+		Long: "github-ids looks up the GitHub login of every maintainer and staff member not yet linked to a GitHub " +
+			"user ID through the GitHub users API, and records the ID, for maintainers as a verified identity. " +
+			"Onboarding commands are then authorized on the ID, which survives renames, rather than on the login. " +
+			"Requires $" + gitHubTokenEnvVar + ".",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			token := viper.GetString(gitHubTokenEnvVar)
+			if token == "" {
+				return fmt.Errorf("environment variable %s is not set", gitHubTokenEnvVar)
+			}
+			conn, err := openDB(dbPath)
+			if err != nil {
+				return err
+			}
+			client := github.NewClient(oauth2.NewClient(cmd.Context(), oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token})))
+			linked, err := resolveGitHubIDs(cmd.Context(), db.NewSQLStore(conn), client.Users, dryRun, cmd.OutOrStdout())
+			cmd.Printf("linked %d maintainers and staff members to their GitHub user IDs\n", linked)
+			return err
+		},
+	}
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print the IDs that would be recorded without recording them")
+	return cmd
+}
+
+// resolveGitHubIDs links every live maintainer and staff member who has a GitHub login but no GitHub user ID to the ID
+// GitHub reports for the login, and returns how many it linked. Logins GitHub does not know, and IDs already linked
+// to another maintainer, are reported to out and skipped; the run stops at the first other API error, e.g. a rate
+// limit.
+func resolveGitHubIDs(ctx context.Context, store db.Store, users validate.GitHubUsers, dryRun bool, out io.Writer) (int, error) {
+	byLogin, err := store.GetMaintainerMapByGitHubAccount(ctx)
+	if err != nil {
+		return 0, err
+	}
+	seen := map[uint]bool{}
+	var maintainers []model.Maintainer
+	for _, m := range byLogin {
+		if !seen[m.ID] {
+			seen[m.ID] = true
+			maintainers = append(maintainers, m)
+		}
+	}
+	sort.Slice(maintainers, func(i, j int) bool { return maintainers[i].ID < maintainers[j].ID })
+
+	linked := 0
+	for _, m := range maintainers {
+		if _, ok := m.GitHubID(); ok || model.IsMissing(m.GitHubAccount) {
+			continue
+		}
+		ok, err := linkGitHubID(ctx, users, m.GitHubAccount, fmt.Sprintf("maintainer %d", m.ID), dryRun, out, func(id int64) error {
+			_, err := store.AddMaintainerIdentity(ctx, model.MaintainerIdentity{
+				MaintainerID: m.ID, Kind: model.IdentityGitHubID, Value: strconv.FormatInt(id, 10), Verified: true,
+			})
+			return err
+		})
+		if err != nil {
+			return linked, err
+		}
+		if ok {
+			linked++
+		}
+	}
+
+	staff, err := store.ListStaffMembers(ctx)
+	if err != nil {
+		return linked, err
+	}
+	for _, sm := range staff {
+		if sm.GitHubID != nil || model.IsMissing(sm.GitHubAccount) {
+			continue
+		}
+		ok, err := linkGitHubID(ctx, users, sm.GitHubAccount, fmt.Sprintf("staff member %d", sm.ID), dryRun, out, func(id int64) error {
+			return store.SetStaffGitHubID(ctx, sm.ID, id)
+		})
+		if err != nil {
+			return linked, err
+		}
+		if ok {
+			linked++
+		}
+	}
+	return linked, nil
+}
+
+// linkGitHubID looks up login through the GitHub users API and records the user ID with link, reporting the outcome
+// for subject to out. It reports whether the ID was (or, on a dry run, would be) linked. Unknown logins and
+// db.ErrIdentityTaken from link are reported and skipped; any other error is returned.
+func linkGitHubID(ctx context.Context, users validate.GitHubUsers, login, subject string, dryRun bool, out io.Writer, link func(id int64) error) (bool, error) {
+	user, _, err := users.Get(ctx, login)
+	var notFound *github.ErrorResponse
+	if errors.As(err, &notFound) && notFound.Response != nil && notFound.Response.StatusCode == http.StatusNotFound {
+		fmt.Fprintf(out, "@%s (%s): no such GitHub user, the account may have been renamed or deleted\n", login, subject)
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("look up GitHub user @%s: %w", login, err)
+	}
+	if dryRun {
+		fmt.Fprintf(out, "@%s (%s): would link GitHub user ID %d\n", login, subject, user.GetID())
+		return true, nil
+	}
+	if err := link(user.GetID()); errors.Is(err, db.ErrIdentityTaken) {
+		fmt.Fprintf(out, "@%s (%s): %v\n", login, subject, err)
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	fmt.Fprintf(out, "@%s (%s): linked GitHub user ID %d\n", login, subject, user.GetID())
+	return true, nil
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-github/v55/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"maintainerd/db/dbtest"
+	"maintainerd/model"
+)
+
+// fakeUsers answers Get from a map of logins to IDs and 404s other logins.
+type fakeUsers struct {
+	ids   map[string]int64
+	calls []string
+}
+
+func (f *fakeUsers) Get(_ context.Context, login string) (*github.User, *github.Response, error) {
+	f.calls = append(f.calls, login)
+	id, ok := f.ids[login]
+	if !ok {
+		resp := &http.Response{StatusCode: http.StatusNotFound, Request: &http.Request{}}
+		return nil, &github.Response{Response: resp}, &github.ErrorResponse{Response: resp, Message: "Not Found"}
+	}
+	return &github.User{Login: github.String(login), ID: github.Int64(id)}, nil, nil
+}
+
+func TestResolveGitHubIDs(t *testing.T) {
+	store := dbtest.NewMemStore()
+	alice := store.AddMaintainer(model.Maintainer{Name: "Alice", GitHubAccount: "alice", MaintainerStatus: model.ActiveMaintainer})
+	store.AddMaintainer(model.Maintainer{Name: "Gone", GitHubAccount: "gone", MaintainerStatus: model.ActiveMaintainer})
+	store.AddMaintainer(model.Maintainer{Name: "Nobody", GitHubAccount: "GITHUB_MISSING", MaintainerStatus: model.ActiveMaintainer})
+	linkedAlready := store.AddMaintainer(model.Maintainer{Name: "Carol", GitHubAccount: "carol", MaintainerStatus: model.ActiveMaintainer})
+	_, err := store.AddMaintainerIdentity(t.Context(), model.MaintainerIdentity{MaintainerID: linkedAlready.ID, Kind: model.IdentityGitHubID, Value: "3"})
+	require.NoError(t, err)
+	store.AddStaffMember(model.StaffMember{Name: "Staff", GitHubAccount: "staffer"})
+	users := &fakeUsers{ids: map[string]int64{"alice": 101, "carol": 3, "staffer": 202}}
+
+	var out bytes.Buffer
+	linked, err := resolveGitHubIDs(t.Context(), store, users, true, &out)
+	require.NoError(t, err)
+	assert.Equal(t, 2, linked)
+	assert.Contains(t, out.String(), "@alice (maintainer 1): would link GitHub user ID 101")
+	assert.Contains(t, out.String(), "@gone (maintainer 2): no such GitHub user")
+	assert.Contains(t, out.String(), "@staffer (staff member 1): would link GitHub user ID 202")
+	assert.Equal(t, []string{"alice", "gone", "staffer"}, users.calls, "linked and login-less maintainers are not looked up")
+	ids, err := store.ListMaintainerIdentities(t.Context(), alice.ID)
+	require.NoError(t, err)
+	assert.Empty(t, ids, "a dry run records nothing")
+
+	linked, err = resolveGitHubIDs(t.Context(), store, users, false, &out)
+	require.NoError(t, err)
+	assert.Equal(t, 2, linked)
+	byLogin, err := store.GetMaintainerMapByGitHubAccount(t.Context())
+	require.NoError(t, err)
+	id, ok := byLogin["alice"].GitHubID()
+	require.True(t, ok)
+	assert.Equal(t, int64(101), id)
+	staff, err := store.IsStaffGitHubUser(t.Context(), 202, "staffer-renamed")
+	require.NoError(t, err)
+	assert.True(t, staff, "staff are matched on their linked ID after a rename")
+}
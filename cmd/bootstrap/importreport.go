@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/go-github/v55/github"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"golang.org/x/oauth2"
+
+	"maintainerd/db"
+)
+
+// newImportReportCmd returns the import-report command, which prints the stored report of a bootstrap run.
+func newImportReportCmd(dbPath *string) *cobra.Command {
+	var asJSON bool
+	var issueRepo string
+	cmd := &cobra.Command{
+		Use:   "import-report [RUN_ID]",
+		Short: "Print the row-by-row report of a bootstrap import",
+		Long: "import-report prints the report of the bootstrap run RUN_ID, or of the latest run: the outcome of every " +
+			"source row, created, updated, unchanged, skipped or failed, and why. With --issue, the rows to fix are " +
+			"posted as a GitHub issue on OWNER/REPO, which requires $" + gitHubTokenEnvVar + ".",
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			conn, err := openDB(dbPath)
+			if err != nil {
+				return err
+			}
+			var runID string
+			if len(args) == 1 {
+				runID = args[0]
+			}
+			report, err := db.LoadImportReport(cmd.Context(), conn, runID)
+			if err != nil {
+				return err
+			}
+			if issueRepo != "" {
+				return postImportIssue(cmd, issueRepo, report)
+			}
+			if asJSON {
+				enc := json.NewEncoder(cmd.OutOrStdout())
+				enc.SetIndent("", "  ")
+				return enc.Encode(report)
+			}
+			cmd.Print(report.Markdown())
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&asJSON, "json", false, "Print the report as JSON rather than Markdown")
+	cmd.Flags().StringVar(&issueRepo, "issue", "", "Post the rows to fix as an issue on this OWNER/REPO")
+	return cmd
+}
+
+// writeImportReport writes report to dir as import-RUN_ID.json and import-RUN_ID.md, and returns their paths.
+func writeImportReport(dir string, report db.ImportReport) ([]string, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, err
+	}
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	base := filepath.Join(dir, "import-"+report.RunID)
+	paths := []string{base + ".json", base + ".md"}
+	for i, content := range [][]byte{append(data, '\n'), []byte(report.Markdown())} {
+		if err := os.WriteFile(paths[i], content, 0o600); err != nil {
+			return nil, err
+		}
+	}
+	return paths, nil
+}
+
+// issueCreator is the part of the GitHub issues API importIssue needs.
+type issueCreator interface {
+	Create(ctx context.Context, owner, repo string, issue *github.IssueRequest) (*github.Issue, *github.Response, error)
+}
+
+func postImportIssue(cmd *cobra.Command, repo string, report db.ImportReport) error {
+	token := viper.GetString(gitHubTokenEnvVar)
+	if token == "" {
+		return fmt.Errorf("environment variable %s is not set", gitHubTokenEnvVar)
+	}
+	issues := github.NewClient(oauth2.NewClient(cmd.Context(), oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token}))).Issues
+	issue, err := importIssue(cmd.Context(), issues, repo, report)
+	if err != nil {
+		return err
+	}
+	if issue == nil {
+		cmd.Printf("import %s has no rows to fix, no issue posted\n", report.RunID)
+		return nil
+	}
+	cmd.Printf("posted %s\n", issue.GetHTMLURL())
+	return nil
+}
+
+// importIssue opens an issue on repo, OWNER/REPO, listing the rows of report to fix in the source. It posts nothing,
+// and returns nil, if every row was applied.
+func importIssue(ctx context.Context, issues issueCreator, repo string, report db.ImportReport) (*github.Issue, error) {
+	owner, name, ok := strings.Cut(repo, "/")
+	if !ok || owner == "" || name == "" {
+		return nil, fmt.Errorf("repository %q is not OWNER/REPO", repo)
+	}
+	problems := report.Problems()
+	if len(problems) == 0 {
+		return nil, nil
+	}
+	issue, _, err := issues.Create(ctx, owner, name, &github.IssueRequest{
+		Title: github.String(fmt.Sprintf("Maintainer import %s: %d rows to fix", report.RunID, len(problems))),
+		Body:  github.String(report.Markdown()),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("post import report %s to %s: %w", report.RunID, repo, err)
+	}
+	return issue, nil
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/google/go-github/v55/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"maintainerd/db"
+)
+
+// fakeIssues records the issues it is asked to create.
+type fakeIssues struct {
+	created []*github.IssueRequest
+	repo    string
+}
+
+func (f *fakeIssues) Create(_ context.Context, owner, repo string, issue *github.IssueRequest) (*github.Issue, *github.Response, error) {
+	f.created = append(f.created, issue)
+	f.repo = owner + "/" + repo
+	return &github.Issue{Number: github.Int(7)}, nil, nil
+}
+
+func TestImportIssue(t *testing.T) {
+	report := db.ImportReport{RunID: "20261018T120000Z-0a0b0c0d", Source: "CSV export testdata", Rows: []db.ImportRow{
+		{RowRef: db.RowRef{Origin: "Active", Row: 2}, Subject: "project argo", Outcome: db.ImportCreated},
+	}}
+	issues := &fakeIssues{}
+
+	issue, err := importIssue(t.Context(), issues, "cncf/sheet-fixes", report)
+	require.NoError(t, err)
+	assert.Nil(t, issue, "nothing to fix, nothing posted")
+	assert.Empty(t, issues.created)
+
+	report.Rows = append(report.Rows, db.ImportRow{RowRef: db.RowRef{Origin: "Active", Row: 3}, Subject: "Carol on project argo",
+		Outcome: db.ImportSkipped, Reason: "no email, GitHub login or GitHub email to match a maintainer by"})
+	issue, err = importIssue(t.Context(), issues, "cncf/sheet-fixes", report)
+	require.NoError(t, err)
+	assert.Equal(t, 7, issue.GetNumber())
+	require.Len(t, issues.created, 1)
+	assert.Equal(t, "cncf/sheet-fixes", issues.repo)
+	assert.Equal(t, "Maintainer import 20261018T120000Z-0a0b0c0d: 1 rows to fix", issues.created[0].GetTitle())
+	assert.Contains(t, issues.created[0].GetBody(), "Carol on project argo")
+
+	_, err = importIssue(t.Context(), issues, "sheet-fixes", report)
+	assert.Error(t, err)
+}
+
+func TestWriteImportReport(t *testing.T) {
+	dir := t.TempDir()
+	paths, err := writeImportReport(dir, db.ImportReport{RunID: "run"})
+	require.NoError(t, err)
+	require.Len(t, paths, 2)
+	for _, path := range paths {
+		_, err := os.Stat(path)
+		assert.NoError(t, err)
+	}
+}
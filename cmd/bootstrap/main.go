@@ -4,11 +4,6 @@ import (
 	"fmt"
 	"log"
 	"maintainerd/db"
-	"os"
-	"path/filepath"
-	"sort"
-	"strings"
-	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -20,60 +15,34 @@ const (
 	googleWorkspaceCredentials = "WORKSPACE_CREDENTIALS_FILE" //nolint:gosec
 	defaultDBPath              = "maintainers.db"
 	defaultMaxBackups          = 5
-	backupFileExt              = ".bak"
 )
 
 func main() {
 	var dbPath string
 	var seed bool
-	var doBackup bool
-	var maxBackups int
+	var flags allFlags
 
 	rootCmd := &cobra.Command{
 		Use:   "bootstrap",
 		Short: "Bootstrap the database schema and optionally seed it",
-		Run: func(cmd *cobra.Command, args []string) {
-			spreadsheetID := viper.GetString(spreadsheetEnvVar)
-			if spreadsheetID == "" {
-				log.Fatalf("ERROR: environment variable %s is not set", spreadsheetEnvVar)
-			}
-
-			fossaToken := viper.GetString(apiTokenEnvVar)
-			if fossaToken == "" {
-				log.Fatalf("ERROR: environment variable %s is not set", apiTokenEnvVar)
-			}
-
-			credentialsPath := viper.GetString(googleWorkspaceCredentials)
-			if credentialsPath == "" {
-				log.Fatalf("ERROR: environment variable %s is not set", googleWorkspaceCredentials)
-			}
-			if doBackup {
-				if _, err := os.Stat(dbPath); err == nil {
-					info, err := os.Stat(dbPath)
-					if err == nil {
-						log.Printf("existing database file size: %d bytes", info.Size())
-					}
-					backupPath := fmt.Sprintf("%s.%s%s", dbPath, time.Now().Format("20060102-150405"), backupFileExt)
-					err = copyFile(dbPath, backupPath)
-					if err != nil {
-						log.Fatalf("failed to create DB backup: %v", err)
-					}
-					log.Printf("existing database backed up to %s", backupPath)
-					pruneOldBackups(dbPath, maxBackups)
-				}
-			}
-			_, err := db.BootstrapSQLite(dbPath, spreadsheetID, credentialsPath, fossaToken, seed)
-			if err != nil {
-				log.Fatalf("bootstrap failed: %v", err)
-			}
-
+		Long: "bootstrap sets up and loads the maintainer-d database in stages, each a subcommand that needs only the " +
+			"credentials of its stage: schema, sheet, staff and fossa, or all of them with all. Without a subcommand " +
+			"bootstrap runs all, or with --seed=false only backs up the database and runs schema.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runAll(cmd, &dbPath, flags, seed)
 		},
 	}
 
-	rootCmd.Flags().StringVar(&dbPath, "db", defaultDBPath, "Path to SQLite database file")
+	rootCmd.PersistentFlags().StringVar(&dbPath, "db", defaultDBPath, "SQLite database file or PostgreSQL DSN (overridden by $"+db.DSNEnvVar+")")
 	rootCmd.Flags().BoolVar(&seed, "seed", true, "Whether to load seed data into the database")
-	rootCmd.Flags().BoolVar(&doBackup, "backup", true, "Whether to create a backup of the database if it exists")
-	rootCmd.Flags().IntVar(&maxBackups, "max-backups", defaultMaxBackups, "Maximum number of backups to retain")
+	flags.register(rootCmd)
+
+	rootCmd.AddCommand(newSchemaCmd(&dbPath), newSheetCmd(&dbPath), newStaffCmd(&dbPath), newFossaCmd(&dbPath),
+		newAllCmd(&dbPath), newBackupCmd(&dbPath), newRestoreCmd(&dbPath),
+		newMigrateCmd(&dbPath), newArchiveCmd(&dbPath), newDuplicatesCmd(&dbPath), newMergeCmd(&dbPath),
+		newGitHubIDsCmd(&dbPath), newValidateCmd(&dbPath), newImportReportCmd(&dbPath), newExportCmd(&dbPath),
+		newCompaniesCmd(&dbPath), newRolePolicyCmd(&dbPath))
 
 	viper.AutomaticEnv() // binds environment variables to viper config
 
@@ -81,69 +50,49 @@ func main() {
 		log.Fatalf("command failed: %v", err)
 	}
 }
-func copyFile(src, dst string) error {
-	sourceFileStat, err := os.Stat(src)
-	if err != nil {
-		return err
-	}
-	if !sourceFileStat.Mode().IsRegular() {
-		return fmt.Errorf("%s is not a regular file", src)
-	}
 
-	source, err := os.Open(src)
-	if err != nil {
-		return err
-	}
-	defer func(source *os.File) {
-		err := source.Close()
-		if err != nil {
-			log.Printf("warning: failed to close file %s: %v", src, err)
-		}
-	}(source)
+// The values of the --source flag.
+const (
+	sourceSheets = "sheets"
+	sourceCSV    = "csv"
+	sourceXLSX   = "xlsx"
+	sourceYAML   = "yaml"
+)
 
-	destination, err := os.Create(dst)
-	if err != nil {
-		return err
+// newSource returns the db.Source --source and --source-path select, reading sheets with the layout at layoutPath
+// if set. Only the Google Sheet needs credentials.
+func newSource(kind, path, layoutPath string) (db.Source, error) {
+	if kind != sourceSheets && path == "" {
+		return nil, fmt.Errorf("--source %s needs --source-path", kind)
 	}
-	defer func(destination *os.File) {
-		err := destination.Close()
+	var layout *db.SheetLayout
+	if layoutPath != "" {
+		if kind == sourceYAML {
+			return nil, fmt.Errorf("--sheet-layout does not apply to --source %s", kind)
+		}
+		l, err := db.LoadSheetLayout(layoutPath)
 		if err != nil {
-			log.Printf("warning: failed to close file %s: %v", dst, err)
+			return nil, fmt.Errorf("sheet layout: %w", err)
 		}
-	}(destination)
-
-	_, err = destination.ReadFrom(source)
-	return err
-}
-func pruneOldBackups(dbPath string, max int) {
-	dir := filepath.Dir(dbPath)
-	base := filepath.Base(dbPath)
-	prefix := base + "."
-	files, err := os.ReadDir(dir)
-	if err != nil {
-		log.Printf("warning: failed to read backup directory: %v", err)
-		return
+		layout = &l
 	}
-
-	var backups []string
-	for _, f := range files {
-		if strings.HasPrefix(f.Name(), prefix) && strings.HasSuffix(f.Name(), backupFileExt) {
-			backups = append(backups, filepath.Join(dir, f.Name()))
+	switch kind {
+	case sourceSheets:
+		spreadsheetID := viper.GetString(spreadsheetEnvVar)
+		if spreadsheetID == "" {
+			return nil, fmt.Errorf("environment variable %s is not set", spreadsheetEnvVar)
 		}
-	}
-
-	if len(backups) <= max {
-		return
-	}
-
-	sort.Strings(backups)
-	toRemove := backups[:len(backups)-max]
-	for _, file := range toRemove {
-		err := os.Remove(file)
-		if err != nil {
-			log.Printf("warning: failed to remove old backup %s: %v", file, err)
-		} else {
-			log.Printf("removed old backup: %s", file)
+		credentialsPath := viper.GetString(googleWorkspaceCredentials)
+		if credentialsPath == "" {
+			return nil, fmt.Errorf("environment variable %s is not set", googleWorkspaceCredentials)
 		}
+		return db.SheetsSource{SpreadsheetID: spreadsheetID, CredentialsPath: credentialsPath, Layout: layout}, nil
+	case sourceCSV:
+		return db.CSVSource{Dir: path, Layout: layout}, nil
+	case sourceXLSX:
+		return db.XLSXSource{Path: path, Layout: layout}, nil
+	case sourceYAML:
+		return db.YAMLSource{Dir: path}, nil
 	}
+	return nil, fmt.Errorf("unknown --source %q, want sheets, csv, xlsx or yaml", kind)
 }
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"fmt"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"gorm.io/gorm"
+
+	"maintainerd/db"
+)
+
+// openDB opens the database at *dbPath, or the one named by $MD_DB_DSN.
+func openDB(dbPath *string) (*gorm.DB, error) {
+	return db.Open(resolveDSN(dbPath), nil)
+}
+
+// resolveDSN returns $MD_DB_DSN if it is set, or else *dbPath.
+func resolveDSN(dbPath *string) string {
+	if v := viper.GetString(db.DSNEnvVar); v != "" {
+		return v
+	}
+	return *dbPath
+}
+
+// newMigrateCmd returns the migrate command, which moves the schema of the database at *dbPath between versions.
+func newMigrateCmd(dbPath *string) *cobra.Command {
+	open := func() (*gorm.DB, error) { return openDB(dbPath) }
+
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Apply, revert and inspect versioned schema migrations",
+	}
+
+	var upTo int
+	up := &cobra.Command{
+		Use:   "up",
+		Short: "Apply pending migrations",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			conn, err := open()
+			if err != nil {
+				return err
+			}
+			target := upTo
+			if target == 0 {
+				target = db.LatestVersion()
+			}
+			current, err := db.SchemaVersion(conn)
+			if err != nil {
+				return err
+			}
+			if target < current {
+				return fmt.Errorf("database is at version %d, use 'migrate down --to %d' to go back", current, target)
+			}
+			ran, err := db.MigrateTo(conn, target)
+			printMigrations(cmd, "applied", ran)
+			return err
+		},
+	}
+	up.Flags().IntVar(&upTo, "to", 0, "Version to migrate up to (default: latest)")
+
+	var steps, downTo int
+	down := &cobra.Command{
+		Use:   "down",
+		Short: "Revert applied migrations",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			conn, err := open()
+			if err != nil {
+				return err
+			}
+			var ran []db.Migration
+			if cmd.Flags().Changed("to") {
+				current, err := db.SchemaVersion(conn)
+				if err != nil {
+					return err
+				}
+				if downTo > current {
+					return fmt.Errorf("database is at version %d, use 'migrate up --to %d' to go forward", current, downTo)
+				}
+				ran, err = db.MigrateTo(conn, downTo)
+			} else {
+				ran, err = db.Rollback(conn, steps)
+			}
+			printMigrations(cmd, "reverted", ran)
+			return err
+		},
+	}
+	down.Flags().IntVar(&steps, "steps", 1, "Number of migrations to revert")
+	down.Flags().IntVar(&downTo, "to", 0, "Version to migrate down to, 0 reverts everything (overrides --steps)")
+
+	status := &cobra.Command{
+		Use:   "status",
+		Short: "Show which migrations have been applied",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			conn, err := open()
+			if err != nil {
+				return err
+			}
+			states, err := db.MigrationStatus(conn)
+			if err != nil {
+				return err
+			}
+			current, err := db.SchemaVersion(conn)
+			if err != nil {
+				return err
+			}
+			tw := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 4, 2, ' ', 0)
+			fmt.Fprintln(tw, "VERSION\tNAME\tAPPLIED AT")
+			for _, s := range states {
+				appliedAt := "pending"
+				if s.AppliedAt != nil {
+					appliedAt = s.AppliedAt.Format(time.RFC3339)
+				}
+				fmt.Fprintf(tw, "%d\t%s\t%s\n", s.Version, s.Name, appliedAt)
+			}
+			if err := tw.Flush(); err != nil {
+				return err
+			}
+			cmd.Printf("\ndatabase is at version %d, this build expects %d\n", current, db.LatestVersion())
+			return nil
+		},
+	}
+
+	cmd.AddCommand(up, down, status)
+	return cmd
+}
+
+func printMigrations(cmd *cobra.Command, verb string, ran []db.Migration) {
+	if len(ran) == 0 {
+		cmd.Println("nothing to do")
+		return
+	}
+	for _, m := range ran {
+		cmd.Printf("%s migration %d (%s)\n", verb, m.Version, m.Name)
+	}
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"maintainerd/db"
+	"maintainerd/model"
+	"maintainerd/plugins/fossa"
+)
+
+// newRolePolicyCmd returns the role-policy command, which shows and overrides the roles a project's people hold on its
+// FOSSA team.
+func newRolePolicyCmd(dbPath *string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "role-policy",
+		Short: "Show or override the FOSSA team roles of a project's maintainers, collaborators and staff",
+		Long: "By default maintainers and foundation staff are Team Admins on their project's FOSSA team and " +
+			"collaborators Team Editors. A project can override any of the three; the onboarding server applies the " +
+			"policy when it next reconciles the team.",
+	}
+	cmd.AddCommand(newRolePolicyShowCmd(dbPath), newRolePolicySetCmd(dbPath), newRolePolicyResetCmd(dbPath))
+	return cmd
+}
+
+func newRolePolicyShowCmd(dbPath *string) *cobra.Command {
+	return &cobra.Command{
+		Use:   "show PROJECT",
+		Short: "Print the FOSSA team roles the project's people hold",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			store, project, err := rolePolicyProject(cmd, dbPath, args[0])
+			if err != nil {
+				return err
+			}
+			override, err := store.GetServiceTeamRolePolicy(cmd.Context(), project.ID, "FOSSA")
+			if err != nil {
+				return err
+			}
+			if override == nil {
+				override = &model.ServiceTeamRolePolicy{}
+			}
+			tw := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 4, 2, ' ', 0)
+			fmt.Fprintln(tw, "PERSON\tROLE\tSOURCE")
+			for _, r := range []struct {
+				person   string
+				override int
+				fallback fossa.TeamRole
+			}{
+				{"maintainer", override.MaintainerRole, fossa.DefaultTeamRolePolicy.Maintainer},
+				{"collaborator", override.CollaboratorRole, fossa.DefaultTeamRolePolicy.Collaborator},
+				{"staff", override.StaffRole, fossa.DefaultTeamRolePolicy.Staff},
+			} {
+				if r.override == 0 {
+					fmt.Fprintf(tw, "%s\t%s\tdefault\n", r.person, r.fallback)
+				} else {
+					fmt.Fprintf(tw, "%s\t%s\t%s\n", r.person, fossa.TeamRole(r.override), project.Name)
+				}
+			}
+			return tw.Flush()
+		},
+	}
+}
+
+func newRolePolicySetCmd(dbPath *string) *cobra.Command {
+	var maintainer, collaborator, staff string
+	cmd := &cobra.Command{
+		Use:   "set PROJECT [--maintainer ROLE] [--collaborator ROLE] [--staff ROLE]",
+		Short: "Override the FOSSA team role of the project's maintainers, collaborators or staff",
+		Long: "set overrides the role of each kind of person given a flag, keeping the project's other overrides. A " +
+			"ROLE is admin, editor or viewer; default drops the override.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			store, project, err := rolePolicyProject(cmd, dbPath, args[0])
+			if err != nil {
+				return err
+			}
+			policy := model.ServiceTeamRolePolicy{ProjectID: project.ID}
+			if current, err := store.GetServiceTeamRolePolicy(cmd.Context(), project.ID, "FOSSA"); err != nil {
+				return err
+			} else if current != nil {
+				policy.MaintainerRole, policy.CollaboratorRole, policy.StaffRole =
+					current.MaintainerRole, current.CollaboratorRole, current.StaffRole
+			}
+			for _, f := range []struct {
+				flag  string
+				value string
+				role  *int
+			}{
+				{"maintainer", maintainer, &policy.MaintainerRole},
+				{"collaborator", collaborator, &policy.CollaboratorRole},
+				{"staff", staff, &policy.StaffRole},
+			} {
+				if !cmd.Flags().Changed(f.flag) {
+					continue
+				}
+				role, err := fossa.ParseTeamRole(f.value)
+				if err != nil {
+					return fmt.Errorf("--%s: %w", f.flag, err)
+				}
+				*f.role = int(role)
+			}
+			if err := store.SetServiceTeamRolePolicy(cmd.Context(), "FOSSA", policy); err != nil {
+				return err
+			}
+			cmd.Printf("FOSSA role policy of %s updated\n", project.Name)
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&maintainer, "maintainer", "", "Team role of the project's maintainers")
+	cmd.Flags().StringVar(&collaborator, "collaborator", "", "Team role of the project's collaborators")
+	cmd.Flags().StringVar(&staff, "staff", "", "Team role of foundation staff")
+	return cmd
+}
+
+func newRolePolicyResetCmd(dbPath *string) *cobra.Command {
+	return &cobra.Command{
+		Use:   "reset PROJECT",
+		Short: "Drop the project's overrides, so its FOSSA team follows the default policy",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			store, project, err := rolePolicyProject(cmd, dbPath, args[0])
+			if err != nil {
+				return err
+			}
+			if err := store.SetServiceTeamRolePolicy(cmd.Context(), "FOSSA", model.ServiceTeamRolePolicy{ProjectID: project.ID}); err != nil {
+				return err
+			}
+			cmd.Printf("FOSSA role policy of %s reset to the default\n", project.Name)
+			return nil
+		},
+	}
+}
+
+// rolePolicyProject opens the database and returns its store and the live project called name.
+func rolePolicyProject(cmd *cobra.Command, dbPath *string, name string) (db.Store, model.Project, error) {
+	conn, err := openDB(dbPath)
+	if err != nil {
+		return nil, model.Project{}, err
+	}
+	store := db.NewSQLStore(conn)
+	projects, err := store.GetProjectMapByName(cmd.Context())
+	if err != nil {
+		return nil, model.Project{}, err
+	}
+	project, ok := projects[name]
+	if !ok {
+		return nil, model.Project{}, fmt.Errorf("%w: %q", db.ErrProjectNotFound, name)
+	}
+	return store, project, nil
+}
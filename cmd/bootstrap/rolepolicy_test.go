@@ -0,0 +1,49 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"maintainerd/db"
+)
+
+func TestRolePolicyCommands(t *testing.T) {
+	dir := t.TempDir()
+	dbPath := filepath.Join(dir, "maintainers.db")
+	sheet := db.ProjectHdr + "," + db.StatusHdr + "," + db.MaintainerNameHdr + "," + db.EmailHdr + "," + db.GitHubHdr + "\n" +
+		"argo,Graduated,Alice,alice@example.com,alice\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "Active.csv"), []byte(sheet), 0o600))
+	_, err := execute(t, newSchemaCmd(&dbPath))
+	require.NoError(t, err)
+	_, err = execute(t, newSheetCmd(&dbPath), "--source", "csv", "--source-path", dir)
+	require.NoError(t, err)
+
+	out, err := execute(t, newRolePolicyCmd(&dbPath), "show", "argo")
+	require.NoError(t, err)
+	assert.Regexp(t, `collaborator\s+Team Editor\s+default\n`, out)
+
+	_, err = execute(t, newRolePolicyCmd(&dbPath), "set", "argo", "--collaborator", "viewer")
+	require.NoError(t, err)
+	_, err = execute(t, newRolePolicyCmd(&dbPath), "set", "argo", "--staff", "editor")
+	require.NoError(t, err)
+	out, err = execute(t, newRolePolicyCmd(&dbPath), "show", "argo")
+	require.NoError(t, err)
+	assert.Regexp(t, `maintainer\s+Team Admin\s+default\n`, out)
+	assert.Regexp(t, `collaborator\s+Team Viewer\s+argo\n`, out, "set keeps the overrides it is not given")
+	assert.Regexp(t, `staff\s+Team Editor\s+argo\n`, out)
+
+	_, err = execute(t, newRolePolicyCmd(&dbPath), "set", "argo", "--maintainer", "owner")
+	assert.ErrorContains(t, err, "--maintainer")
+	_, err = execute(t, newRolePolicyCmd(&dbPath), "show", "flux")
+	assert.ErrorIs(t, err, db.ErrProjectNotFound)
+
+	_, err = execute(t, newRolePolicyCmd(&dbPath), "reset", "argo")
+	require.NoError(t, err)
+	out, err = execute(t, newRolePolicyCmd(&dbPath), "show", "argo")
+	require.NoError(t, err)
+	assert.NotContains(t, out, "argo\n")
+}
@@ -0,0 +1,222 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"maintainerd/db"
+	"maintainerd/plugins/fossa"
+)
+
+// sourceFlags are the flags of the commands that read a db.Source and report on the rows they read.
+type sourceFlags struct {
+	kind, path, layoutPath string
+	reportDir, reportIssue string
+}
+
+func (f *sourceFlags) register(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&f.kind, "source", sourceSheets, "Where to read maintainers, projects and staff from: sheets, csv, xlsx or yaml")
+	cmd.Flags().StringVar(&f.path, "source-path", "", "The CSV directory, XLSX file or maintainer.yaml directory to read with --source csv, xlsx or yaml")
+	cmd.Flags().StringVar(&f.layoutPath, "sheet-layout", "", "YAML file mapping sheet columns to fields, overriding the default layout (sheets, csv and xlsx sources)")
+	cmd.Flags().StringVar(&f.reportDir, "report-dir", "", "Write the import report, as JSON and Markdown, to this directory")
+	cmd.Flags().StringVar(&f.reportIssue, "report-issue", "", "Post the rows the import skipped or failed as an issue on this OWNER/REPO (requires $"+gitHubTokenEnvVar+")")
+}
+
+func (f *sourceFlags) source() (db.Source, error) {
+	return newSource(f.kind, f.path, f.layoutPath)
+}
+
+// fossaClient returns a client for the FOSSA organization of $FOSSA_API_TOKEN.
+func fossaClient() (*fossa.Client, error) {
+	token := viper.GetString(apiTokenEnvVar)
+	if token == "" {
+		return nil, fmt.Errorf("environment variable %s is not set", apiTokenEnvVar)
+	}
+	return fossa.NewClient(token), nil
+}
+
+// newSchemaCmd returns the schema command, which runs the schema stage of bootstrap.
+func newSchemaCmd(dbPath *string) *cobra.Command {
+	var opts db.StageOptions
+	cmd := &cobra.Command{
+		Use:   "schema",
+		Short: "Migrate the schema and add the known services",
+		Long: "schema applies every pending migration, like 'migrate up', and adds the services maintainer-d knows " +
+			"about. It needs no credentials.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.Stages = []db.Stage{db.StageSchema}
+			_, err := runStages(cmd, dbPath, opts, sourceFlags{})
+			return err
+		},
+	}
+	cmd.Flags().BoolVar(&opts.DryRun, "dry-run", false, "List the pending migrations without applying them")
+	return cmd
+}
+
+// newSourceStageCmd returns the command of the sheet or staff stage, which read a db.Source.
+func newSourceStageCmd(dbPath *string, stage db.Stage, short, long string) *cobra.Command {
+	var opts db.StageOptions
+	var flags sourceFlags
+	cmd := &cobra.Command{
+		Use:   string(stage),
+		Short: short,
+		Long: long + " The database must be migrated, see schema. Only the Google Sheet source needs credentials, " +
+			"$" + spreadsheetEnvVar + " and $" + googleWorkspaceCredentials + ".",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			source, err := flags.source()
+			if err != nil {
+				return err
+			}
+			opts.Stages, opts.Source = []db.Stage{stage}, source
+			_, err = runStages(cmd, dbPath, opts, flags)
+			return err
+		},
+	}
+	flags.register(cmd)
+	cmd.Flags().BoolVar(&opts.DryRun, "dry-run", false, "Print the changes and row outcomes without writing them")
+	return cmd
+}
+
+func newSheetCmd(dbPath *string) *cobra.Command {
+	return newSourceStageCmd(dbPath, db.StageSheet, "Sync projects and maintainers from the source",
+		"sheet brings the projects, maintainers and memberships of the database in line with the source, see "+
+			"'Sheet sync', and reports the outcome of each row.")
+}
+
+func newStaffCmd(dbPath *string) *cobra.Command {
+	return newSourceStageCmd(dbPath, db.StageStaff, "Load the staff members of the source",
+		"staff creates and updates the foundation staff members of the source, matched by email, and reports the "+
+			"outcome of each row.")
+}
+
+// allFlags are the flags of the all command, which the root command shares.
+type allFlags struct {
+	sourceFlags
+	backup       bool
+	backupTarget string
+	maxBackups   int
+	dryRun       bool
+}
+
+func (f *allFlags) register(cmd *cobra.Command) {
+	f.sourceFlags.register(cmd)
+	cmd.Flags().BoolVar(&f.backup, "backup", true, "Whether to create a backup of the database if it exists")
+	cmd.Flags().StringVar(&f.backupTarget, "backup-target", "", backupTargetUsage)
+	cmd.Flags().IntVar(&f.maxBackups, "max-backups", defaultMaxBackups, "Maximum number of backups to retain")
+	cmd.Flags().BoolVar(&f.dryRun, "dry-run", false, "Print what every stage would change without writing anything")
+}
+
+// newAllCmd returns the all command, which backs up the database and runs every stage of bootstrap.
+func newAllCmd(dbPath *string) *cobra.Command {
+	var flags allFlags
+	cmd := &cobra.Command{
+		Use:   "all",
+		Short: "Back up the database and run the schema, sheet, staff and fossa stages",
+		Long: "all backs up the SQLite database, then runs schema, sheet, staff and fossa in turn. The fossa stage is " +
+			"left out when $" + apiTokenEnvVar + " is not set, and a FOSSA import that fails is logged without failing " +
+			"the command; rerun it with 'fossa --resume'. With --dry-run nothing is backed up or written, and the data " +
+			"stages only run against a database that is already migrated.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runAll(cmd, dbPath, flags, true)
+		},
+	}
+	flags.register(cmd)
+	return cmd
+}
+
+// runAll runs the all command, or without seed only its backup and schema stage.
+func runAll(cmd *cobra.Command, dbPath *string, flags allFlags, seed bool) error {
+	opts := db.StageOptions{Stages: []db.Stage{db.StageSchema}, FossaBestEffort: true, DryRun: flags.dryRun}
+	if seed {
+		source, err := flags.source()
+		if err != nil {
+			return err
+		}
+		opts.Stages, opts.Source = append(opts.Stages, db.StageSheet, db.StageStaff), source
+		if client, err := fossaClient(); err != nil {
+			log.Printf("WARN: %v, FOSSA data will not be loaded", err)
+		} else {
+			opts.Stages, opts.Fossa = append(opts.Stages, db.StageFossa), client
+		}
+	}
+
+	if flags.backup && !flags.dryRun {
+		if err := backupBeforeLoad(cmd, resolveDSN(dbPath), flags.backupTarget, flags.maxBackups); err != nil {
+			return err
+		}
+	}
+	_, err := runStages(cmd, dbPath, opts, flags.sourceFlags)
+	return err
+}
+
+// runStages runs the stages of opts against the database at *dbPath and prints what they did, or with a dry run
+// would do. The import report of the sheet and staff stages is then written to flags.reportDir and, unless this is a
+// dry run, posted to flags.reportIssue.
+func runStages(cmd *cobra.Command, dbPath *string, opts db.StageOptions, flags sourceFlags) (db.StageRun, error) {
+	conn, err := db.OpenSilent(resolveDSN(dbPath))
+	if err != nil {
+		return db.StageRun{}, err
+	}
+	run, err := db.RunStages(cmd.Context(), conn, opts)
+	printStageRun(cmd, run, opts)
+	if err != nil || run.Import == nil {
+		return run, err
+	}
+	if flags.reportDir != "" {
+		paths, err := writeImportReport(flags.reportDir, *run.Import)
+		if err != nil {
+			return run, fmt.Errorf("failed to write the import report: %w", err)
+		}
+		log.Printf("import report written to %s", strings.Join(paths, " and "))
+	}
+	if flags.reportIssue != "" && !opts.DryRun {
+		if err := postImportIssue(cmd, flags.reportIssue, *run.Import); err != nil {
+			return run, fmt.Errorf("failed to post the import report: %w", err)
+		}
+	}
+	return run, nil
+}
+
+// printStageRun prints the migrations, sheet changes, row outcomes and FOSSA errors of run.
+func printStageRun(cmd *cobra.Command, run db.StageRun, opts db.StageOptions) {
+	if opts.DryRun {
+		cmd.Println("dry run, nothing is written")
+	}
+	if slices.Contains(opts.Stages, db.StageSchema) {
+		verb := "applied"
+		if opts.DryRun {
+			verb = "would apply"
+		}
+		printMigrations(cmd, verb, run.Migrations)
+	}
+	if run.Sheet != nil {
+		for _, c := range run.Sheet.Changes {
+			cmd.Printf("%s\n", c)
+		}
+	}
+	if run.Import != nil {
+		w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 4, 2, ' ', 0)
+		for _, row := range run.Import.Rows {
+			if row.Outcome != db.ImportUnchanged {
+				_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", row.RowRef, row.Subject, row.Outcome, row.Reason)
+			}
+		}
+		_ = w.Flush()
+		cmd.Printf("import %s: %s\n", run.Import.RunID, run.Import.Summary())
+	}
+	if run.Fossa != nil {
+		for _, e := range run.Fossa.Errors {
+			cmd.Printf("%s\n", e)
+		}
+		cmd.Printf("%d FOSSA users: %d loaded, %d resumed, %d failed\n", run.Fossa.Users, run.Fossa.Loaded, run.Fossa.Resumed, len(run.Fossa.Errors))
+	}
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"maintainerd/backup"
+	"maintainerd/db"
+	"maintainerd/model"
+)
+
+// execute runs cmd with args and returns what it printed.
+func execute(t *testing.T, cmd *cobra.Command, args ...string) (string, error) {
+	t.Helper()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetArgs(args)
+	err := cmd.ExecuteContext(t.Context())
+	return out.String(), err
+}
+
+// writeSheet writes a CSV export of the sheet with one project and maintainer to dir.
+func writeSheet(t *testing.T, dir string) {
+	t.Helper()
+	sheet := db.ProjectHdr + "," + db.StatusHdr + "," + db.MaintainerNameHdr + "," + db.EmailHdr + "," + db.GitHubHdr + "\n" +
+		"argo,Graduated,Alice,alice@example.com,alice\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "Active.csv"), []byte(sheet), 0o600))
+}
+
+func countProjects(t *testing.T, dbPath string) int64 {
+	t.Helper()
+	conn, err := db.Open(dbPath, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		if sqlDB, err := conn.DB(); err == nil {
+			_ = sqlDB.Close()
+		}
+	})
+	var n int64
+	require.NoError(t, conn.Model(&model.Project{}).Count(&n).Error)
+	return n
+}
+
+func TestStageCommandsWithoutCredentials(t *testing.T) {
+	dir := t.TempDir()
+	dbPath := filepath.Join(dir, "maintainers.db")
+	writeSheet(t, dir)
+
+	_, err := execute(t, newSheetCmd(&dbPath), "--source", "csv", "--source-path", dir)
+	assert.ErrorIs(t, err, db.ErrSchemaVersionMismatch, "sheet does not migrate the schema")
+
+	out, err := execute(t, newSchemaCmd(&dbPath))
+	require.NoError(t, err)
+	assert.Contains(t, out, "applied migration 1 ")
+
+	out, err = execute(t, newSheetCmd(&dbPath), "--source", "csv", "--source-path", dir, "--dry-run")
+	require.NoError(t, err)
+	assert.Contains(t, out, "dry run, nothing is written")
+	assert.Contains(t, out, "create project argo")
+	assert.Contains(t, out, "2 created, 0 updated")
+	assert.NotContains(t, out, "alice@example.com")
+	assert.Zero(t, countProjects(t, dbPath))
+
+	_, err = execute(t, newSheetCmd(&dbPath), "--source", "csv", "--source-path", dir)
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, countProjects(t, dbPath))
+
+	_, err = execute(t, newSheetCmd(&dbPath), "--dry-run")
+	assert.ErrorContains(t, err, spreadsheetEnvVar, "only the Google Sheet source needs credentials")
+}
+
+func TestBackupAndRestore(t *testing.T) {
+	dir := t.TempDir()
+	dbPath := filepath.Join(dir, "maintainers.db")
+	writeSheet(t, dir)
+	target := filepath.Join(dir, "backups")
+
+	_, err := execute(t, newBackupCmd(&dbPath), "--target", target)
+	assert.ErrorIs(t, err, backup.ErrNoDatabase)
+	_, err = execute(t, newSchemaCmd(&dbPath))
+	require.NoError(t, err)
+	out, err := execute(t, newBackupCmd(&dbPath), "--target", target)
+	require.NoError(t, err)
+	assert.Contains(t, out, "sha256")
+	out, err = execute(t, newBackupCmd(&dbPath), "list", "--target", target)
+	require.NoError(t, err)
+	assert.Regexp(t, `^maintainers\.db\.\d{8}-\d{6}\.bak\n$`, out)
+
+	_, err = execute(t, newSheetCmd(&dbPath), "--source", "csv", "--source-path", dir)
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, countProjects(t, dbPath))
+
+	out, err = execute(t, newRestoreCmd(&dbPath), "backup", "--target", target, "--dry-run")
+	require.NoError(t, err)
+	assert.Contains(t, out, "is intact")
+	assert.EqualValues(t, 1, countProjects(t, dbPath))
+
+	_, err = execute(t, newRestoreCmd(&dbPath), "backup", "--target", target)
+	require.NoError(t, err)
+	assert.Zero(t, countProjects(t, dbPath))
+	assert.EqualValues(t, 1, countProjects(t, dbPath+backup.PreRestoreExt), "the replaced database is kept")
+
+	_, err = execute(t, newBackupCmd(&dbPath), "--target", "s3://bucket/prefix")
+	assert.ErrorContains(t, err, s3AccessKeyEnvVar)
+}
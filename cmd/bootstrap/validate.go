@@ -0,0 +1,85 @@
+package main
+
+import (
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/google/go-github/v55/github"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"golang.org/x/oauth2"
+
+	"maintainerd/db"
+	"maintainerd/model"
+	"maintainerd/validate"
+)
+
+// newValidateCmd returns the validate command, which checks the registry for data quality problems and records the
+// findings.
+func newValidateCmd(dbPath *string) *cobra.Command {
+	var dryRun, checkGitHub bool
+	cmd := &cobra.Command{
+		Use:   "validate",
+		Short: "Check the registry for data quality problems",
+		Long: "validate checks every live maintainer and project: missing or invalid emails, missing or unknown GitHub " +
+			"logins, projects without maintainers, invalid maturities and parent projects that do not exist. The " +
+			"findings replace those of the previous run, are served at /findings and are copied into each " +
+			"maintainer's import warnings. With --check-github, logins not yet linked to a GitHub user ID are looked " +
+			"up through the GitHub users API, which requires $" + gitHubTokenEnvVar + ".",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var users validate.GitHubUsers
+			if checkGitHub {
+				token := viper.GetString(gitHubTokenEnvVar)
+				if token == "" {
+					return fmt.Errorf("environment variable %s is not set", gitHubTokenEnvVar)
+				}
+				users = github.NewClient(oauth2.NewClient(cmd.Context(), oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token}))).Users
+			}
+			conn, err := openDB(dbPath)
+			if err != nil {
+				return err
+			}
+			store := db.NewSQLStore(conn)
+			reg, err := validate.Load(cmd.Context(), store)
+			if err != nil {
+				return err
+			}
+			findings, err := validate.Run(cmd.Context(), reg, validate.Rules(users))
+			if err != nil {
+				return err
+			}
+			if err := printFindings(cmd, findings); err != nil {
+				return err
+			}
+			if dryRun {
+				return nil
+			}
+			return store.ReplaceFindings(cmd.Context(), findings)
+		},
+	}
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print the findings without recording them")
+	cmd.Flags().BoolVar(&checkGitHub, "check-github", false, "Look up GitHub logins through the GitHub API")
+	return cmd
+}
+
+func printFindings(cmd *cobra.Command, findings []model.Finding) error {
+	errs := 0
+	for _, f := range findings {
+		if f.Severity == model.SeverityError {
+			errs++
+		}
+	}
+	if len(findings) > 0 {
+		tw := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "SEVERITY\tRULE\tKIND\tID\tSUBJECT\tMESSAGE")
+		for _, f := range findings {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%d\t%s\t%s\n", f.Severity, f.Rule, f.SubjectKind, f.SubjectID, f.Subject, f.Message)
+		}
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+	}
+	cmd.Printf("%d findings, %d errors\n", len(findings), errs)
+	return nil
+}
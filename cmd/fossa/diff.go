@@ -0,0 +1,153 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"maintainerd/db"
+	"maintainerd/model"
+	"maintainerd/plugins/fossa"
+)
+
+// Membership states reported by diff.
+const (
+	diffInTeam  = "in-team"   // registered maintainer on the FOSSA team
+	diffInvited = "invited"   // registered maintainer with a pending invitation to the organization
+	diffMissing = "missing"   // registered maintainer neither on the team nor invited
+	diffExtra   = "not-in-db" // FOSSA team member who is not a registered maintainer of the project
+)
+
+// diffEntry is one line of the comparison between maintainer-d and a FOSSA team.
+type diffEntry struct {
+	GitHubAccount string `json:"githubAccount,omitempty"`
+	Email         string `json:"email"`
+	Status        string `json:"status"`
+	Role          string `json:"role,omitempty"`
+}
+
+func newDiffCmd(opts *options) *cobra.Command {
+	var projectName string
+	cmd := &cobra.Command{
+		Use:   "diff",
+		Short: "Compare a project's maintainers in maintainer-d with the members of its FOSSA team",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dbConn, err := db.Open(opts.dbPath, nil)
+			if err != nil {
+				return err
+			}
+			store := db.NewSQLStore(dbConn)
+			ctx := cmd.Context()
+
+			projects, err := store.GetProjectMapByName(ctx)
+			if err != nil {
+				return err
+			}
+			project, ok := projects[projectName]
+			if !ok {
+				return fmt.Errorf("project %q not found in %s", projectName, db.RedactDSN(opts.dbPath))
+			}
+			maintainers, err := store.GetMaintainersByProject(ctx, project.ID)
+			if err != nil {
+				return err
+			}
+
+			client := newClient()
+			teamID, err := fossaTeamID(ctx, store, client, project)
+			if err != nil {
+				return err
+			}
+			members, err := client.ListTeamMembersWithRoles(teamID)
+			if err != nil {
+				return err
+			}
+			invitations, err := client.ListInvitations()
+			if err != nil {
+				return err
+			}
+
+			entries := diffMembers(maintainers, members, invitations, time.Now())
+			t := table{
+				headers: []string{"github", "email", "status", "role"},
+				records: entries,
+			}
+			for _, e := range entries {
+				t.add(e.GitHubAccount, e.Email, e.Status, e.Role)
+			}
+			return render(cmd.OutOrStdout(), opts.output, t)
+		},
+	}
+	cmd.Flags().StringVar(&projectName, "project", "", "Project name as recorded in maintainer-d")
+	_ = cmd.MarkFlagRequired("project")
+	return cmd
+}
+
+// fossaTeamID returns the FOSSA team recorded for project, falling back to the team named after the project.
+func fossaTeamID(ctx context.Context, store db.Store, client *fossa.Client, project model.Project) (int, error) {
+	teams, err := store.GetProjectServiceTeamMap(ctx, "FOSSA")
+	if err != nil {
+		return 0, err
+	}
+	if st, ok := teams[project.ID]; ok && st.ServiceTeamID != 0 {
+		return st.ServiceTeamID, nil
+	}
+	team, err := client.FetchTeam(project.Name)
+	if err != nil {
+		return 0, fmt.Errorf("project %s has no FOSSA team: %w", project.Name, err)
+	}
+	return team.ID, nil
+}
+
+// diffMembers classifies every maintainer of the project and every member of its FOSSA team. Maintainers are matched
+// to team members by either of their emails, case-insensitively. Entries are sorted by status, then email.
+func diffMembers(maintainers []model.Maintainer, members []fossa.TeamMember, invitations []fossa.Invitation,
+	now time.Time) []diffEntry {
+	memberByEmail := make(map[string]fossa.TeamMember, len(members))
+	for _, m := range members {
+		memberByEmail[strings.ToLower(m.Email)] = m
+	}
+	invited := make(map[string]bool, len(invitations))
+	for _, inv := range invitations {
+		if !inv.IsExpired(now) {
+			invited[strings.ToLower(inv.Email)] = true
+		}
+	}
+
+	matched := map[string]bool{}
+	var entries []diffEntry
+	for _, m := range maintainers {
+		entry := diffEntry{GitHubAccount: m.GitHubAccount, Email: m.Email, Status: diffMissing}
+		for _, key := range m.Emails() {
+			if member, ok := memberByEmail[key]; ok {
+				entry.Email = member.Email
+				entry.Status = diffInTeam
+				entry.Role = member.RoleID.String()
+				matched[key] = true
+				break
+			}
+			if invited[key] {
+				entry.Status = diffInvited
+			}
+		}
+		entries = append(entries, entry)
+	}
+	for _, member := range members {
+		if matched[strings.ToLower(member.Email)] {
+			continue
+		}
+		entries = append(entries, diffEntry{Email: member.Email, Status: diffExtra, Role: member.RoleID.String()})
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].Status != entries[j].Status {
+			return entries[i].Status < entries[j].Status
+		}
+		return strings.ToLower(entries[i].Email) < strings.ToLower(entries[j].Email)
+	})
+	return entries
+}
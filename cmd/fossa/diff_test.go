@@ -0,0 +1,63 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"maintainerd/model"
+	"maintainerd/plugins/fossa"
+)
+
+func TestDiffMembers(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	maintainers := []model.Maintainer{
+		{GitHubAccount: "alice", Email: "alice@example.com"},
+		{GitHubAccount: "bob", Email: "bob@example.com", GitHubEmail: "bob@users.noreply.github.com"},
+		{GitHubAccount: "carol", Email: "carol@example.com"},
+		{GitHubAccount: "dave", Email: "dave@example.com"},
+	}
+	members := []fossa.TeamMember{
+		{UserID: 1, Email: "Alice@Example.com", RoleID: fossa.TeamRoleAdmin},
+		{UserID: 2, Email: "bob@users.noreply.github.com", RoleID: fossa.TeamRoleEditor},
+		{UserID: 3, Email: "eve@example.com", RoleID: fossa.TeamRoleViewer},
+	}
+	invitations := []fossa.Invitation{
+		{Email: "carol@example.com", CreatedAt: now.Add(-24 * time.Hour)},
+		{Email: "dave@example.com", CreatedAt: now.Add(-30 * 24 * time.Hour)},
+	}
+
+	got := diffMembers(maintainers, members, invitations, now)
+
+	assert.Equal(t, []diffEntry{
+		{GitHubAccount: "alice", Email: "Alice@Example.com", Status: diffInTeam, Role: "Team Admin"},
+		{GitHubAccount: "bob", Email: "bob@users.noreply.github.com", Status: diffInTeam, Role: "Team Editor"},
+		{GitHubAccount: "carol", Email: "carol@example.com", Status: diffInvited},
+		{GitHubAccount: "dave", Email: "dave@example.com", Status: diffMissing},
+		{Email: "eve@example.com", Status: diffExtra, Role: "Team Viewer"},
+	}, got)
+}
+
+func TestRender(t *testing.T) {
+	tbl := projectsTable([]fossa.ImportedProject{{Title: "argo-cd", Locator: "git+github.com/argoproj/argo-cd"}})
+
+	var csvOut, tableOut, jsonOut strings.Builder
+	assert.NoError(t, render(&csvOut, outputCSV, tbl))
+	assert.NoError(t, render(&tableOut, outputTable, tbl))
+	assert.NoError(t, render(&jsonOut, outputJSON, tbl))
+
+	assert.Equal(t, "title,locator\nargo-cd,git+github.com/argoproj/argo-cd\n", csvOut.String())
+	assert.Contains(t, tableOut.String(), "TITLE")
+	assert.JSONEq(t, `[{"title":"argo-cd","locator":"git+github.com/argoproj/argo-cd"}]`, jsonOut.String())
+	assert.Error(t, validateOutput("yaml"))
+
+	// An empty result is an empty list, not null.
+	jsonOut.Reset()
+	assert.NoError(t, render(&jsonOut, outputJSON, projectsTable(nil)))
+	assert.JSONEq(t, `[]`, jsonOut.String())
+	jsonOut.Reset()
+	assert.NoError(t, render(&jsonOut, outputJSON, table{}))
+	assert.JSONEq(t, `[]`, jsonOut.String())
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"maintainerd/plugins/fossa"
+)
+
+func newInvitesCmd(opts *options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "invites",
+		Short: "Manage invitations to join the FOSSA organization",
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "list",
+		Short: "List pending invitations",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			invitations, err := newClient().ListInvitations()
+			if err != nil {
+				return err
+			}
+			return render(cmd.OutOrStdout(), opts.output, invitationsTable(invitations, time.Now()))
+		},
+	})
+
+	var resend bool
+	send := &cobra.Command{
+		Use:   "send <email>",
+		Short: "Invite someone to the FOSSA organization",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client := newClient()
+			if resend {
+				inv, err := client.ResendInvitation(args[0])
+				if err != nil {
+					return err
+				}
+				cmd.Printf("sent a new invitation to %s, it expires on %s\n", args[0], inv.Expiry().Format(time.DateOnly))
+				return nil
+			}
+			err := client.SendUserInvitation(args[0])
+			switch {
+			case errors.Is(err, fossa.ErrInviteAlreadyExists):
+				cmd.Printf("%s already has a pending invitation, use --resend to replace it\n", args[0])
+				return nil
+			case errors.Is(err, fossa.ErrUserAlreadyMember):
+				cmd.Printf("%s is already a member of the organization\n", args[0])
+				return nil
+			case err != nil:
+				return err
+			}
+			cmd.Printf("invited %s\n", args[0])
+			return nil
+		},
+	}
+	send.Flags().BoolVar(&resend, "resend", false, "Revoke any pending invitation and send a fresh one")
+	cmd.AddCommand(send)
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "revoke <email>",
+		Short: "Withdraw a pending invitation",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := newClient().RevokeInvitation(args[0]); err != nil {
+				return err
+			}
+			cmd.Printf("revoked the invitation sent to %s\n", args[0])
+			return nil
+		},
+	})
+
+	return cmd
+}
+
+func invitationsTable(invitations []fossa.Invitation, now time.Time) table {
+	t := table{
+		headers: []string{"id", "email", "sent", "expires", "status"},
+		records: invitations,
+	}
+	for _, inv := range invitations {
+		status := "pending"
+		if inv.IsExpired(now) {
+			status = "expired"
+		}
+		t.add(strconv.Itoa(inv.ID), inv.Email, inv.CreatedAt.Format(time.DateOnly),
+			inv.Expiry().Format(time.DateOnly), status)
+	}
+	return t
+}
@@ -3,60 +3,85 @@ package main
 import (
 	"fmt"
 	"log"
-	"maintainerd/plugins/fossa"
 	"os"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"maintainerd/db"
+	"maintainerd/plugins/fossa"
 )
 
 const (
 	apiTokenEnvVar = "FOSSA_API_TOKEN" //nolint:gosec
+	apiBaseEnvVar  = "FOSSA_API_BASE"
+	defaultDBPath  = "maintainers.db"
 )
 
-func main() {
-	token := os.Getenv(apiTokenEnvVar)
-	if token == "" {
-		log.Fatalf("please set $%s\n", apiTokenEnvVar)
-	}
-	fossaClient := fossa.NewClient(token)
+// options holds the flags shared by every subcommand.
+type options struct {
+	output string
+	dbPath string
+}
 
-	teams, err := fossaClient.FetchTeams()
+func main() {
+	opts := &options{}
 
-	if err != nil {
-		log.Fatalf("error fetching teams: %v\n", err)
-	}
-	fmt.Println("Your teams:")
-	for _, t := range teams {
-		fmt.Printf("  %3d  %-20s  users:%3d  projects:%3d  releases:%3d\n",
-			t.ID, t.Name,
-			len(t.TeamUsers),
-			t.TeamProjectsCount,
-			t.TeamReleaseGroupsCount,
-		)
+	rootCmd := &cobra.Command{
+		Use:   "fossa",
+		Short: "Administer the CNCF FOSSA organization: teams, members, invitations and imported projects",
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if dsn := viper.GetString(db.DSNEnvVar); dsn != "" {
+				opts.dbPath = dsn
+			}
+			return validateOutput(opts.output)
+		},
+		SilenceUsage: true,
 	}
+	rootCmd.PersistentFlags().StringVarP(&opts.output, "output", "o", outputTable, "Output format: table, json or csv")
+	rootCmd.PersistentFlags().StringVar(&opts.dbPath, "db", defaultDBPath,
+		"maintainer-d SQLite database file or PostgreSQL DSN, used by diff (overridden by $"+db.DSNEnvVar+")")
 
-	// 2) pick a team ID on the CLI
-	if len(os.Args) < 2 {
-		log.Fatalf("usage: %s <teamName>\n", os.Args[0])
-	}
-	teamName := os.Args[1]
+	rootCmd.AddCommand(
+		newTeamsCmd(opts),
+		newMembersCmd(opts),
+		newInvitesCmd(opts),
+		newProjectsCmd(opts),
+		newDiffCmd(opts),
+	)
 
-	teamID, err := fossaClient.GetTeamId(teams, teamName)
+	viper.AutomaticEnv() // binds environment variables to viper config
 
-	if err != nil {
-		log.Fatalf("error fetching team: %v", err)
+	if err := rootCmd.Execute(); err != nil {
+		os.Exit(1)
 	}
+}
 
-	emails, err := fossaClient.FetchTeamUserEmails(teamID)
-	if err != nil {
-		log.Fatalf("error fetching users: %v", err)
+// newClient returns a FOSSA client authenticated with $FOSSA_API_TOKEN, talking to $FOSSA_API_BASE when set.
+func newClient() *fossa.Client {
+	token := viper.GetString(apiTokenEnvVar)
+	if token == "" {
+		log.Fatalf("ERROR: environment variable %s is not set", apiTokenEnvVar)
+	}
+	client := fossa.NewClient(token)
+	if base := viper.GetString(apiBaseEnvVar); base != "" {
+		client.APIBase = base
 	}
+	return client
+}
 
-	fmt.Println("Team members’ emails:")
-	for i, email := range emails {
-		if i == 0 {
-			fmt.Println(email)
-		} else {
-			fmt.Printf(", %s", email)
+// resolveTeam finds a team by its numeric ID or by its exact name.
+func resolveTeam(client *fossa.Client, ref string) (*fossa.Team, error) {
+	if id, err := strconv.Atoi(ref); err == nil {
+		team, err := client.GetTeam(id)
+		if err != nil {
+			return nil, err
+		}
+		if team.ID == 0 {
+			return nil, fmt.Errorf("team %d not found", id)
 		}
+		return team, nil
 	}
-	fmt.Println()
+	return client.FetchTeam(ref)
 }
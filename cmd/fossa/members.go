@@ -0,0 +1,118 @@
+package main
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"maintainerd/plugins/fossa"
+)
+
+func newMembersCmd(opts *options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "members",
+		Short: "Manage the members of a FOSSA team",
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "list <team>",
+		Short: "List the members of a team and their roles",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client := newClient()
+			team, err := resolveTeam(client, args[0])
+			if err != nil {
+				return err
+			}
+			members, err := client.ListTeamMembersWithRoles(team.ID)
+			if err != nil {
+				return err
+			}
+			return render(cmd.OutOrStdout(), opts.output, membersTable(members))
+		},
+	})
+
+	var addRole string
+	add := &cobra.Command{
+		Use:   "add <team> <email>",
+		Short: "Add an existing FOSSA user to a team",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			role, err := fossa.ParseTeamRole(addRole)
+			if err != nil {
+				return err
+			}
+			client := newClient()
+			team, err := resolveTeam(client, args[0])
+			if err != nil {
+				return err
+			}
+			err = client.AddUserToTeamByEmail(team.ID, args[1], int(role))
+			if errors.Is(err, fossa.ErrUserAlreadyMember) {
+				cmd.Printf("%s is already a member of %s\n", args[1], team.Name)
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			cmd.Printf("added %s to %s as %s\n", args[1], team.Name, role)
+			return nil
+		},
+	}
+	add.Flags().StringVar(&addRole, "role", "default", "Team role: admin, editor, viewer or default")
+	cmd.AddCommand(add)
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "remove <team> <email>",
+		Short: "Remove a user from a team",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client := newClient()
+			team, err := resolveTeam(client, args[0])
+			if err != nil {
+				return err
+			}
+			if err := client.RemoveUserFromTeamByEmail(team.ID, args[1]); err != nil {
+				return err
+			}
+			cmd.Printf("removed %s from %s\n", args[1], team.Name)
+			return nil
+		},
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "set-role <team> <email> <admin|editor|viewer>",
+		Short: "Change the role a member holds on a team",
+		Args:  cobra.ExactArgs(3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			role, err := fossa.ParseTeamRole(args[2])
+			if err != nil {
+				return err
+			}
+			client := newClient()
+			team, err := resolveTeam(client, args[0])
+			if err != nil {
+				return err
+			}
+			if err := client.UpdateTeamMemberRoleByEmail(team.ID, args[1], role); err != nil {
+				return err
+			}
+			cmd.Printf("%s is now %s on %s\n", args[1], role, team.Name)
+			return nil
+		},
+	})
+
+	return cmd
+}
+
+func membersTable(members []fossa.TeamMember) table {
+	t := table{
+		headers: []string{"user id", "username", "email", "role"},
+		records: members,
+	}
+	for _, m := range members {
+		t.add(strconv.Itoa(m.UserID), m.Username, m.Email, m.RoleID.String())
+	}
+	return t
+}
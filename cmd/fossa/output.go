@@ -0,0 +1,74 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"text/tabwriter"
+)
+
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+	outputCSV   = "csv"
+)
+
+func validateOutput(format string) error {
+	switch format {
+	case outputTable, outputJSON, outputCSV:
+		return nil
+	}
+	return fmt.Errorf("unknown output format %q, expected table, json or csv", format)
+}
+
+// table is the result of a subcommand. Table and CSV output render headers and rows; JSON output encodes records,
+// which keeps the typed field names and values.
+type table struct {
+	headers []string
+	rows    [][]string
+	records interface{}
+}
+
+func (t *table) add(cells ...string) {
+	t.rows = append(t.rows, cells)
+}
+
+// jsonRecords returns records, or an empty list if there are none: a nil slice of any type would encode as null.
+func jsonRecords(records interface{}) interface{} {
+	if records == nil {
+		return []struct{}{}
+	}
+	if v := reflect.ValueOf(records); v.Kind() == reflect.Slice && v.IsNil() {
+		return reflect.MakeSlice(v.Type(), 0, 0).Interface()
+	}
+	return records
+}
+
+func render(w io.Writer, format string, t table) error {
+	switch format {
+	case outputJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(jsonRecords(t.records))
+	case outputCSV:
+		cw := csv.NewWriter(w)
+		if err := cw.Write(t.headers); err != nil {
+			return err
+		}
+		if err := cw.WriteAll(t.rows); err != nil {
+			return err
+		}
+		cw.Flush()
+		return cw.Error()
+	default:
+		tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, strings.ToUpper(strings.Join(t.headers, "\t")))
+		for _, row := range t.rows {
+			fmt.Fprintln(tw, strings.Join(row, "\t"))
+		}
+		return tw.Flush()
+	}
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+
+	"maintainerd/plugins/fossa"
+)
+
+func newProjectsCmd(opts *options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "projects",
+		Short: "Inspect the projects imported into FOSSA",
+	}
+
+	var teamRef string
+	list := &cobra.Command{
+		Use:   "list",
+		Short: "List the projects imported by a team",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client := newClient()
+			team, err := resolveTeam(client, teamRef)
+			if err != nil {
+				return err
+			}
+			_, projects, err := client.FetchImportedRepos(team.ID)
+			if err != nil {
+				return err
+			}
+			return render(cmd.OutOrStdout(), opts.output, projectsTable(projects.Results))
+		},
+	}
+	list.Flags().StringVar(&teamRef, "team", "", "Team name or ID")
+	_ = list.MarkFlagRequired("team")
+	cmd.AddCommand(list)
+
+	return cmd
+}
+
+func projectsTable(projects []fossa.ImportedProject) table {
+	t := table{
+		headers: []string{"title", "locator"},
+		records: projects,
+	}
+	for _, p := range projects {
+		t.add(p.Title, p.Locator)
+	}
+	return t
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"maintainerd/plugins/fossa"
+)
+
+func newTeamsCmd(opts *options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "teams",
+		Short: "List, create and inspect FOSSA teams",
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "list",
+		Short: "List every team in the organization",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			teams, err := newClient().FetchTeams()
+			if err != nil {
+				return err
+			}
+			return render(cmd.OutOrStdout(), opts.output, teamsTable(teams))
+		},
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "create <name>",
+		Short: "Create a team, or return the existing team with that name",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			team, err := newClient().CreateTeam(args[0])
+			if err != nil {
+				return err
+			}
+			return render(cmd.OutOrStdout(), opts.output, teamsTable([]fossa.Team{*team}))
+		},
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "show <name|id>",
+		Short: "Show a team and its members",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client := newClient()
+			team, err := resolveTeam(client, args[0])
+			if err != nil {
+				return err
+			}
+			members, err := client.ListTeamMembersWithRoles(team.ID)
+			if err != nil {
+				return err
+			}
+			if opts.output == outputJSON {
+				return render(cmd.OutOrStdout(), opts.output, table{records: struct {
+					fossa.Team
+					Members []fossa.TeamMember `json:"members"`
+				}{*team, members}})
+			}
+			if err := render(cmd.OutOrStdout(), opts.output, teamsTable([]fossa.Team{*team})); err != nil {
+				return err
+			}
+			cmd.Println()
+			return render(cmd.OutOrStdout(), opts.output, membersTable(members))
+		},
+	})
+
+	return cmd
+}
+
+func teamsTable(teams []fossa.Team) table {
+	t := table{
+		headers: []string{"id", "name", "users", "projects", "releases"},
+		records: teams,
+	}
+	for _, team := range teams {
+		t.add(strconv.Itoa(team.ID), team.Name, strconv.Itoa(len(team.TeamUsers)),
+			strconv.Itoa(team.TeamProjectsCount), strconv.Itoa(team.TeamReleaseGroupsCount))
+	}
+	return t
+}
@@ -2,15 +2,19 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"log"
+	"os"
+	"os/signal"
 	"regexp"
+	"slices"
 	"strings"
+	"syscall"
 
 	apis "maintainerd/apis/maintainers/v1alpha1"
 	"maintainerd/db"
 
-	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -28,9 +32,18 @@ const (
 )
 
 func main() {
-	ctx := context.Background()
+	dsn := flag.String("db", defaultDBPath, "SQLite database file or PostgreSQL DSN (overridden by $"+db.DSNEnvVar+")")
+	namespace := flag.String("namespace", defaultNamespace, "Namespace to sync maintainer-d resources into")
+	flag.Parse()
+	if v := os.Getenv(db.DSNEnvVar); v != "" {
+		*dsn = v
+	}
+
+	// A CronJob pod is sent SIGTERM when it is deleted or exceeds its deadline; stop querying the database then.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
-	dbConn, err := openDB(defaultDBPath)
+	dbConn, err := openDB(*dsn)
 	if err != nil {
 		log.Fatalf("failed to open DB: %v", err)
 	}
@@ -41,15 +54,15 @@ func main() {
 		log.Fatalf("failed to create k8s client: %v", err)
 	}
 
-	if err := syncAll(ctx, store, k8sClient, defaultNamespace); err != nil {
+	if err := syncAll(ctx, store, k8sClient, *namespace); err != nil {
 		log.Fatalf("sync failed: %v", err)
 	}
 
 	log.Println("sync completed successfully")
 }
 
-func openDB(path string) (*gorm.DB, error) {
-	dbConn, err := gorm.Open(sqlite.Open(path), &gorm.Config{})
+func openDB(dsn string) (*gorm.DB, error) {
+	dbConn, err := db.Open(dsn, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -73,7 +86,7 @@ func newClient() (client.Client, error) {
 	return client.New(restCfg, client.Options{Scheme: scheme})
 }
 
-func syncAll(ctx context.Context, store *db.SQLStore, c client.Client, ns string) error {
+func syncAll(ctx context.Context, store db.Store, c client.Client, ns string) error {
 	if err := syncCompanies(ctx, store, c, ns); err != nil {
 		return fmt.Errorf("companies: %w", err)
 	}
@@ -92,8 +105,8 @@ func syncAll(ctx context.Context, store *db.SQLStore, c client.Client, ns string
 	return nil
 }
 
-func syncStaff(ctx context.Context, store *db.SQLStore, c client.Client, ns string) error {
-	staffMembers, err := store.ListStaffMembers()
+func syncStaff(ctx context.Context, store db.Store, c client.Client, ns string) error {
+	staffMembers, err := store.ListStaffMembers(ctx)
 	if err != nil {
 		return err
 	}
@@ -147,20 +160,26 @@ func syncStaff(ctx context.Context, store *db.SQLStore, c client.Client, ns stri
 	return nil
 }
 
-func syncCompanies(ctx context.Context, store *db.SQLStore, c client.Client, ns string) error {
-	companies, err := store.ListCompanies()
+func syncCompanies(ctx context.Context, store db.Store, c client.Client, ns string) error {
+	archived, err := store.ListArchivedCompanies(ctx)
+	if err != nil {
+		return err
+	}
+	companies, err := store.ListCompanies(ctx)
 	if err != nil {
 		return err
 	}
-	for _, comp := range companies {
+	// Archived first, so a live company wins if both sanitize to the same name.
+	for _, comp := range append(archived, companies...) {
 		obj := &apis.Company{}
 		name := sanitizeName(comp.Name)
 		key := client.ObjectKey{Name: name, Namespace: ns}
+		spec := apis.CompanySpec{DisplayName: comp.Name, ArchivedAt: archivedAt(comp.DeletedAt)}
 		err := c.Get(ctx, key, obj)
 		if errors.IsNotFound(err) {
 			obj = &apis.Company{
 				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: ns},
-				Spec:       apis.CompanySpec{DisplayName: comp.Name},
+				Spec:       spec,
 			}
 			if err := c.Create(ctx, obj); err != nil {
 				return fmt.Errorf("create company %s: %w", name, err)
@@ -170,8 +189,9 @@ func syncCompanies(ctx context.Context, store *db.SQLStore, c client.Client, ns
 		if err != nil {
 			return err
 		}
-		if obj.Spec.DisplayName != comp.Name {
-			obj.Spec.DisplayName = comp.Name
+		if obj.Spec.DisplayName != spec.DisplayName || !timePtrEqual(obj.Spec.ArchivedAt, spec.ArchivedAt) {
+			obj.Spec.DisplayName = spec.DisplayName
+			obj.Spec.ArchivedAt = spec.ArchivedAt
 			if err := c.Update(ctx, obj); err != nil {
 				return fmt.Errorf("update company %s: %w", name, err)
 			}
@@ -180,12 +200,25 @@ func syncCompanies(ctx context.Context, store *db.SQLStore, c client.Client, ns
 	return nil
 }
 
-func syncMaintainers(ctx context.Context, store *db.SQLStore, c client.Client, ns string) error {
-	mByEmail, err := store.GetMaintainerMapByEmail()
+func syncMaintainers(ctx context.Context, store db.Store, c client.Client, ns string) error {
+	maintainers, err := store.ListArchivedMaintainers(ctx)
 	if err != nil {
 		return err
 	}
+	mByEmail, err := store.GetMaintainerMapByEmail(ctx)
+	if err != nil {
+		return err
+	}
+	// Archived first, so a live maintainer wins if both sanitize to the same name. The map holds a maintainer once per
+	// email they are known by.
+	seen := make(map[uint]bool, len(mByEmail))
 	for _, m := range mByEmail {
+		if !seen[m.ID] {
+			seen[m.ID] = true
+			maintainers = append(maintainers, m)
+		}
+	}
+	for _, m := range maintainers {
 		name := sanitizeName(m.Email)
 		obj := &apis.Maintainer{}
 		key := client.ObjectKey{Name: name, Namespace: ns}
@@ -206,6 +239,7 @@ func syncMaintainers(ctx context.Context, store *db.SQLStore, c client.Client, n
 			GitHubEmail:   m.GitHubEmail,
 			Status:        status,
 			RegisteredAt:  registeredAt,
+			ArchivedAt:    archivedAt(m.DeletedAt),
 		}
 		if m.CompanyID != nil && m.Company.Name != "" {
 			spec.CompanyRef = &apis.ResourceReference{Name: sanitizeName(m.Company.Name)}
@@ -236,16 +270,24 @@ func syncMaintainers(ctx context.Context, store *db.SQLStore, c client.Client, n
 	return nil
 }
 
-func syncProjects(ctx context.Context, store *db.SQLStore, c client.Client, ns string) error {
-	projectsByName, err := store.GetProjectMapByName()
+func syncProjects(ctx context.Context, store db.Store, c client.Client, ns string) error {
+	projects, err := store.ListArchivedProjects(ctx)
 	if err != nil {
 		return err
 	}
-	parentNameByID := make(map[uint]string, len(projectsByName))
+	projectsByName, err := store.GetProjectMapByName(ctx)
+	if err != nil {
+		return err
+	}
+	// Archived first, so a live project wins if both sanitize to the same name.
 	for _, p := range projectsByName {
+		projects = append(projects, p)
+	}
+	parentNameByID := make(map[uint]string, len(projects))
+	for _, p := range projects {
 		parentNameByID[p.ID] = p.Name
 	}
-	for _, p := range projectsByName {
+	for _, p := range projects {
 		name := sanitizeName(p.Name)
 		obj := &apis.Project{}
 		key := client.ObjectKey{Name: name, Namespace: ns}
@@ -253,6 +295,7 @@ func syncProjects(ctx context.Context, store *db.SQLStore, c client.Client, ns s
 			DisplayName:    p.Name,
 			Maturity:       apis.ProjectMaturity(p.Maturity),
 			MaintainerRefs: make([]apis.ResourceReference, 0, len(p.Maintainers)),
+			ArchivedAt:     archivedAt(p.DeletedAt),
 		}
 		if p.OnboardingIssue != nil {
 			spec.OnboardingIssue = *p.OnboardingIssue
@@ -295,19 +338,27 @@ func syncProjects(ctx context.Context, store *db.SQLStore, c client.Client, ns s
 	return nil
 }
 
-func syncMemberships(ctx context.Context, store *db.SQLStore, c client.Client, ns string) error {
-	projectsByName, err := store.GetProjectMapByName()
+func syncMemberships(ctx context.Context, store db.Store, c client.Client, ns string) error {
+	projectsByName, err := store.GetProjectMapByName(ctx)
 	if err != nil {
 		return err
 	}
+	live := map[string]bool{}
 	for _, p := range projectsByName {
-		for _, m := range p.Maintainers {
+		memberships, err := store.GetProjectMemberships(ctx, p.ID)
+		if err != nil {
+			return fmt.Errorf("list memberships of project %s: %w", p.Name, err)
+		}
+		for _, ms := range memberships {
+			m := ms.Maintainer
 			name := sanitizeName(fmt.Sprintf("%s-%s", p.Name, m.Email))
+			live[name] = true
 			obj := &apis.ProjectMembership{}
 			key := client.ObjectKey{Name: name, Namespace: ns}
 			spec := apis.ProjectMembershipSpec{
 				ProjectRef:    apis.ResourceReference{Name: sanitizeName(p.Name)},
 				MaintainerRef: apis.ResourceReference{Name: sanitizeName(m.Email)},
+				Roles:         ms.Roles.Strings(),
 			}
 			err := c.Get(ctx, key, obj)
 			if errors.IsNotFound(err) {
@@ -323,7 +374,8 @@ func syncMemberships(ctx context.Context, store *db.SQLStore, c client.Client, n
 			if err != nil {
 				return err
 			}
-			if obj.Spec.ProjectRef.Name != spec.ProjectRef.Name || obj.Spec.MaintainerRef.Name != spec.MaintainerRef.Name {
+			if obj.Spec.ProjectRef.Name != spec.ProjectRef.Name || obj.Spec.MaintainerRef.Name != spec.MaintainerRef.Name ||
+				!slices.Equal(obj.Spec.Roles, spec.Roles) || obj.Spec.ArchivedAt != nil {
 				obj.Spec = spec
 				if err := c.Update(ctx, obj); err != nil {
 					return fmt.Errorf("update membership %s: %w", name, err)
@@ -331,6 +383,53 @@ func syncMemberships(ctx context.Context, store *db.SQLStore, c client.Client, n
 			}
 		}
 	}
+	return archiveMemberships(ctx, store, c, ns, live)
+}
+
+// archiveMemberships stamps ArchivedAt on the ProjectMembership resources, other than those in live, whose project or
+// maintainer is archived. maintainer-d archives memberships along with either side, so they are no longer listed by
+// the store; the resources are kept so that restoring brings them back unchanged.
+func archiveMemberships(ctx context.Context, store db.Store, c client.Client, ns string, live map[string]bool) error {
+	archived := map[string]*metav1.Time{}
+	projects, err := store.ListArchivedProjects(ctx)
+	if err != nil {
+		return err
+	}
+	for _, p := range projects {
+		archived["project/"+sanitizeName(p.Name)] = archivedAt(p.DeletedAt)
+	}
+	maintainers, err := store.ListArchivedMaintainers(ctx)
+	if err != nil {
+		return err
+	}
+	for _, m := range maintainers {
+		archived["maintainer/"+sanitizeName(m.Email)] = archivedAt(m.DeletedAt)
+	}
+	if len(archived) == 0 {
+		return nil
+	}
+
+	var memberships apis.ProjectMembershipList
+	if err := c.List(ctx, &memberships, client.InNamespace(ns)); err != nil {
+		return fmt.Errorf("list memberships: %w", err)
+	}
+	for i := range memberships.Items {
+		obj := &memberships.Items[i]
+		if live[obj.Name] || obj.Spec.ArchivedAt != nil {
+			continue
+		}
+		at, ok := archived["project/"+obj.Spec.ProjectRef.Name]
+		if !ok {
+			at, ok = archived["maintainer/"+obj.Spec.MaintainerRef.Name]
+		}
+		if !ok {
+			continue
+		}
+		obj.Spec.ArchivedAt = at
+		if err := c.Update(ctx, obj); err != nil {
+			return fmt.Errorf("archive membership %s: %w", obj.Name, err)
+		}
+	}
 	return nil
 }
 
@@ -359,6 +458,9 @@ func projectSpecEqual(a, b apis.ProjectSpec) bool {
 	if a.DisplayName != b.DisplayName || a.Maturity != b.Maturity || a.MailingList != b.MailingList || a.OnboardingIssue != b.OnboardingIssue {
 		return false
 	}
+	if !timePtrEqual(a.ArchivedAt, b.ArchivedAt) {
+		return false
+	}
 	if (a.ParentProjectRef == nil) != (b.ParentProjectRef == nil) {
 		return false
 	}
@@ -393,7 +495,8 @@ func maintainerSpecEqual(a, b apis.MaintainerSpec) bool {
 		a.GitHubAccount != b.GitHubAccount ||
 		a.GitHubEmail != b.GitHubEmail ||
 		a.Status != b.Status ||
-		!timePtrEqual(a.RegisteredAt, b.RegisteredAt) {
+		!timePtrEqual(a.RegisteredAt, b.RegisteredAt) ||
+		!timePtrEqual(a.ArchivedAt, b.ArchivedAt) {
 		return false
 	}
 	if (a.CompanyRef == nil) != (b.CompanyRef == nil) {
@@ -422,6 +525,15 @@ func staffSpecEqual(a, b apis.StaffMemberSpec) bool {
 	return true
 }
 
+// archivedAt returns when a soft-deleted record was archived, or nil for a live one.
+func archivedAt(deletedAt gorm.DeletedAt) *metav1.Time {
+	if !deletedAt.Valid {
+		return nil
+	}
+	t := metav1.NewTime(deletedAt.Time)
+	return &t
+}
+
 func timePtrEqual(a, b *metav1.Time) bool {
 	switch {
 	case a == nil && b == nil:
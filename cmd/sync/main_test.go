@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apis "maintainerd/apis/maintainers/v1alpha1"
+	"maintainerd/db/dbtest"
+	"maintainerd/model"
+)
+
+func newFakeClient(t *testing.T) client.Client {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	require.NoError(t, apis.AddToScheme(scheme))
+	return fake.NewClientBuilder().WithScheme(scheme).Build()
+}
+
+func TestSyncAll(t *testing.T) {
+	ctx := context.Background()
+	store := dbtest.NewMemStore()
+	acme := store.AddCompany("Acme Corp")
+	argo := store.AddProject(model.Project{Name: "Argo", Maturity: model.Graduated})
+	store.AddMaintainer(model.Maintainer{
+		Name: "Alice", Email: "alice@example.com", GitHubAccount: "alice",
+		MaintainerStatus: model.ActiveMaintainer, CompanyID: &acme.ID,
+	}, argo.ID)
+	store.AddStaffMember(model.StaffMember{Name: "Staff", Email: "staff@cncf.io"})
+
+	c := newFakeClient(t)
+	require.NoError(t, syncAll(ctx, store, c, "maintainerd"))
+
+	var company apis.Company
+	require.NoError(t, c.Get(ctx, client.ObjectKey{Namespace: "maintainerd", Name: "acme-corp"}, &company))
+	assert.Equal(t, "Acme Corp", company.Spec.DisplayName)
+
+	var maintainer apis.Maintainer
+	require.NoError(t, c.Get(ctx, client.ObjectKey{Namespace: "maintainerd", Name: "alice-example.com"}, &maintainer))
+	require.NotNil(t, maintainer.Spec.CompanyRef)
+	assert.Equal(t, "acme-corp", maintainer.Spec.CompanyRef.Name)
+
+	var project apis.Project
+	require.NoError(t, c.Get(ctx, client.ObjectKey{Namespace: "maintainerd", Name: "argo"}, &project))
+	require.Len(t, project.Spec.MaintainerRefs, 1)
+
+	var memberships apis.ProjectMembershipList
+	require.NoError(t, c.List(ctx, &memberships, client.InNamespace("maintainerd")))
+	require.Len(t, memberships.Items, 1)
+	assert.Equal(t, []string{"maintainer"}, memberships.Items[0].Spec.Roles)
+
+	// A second run with unchanged data is a no-op.
+	require.NoError(t, syncAll(ctx, store, c, "maintainerd"))
+	var again apis.Company
+	require.NoError(t, c.Get(ctx, client.ObjectKey{Namespace: "maintainerd", Name: "acme-corp"}, &again))
+	assert.Equal(t, company.ResourceVersion, again.ResourceVersion)
+}
+
+func TestSyncAllArchivedRecords(t *testing.T) {
+	ctx := context.Background()
+	store := dbtest.NewMemStore()
+	acme := store.AddCompany("Acme Corp")
+	argo := store.AddProject(model.Project{Name: "Argo", Maturity: model.Graduated})
+	store.AddMaintainer(model.Maintainer{
+		Name: "Alice", Email: "alice@example.com", GitHubAccount: "alice",
+		MaintainerStatus: model.ActiveMaintainer, CompanyID: &acme.ID,
+	}, argo.ID)
+	c := newFakeClient(t)
+	require.NoError(t, syncAll(ctx, store, c, "maintainerd"))
+
+	require.NoError(t, store.ArchiveProject(ctx, argo.ID))
+	require.NoError(t, store.ArchiveCompany(ctx, acme.ID))
+	require.NoError(t, syncAll(ctx, store, c, "maintainerd"))
+
+	var project apis.Project
+	require.NoError(t, c.Get(ctx, client.ObjectKey{Namespace: "maintainerd", Name: "argo"}, &project))
+	assert.NotNil(t, project.Spec.ArchivedAt)
+	assert.Empty(t, project.Spec.MaintainerRefs)
+	var company apis.Company
+	require.NoError(t, c.Get(ctx, client.ObjectKey{Namespace: "maintainerd", Name: "acme-corp"}, &company))
+	assert.NotNil(t, company.Spec.ArchivedAt)
+	var maintainer apis.Maintainer
+	require.NoError(t, c.Get(ctx, client.ObjectKey{Namespace: "maintainerd", Name: "alice-example.com"}, &maintainer))
+	assert.Nil(t, maintainer.Spec.ArchivedAt)
+	assert.Nil(t, maintainer.Spec.CompanyRef)
+	var membership apis.ProjectMembership
+	membershipKey := client.ObjectKey{Namespace: "maintainerd", Name: "argo-alice-example.com"}
+	require.NoError(t, c.Get(ctx, membershipKey, &membership))
+	assert.NotNil(t, membership.Spec.ArchivedAt)
+
+	require.NoError(t, store.RestoreProject(ctx, argo.ID))
+	require.NoError(t, syncAll(ctx, store, c, "maintainerd"))
+
+	require.NoError(t, c.Get(ctx, client.ObjectKey{Namespace: "maintainerd", Name: "argo"}, &project))
+	assert.Nil(t, project.Spec.ArchivedAt)
+	assert.Len(t, project.Spec.MaintainerRefs, 1)
+	require.NoError(t, c.Get(ctx, membershipKey, &membership))
+	assert.Nil(t, membership.Spec.ArchivedAt)
+}
+
+func TestSyncAllMembershipRoles(t *testing.T) {
+	ctx := context.Background()
+	store := dbtest.NewMemStore()
+	argo := store.AddProject(model.Project{Name: "Argo", Maturity: model.Graduated})
+	alice := store.AddMaintainer(model.Maintainer{
+		Name: "Alice", Email: "alice@example.com", GitHubAccount: "alice", MaintainerStatus: model.ActiveMaintainer,
+	}, argo.ID)
+	c := newFakeClient(t)
+	require.NoError(t, syncAll(ctx, store, c, "maintainerd"))
+
+	roles := model.MembershipRoles{model.RoleLead, model.RoleMaintainer}
+	require.NoError(t, store.SetMembershipRoles(ctx, alice.ID, argo.ID, roles, time.Now()))
+	require.NoError(t, syncAll(ctx, store, c, "maintainerd"))
+
+	var membership apis.ProjectMembership
+	require.NoError(t, c.Get(ctx, client.ObjectKey{Namespace: "maintainerd", Name: "argo-alice-example.com"}, &membership))
+	assert.Equal(t, []string{"lead", "maintainer"}, membership.Spec.Roles)
+}
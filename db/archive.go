@@ -0,0 +1,186 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"maintainerd/model"
+)
+
+// ErrNotArchived is returned when restoring a record that has not been archived.
+var ErrNotArchived = errors.New("record is not archived")
+
+// SetupJoinTables registers model.MaintainerProject as the maintainer_projects join table on conn, so association
+// queries skip memberships archived with their maintainer or project. Open, NewSQLStore and MigrateTo call it;
+// callers that build a *gorm.DB themselves and query memberships through it must call it too.
+func SetupJoinTables(conn *gorm.DB) error {
+	if err := conn.SetupJoinTable(&model.Maintainer{}, "Projects", &model.MaintainerProject{}); err != nil {
+		return fmt.Errorf("setup maintainer_projects join table: %w", err)
+	}
+	if err := conn.SetupJoinTable(&model.Project{}, "Maintainers", &model.MaintainerProject{}); err != nil {
+		return fmt.Errorf("setup maintainer_projects join table: %w", err)
+	}
+	return nil
+}
+
+// ArchiveReason is the reason recorded on the membership periods closed by archiving a maintainer or project.
+// Restoring reopens them.
+const ArchiveReason = "archived"
+
+// cascade names the rows archived and restored along with a record: those of model whose column holds its ID.
+// Memberships close their open membership periods when archived and reopen them when restored.
+type cascade struct {
+	model       interface{}
+	column      string
+	memberships bool
+}
+
+var (
+	maintainerCascades = []cascade{
+		{&model.MaintainerProject{}, "maintainer_id", true},
+		{&model.ServiceUserTeams{}, "maintainer_id", false},
+	}
+	projectCascades = []cascade{
+		{&model.MaintainerProject{}, "project_id", true},
+		{&model.ServiceTeam{}, "project_id", false},
+	}
+)
+
+// liveMembership matches the membership periods whose membership is live.
+const liveMembership = `EXISTS (SELECT 1 FROM maintainer_projects mp
+	WHERE mp.maintainer_id = membership_periods.maintainer_id AND mp.project_id = membership_periods.project_id
+	AND mp.deleted_at IS NULL)`
+
+// ArchiveMaintainer soft deletes the maintainer, their project memberships and their links to service teams, closing
+// their membership periods.
+func (s *SQLStore) ArchiveMaintainer(ctx context.Context, id uint) error {
+	return s.archive(ctx, &model.Maintainer{}, id, ErrMaintainerNotFound, maintainerCascades)
+}
+
+// RestoreMaintainer undoes ArchiveMaintainer, bringing back the memberships and service team links that were
+// archived with the maintainer and reopening their membership periods.
+func (s *SQLStore) RestoreMaintainer(ctx context.Context, id uint) error {
+	return s.restore(ctx, &model.Maintainer{}, id, ErrMaintainerNotFound, maintainerCascades)
+}
+
+// ArchiveProject soft deletes the project, its maintainer memberships and its service teams, closing the membership
+// periods.
+func (s *SQLStore) ArchiveProject(ctx context.Context, id uint) error {
+	return s.archive(ctx, &model.Project{}, id, ErrProjectNotFound, projectCascades)
+}
+
+// RestoreProject undoes ArchiveProject, bringing back the memberships and service teams archived with the project
+// and reopening the membership periods.
+func (s *SQLStore) RestoreProject(ctx context.Context, id uint) error {
+	return s.restore(ctx, &model.Project{}, id, ErrProjectNotFound, projectCascades)
+}
+
+// ArchiveCompany soft deletes the company. Its maintainers stay active but lose the affiliation until the company
+// is restored.
+func (s *SQLStore) ArchiveCompany(ctx context.Context, id uint) error {
+	return s.archive(ctx, &model.Company{}, id, ErrCompanyNotFound, nil)
+}
+
+// RestoreCompany undoes ArchiveCompany.
+func (s *SQLStore) RestoreCompany(ctx context.Context, id uint) error {
+	return s.restore(ctx, &model.Company{}, id, ErrCompanyNotFound, nil)
+}
+
+// ListArchivedMaintainers returns archived maintainers, with their companies, ordered by ID.
+func (s *SQLStore) ListArchivedMaintainers(ctx context.Context) ([]model.Maintainer, error) {
+	var maintainers []model.Maintainer
+	err := s.db.WithContext(ctx).Unscoped().
+		Preload("Company").Preload("Identities").
+		Where("deleted_at IS NOT NULL").
+		Order("id").
+		Find(&maintainers).Error
+	return maintainers, err
+}
+
+// ListArchivedProjects returns archived projects ordered by ID.
+func (s *SQLStore) ListArchivedProjects(ctx context.Context) ([]model.Project, error) {
+	var projects []model.Project
+	err := s.db.WithContext(ctx).Unscoped().Where("deleted_at IS NOT NULL").Order("id").Find(&projects).Error
+	return projects, err
+}
+
+// ListArchivedCompanies returns archived companies ordered by ID.
+func (s *SQLStore) ListArchivedCompanies(ctx context.Context) ([]model.Company, error) {
+	var companies []model.Company
+	err := s.db.WithContext(ctx).Unscoped().Where("deleted_at IS NOT NULL").Order("id").Find(&companies).Error
+	return companies, err
+}
+
+// archive stamps the live record m with ID id, and the live rows cascading from it, with the same deleted_at so that
+// restore can tell them apart from rows archived on their own.
+func (s *SQLStore) archive(ctx context.Context, m interface{}, id uint, notFound error, cascades []cascade) error {
+	// PostgreSQL keeps microseconds; truncating keeps the stamp comparable after a round trip.
+	at := time.Now().UTC().Truncate(time.Microsecond)
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(m).Where("id = ?", id).Update("deleted_at", at)
+		if res.Error != nil {
+			return fmt.Errorf("archive %d: %w", id, res.Error)
+		}
+		if res.RowsAffected == 0 {
+			return fmt.Errorf("%w: %d", notFound, id)
+		}
+		for _, c := range cascades {
+			if c.memberships {
+				err := tx.Model(&model.MembershipPeriod{}).
+					Where(c.column+" = ? AND ended_at IS NULL", id).
+					Where(liveMembership).
+					Updates(map[string]interface{}{"ended_at": at, "reason": ArchiveReason}).Error
+				if err != nil {
+					return fmt.Errorf("archive %d: close membership periods: %w", id, err)
+				}
+			}
+			if err := tx.Model(c.model).Where(c.column+" = ?", id).Update("deleted_at", at).Error; err != nil {
+				return fmt.Errorf("archive %d: %w", id, err)
+			}
+		}
+		return nil
+	})
+}
+
+func (s *SQLStore) restore(ctx context.Context, m interface{}, id uint, notFound error, cascades []cascade) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var stamps []sql.NullTime
+		if err := tx.Unscoped().Model(m).Where("id = ?", id).Pluck("deleted_at", &stamps).Error; err != nil {
+			return fmt.Errorf("restore %d: %w", id, err)
+		}
+		if len(stamps) == 0 {
+			return fmt.Errorf("%w: %d", notFound, id)
+		}
+		if !stamps[0].Valid {
+			return fmt.Errorf("%w: %d", ErrNotArchived, id)
+		}
+		at := stamps[0].Time
+		if err := tx.Unscoped().Model(m).Where("id = ?", id).Update("deleted_at", nil).Error; err != nil {
+			return fmt.Errorf("restore %d: %w", id, err)
+		}
+		for _, c := range cascades {
+			err := tx.Unscoped().Model(c.model).
+				Where(c.column+" = ? AND deleted_at = ?", id, at).
+				Update("deleted_at", nil).Error
+			if err != nil {
+				return fmt.Errorf("restore %d: %w", id, err)
+			}
+			if !c.memberships {
+				continue
+			}
+			err = tx.Model(&model.MembershipPeriod{}).
+				Where(c.column+" = ? AND ended_at = ? AND reason = ?", id, at, ArchiveReason).
+				Where(liveMembership).
+				Updates(map[string]interface{}{"ended_at": nil, "reason": ""}).Error
+			if err != nil {
+				return fmt.Errorf("restore %d: reopen membership periods: %w", id, err)
+			}
+		}
+		return nil
+	})
+}
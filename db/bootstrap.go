@@ -13,9 +13,6 @@ import (
 
 	"gorm.io/gorm/logger"
 
-	"google.golang.org/api/option"
-	"google.golang.org/api/sheets/v4"
-	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 )
 
@@ -32,9 +29,47 @@ const (
 	ParentProjectHdr     string = "Parent Project"
 	MaintainerFileRefHdr string = "OWNERS/MAINTAINERS"
 	MailingListAddrHdr   string = "Mailing List Address"
+	// RolesHdr is an optional column of comma separated membership roles, e.g. "lead, security-contact".
+	RolesHdr string = "Roles"
 )
 
-func BootstrapSQLite(dbPath, spreadsheetID, worksheetCredentialsPath, fossaToken string, seed bool) (*gorm.DB, error) {
+// Bootstrap opens the SQLite or PostgreSQL database identified by dsn (see Driver) and runs StageSchema on it and,
+// when seed is set, the stages that load the maintainers, projects and staff of source, and with a fossaToken FOSSA
+// data, into it. The outcome of each source row is returned as an ImportReport, nil without seed, which is also
+// stored as an ImportRun. A FOSSA import that fails is logged rather than returned, see LoadFossa. Cancelling ctx
+// stops the load between rows.
+func Bootstrap(ctx context.Context, dsn string, source Source, fossaToken string, seed bool) (*gorm.DB, *ImportReport, error) {
+	db, err := OpenSilent(dsn)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open DB: %w", err)
+	}
+
+	opts := StageOptions{Stages: []Stage{StageSchema}, FossaBestEffort: true}
+	if seed {
+		opts.Stages = append(opts.Stages, StageSheet, StageStaff)
+		opts.Source = source
+		if fossaToken == "" {
+			log.Println("bootstrap: no FOSSA token, FOSSA teams and users not loaded")
+		} else {
+			opts.Stages = append(opts.Stages, StageFossa)
+			opts.Fossa = fossa.NewClient(fossaToken)
+		}
+	}
+	run, err := RunStages(ctx, db, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !seed {
+		log.Println("bootstrap: database schema created but no seed data loaded")
+		return db, nil, nil
+	}
+	log.Printf("bootstrap: completed and loaded seed data into %s", RedactDSN(dsn))
+	return db, run.Import, nil
+}
+
+// OpenSilent is Open with SQL logging turned off, as the bootstrap stages run it: their lookups of records that do
+// not exist yet are expected.
+func OpenSilent(dsn string) (*gorm.DB, error) {
 	newLogger := logger.New(
 		log.New(os.Stdout, "\r\n", log.LstdFlags), // io writer
 		logger.Config{
@@ -45,407 +80,113 @@ func BootstrapSQLite(dbPath, spreadsheetID, worksheetCredentialsPath, fossaToken
 			Colorful:                  false,         // Disable color
 		},
 	)
-	db, err := gorm.Open(sqlite.Open(dbPath), &gorm.Config{
+	return Open(dsn, &gorm.Config{
 		Logger: newLogger,
 	})
-	// var s Store = NewSQLStore(db)
-	if err != nil {
-		return nil, fmt.Errorf("failed to open DB: %w", err)
-	}
-
-	if err := db.AutoMigrate(
-		&model.Company{},
-		&model.Foundation{},
-		&model.Project{},
-		&model.Maintainer{},
-		&model.StaffMember{},
-		&model.FoundationOfficer{},
-		&model.Collaborator{},
-		&model.MaintainerProject{},
-		&model.Service{},
-		&model.ServiceTeam{},
-		&model.ServiceUser{},
-		&model.ServiceUserTeams{},
-	); err != nil {
-		return nil, fmt.Errorf("auto-migration failed: %w", err)
-	}
-
-	if !seed {
-		log.Println("bootstrap: database schema created but no seed data loaded")
-		return db, nil
-	}
-
-	services := []model.Service{
-		{Name: "FOSSA", Description: "Static code check we use to ensure 3rd Party License Policy"},
-		{Name: "Service Desk", Description: "Jira"},
-		{Name: "cncf.groups.io", Description: "Mailing list channels"},
-		{Name: "Snyk", Description: "Static code checker for 3rd Party License Policy monitoring and compliance"},
-	}
-	if err := db.Transaction(func(tx *gorm.DB) error {
-		for _, service := range services {
-			if err := tx.FirstOrCreate(&service, model.Service{Name: service.Name}).Error; err != nil {
-				return fmt.Errorf("bootstrap: failed to insert service %s: %w", service.Name, err)
-			}
-		}
-		return nil
-	}); err != nil {
-		return nil, err
-	}
-
-	if err := loadMaintainersAndProjects(db, spreadsheetID, worksheetCredentialsPath); err != nil {
-		return nil, fmt.Errorf("bootstrap: failed to load maintainers and projects: %w", err)
-	}
-
-	if err := loadStaff(db, spreadsheetID, worksheetCredentialsPath); err != nil {
-		return nil, fmt.Errorf("bootstrap: failed to load staff: %w", err)
-	}
-
-	//fossaService := model.Service{Model: gorm.Model{ID: 1}, Name: "FOSSA"}
-	if err := loadFOSSA(db, fossaToken); err != nil {
-		return nil, fmt.Errorf("bootstrap: failed to load FOSSA projects: %w", err)
-	}
-
-	log.Printf("bootstrap: completed and loaded seed data into %s", dbPath)
-	return db, nil
 }
 
-// Reads data from spreadsheetID inserts it into db.
-func loadMaintainersAndProjects(db *gorm.DB, spreadsheetID, credentialsPath string) error {
-	ctx := context.Background()
-
-	srv, err := sheets.NewService(
-		ctx,
-		option.WithCredentialsFile(credentialsPath),
-		option.WithScopes(sheets.SpreadsheetsReadonlyScope),
-	)
-
+// loadMaintainersAndProjects syncs the projects and maintainers of source into db with SyncSheet, logs the changes
+// and returns the SyncReport.
+func loadMaintainersAndProjects(ctx context.Context, db *gorm.DB, source Source) (SyncReport, error) {
+	records, err := source.Records(ctx)
 	if err != nil {
-		return fmt.Errorf("maintainerd: backend: loadMaintainersAndProjects: unable to retrieve Sheets client: %w", err)
+		return SyncReport{}, fmt.Errorf("loadMaintainersAndProjects: read %s: %w", source, err)
 	}
-
-	rows, err := readSheetRows(
-		ctx,
-		srv,
-		spreadsheetID,
-		"Active!A:J",
-		ProjectHdr,
-		StatusHdr,
-		MaintainerFileRefHdr,
-		MailingListAddrHdr,
-	)
-
+	report, err := SyncSheet(ctx, db, records, false)
 	if err != nil {
-		return fmt.Errorf("maintainerd-backend: loadMaintainersAndProjects - readSheetRows: %w", err)
+		return SyncReport{}, fmt.Errorf("loadMaintainersAndProjects: sync: %w", err)
 	}
-
-	for _, row := range rows {
-		projectName := row[ProjectHdr]
-		if projectName == "" {
-			log.Printf("WARN, loadMaintainersAndProjects: skipping row with empty %q: %v", ProjectHdr, row)
-			continue
-		}
-
-		name := row[MaintainerNameHdr]
-		companyName := row[CompanyNameHdr]
-		email := row[EmailHdr]
-		github := row[GitHubHdr]
-		githubEmail := row[GitHubEmail]
-
-		maintainerRef := row[MaintainerFileRefHdr]
-		mailingList := row[MailingListAddrHdr]
-		var mailingListPtr *string
-		if mailingList != "" {
-			mailingListPtr = &mailingList
-		}
-
-		// Some sheets include rows that only exist to carry project metadata.
-		// Only create/update a maintainer if we have a stable identifier.
-		createMaintainer := email != ""
-		if !createMaintainer && (name != "" || github != "" || githubEmail != "" || companyName != "") {
-			log.Printf("WARN, loadMaintainersAndProjects: skipping maintainer row for project %q due to missing %q: %v", projectName, EmailHdr, row)
-		}
-
-		var parent model.Project
-		if parentName := row[ParentProjectHdr]; parentName != "" {
-			if err := db.Where("name = ?", parentName).
-				First(&parent).Error; err != nil {
-				if errors.Is(err, gorm.ErrRecordNotFound) {
-					log.Printf("WARN, parent project '%s' not found for project '%s', importing without parent", parentName, row[ProjectHdr])
-				} else {
-					log.Printf("ERR, error looking up parent project '%s' for project '%s': %v", parentName, row[ProjectHdr], err)
-				}
-			} else {
-				log.Printf("INFO, project '%s' will be associated with parent project '%s' (ID: %d)", row[ProjectHdr], parentName, parent.ID)
-			}
-		}
-
-		if err := db.Transaction(func(tx *gorm.DB) error {
-			var project model.Project
-			if parent.Name == "" {
-				project = model.Project{
-					Name:          projectName,
-					Maturity:      model.Maturity(row[StatusHdr]),
-					MaintainerRef: maintainerRef,
-					MailingList:   mailingListPtr,
-				}
-			} else {
-				project = model.Project{
-					Name:            projectName,
-					Maturity:        parent.Maturity,
-					MaintainerRef:   maintainerRef,
-					MailingList:     mailingListPtr,
-					ParentProjectID: &parent.ID,
-				}
-			}
-			if err := tx.FirstOrCreate(&project, model.Project{Name: project.Name}).Error; err != nil {
-				return fmt.Errorf("ERR, loadMaintainersAndProjects - failed calling FirstOrCreate on project %v: error %v", project, err)
-			}
-
-			if !createMaintainer {
-				return nil
-			}
-
-			company := model.Company{Name: companyName}
-			if companyName != "" {
-				if err := tx.FirstOrCreate(&company, model.Company{Name: company.Name}).Error; err != nil {
-					return fmt.Errorf("ERR, loadMaintainersAndProjects - failed calling FirstOrCreate on company %v: error %v", company, err)
-				}
-			}
-
-			maintainer := model.Maintainer{
-				Name:             name,
-				GitHubAccount:    github,
-				GitHubEmail:      githubEmail,
-				Email:            email,
-				MaintainerStatus: model.ActiveMaintainer,
-			}
-			if company.ID != 0 {
-				maintainer.CompanyID = &company.ID
-			}
-
-			if err := tx.Where("email = ?", email).FirstOrCreate(&maintainer).Error; err != nil {
-				return fmt.Errorf("ERR, loadMaintainersAndProjects - failed calling FirstOrCreate on maintainer %v: error %v", maintainer, err)
-			}
-
-			// Ensure the association (in case the maintainer existed already)
-			return tx.Model(&maintainer).Association("Projects").Append(&project)
-		}); err != nil {
-			log.Printf("WARN, loadMaintainersAndProjects Database transaction not committed, row skipped %v : error %v ", row, err)
-		}
-	}
-	return nil
+	LogSyncReport(report)
+	return report, nil
 }
 
-// Reads data from spreadsheetID inserts it into db.
-func loadStaff(db *gorm.DB, spreadsheetID, credentialsPath string) error {
-	ctx := context.Background()
-
-	srv, err := sheets.NewService(
-		ctx,
-		option.WithCredentialsFile(credentialsPath),
-		option.WithScopes(sheets.SpreadsheetsReadonlyScope),
-	)
-
-	if err != nil {
-		return fmt.Errorf("maintainerd: backend: loadStaff: unable to retrieve Sheets client: %w", err)
+// LogSyncReport logs each change and skipped record of report.
+func LogSyncReport(report SyncReport) {
+	for _, c := range report.Changes {
+		log.Printf("sheet sync: INF, %s", c)
 	}
+	for _, skipped := range report.Skipped {
+		log.Printf("sheet sync: WRN, skipped %s", skipped)
+	}
+	log.Printf("sheet sync: INF, %d changes, %d records skipped", len(report.Changes), len(report.Skipped))
+}
 
-	rows, err := readSheetRows(
-		ctx,
-		srv,
-		spreadsheetID,
-		"Staff!A:F",
-		FoundationHdr,
-	)
-
+// loadStaff creates or updates the staff members of source in db, matching them by email, and returns the outcome of
+// each row. Rows without a foundation or email are skipped.
+func loadStaff(ctx context.Context, db *gorm.DB, source Source) ([]ImportRow, error) {
+	staff, err := source.Staff(ctx)
 	if err != nil {
-		return fmt.Errorf("maintainerd-backend: loadStaff - readSheetRows: %w", err)
+		return nil, fmt.Errorf("loadStaff: read %s: %w", source, err)
 	}
-
-	for _, row := range rows {
-		foundationName := row[FoundationHdr]
-		name := row[StaffMemberNameHdr]
-		if name == "" {
-			// Backwards-compat if the sheet reuses the maintainer header.
-			name = row[MaintainerNameHdr]
-		}
-		email := row[EmailHdr]
-		github := row[GitHubHdr]
-		githubEmail := row[GitHubEmail]
-
-		if foundationName == "" && name == "" && email == "" && github == "" && githubEmail == "" {
-			continue
-		}
-
-		var missing []string
-		if foundationName == "" {
-			missing = append(missing, FoundationHdr)
-		}
-		if email == "" {
-			missing = append(missing, EmailHdr)
+	db = db.WithContext(ctx)
+	var rows []ImportRow
+	for _, r := range staff {
+		if err := ctx.Err(); err != nil {
+			return nil, err
 		}
-		if len(missing) > 0 {
-			log.Printf("WARN, loadStaff: skipping row due to missing %v: %v", missing, row)
+		row := ImportRow{RowRef: r.Ref, Subject: r.subject()}
+		if missing := r.missing(); len(missing) > 0 {
+			row.Outcome, row.Reason = ImportSkipped, fmt.Sprintf("no %v", missing)
+			rows = append(rows, row)
 			continue
 		}
-
 		if err := db.Transaction(func(tx *gorm.DB) error {
-			foundation := model.Foundation{Name: foundationName}
+			foundation := model.Foundation{Name: r.Foundation}
 			if err := tx.FirstOrCreate(&foundation, model.Foundation{Name: foundation.Name}).Error; err != nil {
-				return fmt.Errorf("loadStaff: failed to upsert foundation %q: %w", foundationName, err)
+				return fmt.Errorf("loadStaff: failed to upsert foundation %q: %w", r.Foundation, err)
 			}
 
 			var staff model.StaffMember
-			err := tx.Where("email = ?", email).First(&staff).Error
+			err := tx.Where("email = ?", r.Email).First(&staff).Error
 			switch {
 			case errors.Is(err, gorm.ErrRecordNotFound):
 				staff = model.StaffMember{
-					Name:          name,
-					Email:         email,
-					GitHubAccount: github,
-					GitHubEmail:   githubEmail,
+					Name:          r.Name,
+					Email:         r.Email,
+					GitHubAccount: r.GitHub,
+					GitHubEmail:   r.GitHubEmail,
 					FoundationID:  &foundation.ID,
 				}
 				if err := tx.Create(&staff).Error; err != nil {
-					return fmt.Errorf("loadStaff: failed to create staff member %q (%s): %w", name, email, err)
+					return fmt.Errorf("failed to create staff member: %w", err)
 				}
+				row.Outcome = ImportCreated
 				return nil
 			case err != nil:
-				return fmt.Errorf("loadStaff: failed to lookup staff member by email %q: %w", email, err)
+				return fmt.Errorf("failed to look up staff member by email: %w", err)
 			default:
+				row.Outcome = ImportUnchanged
+				same := func(stored, value string) bool {
+					return stored == value || model.IsMissing(stored) && model.IsMissing(value)
+				}
+				if staff.Name == r.Name && same(staff.GitHubAccount, r.GitHub) && same(staff.GitHubEmail, r.GitHubEmail) &&
+					staff.FoundationID != nil && *staff.FoundationID == foundation.ID {
+					return nil
+				}
 				updates := map[string]interface{}{
-					"name":            name,
-					"git_hub_account": github,
-					"git_hub_email":   githubEmail,
+					"name":            r.Name,
+					"git_hub_account": r.GitHub,
+					"git_hub_email":   r.GitHubEmail,
 					"foundation_id":   foundation.ID,
 				}
 				if err := tx.Model(&staff).Updates(updates).Error; err != nil {
-					return fmt.Errorf("loadStaff: failed to update staff member %q (%s): %w", name, email, err)
+					return fmt.Errorf("failed to update staff member: %w", err)
 				}
+				row.Outcome = ImportUpdated
 				return nil
 			}
 		}); err != nil {
-			log.Printf("WARN, loadStaff: database transaction not committed, row skipped %v : error %v ", row, err)
-		}
-	}
-	return nil
-}
-
-// readSheetRows returns every row as a map keyed by the header row and carries forward the last non‐empty Project and
-// Status values when those cells are blank or missing.
-// The readRange must include the header row.
-// The
-func readSheetRows(ctx context.Context, srv *sheets.Service, spreadsheetID, readRange string, carryForwardHeaders ...string) ([]map[string]string, error) {
-	resp, err := srv.Spreadsheets.Values.
-		Get(spreadsheetID, readRange).
-		Context(ctx).
-		Do()
-	if err != nil {
-		return nil, fmt.Errorf("db: Using %s unable to retrieve worksheet data (%s): %w", spreadsheetID, readRange, err)
-	}
-	if len(resp.Values) == 0 {
-		return nil, fmt.Errorf("db: %s worksheet is empty", spreadsheetID)
-	}
-
-	// First row → headers
-	headers := make([]string, len(resp.Values[0]))
-	for i, cell := range resp.Values[0] {
-		headers[i] = strings.TrimSpace(fmt.Sprint(cell))
-	}
-
-	carryForwardIdx := make(map[int]struct{}, len(carryForwardHeaders))
-	for _, hdr := range carryForwardHeaders {
-		for i, h := range headers {
-			if h == hdr {
-				carryForwardIdx[i] = struct{}{}
-				break
-			}
+			log.Printf("loadStaff: WRN, database transaction not committed, staff member %s skipped: %v", r.subject(), err)
+			row.Outcome, row.Reason = ImportFailed, err.Error()
 		}
+		rows = append(rows, row)
 	}
-
-	var rows []map[string]string
-	lastVals := make(map[int]string, len(carryForwardIdx))
-
-	// Remaining rows → maps
-	for _, r := range resp.Values[1:] {
-		rowMap := make(map[string]string, len(headers))
-		hasAnyValue := false
-
-		for i, h := range headers {
-			// read raw cell if present
-			var cellVal string
-			if i < len(r) {
-				cellVal = strings.TrimSpace(fmt.Sprint(r[i]))
-			}
-			if cellVal != "" {
-				hasAnyValue = true
-			}
-
-			if _, ok := carryForwardIdx[i]; ok {
-				if cellVal != "" {
-					lastVals[i] = cellVal
-				}
-				rowMap[h] = lastVals[i]
-				continue
-			}
-
-			rowMap[h] = cellVal
-		}
-
-		// Skip fully empty rows.
-		if !hasAnyValue {
-			continue
-		}
-
-		rows = append(rows, rowMap)
-	}
-
 	return rows, nil
 }
 
-// loadFOSSA synchronizes all data in CNCF FOSSA
-func loadFOSSA(db *gorm.DB, token string) error {
-	users, teams, err := FetchFossaData(token)
-	if err != nil {
-		return fmt.Errorf("loadFOSSA: fetching FOSSA data: %s", err)
-	}
-	log.Printf("INF, FetchFossaData found %d users, and %d teams\n", len(users), len(teams))
-
-	for _, user := range users {
-		var maintainer *model.Maintainer     // A registered maintainer
-		var collaborator *model.Collaborator // A contributor who has been signed up
-		var su *model.ServiceUser
-		ghName := safeGitHubName(user.GitHub.Name)
-		if su, err = FirstOrCreateServiceUser(db, user); err != nil {
-			log.Printf("ERR, FirstOrCreateServiceUser, error creating service user for %s: %v", user.Email, err)
-		}
-		if su == nil {
-			log.Fatalf("ERR, FirstOrCreateServiceUser, service user for %s is nil! Exiting!", user.Email)
-		}
-
-		if maintainer = MapFossaUserToMaintainer(db, user.Email, ghName); maintainer != nil {
-			log.Printf("INFO, MapFossaUserToMaintainer: %s was not used for maintainer registration", user.Email)
-		} else {
-			if collaborator = MapFossaUserCollaborator(db, user.Email, ghName, user); collaborator == nil {
-				log.Printf("ERR, MapFossaUserCollaborator: error mapping service user using %s: %v", user.Email, err)
-			}
-		}
-		st, err := CreateServiceTeamsForUser(db, user.TeamUsers)
-		if err != nil {
-			log.Printf("ERR, CreateServiceTeamsForUser failed for user %d (%s): %v", user.ID, user.Email, err)
-			continue
-		}
-
-		if err := LinkServiceUserToTeam(db, su, st, maintainer, collaborator); err != nil {
-			log.Printf("ERR, LinkServiceUserToTeam failed for user %d (%s): %v", user.ID, user.Email, err)
-		}
-	}
-
-	return nil
-}
-
 // CreateServiceTeamsForUser takes a @db connection, and an array of FOSSA TeamUsers and adds them to the DB.
 func CreateServiceTeamsForUser(
+	ctx context.Context,
 	db *gorm.DB,
 	teamUsers []struct {
 		RoleID int `json:"roleId"`
@@ -459,7 +200,7 @@ func CreateServiceTeamsForUser(
 	var teams []*model.ServiceTeam
 	var errMessages []string
 	s := NewSQLStore(db)
-	projects, err := s.GetProjectMapByName()
+	projects, err := s.GetProjectMapByName(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("CreateServiceTeamsForUser: GetProjectMapByName failed to get project map: %v", err)
 	}
@@ -472,7 +213,7 @@ func CreateServiceTeamsForUser(
 				ProjectID:       project.ID,
 				ProjectName:     &project.Name,
 			}
-			err := db.Where("service_team_id = ?", team.Team.ID).
+			err := db.WithContext(ctx).Where("service_team_id = ?", team.Team.ID).
 				FirstOrCreate(st).Error
 			if err != nil {
 				msg := fmt.Sprintf("CreateServiceTeamsForUser: failed for team %d (%s): %v", team.Team.ID, team.Team.Name, err)
@@ -532,28 +273,30 @@ func MapFossaUserCollaborator(db *gorm.DB, email string, github string, user fos
 // MapFossaUserToMaintainer attempts to match a FOSSA user to a registered Maintainer.
 // returns a *model.maintainer if found, nil if not found
 func MapFossaUserToMaintainer(db *gorm.DB, email string, github string) *model.Maintainer {
-	var m model.Maintainer
-
-	if github != "" {
-		if err := db.
-			Where("LOWER(git_hub_account) = ?", strings.ToLower(github)).
-			First(&m).Error; err == nil {
-			return &m
-		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil
-		}
-	}
-
-	if err := db.
-		Where("LOWER(email) = ? OR LOWER(git_hub_email) = ?", strings.ToLower(email), strings.ToLower(email)).
-		First(&m).Error; err == nil {
-		return &m
-	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+	m, err := maintainerByAnyIdentity(db,
+		model.MaintainerIdentity{Kind: model.IdentityGitHub, Value: github},
+		model.MaintainerIdentity{Kind: model.IdentityEmail, Value: email},
+	)
+	if err != nil {
+		// No match in the registered maintainers or their identities
 		return nil
 	}
+	return m
+}
 
-	// No match in the registered maintainers on the maintainer's table
-	return nil
+// maintainerByAnyIdentity returns the maintainer known by the first of identities that matches one, skipping empty
+// identities, or an ErrMaintainerNotFound error.
+func maintainerByAnyIdentity(tx *gorm.DB, identities ...model.MaintainerIdentity) (*model.Maintainer, error) {
+	for _, identity := range identities {
+		if strings.TrimSpace(identity.Value) == "" {
+			continue
+		}
+		m, err := maintainerByIdentity(tx, identity)
+		if !errors.Is(err, ErrMaintainerNotFound) {
+			return m, err
+		}
+	}
+	return nil, fmt.Errorf("%w: no identity of %v matched", ErrMaintainerNotFound, identities)
 }
 
 func LinkServiceUserToTeam(
@@ -677,19 +420,17 @@ func MapFossaUserToMaintainerOrCollaborator(db *gorm.DB, user fossa.User) (model
 	var c model.Collaborator
 
 	// Do we have a maintainer that matches this fossa User?
-	if err := db.Where("LOWER(email) = ?", strings.ToLower(user.Email)).
-		First(&m).Error; err == nil {
-		return m, c, nil
-	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+	if found, err := maintainerByIdentity(db, model.MaintainerIdentity{Kind: model.IdentityEmail, Value: user.Email}); err == nil {
+		return *found, c, nil
+	} else if !errors.Is(err, ErrMaintainerNotFound) {
 		return m, c, fmt.Errorf("query error during email lookup: %w", err)
 	}
 
 	// Do we have the Maintainer that has a GitHub handle match? (if present in FOSSA)
 	if *user.GitHub.Name != "" {
-		if err := db.Where("LOWER(git_hub_account) = ?", strings.ToLower(*user.GitHub.Name)).
-			First(&m).Error; err == nil {
-			return m, c, nil
-		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		if found, err := maintainerByIdentity(db, model.MaintainerIdentity{Kind: model.IdentityGitHub, Value: *user.GitHub.Name}); err == nil {
+			return *found, c, nil
+		} else if !errors.Is(err, ErrMaintainerNotFound) {
 			// Create a Collaborator record
 			c = model.Collaborator{
 				Model:         gorm.Model{},
@@ -719,25 +460,3 @@ func MapFossaUserToMaintainerOrCollaborator(db *gorm.DB, user fossa.User) (model
 	}
 	return m, c, nil
 }
-
-func FetchFossaData(token string) ([]fossa.User, []fossa.Team, interface{}) {
-	fossaClient := fossa.NewClient(token)
-
-	users, err := fossaClient.FetchUsers()
-	if err != nil {
-		return nil, nil, err
-	}
-
-	teams, err := fossaClient.FetchTeams()
-	if err != nil {
-		return nil, nil, err
-	}
-
-	return users, teams, nil
-}
-func safeGitHubName(ghName *string) string {
-	if ghName != nil {
-		return *ghName
-	}
-	return ""
-}
@@ -0,0 +1,430 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"maintainerd/model"
+)
+
+// FindCompany returns the live company with an alias whose key is the key of name, see model.CompanyKey. Companies
+// recorded without aliases are matched by the key of their name.
+func (s *SQLStore) FindCompany(ctx context.Context, name string) (*model.Company, error) {
+	company, err := findCompany(s.db.WithContext(ctx), name)
+	if err != nil {
+		return nil, err
+	}
+	if company.DeletedAt.Valid {
+		return nil, fmt.Errorf("%w: %q is archived", ErrCompanyNotFound, name)
+	}
+	return company, nil
+}
+
+// AddCompanyAlias makes alias a spelling of the company's name. Adding an alias the company already has does nothing.
+func (s *SQLStore) AddCompanyAlias(ctx context.Context, companyID uint, alias string) error {
+	key := model.CompanyKey(alias)
+	if key == "" {
+		return fmt.Errorf("company alias %q has no letters or digits", alias)
+	}
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := exists(tx, &model.Company{}, companyID, ErrCompanyNotFound); err != nil {
+			return err
+		}
+		var existing model.CompanyAlias
+		err := tx.Preload("Company", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }).
+			Where("key = ?", key).First(&existing).Error
+		switch {
+		case err == nil && existing.CompanyID == companyID:
+			return nil
+		case err == nil:
+			return fmt.Errorf("%w: %q is an alias of company %d (%s)", ErrCompanyAliasTaken, alias, existing.CompanyID, existing.Company.Name)
+		case !errors.Is(err, gorm.ErrRecordNotFound):
+			return fmt.Errorf("add alias %q to company %d: %w", alias, companyID, err)
+		}
+		return addCompanyAlias(tx, companyID, alias)
+	})
+}
+
+// SetMaintainerCompany changes the maintainer's company, closing their open affiliation period at at with reason and
+// opening one for the new company.
+func (s *SQLStore) SetMaintainerCompany(ctx context.Context, maintainerID uint, companyID *uint, reason string, at time.Time) error {
+	at = historyTime(at)
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var m model.Maintainer
+		if err := tx.First(&m, maintainerID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("%w: %d", ErrMaintainerNotFound, maintainerID)
+			}
+			return fmt.Errorf("set company of maintainer %d: %w", maintainerID, err)
+		}
+		if companyID != nil {
+			if err := exists(tx, &model.Company{}, *companyID, ErrCompanyNotFound); err != nil {
+				return err
+			}
+		}
+		return setMaintainerCompany(tx, m, companyID, reason, at)
+	})
+}
+
+// MaintainerAffiliations returns the maintainer's affiliation periods with their companies, archived ones included.
+func (s *SQLStore) MaintainerAffiliations(ctx context.Context, maintainerID uint) ([]model.AffiliationPeriod, error) {
+	tx := s.db.WithContext(ctx).Unscoped().Session(&gorm.Session{})
+	if err := exists(tx, &model.Maintainer{}, maintainerID, ErrMaintainerNotFound); err != nil {
+		return nil, err
+	}
+	var periods []model.AffiliationPeriod
+	err := tx.Preload("Company", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }).
+		Where("maintainer_id = ?", maintainerID).
+		Order("started_at, id").
+		Find(&periods).Error
+	return periods, err
+}
+
+// findCompany returns the company, archived or not, known by name: the company of the alias with its key, or else the
+// oldest live company whose name has that key.
+func findCompany(tx *gorm.DB, name string) (*model.Company, error) {
+	key := model.CompanyKey(name)
+	if key == "" {
+		return nil, fmt.Errorf("%w: %q", ErrCompanyNotFound, name)
+	}
+	var alias model.CompanyAlias
+	err := tx.Preload("Company", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }).
+		Where("key = ?", key).First(&alias).Error
+	if err == nil {
+		return &alias.Company, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("find company %q: %w", name, err)
+	}
+	var companies []model.Company
+	if err := tx.Order("id").Find(&companies).Error; err != nil {
+		return nil, fmt.Errorf("find company %q: %w", name, err)
+	}
+	for _, c := range companies {
+		if model.CompanyKey(c.Name) == key {
+			return &c, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %q", ErrCompanyNotFound, name)
+}
+
+// resolveCompany returns the live company known by name, creating it, with name as its first alias, if there is
+// none. created reports whether it did.
+func resolveCompany(tx *gorm.DB, name string) (company model.Company, created bool, err error) {
+	name = strings.TrimSpace(name)
+	found, err := findCompany(tx, name)
+	switch {
+	case err == nil && found.DeletedAt.Valid:
+		return *found, false, fmt.Errorf("company %q is archived as %q", name, found.Name)
+	case err == nil:
+		return *found, false, addCompanyAlias(tx, found.ID, name)
+	case !errors.Is(err, ErrCompanyNotFound):
+		return company, false, err
+	}
+	company = model.Company{Name: name}
+	if err := tx.Create(&company).Error; err != nil {
+		return company, false, fmt.Errorf("create company %q: %w", name, err)
+	}
+	return company, true, addCompanyAlias(tx, company.ID, name)
+}
+
+// addCompanyAlias records name as an alias of the company, unless its key is taken.
+func addCompanyAlias(tx *gorm.DB, companyID uint, name string) error {
+	key := model.CompanyKey(name)
+	if key == "" {
+		return nil
+	}
+	alias := model.CompanyAlias{CompanyID: companyID, Key: key, Name: name}
+	err := tx.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "key"}}, DoNothing: true}).
+		Omit(clause.Associations).Create(&alias).Error
+	if err != nil {
+		return fmt.Errorf("add alias %q to company %d: %w", name, companyID, err)
+	}
+	return nil
+}
+
+// setMaintainerCompany is SetMaintainerCompany within tx for the maintainer m.
+func setMaintainerCompany(tx *gorm.DB, m model.Maintainer, companyID *uint, reason string, at time.Time) error {
+	if m.CompanyID == nil && companyID == nil || m.CompanyID != nil && companyID != nil && *m.CompanyID == *companyID {
+		return nil
+	}
+	if err := tx.Model(&model.Maintainer{}).Where("id = ?", m.ID).Update("company_id", companyID).Error; err != nil {
+		return fmt.Errorf("set company of maintainer %d: %w", m.ID, err)
+	}
+	err := tx.Model(&model.AffiliationPeriod{}).
+		Where("maintainer_id = ? AND ended_at IS NULL", m.ID).
+		Updates(map[string]interface{}{"ended_at": at, "reason": reason}).Error
+	if err != nil {
+		return fmt.Errorf("close affiliation of maintainer %d: %w", m.ID, err)
+	}
+	if companyID == nil {
+		return nil
+	}
+	return openAffiliation(tx, m.ID, *companyID, at)
+}
+
+// openAffiliation records that the maintainer started working for the company at at.
+func openAffiliation(tx *gorm.DB, maintainerID, companyID uint, at time.Time) error {
+	period := model.AffiliationPeriod{MaintainerID: maintainerID, CompanyID: companyID, StartedAt: at}
+	if err := tx.Omit(clause.Associations).Create(&period).Error; err != nil {
+		return fmt.Errorf("open affiliation of maintainer %d with company %d: %w", maintainerID, companyID, err)
+	}
+	return nil
+}
+
+// A CompanyMerge describes the merge of a duplicate company into the company that survives it.
+type CompanyMerge struct {
+	Survivor    model.Company `json:"-"`
+	Duplicate   model.Company `json:"-"`
+	Maintainers int64         `json:"maintainers"` // whose company was the duplicate
+	Periods     int64         `json:"periods"`     // affiliation periods repointed
+	Aliases     int64         `json:"aliases"`     // moved to the survivor
+}
+
+func (m CompanyMerge) String() string {
+	return fmt.Sprintf("company %d (%s) merged into %d (%s): %d maintainers, %d affiliation periods, %d aliases moved",
+		m.Duplicate.ID, m.Duplicate.Name, m.Survivor.ID, m.Survivor.Name, m.Maintainers, m.Periods, m.Aliases)
+}
+
+// MergeCompanies folds the live company with duplicateID into the one with survivorID: maintainers, affiliation
+// periods and aliases are repointed to the survivor, which takes the duplicate's name as an alias, and the duplicate
+// is archived. The merge is audit logged. With dryRun nothing is written. It returns ErrInvalidMerge for a company
+// merged into itself.
+func MergeCompanies(ctx context.Context, conn *gorm.DB, survivorID, duplicateID uint, dryRun bool) (CompanyMerge, error) {
+	var merge CompanyMerge
+	if survivorID == duplicateID {
+		return merge, fmt.Errorf("%w: company %d cannot be merged into itself", ErrInvalidMerge, survivorID)
+	}
+	err := conn.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		if merge, err = mergeCompany(tx, survivorID, duplicateID); err != nil {
+			return err
+		}
+		if dryRun {
+			return errDryRun
+		}
+		return nil
+	})
+	if errors.Is(err, errDryRun) {
+		err = nil
+	}
+	return merge, err
+}
+
+// NormalizeCompanies merges the live companies whose names have the same model.CompanyKey, e.g. "Google" and "Google
+// LLC", into the one with the most maintainers, the oldest of them on a tie, and records every live company's name as
+// its alias. With dryRun nothing is written.
+func NormalizeCompanies(ctx context.Context, conn *gorm.DB, dryRun bool) ([]CompanyMerge, error) {
+	var merges []CompanyMerge
+	err := conn.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var companies []model.Company
+		if err := tx.Order("id").Find(&companies).Error; err != nil {
+			return fmt.Errorf("list companies: %w", err)
+		}
+		var counts []struct {
+			CompanyID uint
+			N         int
+		}
+		err := tx.Model(&model.Maintainer{}).Select("company_id, COUNT(*) AS n").
+			Where("company_id IS NOT NULL").Group("company_id").Scan(&counts).Error
+		if err != nil {
+			return fmt.Errorf("count maintainers per company: %w", err)
+		}
+		maintainers := map[uint]int{}
+		for _, c := range counts {
+			maintainers[c.CompanyID] = c.N
+		}
+		groups := map[string][]model.Company{}
+		var keys []string
+		for _, c := range companies {
+			key := model.CompanyKey(c.Name)
+			if key == "" {
+				continue
+			}
+			if groups[key] == nil {
+				keys = append(keys, key)
+			}
+			groups[key] = append(groups[key], c)
+		}
+		for _, key := range keys {
+			group := groups[key]
+			sort.SliceStable(group, func(i, j int) bool { return maintainers[group[i].ID] > maintainers[group[j].ID] })
+			for _, dup := range group[1:] {
+				merge, err := mergeCompany(tx, group[0].ID, dup.ID)
+				if err != nil {
+					return err
+				}
+				merges = append(merges, merge)
+			}
+			if err := addCompanyAlias(tx, group[0].ID, group[0].Name); err != nil {
+				return err
+			}
+		}
+		if dryRun {
+			return errDryRun
+		}
+		return nil
+	})
+	if errors.Is(err, errDryRun) {
+		err = nil
+	}
+	return merges, err
+}
+
+// mergeCompany is MergeCompanies within tx.
+func mergeCompany(tx *gorm.DB, survivorID, duplicateID uint) (CompanyMerge, error) {
+	var merge CompanyMerge
+	for _, c := range []struct {
+		id      uint
+		company *model.Company
+	}{{survivorID, &merge.Survivor}, {duplicateID, &merge.Duplicate}} {
+		if err := tx.First(c.company, c.id).Error; errors.Is(err, gorm.ErrRecordNotFound) {
+			return merge, fmt.Errorf("%w: %d", ErrCompanyNotFound, c.id)
+		} else if err != nil {
+			return merge, err
+		}
+	}
+	for _, ref := range []struct {
+		model interface{}
+		count *int64
+	}{
+		{&model.Maintainer{}, &merge.Maintainers},
+		{&model.AffiliationPeriod{}, &merge.Periods},
+		{&model.CompanyAlias{}, &merge.Aliases},
+	} {
+		res := tx.Unscoped().Model(ref.model).Where("company_id = ?", duplicateID).Update("company_id", survivorID)
+		if res.Error != nil {
+			return merge, fmt.Errorf("merge company %d into %d: %w", duplicateID, survivorID, res.Error)
+		}
+		*ref.count = res.RowsAffected
+	}
+	if err := addCompanyAlias(tx, survivorID, merge.Duplicate.Name); err != nil {
+		return merge, err
+	}
+	if err := tx.Delete(&model.Company{}, duplicateID).Error; err != nil {
+		return merge, fmt.Errorf("archive company %d: %w", duplicateID, err)
+	}
+	metadata, err := json.Marshal(merge)
+	if err != nil {
+		return merge, err
+	}
+	err = tx.Create(&model.AuditLog{
+		Action:   "MERGE_COMPANY",
+		Message:  fmt.Sprintf("Merged company %d (%s) into company %d (%s)", duplicateID, merge.Duplicate.Name, survivorID, merge.Survivor.Name),
+		Metadata: string(metadata),
+	}).Error
+	if err != nil {
+		return merge, fmt.Errorf("write audit log: %w", err)
+	}
+	return merge, nil
+}
+
+// Unaffiliated is the company CompanyReport counts maintainers without a company under.
+const Unaffiliated = "(unaffiliated)"
+
+// A CompanyShare is how many of a project's maintainers worked for a company.
+type CompanyShare struct {
+	Company     string `json:"company"`
+	Maintainers int    `json:"maintainers"`
+}
+
+// ProjectCompanies are the companies a project's maintainers worked for on a date, which CNCF looks at for graduation:
+// a graduated project needs maintainers from at least two organizations.
+type ProjectCompanies struct {
+	Project     string         `json:"project"`
+	Maturity    model.Maturity `json:"maturity"`
+	Maintainers int            `json:"maintainers"`
+	// Companies has the most represented company first, and Unaffiliated among them if any maintainer had no company.
+	Companies []CompanyShare `json:"companies"`
+}
+
+// CompanyCount returns how many companies the maintainers worked for, not counting Unaffiliated.
+func (p ProjectCompanies) CompanyCount() int {
+	n := 0
+	for _, c := range p.Companies {
+		if c.Company != Unaffiliated {
+			n++
+		}
+	}
+	return n
+}
+
+// LargestShare returns the company with the most maintainers, not counting Unaffiliated, and the fraction of the
+// project's maintainers it had.
+func (p ProjectCompanies) LargestShare() (CompanyShare, float64) {
+	for _, c := range p.Companies {
+		if c.Company != Unaffiliated {
+			return c, float64(c.Maintainers) / float64(p.Maintainers)
+		}
+	}
+	return CompanyShare{}, 0
+}
+
+// CompanyReport returns, for each live project ordered by name, how many of its maintainers worked for each company
+// on the date on, from membership and affiliation periods. It names no maintainers.
+func CompanyReport(ctx context.Context, conn *gorm.DB, on time.Time) ([]ProjectCompanies, error) {
+	on = historyTime(on)
+	conn = conn.WithContext(ctx)
+	var projects []model.Project
+	if err := conn.Order("name").Find(&projects).Error; err != nil {
+		return nil, fmt.Errorf("company report: %w", err)
+	}
+	var members []model.MembershipPeriod
+	err := conn.Select("DISTINCT maintainer_id, project_id").
+		Where("started_at <= ? AND (ended_at IS NULL OR ended_at > ?)", on, on).
+		Find(&members).Error
+	if err != nil {
+		return nil, fmt.Errorf("company report: %w", err)
+	}
+	var affiliations []model.AffiliationPeriod
+	err = conn.Preload("Company", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }).
+		Where("started_at <= ? AND (ended_at IS NULL OR ended_at > ?)", on, on).
+		Order("started_at, id").
+		Find(&affiliations).Error
+	if err != nil {
+		return nil, fmt.Errorf("company report: %w", err)
+	}
+	companyOf := map[uint]string{}
+	for _, a := range affiliations {
+		companyOf[a.MaintainerID] = a.Company.Name
+	}
+	counts := map[uint]map[string]int{}
+	for _, m := range members {
+		company, ok := companyOf[m.MaintainerID]
+		if !ok {
+			company = Unaffiliated
+		}
+		if counts[m.ProjectID] == nil {
+			counts[m.ProjectID] = map[string]int{}
+		}
+		counts[m.ProjectID][company]++
+	}
+	report := make([]ProjectCompanies, 0, len(projects))
+	for _, p := range projects {
+		pc := ProjectCompanies{Project: p.Name, Maturity: p.Maturity, Companies: []CompanyShare{}}
+		for company, n := range counts[p.ID] {
+			pc.Maintainers += n
+			pc.Companies = append(pc.Companies, CompanyShare{Company: company, Maintainers: n})
+		}
+		sort.Slice(pc.Companies, func(i, j int) bool {
+			a, b := pc.Companies[i], pc.Companies[j]
+			if (a.Company == Unaffiliated) != (b.Company == Unaffiliated) {
+				return b.Company == Unaffiliated
+			}
+			if a.Maintainers != b.Maintainers {
+				return a.Maintainers > b.Maintainers
+			}
+			return a.Company < b.Company
+		})
+		report = append(report, pc)
+	}
+	return report, nil
+}
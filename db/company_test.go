@@ -0,0 +1,204 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"maintainerd/model"
+)
+
+func TestCompanyKey(t *testing.T) {
+	for name, key := range map[string]string{
+		"Google":                   "google",
+		"Google LLC":               "google",
+		"  google  ":               "google",
+		"Red Hat, Inc.":            "red hat",
+		"Microsoft Corporation":    "microsoft",
+		"SAP SE":                   "sap",
+		"Booz Allen Hamilton Inc":  "booz allen hamilton",
+		"Procter & Gamble Co.":     "procter gamble",
+		"O'Reilly Media":           "oreilly media",
+		"Giant Swarm GmbH":         "giant swarm",
+		"Limited":                  "limited",
+		"...":                      "",
+		"Deutsche Telekom AG, Ltd": "deutsche telekom",
+	} {
+		assert.Equal(t, key, model.CompanyKey(name), name)
+	}
+}
+
+func TestSyncSheetCanonicalizesCompanies(t *testing.T) {
+	conn := openEmptyDB(t)
+	require.NoError(t, Migrate(conn))
+	ctx := t.Context()
+	records := Records{
+		Projects: []ProjectRecord{{Name: "argo", Maturity: model.Graduated}},
+		Maintainers: []MaintainerRecord{
+			{Project: "argo", Name: "Alice", Company: "Google", Email: "alice@example.com", GitHub: "alice"},
+			{Project: "argo", Name: "Bob", Company: "Google LLC", Email: "bob@example.com", GitHub: "bob"},
+			{Project: "argo", Name: "Carol", Company: "google", Email: "carol@example.com", GitHub: "carol"},
+		},
+	}
+	_, err := SyncSheet(ctx, conn, records, false)
+	require.NoError(t, err)
+	var companies []model.Company
+	require.NoError(t, conn.Find(&companies).Error)
+	require.Len(t, companies, 1)
+	assert.Equal(t, "Google", companies[0].Name)
+
+	// Bob moves to Initech, then spells his company differently; only the move is recorded.
+	records.Maintainers[1].Company = "Initech"
+	_, err = SyncSheet(ctx, conn, records, false)
+	require.NoError(t, err)
+	records.Maintainers[1].Company = "Initech, Inc."
+	report, err := SyncSheet(ctx, conn, records, false)
+	require.NoError(t, err)
+	assert.Empty(t, report.Changes)
+
+	store := NewSQLStore(conn)
+	bob, err := store.FindMaintainerByIdentity(ctx, model.MaintainerIdentity{Kind: model.IdentityGitHub, Value: "bob"})
+	require.NoError(t, err)
+	assert.Equal(t, "Initech", bob.Company.Name)
+	periods, err := store.MaintainerAffiliations(ctx, bob.ID)
+	require.NoError(t, err)
+	require.Len(t, periods, 2)
+	assert.Equal(t, "Google", periods[0].Company.Name)
+	require.NotNil(t, periods[0].EndedAt)
+	assert.Equal(t, "changed in the maintainer sheet", periods[0].Reason)
+	assert.Equal(t, "Initech", periods[1].Company.Name)
+	assert.Nil(t, periods[1].EndedAt)
+
+	found, err := store.FindCompany(ctx, "INITECH INC")
+	require.NoError(t, err)
+	assert.Equal(t, bob.Company.ID, found.ID)
+	_, err = store.FindCompany(ctx, "Globex")
+	assert.ErrorIs(t, err, ErrCompanyNotFound)
+}
+
+func TestNormalizeAndMergeCompanies(t *testing.T) {
+	conn := openEmptyDB(t)
+	require.NoError(t, Migrate(conn))
+	ctx := t.Context()
+	at := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	// Companies created before aliases existed, one spelling per maintainer.
+	companies := map[string]*model.Company{}
+	for _, name := range []string{"google", "Google LLC", "Red Hat", "Red Hat, Inc.", "Initech"} {
+		c := model.Company{Name: name}
+		require.NoError(t, conn.Create(&c).Error)
+		companies[name] = &c
+	}
+	store := NewSQLStore(conn)
+	for i, name := range []string{"Google LLC", "Google LLC", "google", "Red Hat, Inc."} {
+		m := model.Maintainer{Email: string(rune('a'+i)) + "@example.com", MaintainerStatus: model.ActiveMaintainer}
+		require.NoError(t, conn.Create(&m).Error)
+		require.NoError(t, store.SetMaintainerCompany(ctx, m.ID, &companies[name].ID, "", at))
+	}
+
+	merges, err := NormalizeCompanies(ctx, conn, true)
+	require.NoError(t, err)
+	require.Len(t, merges, 2)
+	var n int64
+	require.NoError(t, conn.Model(&model.Company{}).Count(&n).Error)
+	assert.EqualValues(t, 5, n, "a dry run changes nothing")
+
+	merges, err = NormalizeCompanies(ctx, conn, false)
+	require.NoError(t, err)
+	require.Len(t, merges, 2)
+	assert.Equal(t, "Google LLC", merges[0].Survivor.Name, "the spelling most maintainers use survives")
+	assert.Equal(t, "google", merges[0].Duplicate.Name)
+	assert.EqualValues(t, 1, merges[0].Maintainers)
+	assert.EqualValues(t, 1, merges[0].Periods)
+	assert.Equal(t, "Red Hat, Inc.", merges[1].Survivor.Name)
+	var live []string
+	require.NoError(t, conn.Model(&model.Company{}).Order("name").Pluck("name", &live).Error)
+	assert.Equal(t, []string{"Google LLC", "Initech", "Red Hat, Inc."}, live)
+	found, err := store.FindCompany(ctx, "Google")
+	require.NoError(t, err)
+	assert.Equal(t, "Google LLC", found.Name)
+	var audit int64
+	require.NoError(t, conn.Model(&model.AuditLog{}).Where("action = ?", "MERGE_COMPANY").Count(&audit).Error)
+	assert.EqualValues(t, 2, audit)
+
+	merges, err = NormalizeCompanies(ctx, conn, false)
+	require.NoError(t, err)
+	assert.Empty(t, merges, "normalizing twice merges nothing")
+
+	initech := companies["Initech"].ID
+	require.NoError(t, store.AddCompanyAlias(ctx, initech, "Initrode"))
+	assert.ErrorIs(t, store.AddCompanyAlias(ctx, initech, "Google Inc"), ErrCompanyAliasTaken)
+	merge, err := MergeCompanies(ctx, conn, initech, found.ID, false)
+	require.NoError(t, err)
+	assert.EqualValues(t, 3, merge.Maintainers)
+	found, err = store.FindCompany(ctx, "google")
+	require.NoError(t, err)
+	assert.Equal(t, "Initech", found.Name)
+	_, err = MergeCompanies(ctx, conn, initech, initech, false)
+	assert.ErrorIs(t, err, ErrInvalidMerge)
+	_, err = MergeCompanies(ctx, conn, initech, companies["google"].ID, false)
+	assert.ErrorIs(t, err, ErrCompanyNotFound, "archived companies are not merged")
+}
+
+func TestCompanyReport(t *testing.T) {
+	conn := openEmptyDB(t)
+	require.NoError(t, Migrate(conn))
+	ctx := t.Context()
+	store := NewSQLStore(conn)
+	joined := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	moved := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	argo := model.Project{Name: "argo", Maturity: model.Graduated}
+	flux := model.Project{Name: "flux", Maturity: model.Incubating}
+	require.NoError(t, conn.Create(&argo).Error)
+	require.NoError(t, conn.Create(&flux).Error)
+	acme := model.Company{Name: "Acme"}
+	initech := model.Company{Name: "Initech"}
+	require.NoError(t, conn.Create(&acme).Error)
+	require.NoError(t, conn.Create(&initech).Error)
+	var ids []uint
+	for _, email := range []string{"alice@example.com", "bob@example.com", "carol@example.com"} {
+		m := model.Maintainer{Email: email, MaintainerStatus: model.ActiveMaintainer}
+		require.NoError(t, conn.Create(&m).Error)
+		require.NoError(t, store.StartMembership(ctx, m.ID, argo.ID, nil, joined))
+		ids = append(ids, m.ID)
+	}
+	require.NoError(t, store.SetMaintainerCompany(ctx, ids[0], &acme.ID, "", joined))
+	require.NoError(t, store.SetMaintainerCompany(ctx, ids[1], &acme.ID, "", joined))
+	require.NoError(t, store.SetMaintainerCompany(ctx, ids[1], &initech.ID, "moved", moved))
+
+	report, err := CompanyReport(ctx, conn, moved.Add(-time.Hour))
+	require.NoError(t, err)
+	require.Len(t, report, 2)
+	assert.Equal(t, ProjectCompanies{Project: "argo", Maturity: model.Graduated, Maintainers: 3, Companies: []CompanyShare{
+		{Company: "Acme", Maintainers: 2},
+		{Company: Unaffiliated, Maintainers: 1},
+	}}, report[0])
+	assert.Equal(t, 1, report[0].CompanyCount())
+	largest, share := report[0].LargestShare()
+	assert.Equal(t, "Acme", largest.Company)
+	assert.InDelta(t, 2.0/3, share, 0.001)
+	assert.Equal(t, ProjectCompanies{Project: "flux", Maturity: model.Incubating, Companies: []CompanyShare{}}, report[1])
+
+	report, err = CompanyReport(ctx, conn, moved)
+	require.NoError(t, err)
+	assert.Equal(t, []CompanyShare{
+		{Company: "Acme", Maintainers: 1},
+		{Company: "Initech", Maintainers: 1},
+		{Company: Unaffiliated, Maintainers: 1},
+	}, report[0].Companies)
+	assert.Equal(t, 2, report[0].CompanyCount())
+
+	report, err = CompanyReport(ctx, conn, joined.Add(-time.Hour))
+	require.NoError(t, err)
+	assert.Zero(t, report[0].Maintainers, "nobody was a maintainer yet")
+
+	require.NoError(t, store.ArchiveMaintainer(ctx, ids[2]))
+	report, err = CompanyReport(ctx, conn, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, []CompanyShare{
+		{Company: "Acme", Maintainers: 1},
+		{Company: "Initech", Maintainers: 1},
+	}, report[0].Companies, "archived maintainers are not counted")
+}
@@ -0,0 +1,203 @@
+package dbtest
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"maintainerd/db"
+	"maintainerd/model"
+)
+
+func (s *MemStore) ArchiveMaintainer(ctx context.Context, id uint) error {
+	return s.archive(ctx, "maintainers", id, db.ErrMaintainerNotFound)
+}
+
+func (s *MemStore) RestoreMaintainer(ctx context.Context, id uint) error {
+	return s.restore(ctx, "maintainers", id, db.ErrMaintainerNotFound)
+}
+
+func (s *MemStore) ArchiveProject(ctx context.Context, id uint) error {
+	return s.archive(ctx, "projects", id, db.ErrProjectNotFound)
+}
+
+func (s *MemStore) RestoreProject(ctx context.Context, id uint) error {
+	return s.restore(ctx, "projects", id, db.ErrProjectNotFound)
+}
+
+func (s *MemStore) ArchiveCompany(ctx context.Context, id uint) error {
+	return s.archive(ctx, "companies", id, db.ErrCompanyNotFound)
+}
+
+func (s *MemStore) RestoreCompany(ctx context.Context, id uint) error {
+	return s.restore(ctx, "companies", id, db.ErrCompanyNotFound)
+}
+
+func (s *MemStore) ListArchivedMaintainers(ctx context.Context) ([]model.Maintainer, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var maintainers []model.Maintainer
+	for _, m := range s.maintainers {
+		if m.DeletedAt.Valid {
+			maintainers = append(maintainers, s.withRelations(m))
+		}
+	}
+	return maintainers, nil
+}
+
+func (s *MemStore) ListArchivedProjects(ctx context.Context) ([]model.Project, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var projects []model.Project
+	for _, p := range s.projects {
+		if p.DeletedAt.Valid {
+			projects = append(projects, p)
+		}
+	}
+	return projects, nil
+}
+
+func (s *MemStore) ListArchivedCompanies(ctx context.Context) ([]model.Company, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var companies []model.Company
+	for _, c := range s.companies {
+		if c.DeletedAt.Valid {
+			companies = append(companies, c)
+		}
+	}
+	return companies, nil
+}
+
+// archive mirrors SQLStore: the record and the rows cascading from it share one deletion stamp, which restore uses
+// to find them again.
+func (s *MemStore) archive(ctx context.Context, table string, id uint, notFound error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	deletedAt := s.deletedAt(table, id)
+	if deletedAt == nil || deletedAt.Valid {
+		return fmt.Errorf("%w: %d", notFound, id)
+	}
+	stamp := gorm.DeletedAt{Time: historyTime(time.Now()), Valid: true}
+	for _, ms := range s.memberships {
+		if !ms.deletedAt.Valid && ms.of(table, id) {
+			for i := range s.periods {
+				if p := &s.periods[i]; p.MaintainerID == ms.maintainerID && p.ProjectID == ms.projectID && p.EndedAt == nil {
+					at := stamp.Time
+					p.EndedAt, p.Reason = &at, db.ArchiveReason
+				}
+			}
+		}
+	}
+	*deletedAt = stamp
+	for _, d := range s.cascades(table, id) {
+		if !d.Valid {
+			*d = stamp
+		}
+	}
+	return nil
+}
+
+func (s *MemStore) restore(ctx context.Context, table string, id uint, notFound error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	deletedAt := s.deletedAt(table, id)
+	if deletedAt == nil {
+		return fmt.Errorf("%w: %d", notFound, id)
+	}
+	if !deletedAt.Valid {
+		return fmt.Errorf("%w: %d", db.ErrNotArchived, id)
+	}
+	stamp := deletedAt.Time
+	*deletedAt = gorm.DeletedAt{}
+	for _, d := range s.cascades(table, id) {
+		if d.Valid && d.Time.Equal(stamp) {
+			*d = gorm.DeletedAt{}
+		}
+	}
+	for _, ms := range s.memberships {
+		if ms.deletedAt.Valid || !ms.of(table, id) {
+			continue
+		}
+		for i := range s.periods {
+			p := &s.periods[i]
+			if p.MaintainerID == ms.maintainerID && p.ProjectID == ms.projectID && p.EndedAt != nil &&
+				p.EndedAt.Equal(stamp) && p.Reason == db.ArchiveReason {
+				p.EndedAt, p.Reason = nil, ""
+			}
+		}
+	}
+	return nil
+}
+
+// of reports whether the membership is archived and restored along with the record with id in table.
+func (ms membership) of(table string, id uint) bool {
+	return table == "maintainers" && ms.maintainerID == id || table == "projects" && ms.projectID == id
+}
+
+// deletedAt returns the DeletedAt of the record with id in table, archived or not, or nil if there is none.
+func (s *MemStore) deletedAt(table string, id uint) *gorm.DeletedAt {
+	switch table {
+	case "maintainers":
+		for i := range s.maintainers {
+			if s.maintainers[i].ID == id {
+				return &s.maintainers[i].DeletedAt
+			}
+		}
+	case "projects":
+		for i := range s.projects {
+			if s.projects[i].ID == id {
+				return &s.projects[i].DeletedAt
+			}
+		}
+	case "companies":
+		for i := range s.companies {
+			if s.companies[i].ID == id {
+				return &s.companies[i].DeletedAt
+			}
+		}
+	}
+	return nil
+}
+
+// cascades returns the DeletedAt of every row archived along with the record with id in table.
+func (s *MemStore) cascades(table string, id uint) []*gorm.DeletedAt {
+	var rows []*gorm.DeletedAt
+	for i := range s.memberships {
+		if s.memberships[i].of(table, id) {
+			rows = append(rows, &s.memberships[i].deletedAt)
+		}
+	}
+	switch table {
+	case "maintainers":
+		for i := range s.userTeams {
+			if m := s.userTeams[i].MaintainerID; m != nil && *m == id {
+				rows = append(rows, &s.userTeams[i].DeletedAt)
+			}
+		}
+	case "projects":
+		for i := range s.serviceTeams {
+			if s.serviceTeams[i].ProjectID == id {
+				rows = append(rows, &s.serviceTeams[i].DeletedAt)
+			}
+		}
+	}
+	return rows
+}
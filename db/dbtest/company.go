@@ -0,0 +1,153 @@
+package dbtest
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"maintainerd/db"
+	"maintainerd/model"
+)
+
+func (s *MemStore) FindCompany(ctx context.Context, name string) (*model.Company, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c := s.findCompany(name)
+	if c == nil || c.DeletedAt.Valid {
+		return nil, fmt.Errorf("%w: %q", db.ErrCompanyNotFound, name)
+	}
+	company := *c
+	return &company, nil
+}
+
+func (s *MemStore) AddCompanyAlias(ctx context.Context, companyID uint, alias string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	key := model.CompanyKey(alias)
+	if key == "" {
+		return fmt.Errorf("company alias %q has no letters or digits", alias)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if d := s.deletedAt("companies", companyID); d == nil || d.Valid {
+		return fmt.Errorf("%w: %d", db.ErrCompanyNotFound, companyID)
+	}
+	for _, a := range s.aliases {
+		if a.Key == key && a.CompanyID == companyID {
+			return nil
+		}
+		if a.Key == key {
+			return fmt.Errorf("%w: %q is an alias of company %d", db.ErrCompanyAliasTaken, alias, a.CompanyID)
+		}
+	}
+	s.ids["company_aliases"]++
+	s.aliases = append(s.aliases, model.CompanyAlias{
+		ID:        s.ids["company_aliases"],
+		CompanyID: companyID,
+		Key:       key,
+		Name:      strings.TrimSpace(alias),
+		CreatedAt: time.Now(),
+	})
+	return nil
+}
+
+func (s *MemStore) SetMaintainerCompany(ctx context.Context, maintainerID uint, companyID *uint, reason string, at time.Time) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if companyID != nil {
+		if d := s.deletedAt("companies", *companyID); d == nil || d.Valid {
+			return fmt.Errorf("%w: %d", db.ErrCompanyNotFound, *companyID)
+		}
+	}
+	at = historyTime(at)
+	for i := range s.maintainers {
+		m := &s.maintainers[i]
+		if m.ID != maintainerID || m.DeletedAt.Valid {
+			continue
+		}
+		if m.CompanyID == nil && companyID == nil || m.CompanyID != nil && companyID != nil && *m.CompanyID == *companyID {
+			return nil
+		}
+		if companyID != nil {
+			id := *companyID
+			companyID = &id
+		}
+		m.CompanyID = companyID
+		for j := range s.affiliations {
+			if a := &s.affiliations[j]; a.MaintainerID == maintainerID && a.EndedAt == nil {
+				a.EndedAt, a.Reason = &at, reason
+			}
+		}
+		if companyID != nil {
+			s.ids["affiliation_periods"]++
+			s.affiliations = append(s.affiliations, model.AffiliationPeriod{
+				ID:           s.ids["affiliation_periods"],
+				MaintainerID: maintainerID,
+				CompanyID:    *companyID,
+				StartedAt:    at,
+				CreatedAt:    time.Now(),
+			})
+		}
+		return nil
+	}
+	return fmt.Errorf("%w: %d", db.ErrMaintainerNotFound, maintainerID)
+}
+
+func (s *MemStore) MaintainerAffiliations(ctx context.Context, maintainerID uint) ([]model.AffiliationPeriod, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.deletedAt("maintainers", maintainerID) == nil {
+		return nil, fmt.Errorf("%w: %d", db.ErrMaintainerNotFound, maintainerID)
+	}
+	var periods []model.AffiliationPeriod
+	for _, p := range s.affiliations {
+		if p.MaintainerID != maintainerID {
+			continue
+		}
+		for _, c := range s.companies {
+			if c.ID == p.CompanyID {
+				p.Company = c
+			}
+		}
+		periods = append(periods, p)
+	}
+	sort.SliceStable(periods, func(i, j int) bool { return periods[i].StartedAt.Before(periods[j].StartedAt) })
+	return periods, nil
+}
+
+// findCompany returns the company, archived or not, of the alias with the key of name, or else the oldest live company
+// whose name has that key, or nil.
+func (s *MemStore) findCompany(name string) *model.Company {
+	key := model.CompanyKey(name)
+	if key == "" {
+		return nil
+	}
+	for _, a := range s.aliases {
+		if a.Key != key {
+			continue
+		}
+		for i := range s.companies {
+			if s.companies[i].ID == a.CompanyID {
+				return &s.companies[i]
+			}
+		}
+	}
+	for i := range s.companies {
+		if c := &s.companies[i]; !c.DeletedAt.Valid && model.CompanyKey(c.Name) == key {
+			return c
+		}
+	}
+	return nil
+}
@@ -0,0 +1,290 @@
+package dbtest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"maintainerd/db"
+	"maintainerd/model"
+)
+
+func (s *MemStore) FindDuplicatePeople(ctx context.Context) ([]db.DuplicateGroup, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var people []db.Person
+	for _, m := range s.maintainers {
+		if !m.DeletedAt.Valid {
+			people = append(people, db.MaintainerPerson(s.withRelations(m)))
+		}
+	}
+	for _, c := range s.collaborators {
+		if !c.DeletedAt.Valid {
+			people = append(people, db.CollaboratorPerson(c))
+		}
+	}
+	for _, sm := range s.staffMembers {
+		if !sm.DeletedAt.Valid {
+			people = append(people, db.StaffPerson(sm))
+		}
+	}
+	return db.GroupDuplicates(people), nil
+}
+
+func (s *MemStore) MergePeople(ctx context.Context, survivorID uint, duplicate db.PersonRef, dryRun bool) (db.MergeReport, error) {
+	report := db.MergeReport{Survivor: survivorID, Duplicate: duplicate, DryRun: dryRun}
+	if err := ctx.Err(); err != nil {
+		return report, err
+	}
+	switch {
+	case duplicate.Kind == db.PersonStaff,
+		duplicate.Kind != db.PersonMaintainer && duplicate.Kind != db.PersonCollaborator,
+		duplicate.Kind == db.PersonMaintainer && duplicate.ID == survivorID:
+		return report, fmt.Errorf("%w: %s into maintainer %d", db.ErrInvalidMerge, duplicate, survivorID)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if d := s.deletedAt("maintainers", survivorID); d == nil || d.Valid {
+		return report, fmt.Errorf("%w: %d", db.ErrMaintainerNotFound, survivorID)
+	}
+
+	// Like the SQLStore transaction, a failed or dry-run merge leaves nothing behind.
+	saved := s.snapshot()
+	var err error
+	if duplicate.Kind == db.PersonMaintainer {
+		err = s.mergeMaintainer(survivorID, duplicate.ID, &report)
+	} else {
+		err = s.mergeCollaborator(survivorID, duplicate.ID, &report)
+	}
+	if err == nil {
+		metadata, _ := json.Marshal(report)
+		event := model.AuditLog{
+			Model:        s.newModel("audit_logs"),
+			MaintainerID: &survivorID,
+			Action:       "MERGE_PERSON",
+			Message:      fmt.Sprintf("Merged %s into maintainer %d", duplicate, survivorID),
+			Metadata:     string(metadata),
+		}
+		s.auditLog = append(s.auditLog, event)
+	}
+	if err != nil || dryRun {
+		s.restoreSnapshot(saved)
+	}
+	return report, err
+}
+
+func (s *MemStore) mergeMaintainer(survivorID, duplicateID uint, report *db.MergeReport) error {
+	var duplicate *model.Maintainer
+	for i := range s.maintainers {
+		if s.maintainers[i].ID == duplicateID {
+			duplicate = &s.maintainers[i]
+		}
+	}
+	if duplicate == nil {
+		return fmt.Errorf("%w: %d", db.ErrMaintainerNotFound, duplicateID)
+	}
+	dup := *duplicate
+	now := historyTime(time.Now())
+
+	var moving []membership
+	for _, ms := range s.memberships {
+		if ms.maintainerID == duplicateID {
+			moving = append(moving, ms)
+		}
+	}
+	sort.Slice(moving, func(i, j int) bool { return moving[i].projectID < moving[j].projectID })
+	for _, ms := range moving {
+		i := s.membership(duplicateID, ms.projectID)
+		kept := s.membership(survivorID, ms.projectID)
+		if kept < 0 {
+			s.memberships[i].maintainerID = survivorID
+			report.Changes = append(report.Changes, fmt.Sprintf("membership of project %d moved", ms.projectID))
+			continue
+		}
+		if s.memberships[kept].deletedAt.Valid && !ms.deletedAt.Valid {
+			s.memberships[i].maintainerID = survivorID
+			s.memberships = append(s.memberships[:kept], s.memberships[kept+1:]...)
+			report.Changes = append(report.Changes, fmt.Sprintf(
+				"membership of project %d moved, replacing the survivor's archived one", ms.projectID))
+			continue
+		}
+		roles := append(slices.Clone(s.memberships[kept].roles), ms.roles...).Normalize()
+		if !s.memberships[kept].deletedAt.Valid && !slices.Equal(roles, s.memberships[kept].roles) {
+			s.changeRoles(kept, roles, now)
+		}
+		s.memberships = append(s.memberships[:i], s.memberships[i+1:]...)
+		for j := range s.periods {
+			p := &s.periods[j]
+			if p.MaintainerID == duplicateID && p.ProjectID == ms.projectID && p.EndedAt == nil {
+				p.EndedAt, p.Reason = &now, fmt.Sprintf("merged into maintainer %d", survivorID)
+			}
+		}
+		report.Changes = append(report.Changes, fmt.Sprintf("membership of project %d merged into the survivor's, roles %s",
+			ms.projectID, strings.Join(roles.Strings(), ",")))
+	}
+
+	moved := func(n int, label string) {
+		if n > 0 {
+			report.Changes = append(report.Changes, fmt.Sprintf("%d %s moved", n, label))
+		}
+	}
+	n := 0
+	for i := range s.periods {
+		if s.periods[i].MaintainerID == duplicateID {
+			s.periods[i].MaintainerID = survivorID
+			n++
+		}
+	}
+	moved(n, "membership periods")
+	survivorCompany := slices.ContainsFunc(s.maintainers, func(m model.Maintainer) bool {
+		return m.ID == survivorID && m.CompanyID != nil
+	})
+	n = 0
+	for i := range s.affiliations {
+		a := &s.affiliations[i]
+		if a.MaintainerID != duplicateID {
+			continue
+		}
+		if survivorCompany && a.EndedAt == nil {
+			a.EndedAt, a.Reason = &now, fmt.Sprintf("merged into maintainer %d", survivorID)
+		}
+		a.MaintainerID = survivorID
+		n++
+	}
+	moved(n, "affiliation periods")
+	n = 0
+	for i := range s.statuses {
+		if s.statuses[i].MaintainerID == duplicateID {
+			s.statuses[i].MaintainerID = survivorID
+			n++
+		}
+	}
+	moved(n, "status changes")
+	n = 0
+	for i := range s.identities {
+		if s.identities[i].MaintainerID == duplicateID {
+			s.identities[i].MaintainerID = survivorID
+			n++
+		}
+	}
+	moved(n, "identities")
+	n = 0
+	for i := range s.userTeams {
+		if id := s.userTeams[i].MaintainerID; id != nil && *id == duplicateID {
+			s.userTeams[i].MaintainerID = &survivorID
+			n++
+		}
+	}
+	moved(n, "service team links")
+	n = 0
+	for i := range s.auditLog {
+		if id := s.auditLog[i].MaintainerID; id != nil && *id == duplicateID {
+			s.auditLog[i].MaintainerID = &survivorID
+			n++
+		}
+	}
+	moved(n, "audit log entries")
+
+	for i := range s.maintainers {
+		if m := &s.maintainers[i]; m.ID == survivorID && m.CompanyID == nil && dup.CompanyID != nil {
+			m.CompanyID = dup.CompanyID
+			report.Changes = append(report.Changes, fmt.Sprintf("company %d taken from the duplicate", *dup.CompanyID))
+		}
+	}
+	s.maintainers = slices.DeleteFunc(s.maintainers, func(m model.Maintainer) bool { return m.ID == duplicateID })
+	report.Changes = append(report.Changes, fmt.Sprintf("maintainer %d deleted", duplicateID))
+	return s.recordMergedIdentities(survivorID, dup.DeclaredIdentities(), report)
+}
+
+func (s *MemStore) mergeCollaborator(survivorID, collaboratorID uint, report *db.MergeReport) error {
+	i := slices.IndexFunc(s.collaborators, func(c model.Collaborator) bool { return c.ID == collaboratorID })
+	if i < 0 {
+		return fmt.Errorf("%w: %d", db.ErrCollaboratorNotFound, collaboratorID)
+	}
+	p := db.CollaboratorPerson(s.collaborators[i])
+	n := 0
+	for j := range s.userTeams {
+		if id := s.userTeams[j].CollaboratorID; id != nil && *id == collaboratorID {
+			s.userTeams[j].MaintainerID, s.userTeams[j].CollaboratorID = &survivorID, nil
+			n++
+		}
+	}
+	if n > 0 {
+		report.Changes = append(report.Changes, fmt.Sprintf("%d service team links moved", n))
+	}
+	s.collaborators = slices.Delete(s.collaborators, i, i+1)
+	report.Changes = append(report.Changes, fmt.Sprintf("collaborator %d deleted", collaboratorID))
+	var identities []model.MaintainerIdentity
+	for _, e := range p.Emails {
+		identities = append(identities, model.MaintainerIdentity{Kind: model.IdentityEmail, Value: e})
+	}
+	for _, g := range p.GitHubAccounts {
+		identities = append(identities, model.MaintainerIdentity{Kind: model.IdentityGitHub, Value: g})
+	}
+	return s.recordMergedIdentities(survivorID, identities, report)
+}
+
+func (s *MemStore) recordMergedIdentities(survivorID uint, identities []model.MaintainerIdentity, report *db.MergeReport) error {
+	for _, id := range identities {
+		id.MaintainerID, id.IsPrimary = survivorID, false
+		if _, err := s.recordIdentity(id); errors.Is(err, db.ErrIdentityTaken) {
+			report.Changes = append(report.Changes, fmt.Sprintf("%s identity not recorded, it belongs to another maintainer", id.Kind))
+			continue
+		} else if err != nil {
+			return err
+		}
+		report.Changes = append(report.Changes, fmt.Sprintf("%s identity recorded", id.Kind))
+	}
+	return nil
+}
+
+// memSnapshot holds the tables a merge changes.
+type memSnapshot struct {
+	ids           map[string]uint
+	maintainers   []model.Maintainer
+	memberships   []membership
+	periods       []model.MembershipPeriod
+	statuses      []model.MaintainerStatusChange
+	identities    []model.MaintainerIdentity
+	userTeams     []model.ServiceUserTeams
+	collaborators []model.Collaborator
+	auditLog      []model.AuditLog
+}
+
+func (s *MemStore) snapshot() memSnapshot {
+	ids := make(map[string]uint, len(s.ids))
+	for k, v := range s.ids {
+		ids[k] = v
+	}
+	return memSnapshot{
+		ids:           ids,
+		maintainers:   slices.Clone(s.maintainers),
+		memberships:   slices.Clone(s.memberships),
+		periods:       slices.Clone(s.periods),
+		statuses:      slices.Clone(s.statuses),
+		identities:    slices.Clone(s.identities),
+		userTeams:     slices.Clone(s.userTeams),
+		collaborators: slices.Clone(s.collaborators),
+		auditLog:      slices.Clone(s.auditLog),
+	}
+}
+
+func (s *MemStore) restoreSnapshot(saved memSnapshot) {
+	s.ids = saved.ids
+	s.maintainers = saved.maintainers
+	s.memberships = saved.memberships
+	s.periods = saved.periods
+	s.statuses = saved.statuses
+	s.identities = saved.identities
+	s.userTeams = saved.userTeams
+	s.collaborators = saved.collaborators
+	s.auditLog = saved.auditLog
+}
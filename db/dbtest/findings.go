@@ -0,0 +1,42 @@
+package dbtest
+
+import (
+	"context"
+	"time"
+
+	"maintainerd/db"
+	"maintainerd/model"
+)
+
+func (s *MemStore) ReplaceFindings(ctx context.Context, findings []model.Finding) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.findings = nil
+	for _, f := range findings {
+		s.ids["findings"]++
+		f.ID = s.ids["findings"]
+		if f.CreatedAt.IsZero() {
+			f.CreatedAt = time.Now()
+		}
+		s.findings = append(s.findings, f)
+	}
+	warnings := db.ImportWarnings(findings)
+	for i := range s.maintainers {
+		if !s.maintainers[i].DeletedAt.Valid {
+			s.maintainers[i].ImportWarnings = warnings[s.maintainers[i].ID]
+		}
+	}
+	return nil
+}
+
+func (s *MemStore) ListFindings(ctx context.Context) ([]model.Finding, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]model.Finding(nil), s.findings...), nil
+}
@@ -0,0 +1,200 @@
+package dbtest
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"maintainerd/db"
+	"maintainerd/model"
+)
+
+func (s *MemStore) StartMembership(ctx context.Context, maintainerID, projectID uint, roles model.MembershipRoles, at time.Time) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	roles, err := membershipRoles(roles)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if d := s.deletedAt("maintainers", maintainerID); d == nil || d.Valid {
+		return fmt.Errorf("%w: %d", db.ErrMaintainerNotFound, maintainerID)
+	}
+	if d := s.deletedAt("projects", projectID); d == nil || d.Valid {
+		return fmt.Errorf("%w: %d", db.ErrProjectNotFound, projectID)
+	}
+	if s.membership(maintainerID, projectID) >= 0 {
+		return fmt.Errorf("%w: maintainer %d, project %d", db.ErrAlreadyMember, maintainerID, projectID)
+	}
+	at = historyTime(at)
+	s.memberships = append(s.memberships, membership{maintainerID: maintainerID, projectID: projectID, joinedAt: at, roles: roles})
+	for _, role := range roles {
+		s.addPeriod(model.MembershipPeriod{MaintainerID: maintainerID, ProjectID: projectID, Role: role, StartedAt: at})
+	}
+	return nil
+}
+
+func (s *MemStore) EndMembership(ctx context.Context, maintainerID, projectID uint, reason string, at time.Time) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i := s.membership(maintainerID, projectID)
+	if i < 0 || s.memberships[i].deletedAt.Valid {
+		return fmt.Errorf("%w: maintainer %d, project %d", db.ErrNotMember, maintainerID, projectID)
+	}
+	joinedAt, roles := s.memberships[i].joinedAt, s.memberships[i].roles
+	s.memberships = append(s.memberships[:i], s.memberships[i+1:]...)
+	at = historyTime(at)
+	closed := false
+	for i := range s.periods {
+		p := &s.periods[i]
+		if p.MaintainerID == maintainerID && p.ProjectID == projectID && p.EndedAt == nil {
+			p.EndedAt, p.Reason = &at, reason
+			closed = true
+		}
+	}
+	if closed {
+		return nil
+	}
+	for _, role := range roles {
+		s.addPeriod(model.MembershipPeriod{
+			MaintainerID: maintainerID,
+			ProjectID:    projectID,
+			Role:         role,
+			StartedAt:    historyTime(joinedAt),
+			EndedAt:      &at,
+			Reason:       reason,
+		})
+	}
+	return nil
+}
+
+func (s *MemStore) MaintainersOnDate(ctx context.Context, projectID uint, on time.Time) ([]model.Maintainer, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.deletedAt("projects", projectID) == nil {
+		return nil, fmt.Errorf("%w: %d", db.ErrProjectNotFound, projectID)
+	}
+	covered := map[uint]bool{}
+	for _, p := range s.periods {
+		if p.ProjectID == projectID && !p.StartedAt.After(on) && (p.EndedAt == nil || p.EndedAt.After(on)) {
+			covered[p.MaintainerID] = true
+		}
+	}
+	var maintainers []model.Maintainer
+	for _, m := range s.maintainers {
+		if covered[m.ID] {
+			maintainers = append(maintainers, s.withRelations(m))
+		}
+	}
+	return maintainers, nil
+}
+
+func (s *MemStore) MaintainerTenure(ctx context.Context, maintainerID uint) ([]model.MembershipPeriod, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.deletedAt("maintainers", maintainerID) == nil {
+		return nil, fmt.Errorf("%w: %d", db.ErrMaintainerNotFound, maintainerID)
+	}
+	var periods []model.MembershipPeriod
+	for _, p := range s.periods {
+		if p.MaintainerID != maintainerID {
+			continue
+		}
+		for _, project := range s.projects {
+			if project.ID == p.ProjectID {
+				p.Project = project
+			}
+		}
+		periods = append(periods, p)
+	}
+	sort.SliceStable(periods, func(i, j int) bool { return periods[i].StartedAt.Before(periods[j].StartedAt) })
+	return periods, nil
+}
+
+func (s *MemStore) SetMaintainerStatus(ctx context.Context, maintainerID uint, status model.MaintainerStatus, reason string, at time.Time) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if !status.IsValid() {
+		return fmt.Errorf("invalid maintainer status %q", status)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := range s.maintainers {
+		m := &s.maintainers[i]
+		if m.ID != maintainerID || m.DeletedAt.Valid {
+			continue
+		}
+		if m.MaintainerStatus == status {
+			return nil
+		}
+		s.ids["maintainer_status_changes"]++
+		change := model.MaintainerStatusChange{
+			ID:           s.ids["maintainer_status_changes"],
+			MaintainerID: maintainerID,
+			ToStatus:     status,
+			ChangedAt:    historyTime(at),
+			Reason:       reason,
+		}
+		if from := m.MaintainerStatus; from.IsValid() {
+			change.FromStatus = &from
+		}
+		m.MaintainerStatus = status
+		s.statuses = append(s.statuses, change)
+		return nil
+	}
+	return fmt.Errorf("%w: %d", db.ErrMaintainerNotFound, maintainerID)
+}
+
+func (s *MemStore) MaintainerStatusHistory(ctx context.Context, maintainerID uint) ([]model.MaintainerStatusChange, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.deletedAt("maintainers", maintainerID) == nil {
+		return nil, fmt.Errorf("%w: %d", db.ErrMaintainerNotFound, maintainerID)
+	}
+	var changes []model.MaintainerStatusChange
+	for _, c := range s.statuses {
+		if c.MaintainerID == maintainerID {
+			changes = append(changes, c)
+		}
+	}
+	sort.SliceStable(changes, func(i, j int) bool { return changes[i].ChangedAt.Before(changes[j].ChangedAt) })
+	return changes, nil
+}
+
+// membership returns the index of the maintainer's membership of the project, archived or not, or -1.
+func (s *MemStore) membership(maintainerID, projectID uint) int {
+	for i, ms := range s.memberships {
+		if ms.maintainerID == maintainerID && ms.projectID == projectID {
+			return i
+		}
+	}
+	return -1
+}
+
+func (s *MemStore) addPeriod(p model.MembershipPeriod) {
+	s.ids["membership_periods"]++
+	p.ID = s.ids["membership_periods"]
+	p.CreatedAt = time.Now()
+	s.periods = append(s.periods, p)
+}
+
+// historyTime mirrors the normalisation SQLStore applies to history timestamps.
+func historyTime(t time.Time) time.Time {
+	return t.UTC().Truncate(time.Microsecond)
+}
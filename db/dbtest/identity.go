@@ -0,0 +1,133 @@
+package dbtest
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"maintainerd/db"
+	"maintainerd/model"
+)
+
+func (s *MemStore) AddMaintainerIdentity(ctx context.Context, identity model.MaintainerIdentity) (model.MaintainerIdentity, error) {
+	if err := ctx.Err(); err != nil {
+		return model.MaintainerIdentity{}, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if d := s.deletedAt("maintainers", identity.MaintainerID); d == nil || d.Valid {
+		return model.MaintainerIdentity{}, fmt.Errorf("%w: %d", db.ErrMaintainerNotFound, identity.MaintainerID)
+	}
+	return s.recordIdentity(identity)
+}
+
+func (s *MemStore) ListMaintainerIdentities(ctx context.Context, maintainerID uint) ([]model.MaintainerIdentity, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.deletedAt("maintainers", maintainerID) == nil {
+		return nil, fmt.Errorf("%w: %d", db.ErrMaintainerNotFound, maintainerID)
+	}
+	var identities []model.MaintainerIdentity
+	for _, id := range s.identities {
+		if id.MaintainerID == maintainerID {
+			identities = append(identities, id)
+		}
+	}
+	return identities, nil
+}
+
+func (s *MemStore) FindMaintainerByIdentity(ctx context.Context, identity model.MaintainerIdentity) (*model.Maintainer, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.maintainerByIdentity(identity)
+}
+
+// maintainerByIdentity mirrors the SQLStore helper of the same name.
+func (s *MemStore) maintainerByIdentity(identity model.MaintainerIdentity) (*model.Maintainer, error) {
+	identity = identity.Normalize()
+	if identity.Value == "" {
+		return nil, fmt.Errorf("%w: empty %s identity", db.ErrMaintainerNotFound, identity.Kind)
+	}
+	for _, m := range s.maintainers {
+		if m.DeletedAt.Valid {
+			continue
+		}
+		m = s.withRelations(m)
+		for _, id := range append(m.DeclaredIdentities(), m.Identities...) {
+			if id.Kind == identity.Kind && id.Service == identity.Service && id.Value == identity.Value {
+				return &m, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("%w: %s %s", db.ErrMaintainerNotFound, identity.Kind, identity.Value)
+}
+
+// recordIdentity mirrors the SQLStore helper of the same name.
+func (s *MemStore) recordIdentity(identity model.MaintainerIdentity) (model.MaintainerIdentity, error) {
+	identity = identity.Normalize()
+	if !identity.Kind.IsValid() {
+		return identity, fmt.Errorf("invalid identity kind %q", identity.Kind)
+	}
+	if identity.Value == "" || (identity.Kind == model.IdentityService && identity.Service == "") {
+		return identity, fmt.Errorf("incomplete %s identity %+v", identity.Kind, identity)
+	}
+	i := -1
+	for j, id := range s.identities {
+		if id.Kind == identity.Kind && id.Service == identity.Service && id.Value == identity.Value {
+			i = j
+		}
+	}
+	if i < 0 {
+		if m, err := s.maintainerByIdentity(identity); err == nil && m.ID != identity.MaintainerID {
+			return identity, fmt.Errorf("%w: %s %s is maintainer %d's", db.ErrIdentityTaken, identity.Kind, identity.Value, m.ID)
+		}
+	}
+	switch {
+	case i < 0:
+		s.ids["maintainer_identities"]++
+		identity.ID = s.ids["maintainer_identities"]
+		s.identities = append(s.identities, identity)
+		i = len(s.identities) - 1
+	case s.identities[i].MaintainerID != identity.MaintainerID:
+		return s.identities[i], fmt.Errorf("%w: %s %s is maintainer %d's", db.ErrIdentityTaken, identity.Kind,
+			identity.Value, s.identities[i].MaintainerID)
+	default:
+		s.identities[i].Verified = s.identities[i].Verified || identity.Verified
+		s.identities[i].IsPrimary = s.identities[i].IsPrimary || identity.IsPrimary
+	}
+	recorded := s.identities[i]
+	if !recorded.IsPrimary {
+		return recorded, nil
+	}
+	for j := range s.identities {
+		id := &s.identities[j]
+		if j != i && id.MaintainerID == recorded.MaintainerID && id.Kind == recorded.Kind && id.Service == recorded.Service {
+			id.IsPrimary = false
+		}
+	}
+	for j := range s.maintainers {
+		m := &s.maintainers[j]
+		if m.ID != recorded.MaintainerID {
+			continue
+		}
+		for _, previous := range m.DeclaredIdentities() {
+			if previous.Kind == recorded.Kind && previous.IsPrimary && previous.Value != recorded.Value {
+				previous.IsPrimary = false
+				_, _ = s.recordIdentity(previous)
+			}
+		}
+		switch {
+		case recorded.Kind == model.IdentityEmail && !strings.EqualFold(m.Email, recorded.Value):
+			m.Email = recorded.Value
+		case recorded.Kind == model.IdentityGitHub && !strings.EqualFold(m.GitHubAccount, recorded.Value):
+			m.GitHubAccount = recorded.Value
+		}
+	}
+	return recorded, nil
+}
@@ -0,0 +1,584 @@
+// Package dbtest provides MemStore, an in-memory implementation of db.Store for unit tests that should not need a
+// database.
+package dbtest
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"maintainerd/db"
+	"maintainerd/model"
+)
+
+// MemStore is an in-memory db.Store. Seed it with the Add* methods; IDs are assigned per table starting at 1, the
+// way a freshly migrated database assigns them. Queries return copies, so callers cannot modify the store through
+// their results, and fail with the context's error when called with a cancelled context.
+type MemStore struct {
+	// PingErr, when set, is returned by Ping.
+	PingErr error
+
+	mu            sync.Mutex
+	ids           map[string]uint
+	companies     []model.Company
+	projects      []model.Project
+	maintainers   []model.Maintainer
+	memberships   []membership // in the order they were added
+	services      []model.Service
+	serviceTeams  []model.ServiceTeam
+	userTeams     []model.ServiceUserTeams
+	rolePolicies  []model.ServiceTeamRolePolicy
+	staffMembers  []model.StaffMember
+	collaborators []model.Collaborator
+	findings      []model.Finding
+	auditLog      []model.AuditLog
+	periods       []model.MembershipPeriod
+	statuses      []model.MaintainerStatusChange
+	identities    []model.MaintainerIdentity
+	aliases       []model.CompanyAlias
+	affiliations  []model.AffiliationPeriod
+}
+
+var _ db.Store = (*MemStore)(nil)
+
+// NewMemStore returns an empty MemStore.
+func NewMemStore() *MemStore {
+	return &MemStore{
+		ids: map[string]uint{},
+	}
+}
+
+// membership mirrors a maintainer_projects row.
+type membership struct {
+	maintainerID, projectID uint
+	joinedAt                time.Time
+	roles                   model.MembershipRoles
+	deletedAt               gorm.DeletedAt
+}
+
+func (s *MemStore) newModel(table string) gorm.Model {
+	s.ids[table]++
+	now := time.Now()
+	return gorm.Model{ID: s.ids[table], CreatedAt: now, UpdatedAt: now}
+}
+
+// AddCompany stores a company called name and returns it.
+func (s *MemStore) AddCompany(name string) model.Company {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c := model.Company{Model: s.newModel("companies"), Name: name}
+	s.companies = append(s.companies, c)
+	return c
+}
+
+// AddProject stores p, ignoring its ID, Maintainers and Services, and returns it with its new ID.
+func (s *MemStore) AddProject(p model.Project) model.Project {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p.Model = s.newModel("projects")
+	p.Maintainers = nil
+	p.Services = nil
+	s.projects = append(s.projects, p)
+	return p
+}
+
+// AddMaintainer stores m, ignoring its ID, Projects and Identities, as a maintainer of each of projectIDs and returns
+// it with its new ID. When m.CompanyID is set, m.Company is filled in from the stored companies.
+func (s *MemStore) AddMaintainer(m model.Maintainer, projectIDs ...uint) model.Maintainer {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	m.Model = s.newModel("maintainers")
+	m.Projects = nil
+	m.Identities = nil
+	s.maintainers = append(s.maintainers, m)
+	for _, pid := range projectIDs {
+		s.memberships = append(s.memberships, membership{
+			maintainerID: m.ID,
+			projectID:    pid,
+			joinedAt:     m.CreatedAt,
+			roles:        model.MembershipRoles{model.RoleMaintainer},
+		})
+	}
+	return s.withRelations(m)
+}
+
+// AddService stores a service called name and returns it.
+func (s *MemStore) AddService(name string) model.Service {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	svc := model.Service{Model: s.newModel("services"), Name: name}
+	s.services = append(s.services, svc)
+	return svc
+}
+
+// AddServiceTeam stores st, ignoring its ID, and returns it with its new ID.
+func (s *MemStore) AddServiceTeam(st model.ServiceTeam) model.ServiceTeam {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st.Model = s.newModel("service_teams")
+	s.serviceTeams = append(s.serviceTeams, st)
+	return st
+}
+
+// AddServiceUserTeam stores link, ignoring its ID, and returns it with its new ID.
+func (s *MemStore) AddServiceUserTeam(link model.ServiceUserTeams) model.ServiceUserTeams {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	link.Model = s.newModel("service_user_teams")
+	s.userTeams = append(s.userTeams, link)
+	return link
+}
+
+// ServiceUserTeams returns the live links between service users and teams.
+func (s *MemStore) ServiceUserTeams() []model.ServiceUserTeams {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var links []model.ServiceUserTeams
+	for _, link := range s.userTeams {
+		if !link.DeletedAt.Valid {
+			links = append(links, link)
+		}
+	}
+	return links
+}
+
+// AddServiceTeamRolePolicy stores p, ignoring its ID, and returns it with its new ID.
+func (s *MemStore) AddServiceTeamRolePolicy(p model.ServiceTeamRolePolicy) model.ServiceTeamRolePolicy {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p.Model = s.newModel("service_team_role_policies")
+	s.rolePolicies = append(s.rolePolicies, p)
+	return p
+}
+
+// AddStaffMember stores sm, ignoring its ID, and returns it with its new ID.
+func (s *MemStore) AddStaffMember(sm model.StaffMember) model.StaffMember {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sm.Model = s.newModel("staff_members")
+	s.staffMembers = append(s.staffMembers, sm)
+	return sm
+}
+
+// AddCollaborator stores c, ignoring its ID and Projects, and returns it with its new ID.
+func (s *MemStore) AddCollaborator(c model.Collaborator) model.Collaborator {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c.Model = s.newModel("collaborators")
+	c.Projects = nil
+	s.collaborators = append(s.collaborators, c)
+	return c
+}
+
+// AuditEvents returns every event written with LogAuditEvent, oldest first.
+func (s *MemStore) AuditEvents() []model.AuditLog {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]model.AuditLog(nil), s.auditLog...)
+}
+
+// Ping returns PingErr.
+func (s *MemStore) Ping(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return s.PingErr
+}
+
+func (s *MemStore) GetProjectsUsingService(ctx context.Context, serviceID uint) ([]model.Project, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	uses := map[uint]bool{}
+	for _, st := range s.serviceTeams {
+		if st.DeletedAt.Valid {
+			continue
+		}
+		if st.ServiceID == serviceID {
+			uses[st.ProjectID] = true
+		}
+	}
+	var projects []model.Project
+	for _, p := range s.projects {
+		if p.DeletedAt.Valid {
+			continue
+		}
+		if uses[p.ID] {
+			projects = append(projects, s.withMaintainers(p))
+		}
+	}
+	return projects, nil
+}
+
+func (s *MemStore) GetProjectMapByName(ctx context.Context) (map[string]model.Project, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	projects := make(map[string]model.Project, len(s.projects))
+	for _, p := range s.projects {
+		if p.DeletedAt.Valid {
+			continue
+		}
+		projects[p.Name] = s.withMaintainers(p)
+	}
+	return projects, nil
+}
+
+func (s *MemStore) GetMaintainersByProject(ctx context.Context, projectID uint) ([]model.Maintainer, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, p := range s.projects {
+		if p.DeletedAt.Valid {
+			continue
+		}
+		if p.ID == projectID {
+			return s.withMaintainers(p).Maintainers, nil
+		}
+	}
+	return nil, db.ErrProjectNotFound
+}
+
+func (s *MemStore) GetMaintainerMapByEmail(ctx context.Context) (map[string]model.Maintainer, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	m := make(map[string]model.Maintainer, len(s.maintainers))
+	for _, maintainer := range s.maintainers {
+		if maintainer.DeletedAt.Valid {
+			continue
+		}
+		maintainer = s.withRelations(maintainer)
+		for _, email := range maintainer.Emails() {
+			if _, taken := m[email]; !taken {
+				m[email] = maintainer
+			}
+		}
+	}
+	return m, nil
+}
+
+func (s *MemStore) GetMaintainerMapByGitHubAccount(ctx context.Context) (map[string]model.Maintainer, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	m := make(map[string]model.Maintainer, len(s.maintainers))
+	for _, maintainer := range s.maintainers {
+		if maintainer.DeletedAt.Valid {
+			continue
+		}
+		maintainer = s.withRelations(maintainer)
+		for _, login := range maintainer.GitHubAccounts() {
+			if _, taken := m[login]; !taken {
+				m[login] = maintainer
+			}
+		}
+	}
+	return m, nil
+}
+
+func (s *MemStore) ListMaintainers(ctx context.Context) ([]model.Maintainer, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var maintainers []model.Maintainer
+	for _, m := range s.maintainers {
+		if !m.DeletedAt.Valid {
+			maintainers = append(maintainers, s.withRelations(m))
+		}
+	}
+	return maintainers, nil
+}
+
+func (s *MemStore) GetProjectServiceTeamMap(ctx context.Context, serviceName string) (map[uint]*model.ServiceTeam, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	svc, err := s.serviceByName(serviceName)
+	if err != nil {
+		return nil, err
+	}
+	teams := map[uint]*model.ServiceTeam{}
+	for _, st := range s.serviceTeams {
+		if st.DeletedAt.Valid {
+			continue
+		}
+		if st.ServiceID == svc.ID {
+			st := st
+			teams[st.ProjectID] = &st
+		}
+	}
+	return teams, nil
+}
+
+func (s *MemStore) GetServiceTeamByProject(ctx context.Context, projectID uint, serviceID uint) (*model.ServiceTeam, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, st := range s.serviceTeams {
+		if st.DeletedAt.Valid {
+			continue
+		}
+		if st.ProjectID == projectID && st.ServiceID == serviceID {
+			return &st, nil
+		}
+	}
+	return nil, nil
+}
+
+// CreateServiceTeam mirrors SQLStore.CreateServiceTeam, which records every team against service ID 1 (FOSSA).
+func (s *MemStore) CreateServiceTeam(ctx context.Context, projectID uint, projectName string, serviceTeamID int,
+	serviceTeamName string) (*model.ServiceTeam, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, st := range s.serviceTeams {
+		if st.DeletedAt.Valid {
+			continue
+		}
+		if st.ServiceTeamID == serviceTeamID {
+			return &st, nil
+		}
+	}
+	st := model.ServiceTeam{
+		Model:           s.newModel("service_teams"),
+		ProjectID:       projectID,
+		ServiceID:       1,
+		ServiceTeamID:   serviceTeamID,
+		ServiceTeamName: &serviceTeamName,
+		ProjectName:     &projectName,
+	}
+	s.serviceTeams = append(s.serviceTeams, st)
+	return &st, nil
+}
+
+func (s *MemStore) GetServiceTeamRolePolicy(ctx context.Context, projectID uint, serviceName string) (*model.ServiceTeamRolePolicy, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	svc, err := s.serviceByName(serviceName)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range s.rolePolicies {
+		if p.ProjectID == projectID && p.ServiceID == svc.ID {
+			return &p, nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *MemStore) SetServiceTeamRolePolicy(ctx context.Context, serviceName string, policy model.ServiceTeamRolePolicy) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	svc, err := s.serviceByName(serviceName)
+	if err != nil {
+		return err
+	}
+	if d := s.deletedAt("projects", policy.ProjectID); d == nil || d.Valid {
+		return fmt.Errorf("%w: %d", db.ErrProjectNotFound, policy.ProjectID)
+	}
+	s.rolePolicies = slices.DeleteFunc(s.rolePolicies, func(p model.ServiceTeamRolePolicy) bool {
+		return p.ProjectID == policy.ProjectID && p.ServiceID == svc.ID
+	})
+	if policy.MaintainerRole == 0 && policy.CollaboratorRole == 0 && policy.StaffRole == 0 {
+		return nil
+	}
+	policy.Model, policy.ServiceID = s.newModel("service_team_role_policies"), svc.ID
+	s.rolePolicies = append(s.rolePolicies, policy)
+	return nil
+}
+
+func (s *MemStore) ListCompanies(ctx context.Context) ([]model.Company, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var companies []model.Company
+	for _, c := range s.companies {
+		if !c.DeletedAt.Valid {
+			companies = append(companies, c)
+		}
+	}
+	return companies, nil
+}
+
+func (s *MemStore) ListStaffMembers(ctx context.Context) ([]model.StaffMember, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]model.StaffMember(nil), s.staffMembers...), nil
+}
+
+func (s *MemStore) IsStaffEmail(ctx context.Context, email string) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+	if email == "" {
+		return false, nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, sm := range s.staffMembers {
+		if strings.EqualFold(sm.Email, email) || strings.EqualFold(sm.GitHubEmail, email) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (s *MemStore) ListCollaborators(ctx context.Context) ([]model.Collaborator, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]model.Collaborator(nil), s.collaborators...), nil
+}
+
+func (s *MemStore) IsStaffGitHubUser(ctx context.Context, githubID int64, login string) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, sm := range s.staffMembers {
+		if sm.GitHubID != nil {
+			if githubID != 0 && *sm.GitHubID == githubID {
+				return true, nil
+			}
+		} else if login != "" && strings.EqualFold(sm.GitHubAccount, login) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (s *MemStore) SetStaffGitHubID(ctx context.Context, staffID uint, githubID int64) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := range s.staffMembers {
+		if s.staffMembers[i].ID == staffID {
+			s.staffMembers[i].GitHubID = &githubID
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %d", db.ErrStaffMemberNotFound, staffID)
+}
+
+func (s *MemStore) IsStaffGitHubAccount(ctx context.Context, githubAccount string) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+	if githubAccount == "" {
+		return false, nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, sm := range s.staffMembers {
+		if strings.EqualFold(sm.GitHubAccount, githubAccount) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (s *MemStore) LogAuditEvent(ctx context.Context, logger *zap.SugaredLogger, event model.AuditLog) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if event.Message == "" {
+		event.Message = event.Action
+	}
+	event.Model = s.newModel("audit_logs")
+	s.auditLog = append(s.auditLog, event)
+	return nil
+}
+
+func (s *MemStore) serviceByName(name string) (*model.Service, error) {
+	for _, svc := range s.services {
+		if svc.Name == name {
+			return &svc, nil
+		}
+	}
+	return nil, fmt.Errorf("failed to get service, %s, by name: record not found", name)
+}
+
+// withMaintainers returns p with its maintainers, and their companies, filled in.
+func (s *MemStore) withMaintainers(p model.Project) model.Project {
+	p.Maintainers = nil
+	for _, ms := range s.memberships {
+		if ms.projectID != p.ID || ms.deletedAt.Valid {
+			continue
+		}
+		for _, m := range s.maintainers {
+			if m.DeletedAt.Valid {
+				continue
+			}
+			if m.ID == ms.maintainerID {
+				p.Maintainers = append(p.Maintainers, s.withRelations(m))
+			}
+		}
+	}
+	return p
+}
+
+// withRelations returns m with its company and identities filled in.
+func (s *MemStore) withRelations(m model.Maintainer) model.Maintainer {
+	m.Identities = nil
+	for _, id := range s.identities {
+		if id.MaintainerID == m.ID {
+			m.Identities = append(m.Identities, id)
+		}
+	}
+	m.Company = model.Company{}
+	if m.CompanyID == nil {
+		return m
+	}
+	for _, c := range s.companies {
+		if c.DeletedAt.Valid {
+			continue
+		}
+		if c.ID == *m.CompanyID {
+			m.Company = c
+		}
+	}
+	return m
+}
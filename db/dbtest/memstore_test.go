@@ -0,0 +1,805 @@
+package dbtest_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"maintainerd/db"
+	"maintainerd/db/dbtest"
+	"maintainerd/model"
+	"maintainerd/validate"
+)
+
+// seedSQL loads the same fixtures as seedMem into a migrated SQLite database.
+func seedSQL(t *testing.T) db.Store {
+	return db.NewSQLStore(seedSQLConn(t))
+}
+
+// seedSQLConn returns the database of seedSQL.
+func seedSQLConn(t *testing.T) *gorm.DB {
+	conn, err := db.Open(":memory:", &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+	require.NoError(t, db.Migrate(conn))
+
+	acme := model.Company{Name: "Acme"}
+	require.NoError(t, conn.Create(&acme).Error)
+	fossa := model.Service{Name: "FOSSA"}
+	require.NoError(t, conn.Create(&fossa).Error)
+	argo := model.Project{Name: "argo", Maturity: model.Graduated}
+	flux := model.Project{Name: "flux", Maturity: model.Graduated}
+	require.NoError(t, conn.Create(&argo).Error)
+	require.NoError(t, conn.Create(&flux).Error)
+	alice := model.Maintainer{Name: "Alice", Email: "alice@example.com", GitHubAccount: "alice",
+		MaintainerStatus: model.ActiveMaintainer, CompanyID: &acme.ID}
+	bob := model.Maintainer{Name: "Bob", Email: "bob@example.com", GitHubAccount: "bob",
+		MaintainerStatus: model.ActiveMaintainer}
+	require.NoError(t, conn.Create(&alice).Error)
+	require.NoError(t, conn.Create(&bob).Error)
+	require.NoError(t, conn.Model(&argo).Association("Maintainers").Append(&alice, &bob))
+	require.NoError(t, conn.Model(&flux).Association("Maintainers").Append(&bob))
+	require.NoError(t, conn.Create(&model.ServiceTeam{ProjectID: argo.ID, ServiceID: fossa.ID, ServiceTeamID: 42}).Error)
+	require.NoError(t, conn.Create(&model.ServiceTeamRolePolicy{ProjectID: argo.ID, ServiceID: fossa.ID, MaintainerRole: 4}).Error)
+	require.NoError(t, conn.Create(&model.StaffMember{Name: "Staff", Email: "staff@cncf.io", GitHubAccount: "staffer"}).Error)
+	require.NoError(t, conn.Create(&model.Collaborator{Name: "Carol", Email: "carol@example.com"}).Error)
+	return conn
+}
+
+func seedMem(t *testing.T) db.Store {
+	s := dbtest.NewMemStore()
+	acme := s.AddCompany("Acme")
+	fossa := s.AddService("FOSSA")
+	argo := s.AddProject(model.Project{Name: "argo", Maturity: model.Graduated})
+	flux := s.AddProject(model.Project{Name: "flux", Maturity: model.Graduated})
+	s.AddMaintainer(model.Maintainer{Name: "Alice", Email: "alice@example.com", GitHubAccount: "alice",
+		MaintainerStatus: model.ActiveMaintainer, CompanyID: &acme.ID}, argo.ID)
+	s.AddMaintainer(model.Maintainer{Name: "Bob", Email: "bob@example.com", GitHubAccount: "bob",
+		MaintainerStatus: model.ActiveMaintainer}, argo.ID, flux.ID)
+	s.AddServiceTeam(model.ServiceTeam{ProjectID: argo.ID, ServiceID: fossa.ID, ServiceTeamID: 42})
+	s.AddServiceTeamRolePolicy(model.ServiceTeamRolePolicy{ProjectID: argo.ID, ServiceID: fossa.ID, MaintainerRole: 4})
+	s.AddStaffMember(model.StaffMember{Name: "Staff", Email: "staff@cncf.io", GitHubAccount: "staffer"})
+	s.AddCollaborator(model.Collaborator{Name: "Carol", Email: "carol@example.com"})
+	return s
+}
+
+// TestMemStoreMatchesSQLStore runs the same queries against both implementations so the fake cannot drift from
+// the real store.
+func TestMemStoreMatchesSQLStore(t *testing.T) {
+	for name, seed := range map[string]func(*testing.T) db.Store{"sql": seedSQL, "mem": seedMem} {
+		t.Run(name, func(t *testing.T) {
+			store := seed(t)
+			ctx := t.Context()
+
+			projects, err := store.GetProjectMapByName(ctx)
+			require.NoError(t, err)
+			require.Len(t, projects, 2)
+			argo := projects["argo"]
+			require.Len(t, argo.Maintainers, 2)
+			assert.ElementsMatch(t, []string{"alice", "bob"},
+				[]string{argo.Maintainers[0].GitHubAccount, argo.Maintainers[1].GitHubAccount})
+
+			maintainers, err := store.GetMaintainersByProject(ctx, projects["flux"].ID)
+			require.NoError(t, err)
+			require.Len(t, maintainers, 1)
+			assert.Equal(t, "bob", maintainers[0].GitHubAccount)
+			_, err = store.GetMaintainersByProject(ctx, 999)
+			assert.ErrorIs(t, err, db.ErrProjectNotFound)
+
+			byEmail, err := store.GetMaintainerMapByEmail(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, "Acme", byEmail["alice@example.com"].Company.Name)
+			byHandle, err := store.GetMaintainerMapByGitHubAccount(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, "Bob", byHandle["bob"].Name)
+
+			using, err := store.GetProjectsUsingService(ctx, 1)
+			require.NoError(t, err)
+			require.Len(t, using, 1)
+			assert.Equal(t, "argo", using[0].Name)
+
+			teams, err := store.GetProjectServiceTeamMap(ctx, "FOSSA")
+			require.NoError(t, err)
+			assert.Equal(t, 42, teams[argo.ID].ServiceTeamID)
+			_, err = store.GetProjectServiceTeamMap(ctx, "Snyk")
+			assert.Error(t, err)
+
+			st, err := store.GetServiceTeamByProject(ctx, argo.ID, 1)
+			require.NoError(t, err)
+			assert.Equal(t, 42, st.ServiceTeamID)
+			st, err = store.GetServiceTeamByProject(ctx, projects["flux"].ID, 1)
+			require.NoError(t, err)
+			assert.Nil(t, st)
+
+			created, err := store.CreateServiceTeam(ctx, projects["flux"].ID, "flux", 43, "flux")
+			require.NoError(t, err)
+			again, err := store.CreateServiceTeam(ctx, projects["flux"].ID, "flux", 43, "flux")
+			require.NoError(t, err)
+			assert.Equal(t, created.ID, again.ID)
+
+			policy, err := store.GetServiceTeamRolePolicy(ctx, argo.ID, "FOSSA")
+			require.NoError(t, err)
+			assert.Equal(t, 4, policy.MaintainerRole)
+			policy, err = store.GetServiceTeamRolePolicy(ctx, projects["flux"].ID, "FOSSA")
+			require.NoError(t, err)
+			assert.Nil(t, policy)
+			require.NoError(t, store.SetServiceTeamRolePolicy(ctx, "FOSSA", model.ServiceTeamRolePolicy{ProjectID: argo.ID, StaffRole: 5}))
+			policy, err = store.GetServiceTeamRolePolicy(ctx, argo.ID, "FOSSA")
+			require.NoError(t, err)
+			assert.Zero(t, policy.MaintainerRole, "setting a policy replaces the old one")
+			assert.Equal(t, 5, policy.StaffRole)
+			require.NoError(t, store.SetServiceTeamRolePolicy(ctx, "FOSSA", model.ServiceTeamRolePolicy{ProjectID: argo.ID}))
+			policy, err = store.GetServiceTeamRolePolicy(ctx, argo.ID, "FOSSA")
+			require.NoError(t, err)
+			assert.Nil(t, policy)
+			err = store.SetServiceTeamRolePolicy(ctx, "FOSSA", model.ServiceTeamRolePolicy{ProjectID: 999, StaffRole: 5})
+			assert.ErrorIs(t, err, db.ErrProjectNotFound)
+
+			staff, err := store.IsStaffEmail(ctx, "STAFF@cncf.io")
+			require.NoError(t, err)
+			assert.True(t, staff)
+			staff, err = store.IsStaffGitHubAccount(ctx, "Staffer")
+			require.NoError(t, err)
+			assert.True(t, staff)
+			staff, err = store.IsStaffGitHubAccount(ctx, "")
+			require.NoError(t, err)
+			assert.False(t, staff)
+			staff, err = store.IsStaffGitHubUser(ctx, 42, "STAFFER")
+			require.NoError(t, err)
+			assert.True(t, staff, "staff without a GitHub user ID are matched on their login")
+			staffMembers, err := store.ListStaffMembers(ctx)
+			require.NoError(t, err)
+			require.NoError(t, store.SetStaffGitHubID(ctx, staffMembers[0].ID, 42))
+			staff, err = store.IsStaffGitHubUser(ctx, 42, "staffer-renamed")
+			require.NoError(t, err)
+			assert.True(t, staff)
+			staff, err = store.IsStaffGitHubUser(ctx, 43, "staffer")
+			require.NoError(t, err)
+			assert.False(t, staff, "once linked to an ID, the login alone is not enough")
+			assert.ErrorIs(t, store.SetStaffGitHubID(ctx, 999, 42), db.ErrStaffMemberNotFound)
+			collaborators, err := store.ListCollaborators(ctx)
+			require.NoError(t, err)
+			require.Len(t, collaborators, 1)
+			assert.Equal(t, "carol@example.com", collaborators[0].Email)
+
+			companies, err := store.ListCompanies(ctx)
+			require.NoError(t, err)
+			assert.Len(t, companies, 1)
+			staffMembers, err = store.ListStaffMembers(ctx)
+			require.NoError(t, err)
+			assert.Len(t, staffMembers, 1)
+
+			require.NoError(t, store.LogAuditEvent(ctx, nil, model.AuditLog{ProjectID: argo.ID, Action: "TEST"}))
+		})
+	}
+}
+
+func TestStoresHonourCancelledContext(t *testing.T) {
+	for name, seed := range map[string]func(*testing.T) db.Store{"sql": seedSQL, "mem": seedMem} {
+		t.Run(name, func(t *testing.T) {
+			store := seed(t)
+			ctx, cancel := context.WithCancel(t.Context())
+			cancel()
+
+			_, err := store.GetProjectMapByName(ctx)
+			assert.ErrorIs(t, err, context.Canceled)
+			_, err = store.GetMaintainersByProject(ctx, 1)
+			assert.ErrorIs(t, err, context.Canceled)
+			_, err = store.IsStaffGitHubAccount(ctx, "staffer")
+			assert.ErrorIs(t, err, context.Canceled)
+			_, err = store.CreateServiceTeam(ctx, 2, "flux", 43, "flux")
+			assert.ErrorIs(t, err, context.Canceled)
+			assert.Error(t, store.LogAuditEvent(ctx, zap.NewNop().Sugar(), model.AuditLog{Action: "TEST"}))
+			assert.ErrorIs(t, store.Ping(ctx), context.Canceled)
+			assert.ErrorIs(t, store.StartMembership(ctx, 1, 2, nil, time.Now()), context.Canceled)
+			_, err = store.MaintainerTenure(ctx, 1)
+			assert.ErrorIs(t, err, context.Canceled)
+			_, err = store.AddMaintainerIdentity(ctx, model.MaintainerIdentity{MaintainerID: 1, Kind: model.IdentityEmail, Value: "a@example.com"})
+			assert.ErrorIs(t, err, context.Canceled)
+			_, err = store.FindCompany(ctx, "Acme")
+			assert.ErrorIs(t, err, context.Canceled)
+
+			teams, err := store.GetProjectServiceTeamMap(t.Context(), "FOSSA")
+			require.NoError(t, err)
+			assert.Len(t, teams, 1, "a cancelled CreateServiceTeam must not write")
+		})
+	}
+}
+
+func TestArchiveAndRestore(t *testing.T) {
+	for name, seed := range map[string]func(*testing.T) db.Store{"sql": seedSQL, "mem": seedMem} {
+		t.Run(name, func(t *testing.T) {
+			store := seed(t)
+			ctx := t.Context()
+			projects, err := store.GetProjectMapByName(ctx)
+			require.NoError(t, err)
+			argo, flux := projects["argo"], projects["flux"]
+			byHandle, err := store.GetMaintainerMapByGitHubAccount(ctx)
+			require.NoError(t, err)
+			bob := byHandle["bob"]
+			maintainerHandles := func(projectID uint) []string {
+				t.Helper()
+				maintainers, err := store.GetMaintainersByProject(ctx, projectID)
+				require.NoError(t, err)
+				var handles []string
+				for _, m := range maintainers {
+					handles = append(handles, m.GitHubAccount)
+				}
+				return handles
+			}
+
+			require.NoError(t, store.ArchiveProject(ctx, argo.ID))
+			assert.ErrorIs(t, store.ArchiveProject(ctx, argo.ID), db.ErrProjectNotFound)
+			projects, err = store.GetProjectMapByName(ctx)
+			require.NoError(t, err)
+			assert.NotContains(t, projects, "argo")
+			_, err = store.GetMaintainersByProject(ctx, argo.ID)
+			assert.ErrorIs(t, err, db.ErrProjectNotFound)
+			teams, err := store.GetProjectServiceTeamMap(ctx, "FOSSA")
+			require.NoError(t, err)
+			assert.Empty(t, teams)
+			archivedProjects, err := store.ListArchivedProjects(ctx)
+			require.NoError(t, err)
+			require.Len(t, archivedProjects, 1)
+			assert.Equal(t, "argo", archivedProjects[0].Name)
+
+			require.NoError(t, store.ArchiveMaintainer(ctx, bob.ID))
+			assert.Empty(t, maintainerHandles(flux.ID))
+			byEmail, err := store.GetMaintainerMapByEmail(ctx)
+			require.NoError(t, err)
+			assert.NotContains(t, byEmail, "bob@example.com")
+
+			// Bob's argo membership was archived with argo, not with bob, so it waits for bob to come back.
+			require.NoError(t, store.RestoreProject(ctx, argo.ID))
+			assert.Equal(t, []string{"alice"}, maintainerHandles(argo.ID))
+			teams, err = store.GetProjectServiceTeamMap(ctx, "FOSSA")
+			require.NoError(t, err)
+			assert.Equal(t, 42, teams[argo.ID].ServiceTeamID)
+
+			require.NoError(t, store.RestoreMaintainer(ctx, bob.ID))
+			assert.ElementsMatch(t, []string{"alice", "bob"}, maintainerHandles(argo.ID))
+			assert.Equal(t, []string{"bob"}, maintainerHandles(flux.ID))
+			assert.ErrorIs(t, store.RestoreMaintainer(ctx, bob.ID), db.ErrNotArchived)
+			assert.ErrorIs(t, store.RestoreMaintainer(ctx, 999), db.ErrMaintainerNotFound)
+			archivedMaintainers, err := store.ListArchivedMaintainers(ctx)
+			require.NoError(t, err)
+			assert.Empty(t, archivedMaintainers)
+
+			companies, err := store.ListCompanies(ctx)
+			require.NoError(t, err)
+			require.Len(t, companies, 1)
+			require.NoError(t, store.ArchiveCompany(ctx, companies[0].ID))
+			companies, err = store.ListCompanies(ctx)
+			require.NoError(t, err)
+			assert.Empty(t, companies)
+			byHandle, err = store.GetMaintainerMapByGitHubAccount(ctx)
+			require.NoError(t, err)
+			assert.Empty(t, byHandle["alice"].Company.Name, "an archived company is not an affiliation")
+			archivedCompanies, err := store.ListArchivedCompanies(ctx)
+			require.NoError(t, err)
+			require.Len(t, archivedCompanies, 1)
+			require.NoError(t, store.RestoreCompany(ctx, archivedCompanies[0].ID))
+			byHandle, err = store.GetMaintainerMapByGitHubAccount(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, "Acme", byHandle["alice"].Company.Name)
+		})
+	}
+}
+
+func TestArchiveMaintainerArchivesServiceTeamLinks(t *testing.T) {
+	// Each seed links a maintainer to argo's FOSSA team and counts the live links.
+	seeds := map[string]func(*testing.T) (db.Store, func(maintainerID uint), func() int){
+		"sql": func(t *testing.T) (db.Store, func(uint), func() int) {
+			conn := seedSQLConn(t)
+			link := func(id uint) {
+				require.NoError(t, conn.Create(&model.ServiceUserTeams{ServiceUserID: 7, ServiceTeamID: 1, MaintainerID: &id}).Error)
+			}
+			live := func() int {
+				var n int64
+				require.NoError(t, conn.Model(&model.ServiceUserTeams{}).Count(&n).Error)
+				return int(n)
+			}
+			return db.NewSQLStore(conn), link, live
+		},
+		"mem": func(t *testing.T) (db.Store, func(uint), func() int) {
+			s := seedMem(t).(*dbtest.MemStore)
+			link := func(id uint) {
+				s.AddServiceUserTeam(model.ServiceUserTeams{ServiceUserID: 7, ServiceTeamID: 1, MaintainerID: &id})
+			}
+			return s, link, func() int { return len(s.ServiceUserTeams()) }
+		},
+	}
+	for name, seed := range seeds {
+		t.Run(name, func(t *testing.T) {
+			store, link, live := seed(t)
+			ctx := t.Context()
+			byHandle, err := store.GetMaintainerMapByGitHubAccount(ctx)
+			require.NoError(t, err)
+			link(byHandle["alice"].ID)
+			link(byHandle["bob"].ID)
+
+			require.NoError(t, store.ArchiveMaintainer(ctx, byHandle["bob"].ID))
+			assert.Equal(t, 1, live(), "bob's link is archived with him")
+			require.NoError(t, store.RestoreMaintainer(ctx, byHandle["bob"].ID))
+			assert.Equal(t, 2, live())
+		})
+	}
+}
+
+func TestMembershipHistory(t *testing.T) {
+	for name, seed := range map[string]func(*testing.T) db.Store{"sql": seedSQL, "mem": seedMem} {
+		t.Run(name, func(t *testing.T) {
+			store := seed(t)
+			ctx := t.Context()
+			projects, err := store.GetProjectMapByName(ctx)
+			require.NoError(t, err)
+			argo, flux := projects["argo"], projects["flux"]
+			byHandle, err := store.GetMaintainerMapByGitHubAccount(ctx)
+			require.NoError(t, err)
+			alice, bob := byHandle["alice"], byHandle["bob"]
+			joined := time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC)
+			left := time.Date(2025, 9, 30, 0, 0, 0, 0, time.UTC)
+			handlesOn := func(projectID uint, on time.Time) []string {
+				t.Helper()
+				maintainers, err := store.MaintainersOnDate(ctx, projectID, on)
+				require.NoError(t, err)
+				var handles []string
+				for _, m := range maintainers {
+					handles = append(handles, m.GitHubAccount)
+				}
+				return handles
+			}
+
+			require.NoError(t, store.StartMembership(ctx, alice.ID, flux.ID, nil, joined))
+			assert.ErrorIs(t, store.StartMembership(ctx, alice.ID, flux.ID, nil, joined), db.ErrAlreadyMember)
+			assert.ErrorIs(t, store.StartMembership(ctx, 999, flux.ID, nil, joined), db.ErrMaintainerNotFound)
+			assert.ErrorIs(t, store.StartMembership(ctx, alice.ID, 999, nil, joined), db.ErrProjectNotFound)
+			maintainers, err := store.GetMaintainersByProject(ctx, flux.ID)
+			require.NoError(t, err)
+			assert.Len(t, maintainers, 2)
+
+			require.NoError(t, store.EndMembership(ctx, alice.ID, flux.ID, "stepped down", left))
+			assert.ErrorIs(t, store.EndMembership(ctx, alice.ID, flux.ID, "stepped down", left), db.ErrNotMember)
+			maintainers, err = store.GetMaintainersByProject(ctx, flux.ID)
+			require.NoError(t, err)
+			require.Len(t, maintainers, 1)
+			assert.Equal(t, "bob", maintainers[0].GitHubAccount)
+
+			assert.Empty(t, handlesOn(flux.ID, joined.Add(-time.Hour)))
+			assert.Equal(t, []string{"alice"}, handlesOn(flux.ID, joined))
+			assert.Equal(t, []string{"alice"}, handlesOn(flux.ID, left.Add(-time.Hour)))
+			assert.Empty(t, handlesOn(flux.ID, left))
+			_, err = store.MaintainersOnDate(ctx, 999, joined)
+			assert.ErrorIs(t, err, db.ErrProjectNotFound)
+
+			// Bob's argo membership predates history, so ending it records a period from the day it was joined.
+			require.NoError(t, store.EndMembership(ctx, bob.ID, argo.ID, "emeritus", left))
+			tenure, err := store.MaintainerTenure(ctx, bob.ID)
+			require.NoError(t, err)
+			require.Len(t, tenure, 1)
+			assert.Equal(t, "argo", tenure[0].Project.Name)
+			require.NotNil(t, tenure[0].EndedAt)
+			assert.True(t, left.Equal(*tenure[0].EndedAt))
+			assert.Equal(t, "emeritus", tenure[0].Reason)
+
+			tenure, err = store.MaintainerTenure(ctx, alice.ID)
+			require.NoError(t, err)
+			require.Len(t, tenure, 1)
+			assert.Equal(t, "flux", tenure[0].Project.Name)
+			assert.Equal(t, model.RoleMaintainer, tenure[0].Role)
+			assert.True(t, joined.Equal(tenure[0].StartedAt))
+			assert.Equal(t, left.Sub(joined), tenure[0].Duration(time.Now()))
+			_, err = store.MaintainerTenure(ctx, 999)
+			assert.ErrorIs(t, err, db.ErrMaintainerNotFound)
+
+			// History survives archiving, which ends the memberships until the maintainer or project is restored.
+			require.NoError(t, store.ArchiveMaintainer(ctx, alice.ID))
+			assert.Equal(t, []string{"alice"}, handlesOn(flux.ID, joined))
+			require.NoError(t, store.RestoreMaintainer(ctx, alice.ID))
+			require.NoError(t, store.StartMembership(ctx, bob.ID, argo.ID, nil, left))
+			require.NoError(t, store.ArchiveMaintainer(ctx, bob.ID))
+			assert.Empty(t, handlesOn(argo.ID, time.Now()), "archived maintainers are not maintainers")
+			tenure, err = store.MaintainerTenure(ctx, bob.ID)
+			require.NoError(t, err)
+			require.Len(t, tenure, 2)
+			assert.True(t, left.Equal(tenure[0].StartedAt))
+			require.NotNil(t, tenure[0].EndedAt)
+			assert.Equal(t, db.ArchiveReason, tenure[0].Reason)
+			require.NoError(t, store.RestoreMaintainer(ctx, bob.ID))
+			assert.Equal(t, []string{"bob"}, handlesOn(argo.ID, time.Now()))
+			require.NoError(t, store.ArchiveProject(ctx, argo.ID))
+			assert.Empty(t, handlesOn(argo.ID, time.Now()))
+			require.NoError(t, store.RestoreProject(ctx, argo.ID))
+			assert.Equal(t, []string{"bob"}, handlesOn(argo.ID, time.Now()))
+
+			require.NoError(t, store.SetMaintainerStatus(ctx, alice.ID, model.EmeritusMaintainer, "stepped down", left))
+			require.NoError(t, store.SetMaintainerStatus(ctx, alice.ID, model.EmeritusMaintainer, "again", left))
+			assert.Error(t, store.SetMaintainerStatus(ctx, alice.ID, "Sleeping", "", left))
+			assert.ErrorIs(t, store.SetMaintainerStatus(ctx, 999, model.RetiredMaintainer, "", left), db.ErrMaintainerNotFound)
+			history, err := store.MaintainerStatusHistory(ctx, alice.ID)
+			require.NoError(t, err)
+			require.Len(t, history, 1)
+			require.NotNil(t, history[0].FromStatus)
+			assert.Equal(t, model.ActiveMaintainer, *history[0].FromStatus)
+			assert.Equal(t, model.EmeritusMaintainer, history[0].ToStatus)
+			assert.True(t, left.Equal(history[0].ChangedAt))
+			assert.Equal(t, "stepped down", history[0].Reason)
+			byHandle, err = store.GetMaintainerMapByGitHubAccount(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, model.EmeritusMaintainer, byHandle["alice"].MaintainerStatus)
+		})
+	}
+}
+
+func TestCompanyAffiliations(t *testing.T) {
+	// Both stores with a second company, Globex.
+	for name, seed := range map[string]func(*testing.T) db.Store{
+		"sql": func(t *testing.T) db.Store {
+			conn := seedSQLConn(t)
+			require.NoError(t, conn.Create(&model.Company{Name: "Globex"}).Error)
+			return db.NewSQLStore(conn)
+		},
+		"mem": func(t *testing.T) db.Store {
+			s := seedMem(t).(*dbtest.MemStore)
+			s.AddCompany("Globex")
+			return s
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			store := seed(t)
+			ctx := t.Context()
+			byHandle, err := store.GetMaintainerMapByGitHubAccount(ctx)
+			require.NoError(t, err)
+			alice, bob := byHandle["alice"], byHandle["bob"]
+			joined := time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC)
+			moved := time.Date(2025, 9, 30, 0, 0, 0, 0, time.UTC)
+
+			acme, err := store.FindCompany(ctx, "ACME, Inc.")
+			require.NoError(t, err)
+			assert.Equal(t, "Acme", acme.Name)
+			globex, err := store.FindCompany(ctx, "globex")
+			require.NoError(t, err)
+			_, err = store.FindCompany(ctx, "Initech")
+			assert.ErrorIs(t, err, db.ErrCompanyNotFound)
+
+			require.NoError(t, store.AddCompanyAlias(ctx, acme.ID, "Acme Labs"))
+			require.NoError(t, store.AddCompanyAlias(ctx, acme.ID, "ACME LABS LLC"))
+			found, err := store.FindCompany(ctx, "Acme Labs Ltd")
+			require.NoError(t, err)
+			assert.Equal(t, acme.ID, found.ID)
+			assert.ErrorIs(t, store.AddCompanyAlias(ctx, globex.ID, "acme labs"), db.ErrCompanyAliasTaken)
+			assert.ErrorIs(t, store.AddCompanyAlias(ctx, 999, "Initech"), db.ErrCompanyNotFound)
+			assert.Error(t, store.AddCompanyAlias(ctx, acme.ID, "..."))
+
+			require.NoError(t, store.SetMaintainerCompany(ctx, bob.ID, &globex.ID, "", joined))
+			require.NoError(t, store.SetMaintainerCompany(ctx, bob.ID, &globex.ID, "again", joined))
+			require.NoError(t, store.SetMaintainerCompany(ctx, bob.ID, &acme.ID, "joined Acme", moved))
+			require.NoError(t, store.SetMaintainerCompany(ctx, alice.ID, nil, "left Acme", moved))
+			assert.ErrorIs(t, store.SetMaintainerCompany(ctx, 999, nil, "", moved), db.ErrMaintainerNotFound)
+			missing := uint(999)
+			assert.ErrorIs(t, store.SetMaintainerCompany(ctx, bob.ID, &missing, "", moved), db.ErrCompanyNotFound)
+
+			periods, err := store.MaintainerAffiliations(ctx, bob.ID)
+			require.NoError(t, err)
+			require.Len(t, periods, 2)
+			assert.Equal(t, "Globex", periods[0].Company.Name)
+			assert.True(t, joined.Equal(periods[0].StartedAt))
+			require.NotNil(t, periods[0].EndedAt)
+			assert.True(t, moved.Equal(*periods[0].EndedAt))
+			assert.Equal(t, "joined Acme", periods[0].Reason)
+			assert.Equal(t, "Acme", periods[1].Company.Name)
+			assert.Nil(t, periods[1].EndedAt)
+			assert.True(t, periods[1].Covers(moved))
+			assert.False(t, periods[1].Covers(joined))
+
+			// Alice's company predates affiliation history, so leaving it records nothing.
+			periods, err = store.MaintainerAffiliations(ctx, alice.ID)
+			require.NoError(t, err)
+			assert.Empty(t, periods)
+			_, err = store.MaintainerAffiliations(ctx, 999)
+			assert.ErrorIs(t, err, db.ErrMaintainerNotFound)
+
+			byHandle, err = store.GetMaintainerMapByGitHubAccount(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, "Acme", byHandle["bob"].Company.Name)
+			assert.Nil(t, byHandle["alice"].CompanyID)
+		})
+	}
+}
+
+func TestMembershipRoles(t *testing.T) {
+	for name, seed := range map[string]func(*testing.T) db.Store{"sql": seedSQL, "mem": seedMem} {
+		t.Run(name, func(t *testing.T) {
+			store := seed(t)
+			ctx := t.Context()
+			projects, err := store.GetProjectMapByName(ctx)
+			require.NoError(t, err)
+			argo := projects["argo"]
+			byHandle, err := store.GetMaintainerMapByGitHubAccount(ctx)
+			require.NoError(t, err)
+			alice := byHandle["alice"]
+			promoted := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+
+			memberships, err := store.GetProjectMemberships(ctx, argo.ID)
+			require.NoError(t, err)
+			require.Len(t, memberships, 2)
+			assert.Equal(t, "alice", memberships[0].Maintainer.GitHubAccount)
+			assert.Equal(t, "Acme", memberships[0].Maintainer.Company.Name)
+			assert.Equal(t, model.MembershipRoles{model.RoleMaintainer}, memberships[0].Roles)
+			_, err = store.GetProjectMemberships(ctx, 999)
+			assert.ErrorIs(t, err, db.ErrProjectNotFound)
+
+			roles := model.MembershipRoles{model.RoleSecurityContact, model.RoleLead}
+			require.NoError(t, store.SetMembershipRoles(ctx, alice.ID, argo.ID, roles, promoted))
+			memberships, err = store.GetProjectMemberships(ctx, argo.ID)
+			require.NoError(t, err)
+			assert.Equal(t, model.MembershipRoles{model.RoleLead, model.RoleSecurityContact}, memberships[0].Roles)
+			assert.Error(t, store.SetMembershipRoles(ctx, alice.ID, argo.ID, model.MembershipRoles{"owner"}, promoted))
+			assert.ErrorIs(t, store.SetMembershipRoles(ctx, alice.ID, 999, roles, promoted), db.ErrNotMember)
+
+			// Alice's maintainer role predates history, so only the roles she gained have periods.
+			tenure, err := store.MaintainerTenure(ctx, alice.ID)
+			require.NoError(t, err)
+			var held []model.MembershipRole
+			for _, p := range tenure {
+				assert.True(t, promoted.Equal(p.StartedAt))
+				assert.Nil(t, p.EndedAt)
+				held = append(held, p.Role)
+			}
+			assert.ElementsMatch(t, []model.MembershipRole{model.RoleLead, model.RoleSecurityContact}, held)
+
+			require.NoError(t, store.SetMembershipRoles(ctx, alice.ID, argo.ID, model.MembershipRoles{model.RoleLead}, promoted.Add(time.Hour)))
+			tenure, err = store.MaintainerTenure(ctx, alice.ID)
+			require.NoError(t, err)
+			for _, p := range tenure {
+				if p.Role == model.RoleSecurityContact {
+					require.NotNil(t, p.EndedAt)
+					assert.Equal(t, "role removed", p.Reason)
+				} else {
+					assert.Nil(t, p.EndedAt)
+				}
+			}
+
+			require.NoError(t, store.EndMembership(ctx, alice.ID, argo.ID, "stepped down", promoted.Add(2*time.Hour)))
+			tenure, err = store.MaintainerTenure(ctx, alice.ID)
+			require.NoError(t, err)
+			for _, p := range tenure {
+				assert.NotNil(t, p.EndedAt, p.Role)
+			}
+		})
+	}
+}
+
+func TestMaintainerIdentities(t *testing.T) {
+	for name, seed := range map[string]func(*testing.T) db.Store{"sql": seedSQL, "mem": seedMem} {
+		t.Run(name, func(t *testing.T) {
+			store := seed(t)
+			ctx := t.Context()
+			byHandle, err := store.GetMaintainerMapByGitHubAccount(ctx)
+			require.NoError(t, err)
+			alice, bob := byHandle["alice"], byHandle["bob"]
+
+			work, err := store.AddMaintainerIdentity(ctx, model.MaintainerIdentity{MaintainerID: alice.ID,
+				Kind: model.IdentityEmail, Value: "Alice@Work.example", Verified: true})
+			require.NoError(t, err)
+			assert.Equal(t, "alice@work.example", work.Value)
+			_, err = store.AddMaintainerIdentity(ctx, model.MaintainerIdentity{MaintainerID: alice.ID,
+				Kind: model.IdentityService, Service: "FOSSA", Value: "1234"})
+			require.NoError(t, err)
+
+			byEmail, err := store.GetMaintainerMapByEmail(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, alice.ID, byEmail["alice@work.example"].ID)
+			assert.Equal(t, alice.ID, byEmail["alice@example.com"].ID)
+			found, err := store.FindMaintainerByIdentity(ctx, model.MaintainerIdentity{Kind: model.IdentityService, Service: "FOSSA", Value: "1234"})
+			require.NoError(t, err)
+			assert.Equal(t, alice.ID, found.ID)
+			assert.Len(t, found.Identities, 2)
+			found, err = store.FindMaintainerByIdentity(ctx, model.MaintainerIdentity{Kind: model.IdentityGitHub, Value: "@Bob"})
+			require.NoError(t, err)
+			assert.Equal(t, bob.ID, found.ID, "logins are matched against the maintainer's own column")
+			_, err = store.FindMaintainerByIdentity(ctx, model.MaintainerIdentity{Kind: model.IdentityEmail, Value: "nobody@example.com"})
+			assert.ErrorIs(t, err, db.ErrMaintainerNotFound)
+
+			_, err = store.AddMaintainerIdentity(ctx, model.MaintainerIdentity{MaintainerID: bob.ID, Kind: model.IdentityEmail, Value: "alice@work.example"})
+			assert.ErrorIs(t, err, db.ErrIdentityTaken)
+			_, err = store.AddMaintainerIdentity(ctx, model.MaintainerIdentity{MaintainerID: bob.ID, Kind: model.IdentityEmail, Value: "alice@example.com"})
+			assert.ErrorIs(t, err, db.ErrIdentityTaken, "an email held only in a maintainer's column is taken")
+			_, err = store.AddMaintainerIdentity(ctx, model.MaintainerIdentity{MaintainerID: 999, Kind: model.IdentityEmail, Value: "x@example.com"})
+			assert.ErrorIs(t, err, db.ErrMaintainerNotFound)
+			_, err = store.AddMaintainerIdentity(ctx, model.MaintainerIdentity{MaintainerID: bob.ID, Kind: "phone", Value: "555"})
+			assert.Error(t, err)
+
+			_, err = store.AddMaintainerIdentity(ctx, model.MaintainerIdentity{MaintainerID: alice.ID, Kind: model.IdentityEmail,
+				Value: "alice@work.example", IsPrimary: true})
+			require.NoError(t, err)
+			byHandle, err = store.GetMaintainerMapByGitHubAccount(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, "alice@work.example", byHandle["alice"].Email, "a primary email becomes the maintainer's email")
+			byEmail, err = store.GetMaintainerMapByEmail(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, alice.ID, byEmail["alice@example.com"].ID, "the replaced email is kept")
+			identities, err := store.ListMaintainerIdentities(ctx, alice.ID)
+			require.NoError(t, err)
+			require.Len(t, identities, 3)
+			assert.True(t, identities[0].IsPrimary)
+			assert.True(t, identities[0].Verified)
+			assert.Equal(t, "alice@example.com", identities[2].Value)
+			assert.False(t, identities[2].IsPrimary)
+		})
+	}
+}
+
+// seedDuplicates stores the same people twice over: Alice under two names and emails, and Bob as a maintainer and
+// a collaborator. It returns the store and a func counting its audit log entries.
+var seedDuplicates = map[string]func(*testing.T) (db.Store, func() int){
+	"sql": func(t *testing.T) (db.Store, func() int) {
+		conn, err := db.Open(":memory:", &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+		require.NoError(t, err)
+		require.NoError(t, db.Migrate(conn))
+		acme := model.Company{Name: "Acme"}
+		require.NoError(t, conn.Create(&acme).Error)
+		require.NoError(t, conn.Create(&model.Project{Name: "argo", Maturity: model.Graduated}).Error)
+		require.NoError(t, conn.Create(&model.Project{Name: "flux", Maturity: model.Graduated}).Error)
+		for _, m := range duplicateMaintainers(acme.ID) {
+			require.NoError(t, conn.Create(&m).Error)
+		}
+		require.NoError(t, conn.Create(&model.Collaborator{Name: "Robert", Email: "BOB@example.com", GitHubAccount: ptr("bob")}).Error)
+		require.NoError(t, conn.Create(&model.StaffMember{Name: "Staff", Email: "staff@cncf.io", GitHubAccount: "staffer"}).Error)
+		return db.NewSQLStore(conn), func() int {
+			var n int64
+			require.NoError(t, conn.Model(&model.AuditLog{}).Count(&n).Error)
+			return int(n)
+		}
+	},
+	"mem": func(t *testing.T) (db.Store, func() int) {
+		s := dbtest.NewMemStore()
+		acme := s.AddCompany("Acme")
+		s.AddProject(model.Project{Name: "argo", Maturity: model.Graduated})
+		s.AddProject(model.Project{Name: "flux", Maturity: model.Graduated})
+		for _, m := range duplicateMaintainers(acme.ID) {
+			s.AddMaintainer(m)
+		}
+		s.AddCollaborator(model.Collaborator{Name: "Robert", Email: "BOB@example.com", GitHubAccount: ptr("bob")})
+		s.AddStaffMember(model.StaffMember{Name: "Staff", Email: "staff@cncf.io", GitHubAccount: "staffer"})
+		return s, func() int { return len(s.AuditEvents()) }
+	},
+}
+
+func duplicateMaintainers(companyID uint) []model.Maintainer {
+	return []model.Maintainer{
+		{Name: "Alice Smith", Email: "alice@example.com", GitHubAccount: "alice", MaintainerStatus: model.ActiveMaintainer},
+		{Name: "Smith, Alice", Email: "alice@work.example", GitHubAccount: "alice-work", MaintainerStatus: model.ActiveMaintainer,
+			CompanyID: &companyID},
+		{Name: "Bob", Email: "bob@example.com", GitHubAccount: "bob", MaintainerStatus: model.ActiveMaintainer},
+	}
+}
+
+func ptr[T any](v T) *T { return &v }
+
+func TestFindAndMergeDuplicates(t *testing.T) {
+	for name, seed := range seedDuplicates {
+		t.Run(name, func(t *testing.T) {
+			store, auditEntries := seed(t)
+			ctx := t.Context()
+			joined := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+			require.NoError(t, store.StartMembership(ctx, 1, 1, nil, joined))
+			require.NoError(t, store.StartMembership(ctx, 2, 1, model.MembershipRoles{model.RoleLead}, joined))
+			require.NoError(t, store.StartMembership(ctx, 2, 2, nil, joined))
+
+			groups, err := store.FindDuplicatePeople(ctx)
+			require.NoError(t, err)
+			require.Len(t, groups, 2)
+			assert.Equal(t, []db.PersonRef{{Kind: db.PersonMaintainer, ID: 1}, {Kind: db.PersonMaintainer, ID: 2}},
+				[]db.PersonRef{groups[0].People[0].PersonRef, groups[0].People[1].PersonRef})
+			assert.Equal(t, []string{"name alice smith"}, groups[0].Reasons)
+			assert.Equal(t, db.PersonRef{Kind: db.PersonCollaborator, ID: 1}, groups[1].People[1].PersonRef)
+			assert.Equal(t, []string{"email bob@example.com", "github bob"}, groups[1].Reasons)
+
+			dup := db.PersonRef{Kind: db.PersonMaintainer, ID: 2}
+			preview, err := store.MergePeople(ctx, 1, dup, true)
+			require.NoError(t, err)
+			assert.True(t, preview.DryRun)
+			assert.Contains(t, preview.Changes, "membership of project 1 merged into the survivor's, roles lead,maintainer")
+			assert.Contains(t, preview.Changes, "membership of project 2 moved")
+			assert.Contains(t, preview.Changes, "email identity recorded")
+			for _, change := range preview.Changes {
+				assert.NotContains(t, change, "@", "merge reports are audit logged and must not name emails")
+			}
+			groups, err = store.FindDuplicatePeople(ctx)
+			require.NoError(t, err)
+			assert.Len(t, groups, 2, "a dry run changes nothing")
+			assert.Zero(t, auditEntries())
+
+			report, err := store.MergePeople(ctx, 1, dup, false)
+			require.NoError(t, err)
+			assert.Equal(t, preview.Changes, report.Changes, "the dry run reports what the merge does")
+			assert.Equal(t, 1, auditEntries())
+			memberships, err := store.GetProjectMemberships(ctx, 1)
+			require.NoError(t, err)
+			require.Len(t, memberships, 1)
+			assert.Equal(t, model.MembershipRoles{model.RoleLead, model.RoleMaintainer}, memberships[0].Roles)
+			memberships, err = store.GetProjectMemberships(ctx, 2)
+			require.NoError(t, err)
+			require.Len(t, memberships, 1)
+			assert.Equal(t, uint(1), memberships[0].MaintainerID)
+			assert.Equal(t, "Acme", memberships[0].Maintainer.Company.Name, "the survivor takes the duplicate's company")
+			byEmail, err := store.GetMaintainerMapByEmail(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, uint(1), byEmail["alice@work.example"].ID)
+			tenure, err := store.MaintainerTenure(ctx, 1)
+			require.NoError(t, err)
+			assert.Len(t, tenure, 4, "the duplicate's membership history moves to the survivor")
+			_, err = store.ListMaintainerIdentities(ctx, 2)
+			assert.ErrorIs(t, err, db.ErrMaintainerNotFound)
+
+			report, err = store.MergePeople(ctx, 3, db.PersonRef{Kind: db.PersonCollaborator, ID: 1}, false)
+			require.NoError(t, err)
+			assert.Equal(t, []string{"collaborator 1 deleted", "email identity recorded", "github identity recorded"}, report.Changes)
+			groups, err = store.FindDuplicatePeople(ctx)
+			require.NoError(t, err)
+			assert.Empty(t, groups)
+
+			_, err = store.MergePeople(ctx, 3, db.PersonRef{Kind: db.PersonStaff, ID: 1}, false)
+			assert.ErrorIs(t, err, db.ErrInvalidMerge)
+			_, err = store.MergePeople(ctx, 3, db.PersonRef{Kind: db.PersonMaintainer, ID: 3}, true)
+			assert.ErrorIs(t, err, db.ErrInvalidMerge)
+			_, err = store.MergePeople(ctx, 3, db.PersonRef{Kind: db.PersonCollaborator, ID: 1}, false)
+			assert.ErrorIs(t, err, db.ErrCollaboratorNotFound)
+			_, err = store.MergePeople(ctx, 2, db.PersonRef{Kind: db.PersonMaintainer, ID: 3}, false)
+			assert.ErrorIs(t, err, db.ErrMaintainerNotFound)
+			assert.Equal(t, 2, auditEntries(), "failed merges are not audit logged")
+		})
+	}
+}
+
+func TestFindings(t *testing.T) {
+	for name, seed := range map[string]func(*testing.T) db.Store{"sql": seedSQL, "mem": seedMem} {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			store := seed(t)
+			maintainers, err := store.ListMaintainers(ctx)
+			require.NoError(t, err)
+			require.Len(t, maintainers, 2)
+			alice := maintainers[0]
+			assert.Equal(t, "alice", alice.GitHubAccount)
+
+			reg, err := validate.Load(ctx, store)
+			require.NoError(t, err)
+			require.Len(t, reg.Projects, 2)
+			findings, err := validate.Run(ctx, reg, validate.Rules(nil))
+			require.NoError(t, err)
+			require.Len(t, findings, 2)
+			for _, f := range findings {
+				assert.Equal(t, "missing-mailing-list", f.Rule)
+			}
+
+			findings = append(findings, model.Finding{Rule: "invalid-email", Severity: model.SeverityError,
+				SubjectKind: "maintainer", SubjectID: alice.ID, Subject: "@alice", Message: "1 of 1 email addresses are not valid"})
+			require.NoError(t, store.ReplaceFindings(ctx, findings))
+			stored, err := store.ListFindings(ctx)
+			require.NoError(t, err)
+			require.Len(t, stored, 3)
+			assert.NotZero(t, stored[2].ID)
+			assert.Equal(t, "@alice", stored[2].Subject)
+			maintainers, err = store.ListMaintainers(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, "invalid-email: 1 of 1 email addresses are not valid", maintainers[0].ImportWarnings)
+			assert.Empty(t, maintainers[1].ImportWarnings)
+
+			// A clean run clears the previous findings and warnings.
+			require.NoError(t, store.ReplaceFindings(ctx, nil))
+			stored, err = store.ListFindings(ctx)
+			require.NoError(t, err)
+			assert.Empty(t, stored)
+			maintainers, err = store.ListMaintainers(ctx)
+			require.NoError(t, err)
+			assert.Empty(t, maintainers[0].ImportWarnings)
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package dbtest
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"maintainerd/db"
+	"maintainerd/model"
+)
+
+func (s *MemStore) SetMembershipRoles(ctx context.Context, maintainerID, projectID uint, roles model.MembershipRoles, at time.Time) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	roles, err := membershipRoles(roles)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i := s.membership(maintainerID, projectID)
+	if i < 0 || s.memberships[i].deletedAt.Valid {
+		return fmt.Errorf("%w: maintainer %d, project %d", db.ErrNotMember, maintainerID, projectID)
+	}
+	s.changeRoles(i, roles, historyTime(at))
+	return nil
+}
+
+// changeRoles mirrors the SQLStore helper of the same name for the membership at index i.
+func (s *MemStore) changeRoles(i int, roles model.MembershipRoles, at time.Time) {
+	ms := &s.memberships[i]
+	previous := ms.roles
+	ms.roles = roles
+	for j := range s.periods {
+		p := &s.periods[j]
+		if p.MaintainerID == ms.maintainerID && p.ProjectID == ms.projectID && p.EndedAt == nil && !roles.Has(p.Role) {
+			p.EndedAt, p.Reason = &at, "role removed"
+		}
+	}
+	for _, role := range roles {
+		if !previous.Has(role) {
+			s.addPeriod(model.MembershipPeriod{MaintainerID: ms.maintainerID, ProjectID: ms.projectID, Role: role, StartedAt: at})
+		}
+	}
+}
+
+func (s *MemStore) GetProjectMemberships(ctx context.Context, projectID uint) ([]model.MaintainerProject, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if d := s.deletedAt("projects", projectID); d == nil || d.Valid {
+		return nil, fmt.Errorf("%w: %d", db.ErrProjectNotFound, projectID)
+	}
+	var memberships []model.MaintainerProject
+	for _, m := range s.maintainers {
+		if m.DeletedAt.Valid {
+			continue
+		}
+		i := s.membership(m.ID, projectID)
+		if i < 0 || s.memberships[i].deletedAt.Valid {
+			continue
+		}
+		ms := s.memberships[i]
+		memberships = append(memberships, model.MaintainerProject{
+			MaintainerID: ms.maintainerID,
+			ProjectID:    ms.projectID,
+			JoinedAt:     ms.joinedAt,
+			Roles:        append(model.MembershipRoles(nil), ms.roles...),
+			Maintainer:   s.withRelations(m),
+		})
+	}
+	return memberships, nil
+}
+
+// membershipRoles mirrors the validation SQLStore applies to roles.
+func membershipRoles(roles model.MembershipRoles) (model.MembershipRoles, error) {
+	for _, r := range roles {
+		if !r.IsValid() {
+			return nil, fmt.Errorf("invalid membership role %q", r)
+		}
+	}
+	if len(roles) == 0 {
+		return model.MembershipRoles{model.RoleMaintainer}, nil
+	}
+	return roles.Normalize(), nil
+}
@@ -0,0 +1,460 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"gorm.io/gorm"
+
+	"maintainerd/model"
+)
+
+// A PersonKind names the table a person's record is kept in.
+type PersonKind string
+
+const (
+	PersonMaintainer   PersonKind = "maintainer"
+	PersonCollaborator PersonKind = "collaborator"
+	PersonStaff        PersonKind = "staff"
+)
+
+// A PersonRef points at one person record.
+type PersonRef struct {
+	Kind PersonKind `json:"kind"`
+	ID   uint       `json:"id"`
+}
+
+func (r PersonRef) String() string {
+	return fmt.Sprintf("%s %d", r.Kind, r.ID)
+}
+
+// A Person is a maintainer, collaborator or staff member as the duplicate detector sees them: their name, and their
+// emails and GitHub logins normalized the way identities are.
+type Person struct {
+	PersonRef
+	Name           string
+	Emails         []string
+	GitHubAccounts []string
+}
+
+// A DuplicateGroup is a set of person records that likely describe the same person, with the matches that joined
+// them, e.g. "email alice@example.com".
+type DuplicateGroup struct {
+	People  []Person
+	Reasons []string
+}
+
+// A MergeReport lists what MergePeople changed, or with DryRun would change, to fold Duplicate into the maintainer
+// with ID Survivor.
+type MergeReport struct {
+	Survivor  uint      `json:"survivor"`
+	Duplicate PersonRef `json:"duplicate"`
+	DryRun    bool      `json:"dryRun"`
+	Changes   []string  `json:"changes"`
+}
+
+func (r *MergeReport) add(format string, args ...interface{}) {
+	r.Changes = append(r.Changes, fmt.Sprintf(format, args...))
+}
+
+// errDryRun rolls back the transaction of a dry-run merge.
+var errDryRun = errors.New("dry run")
+
+// GroupDuplicates groups people who share a normalized email or GitHub login, or whose names have the same words.
+// Names of a single word are too weak a signal and are not compared. People in no group are left out; groups are
+// ordered by their first member, and members by kind and ID.
+func GroupDuplicates(people []Person) []DuplicateGroup {
+	people = slices.Clone(people)
+	sort.SliceStable(people, func(i, j int) bool {
+		if people[i].Kind != people[j].Kind {
+			return personKindOrder(people[i].Kind) < personKindOrder(people[j].Kind)
+		}
+		return people[i].ID < people[j].ID
+	})
+
+	parent := make([]int, len(people))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+
+	// Each key is claimed by the first person holding it; later holders join that person's group.
+	claimed := map[string]int{}
+	reasons := map[int][]string{} // by the index of the person who claimed the key
+	for i, p := range people {
+		var keys []string
+		for _, e := range p.Emails {
+			keys = append(keys, "email "+e)
+		}
+		for _, g := range p.GitHubAccounts {
+			keys = append(keys, "github "+g)
+		}
+		if name := normalizeName(p.Name); strings.Contains(name, " ") {
+			keys = append(keys, "name "+name)
+		}
+		for _, key := range keys {
+			first, ok := claimed[key]
+			if !ok {
+				claimed[key] = i
+				continue
+			}
+			parent[find(i)] = find(first)
+			if !slices.Contains(reasons[first], key) {
+				reasons[first] = append(reasons[first], key)
+			}
+		}
+	}
+
+	byRoot := map[int]*DuplicateGroup{}
+	var roots []int
+	for i, p := range people {
+		root := find(i)
+		g, ok := byRoot[root]
+		if !ok {
+			g = &DuplicateGroup{}
+			byRoot[root] = g
+			roots = append(roots, root)
+		}
+		g.People = append(g.People, p)
+		g.Reasons = append(g.Reasons, reasons[i]...)
+	}
+	var groups []DuplicateGroup
+	for _, root := range roots {
+		if g := byRoot[root]; len(g.People) > 1 {
+			groups = append(groups, *g)
+		}
+	}
+	return groups
+}
+
+func personKindOrder(k PersonKind) int {
+	return slices.Index([]PersonKind{PersonMaintainer, PersonCollaborator, PersonStaff}, k)
+}
+
+// normalizeName lower-cases name, drops punctuation and sorts its words, so "Doe, Jane" and "jane doe" compare equal.
+func normalizeName(name string) string {
+	words := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	sort.Strings(words)
+	return strings.Join(words, " ")
+}
+
+// personValues returns values normalized as identities of kind, skipping placeholders and repeats.
+func personValues(kind model.IdentityKind, values ...string) []string {
+	var out []string
+	for _, v := range values {
+		if model.IsMissing(v) {
+			continue
+		}
+		v = model.MaintainerIdentity{Kind: kind, Value: v}.Normalize().Value
+		if !slices.Contains(out, v) {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// MaintainerPerson returns m as the duplicate detector sees them.
+func MaintainerPerson(m model.Maintainer) Person {
+	return Person{PersonRef: PersonRef{PersonMaintainer, m.ID}, Name: m.Name, Emails: m.Emails(), GitHubAccounts: m.GitHubAccounts()}
+}
+
+// CollaboratorPerson returns c as the duplicate detector sees them.
+func CollaboratorPerson(c model.Collaborator) Person {
+	var githubEmail, githubAccount string
+	if c.GitHubEmail != nil {
+		githubEmail = *c.GitHubEmail
+	}
+	if c.GitHubAccount != nil {
+		githubAccount = *c.GitHubAccount
+	}
+	return Person{
+		PersonRef:      PersonRef{PersonCollaborator, c.ID},
+		Name:           c.Name,
+		Emails:         personValues(model.IdentityEmail, c.Email, githubEmail),
+		GitHubAccounts: personValues(model.IdentityGitHub, githubAccount),
+	}
+}
+
+// StaffPerson returns sm as the duplicate detector sees them.
+func StaffPerson(sm model.StaffMember) Person {
+	return Person{
+		PersonRef:      PersonRef{PersonStaff, sm.ID},
+		Name:           sm.Name,
+		Emails:         personValues(model.IdentityEmail, sm.Email, sm.GitHubEmail),
+		GitHubAccounts: personValues(model.IdentityGitHub, sm.GitHubAccount),
+	}
+}
+
+// FindDuplicatePeople groups the live maintainers, collaborators and staff members that likely describe the same
+// person.
+func (s *SQLStore) FindDuplicatePeople(ctx context.Context) ([]DuplicateGroup, error) {
+	tx := s.db.WithContext(ctx).Session(&gorm.Session{})
+	var maintainers []model.Maintainer
+	if err := tx.Preload("Identities").Order("id").Find(&maintainers).Error; err != nil {
+		return nil, fmt.Errorf("list maintainers: %w", err)
+	}
+	var collaborators []model.Collaborator
+	if err := tx.Order("id").Find(&collaborators).Error; err != nil {
+		return nil, fmt.Errorf("list collaborators: %w", err)
+	}
+	var staff []model.StaffMember
+	if err := tx.Order("id").Find(&staff).Error; err != nil {
+		return nil, fmt.Errorf("list staff members: %w", err)
+	}
+	var people []Person
+	for _, m := range maintainers {
+		people = append(people, MaintainerPerson(m))
+	}
+	for _, c := range collaborators {
+		people = append(people, CollaboratorPerson(c))
+	}
+	for _, sm := range staff {
+		people = append(people, StaffPerson(sm))
+	}
+	return GroupDuplicates(people), nil
+}
+
+// MergePeople folds the duplicate maintainer or collaborator into the surviving maintainer in one transaction,
+// recording the merge in the audit log. With dryRun the transaction is rolled back and only the report is returned.
+func (s *SQLStore) MergePeople(ctx context.Context, survivorID uint, duplicate PersonRef, dryRun bool) (MergeReport, error) {
+	report := MergeReport{Survivor: survivorID, Duplicate: duplicate, DryRun: dryRun}
+	if err := checkMerge(survivorID, duplicate); err != nil {
+		return report, err
+	}
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var survivor model.Maintainer
+		if err := tx.First(&survivor, survivorID).Error; errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("%w: %d", ErrMaintainerNotFound, survivorID)
+		} else if err != nil {
+			return err
+		}
+		var err error
+		if duplicate.Kind == PersonMaintainer {
+			err = mergeMaintainer(tx, survivor, duplicate.ID, &report)
+		} else {
+			err = mergeCollaborator(tx, survivor, duplicate.ID, &report)
+		}
+		if err != nil {
+			return err
+		}
+		metadata, err := json.Marshal(report)
+		if err != nil {
+			return err
+		}
+		err = tx.Create(&model.AuditLog{
+			MaintainerID: &survivor.ID,
+			Action:       "MERGE_PERSON",
+			Message:      fmt.Sprintf("Merged %s into maintainer %d", duplicate, survivor.ID),
+			Metadata:     string(metadata),
+		}).Error
+		if err != nil {
+			return fmt.Errorf("write audit log: %w", err)
+		}
+		if dryRun {
+			return errDryRun
+		}
+		return nil
+	})
+	if errors.Is(err, errDryRun) {
+		err = nil
+	}
+	return report, err
+}
+
+// checkMerge rejects merges MergePeople cannot perform: staff members record a role rather than a person, and
+// a maintainer cannot be merged into themselves.
+func checkMerge(survivorID uint, duplicate PersonRef) error {
+	switch {
+	case duplicate.Kind == PersonStaff:
+		return fmt.Errorf("%w: staff members are not merged, they grant staff permissions", ErrInvalidMerge)
+	case duplicate.Kind != PersonMaintainer && duplicate.Kind != PersonCollaborator:
+		return fmt.Errorf("%w: unknown kind %q", ErrInvalidMerge, duplicate.Kind)
+	case duplicate.Kind == PersonMaintainer && duplicate.ID == survivorID:
+		return fmt.Errorf("%w: maintainer %d cannot be merged into themselves", ErrInvalidMerge, survivorID)
+	}
+	return nil
+}
+
+// mergeMaintainer moves the memberships, history, identities, service team links and audit entries of the maintainer
+// with ID duplicateID to survivor, then deletes the duplicate.
+func mergeMaintainer(tx *gorm.DB, survivor model.Maintainer, duplicateID uint, report *MergeReport) error {
+	var duplicate model.Maintainer
+	if err := tx.Unscoped().First(&duplicate, duplicateID).Error; errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("%w: %d", ErrMaintainerNotFound, duplicateID)
+	} else if err != nil {
+		return err
+	}
+	now := historyTime(time.Now())
+
+	var memberships []model.MaintainerProject
+	if err := tx.Unscoped().Where("maintainer_id = ?", duplicateID).Order("project_id").Find(&memberships).Error; err != nil {
+		return fmt.Errorf("list memberships of maintainer %d: %w", duplicateID, err)
+	}
+	for _, mp := range memberships {
+		var kept model.MaintainerProject
+		err := tx.Unscoped().Where("maintainer_id = ? AND project_id = ?", survivor.ID, mp.ProjectID).First(&kept).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err = tx.Unscoped().Model(&model.MaintainerProject{}).
+				Where("maintainer_id = ? AND project_id = ?", duplicateID, mp.ProjectID).
+				Update("maintainer_id", survivor.ID).Error
+			if err != nil {
+				return fmt.Errorf("move membership of project %d: %w", mp.ProjectID, err)
+			}
+			report.add("membership of project %d moved", mp.ProjectID)
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("look up membership of maintainer %d on project %d: %w", survivor.ID, mp.ProjectID, err)
+		}
+		if kept.DeletedAt.Valid && !mp.DeletedAt.Valid {
+			// Only the duplicate is still a member: their live membership, and its open periods, replace the
+			// survivor's archived one.
+			err = tx.Unscoped().Where("maintainer_id = ? AND project_id = ?", survivor.ID, mp.ProjectID).
+				Delete(&model.MaintainerProject{}).Error
+			if err != nil {
+				return fmt.Errorf("remove archived membership of maintainer %d on project %d: %w", survivor.ID, mp.ProjectID, err)
+			}
+			err = tx.Unscoped().Model(&model.MaintainerProject{}).
+				Where("maintainer_id = ? AND project_id = ?", duplicateID, mp.ProjectID).
+				Update("maintainer_id", survivor.ID).Error
+			if err != nil {
+				return fmt.Errorf("move membership of project %d: %w", mp.ProjectID, err)
+			}
+			report.add("membership of project %d moved, replacing the survivor's archived one", mp.ProjectID)
+			continue
+		}
+		// Both are members: keep the survivor's membership with the roles of both, and close the duplicate's periods.
+		roles := append(slices.Clone(kept.Roles), mp.Roles...).Normalize()
+		if !kept.DeletedAt.Valid && !slices.Equal(roles, kept.Roles) {
+			if err := changeRoles(tx, kept, roles, now); err != nil {
+				return err
+			}
+		}
+		err = tx.Unscoped().Where("maintainer_id = ? AND project_id = ?", duplicateID, mp.ProjectID).
+			Delete(&model.MaintainerProject{}).Error
+		if err != nil {
+			return fmt.Errorf("remove membership of maintainer %d on project %d: %w", duplicateID, mp.ProjectID, err)
+		}
+		err = tx.Model(&model.MembershipPeriod{}).
+			Where("maintainer_id = ? AND project_id = ? AND ended_at IS NULL", duplicateID, mp.ProjectID).
+			Updates(map[string]interface{}{"ended_at": now, "reason": fmt.Sprintf("merged into maintainer %d", survivor.ID)}).Error
+		if err != nil {
+			return fmt.Errorf("close periods of maintainer %d on project %d: %w", duplicateID, mp.ProjectID, err)
+		}
+		report.add("membership of project %d merged into the survivor's, roles %s", mp.ProjectID, strings.Join(roles.Strings(), ","))
+	}
+
+	if survivor.CompanyID != nil {
+		// The survivor's company stands; the duplicate's affiliation ends with the merge.
+		err := tx.Model(&model.AffiliationPeriod{}).
+			Where("maintainer_id = ? AND ended_at IS NULL", duplicateID).
+			Updates(map[string]interface{}{"ended_at": now, "reason": fmt.Sprintf("merged into maintainer %d", survivor.ID)}).Error
+		if err != nil {
+			return fmt.Errorf("close affiliation of maintainer %d: %w", duplicateID, err)
+		}
+	}
+	for _, ref := range []struct {
+		model interface{}
+		label string
+	}{
+		{&model.MembershipPeriod{}, "membership periods"},
+		{&model.AffiliationPeriod{}, "affiliation periods"},
+		{&model.MaintainerStatusChange{}, "status changes"},
+		{&model.MaintainerIdentity{}, "identities"},
+		{&model.ServiceUserTeams{}, "service team links"},
+		{&model.AuditLog{}, "audit log entries"},
+	} {
+		res := tx.Unscoped().Model(ref.model).Where("maintainer_id = ?", duplicateID).Update("maintainer_id", survivor.ID)
+		if res.Error != nil {
+			return fmt.Errorf("move %s of maintainer %d: %w", ref.label, duplicateID, res.Error)
+		}
+		if res.RowsAffected > 0 {
+			report.add("%d %s moved", res.RowsAffected, ref.label)
+		}
+	}
+
+	if survivor.CompanyID == nil && duplicate.CompanyID != nil {
+		if err := tx.Model(&survivor).Update("company_id", duplicate.CompanyID).Error; err != nil {
+			return fmt.Errorf("set company of maintainer %d: %w", survivor.ID, err)
+		}
+		report.add("company %d taken from the duplicate", *duplicate.CompanyID)
+	}
+	if err := tx.Unscoped().Delete(&model.Maintainer{}, duplicateID).Error; err != nil {
+		return fmt.Errorf("delete maintainer %d: %w", duplicateID, err)
+	}
+	report.add("maintainer %d deleted", duplicateID)
+	return recordMergedIdentities(tx, survivor.ID, duplicate.DeclaredIdentities(), report)
+}
+
+// mergeCollaborator links the service teams of the collaborator with ID collaboratorID to survivor, records the
+// collaborator's emails and login as identities of survivor and deletes the collaborator.
+func mergeCollaborator(tx *gorm.DB, survivor model.Maintainer, collaboratorID uint, report *MergeReport) error {
+	var c model.Collaborator
+	if err := tx.Unscoped().First(&c, collaboratorID).Error; errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("%w: %d", ErrCollaboratorNotFound, collaboratorID)
+	} else if err != nil {
+		return err
+	}
+	res := tx.Unscoped().Model(&model.ServiceUserTeams{}).Where("collaborator_id = ?", collaboratorID).
+		Updates(map[string]interface{}{"maintainer_id": survivor.ID, "collaborator_id": nil})
+	if res.Error != nil {
+		return fmt.Errorf("move service team links of collaborator %d: %w", collaboratorID, res.Error)
+	}
+	if res.RowsAffected > 0 {
+		report.add("%d service team links moved", res.RowsAffected)
+	}
+	// Collaborator projects are the teams a contributor was signed up to; as a maintainer the survivor's memberships
+	// say which projects they belong to.
+	res = tx.Exec("DELETE FROM collaborator_projects WHERE collaborator_id = ?", collaboratorID)
+	if res.Error != nil {
+		return fmt.Errorf("remove projects of collaborator %d: %w", collaboratorID, res.Error)
+	}
+	if res.RowsAffected > 0 {
+		report.add("%d collaborator projects dropped", res.RowsAffected)
+	}
+	if err := tx.Unscoped().Delete(&model.Collaborator{}, collaboratorID).Error; err != nil {
+		return fmt.Errorf("delete collaborator %d: %w", collaboratorID, err)
+	}
+	report.add("collaborator %d deleted", collaboratorID)
+
+	p := CollaboratorPerson(c)
+	var identities []model.MaintainerIdentity
+	for _, e := range p.Emails {
+		identities = append(identities, model.MaintainerIdentity{Kind: model.IdentityEmail, Value: e})
+	}
+	for _, g := range p.GitHubAccounts {
+		identities = append(identities, model.MaintainerIdentity{Kind: model.IdentityGitHub, Value: g})
+	}
+	return recordMergedIdentities(tx, survivor.ID, identities, report)
+}
+
+// recordMergedIdentities records identities of a deleted duplicate as secondary identities of the survivor. The
+// report, which is audit logged, names identities by kind only, never by their values.
+func recordMergedIdentities(tx *gorm.DB, survivorID uint, identities []model.MaintainerIdentity, report *MergeReport) error {
+	for _, id := range identities {
+		id.MaintainerID, id.IsPrimary = survivorID, false
+		if _, err := recordIdentity(tx, id); errors.Is(err, ErrIdentityTaken) {
+			report.add("%s identity not recorded, it belongs to another maintainer", id.Kind)
+			continue
+		} else if err != nil {
+			return err
+		}
+		report.add("%s identity recorded", id.Kind)
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"maintainerd/model"
+)
+
+func TestGroupDuplicates(t *testing.T) {
+	people := []Person{
+		StaffPerson(model.StaffMember{Model: gorm.Model{ID: 1}, Name: "Carol Jones", Email: "carol@cncf.io", GitHubAccount: "GITHUB_MISSING"}),
+		MaintainerPerson(model.Maintainer{Model: gorm.Model{ID: 1}, Name: "Carol", Email: "carol@example.com", GitHubAccount: "carolj"}),
+		MaintainerPerson(model.Maintainer{Model: gorm.Model{ID: 2}, Name: "Carol", Email: "EMAIL_MISSING", GitHubAccount: "GITHUB_MISSING"}),
+		MaintainerPerson(model.Maintainer{Model: gorm.Model{ID: 3}, Name: "Dave", Email: "EMAIL_MISSING", GitHubAccount: "GITHUB_MISSING"}),
+		CollaboratorPerson(model.Collaborator{Model: gorm.Model{ID: 1}, Name: "jones carol", Email: "Carol@Example.com"}),
+	}
+
+	groups := GroupDuplicates(people)
+	require.Len(t, groups, 1, "single-word names and placeholders do not match")
+	var refs []PersonRef
+	for _, p := range groups[0].People {
+		refs = append(refs, p.PersonRef)
+	}
+	assert.Equal(t, []PersonRef{{PersonMaintainer, 1}, {PersonCollaborator, 1}, {PersonStaff, 1}}, refs,
+		"people are grouped transitively, maintainers first")
+	assert.ElementsMatch(t, []string{"email carol@example.com", "name carol jones"}, groups[0].Reasons)
+}
+
+func TestMergeCollaboratorMovesServiceTeamLinks(t *testing.T) {
+	conn := openEmptyDB(t)
+	require.NoError(t, Migrate(conn))
+	maintainer := model.Maintainer{Name: "Bob", Email: "bob@example.com", GitHubAccount: "bob", MaintainerStatus: model.ActiveMaintainer}
+	require.NoError(t, conn.Create(&maintainer).Error)
+	collaborator := model.Collaborator{Name: "Bob", Email: "bob@example.com"}
+	require.NoError(t, conn.Create(&collaborator).Error)
+	project := model.Project{Name: "argo", Maturity: model.Graduated}
+	require.NoError(t, conn.Create(&project).Error)
+	require.NoError(t, conn.Model(&collaborator).Association("Projects").Append(&project))
+	link := model.ServiceUserTeams{ServiceUserID: 7, CollaboratorID: &collaborator.ID}
+	require.NoError(t, conn.Create(&link).Error)
+	store := NewSQLStore(conn)
+
+	report, err := store.MergePeople(t.Context(), maintainer.ID, PersonRef{PersonCollaborator, collaborator.ID}, false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1 service team links moved", "1 collaborator projects dropped", "collaborator 1 deleted",
+		"email identity recorded"}, report.Changes)
+
+	require.NoError(t, conn.First(&link, link.ID).Error)
+	require.NotNil(t, link.MaintainerID)
+	assert.Equal(t, maintainer.ID, *link.MaintainerID)
+	assert.Nil(t, link.CollaboratorID)
+	var audit model.AuditLog
+	require.NoError(t, conn.Where("action = ?", "MERGE_PERSON").First(&audit).Error)
+	assert.Contains(t, audit.Metadata, `"kind":"collaborator"`)
+	assert.NotContains(t, audit.Metadata, "bob@example.com", "the audit log names no emails")
+}
+
+func TestMergeMaintainerReplacesArchivedMembership(t *testing.T) {
+	conn := openEmptyDB(t)
+	require.NoError(t, Migrate(conn))
+	ctx := t.Context()
+	store := NewSQLStore(conn)
+	project := model.Project{Name: "argo", Maturity: model.Graduated}
+	require.NoError(t, conn.Create(&project).Error)
+	survivor := model.Maintainer{Name: "Alice", Email: "alice@example.com", GitHubAccount: "alice", MaintainerStatus: model.ActiveMaintainer}
+	duplicate := model.Maintainer{Name: "Alice", Email: "alice@work.example", MaintainerStatus: model.ActiveMaintainer}
+	require.NoError(t, conn.Create(&survivor).Error)
+	require.NoError(t, conn.Create(&duplicate).Error)
+	joined := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	require.NoError(t, store.StartMembership(ctx, survivor.ID, project.ID, nil, joined))
+	require.NoError(t, store.EndMembership(ctx, survivor.ID, project.ID, "left", joined.AddDate(1, 0, 0)))
+	require.NoError(t, store.StartMembership(ctx, duplicate.ID, project.ID, model.MembershipRoles{model.RoleLead}, joined))
+	// The survivor's old membership, archived.
+	require.NoError(t, conn.Create(&model.MaintainerProject{MaintainerID: survivor.ID, ProjectID: project.ID,
+		JoinedAt: joined, Roles: model.MembershipRoles{model.RoleMaintainer}}).Error)
+	require.NoError(t, conn.Model(&model.MaintainerProject{}).Where("maintainer_id = ?", survivor.ID).
+		Update("deleted_at", time.Now()).Error)
+
+	report, err := store.MergePeople(ctx, survivor.ID, PersonRef{PersonMaintainer, duplicate.ID}, false)
+	require.NoError(t, err)
+	assert.Contains(t, report.Changes, "membership of project 1 moved, replacing the survivor's archived one")
+	memberships, err := store.GetProjectMemberships(ctx, project.ID)
+	require.NoError(t, err)
+	require.Len(t, memberships, 1)
+	assert.Equal(t, survivor.ID, memberships[0].MaintainerID)
+	assert.Equal(t, model.MembershipRoles{model.RoleLead}, memberships[0].Roles)
+	maintainers, err := store.MaintainersOnDate(ctx, project.ID, time.Now())
+	require.NoError(t, err)
+	require.Len(t, maintainers, 1, "the duplicate's open period moves to the survivor")
+	assert.Equal(t, survivor.ID, maintainers[0].ID)
+}
@@ -0,0 +1,265 @@
+package db
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"maintainerd/model"
+)
+
+// An ExportTable is one table of the registry as exported: a header row and rows of cells, sorted so that exports of
+// the same data are identical and exports of changed data diff line by line.
+type ExportTable struct {
+	Name   string
+	Header []string
+	Rows   [][]string
+}
+
+// ExportOptions choose what an export includes.
+type ExportOptions struct {
+	// IncludeEmails adds the email columns, which are left out by default so exports can be shared and committed.
+	IncludeEmails bool
+}
+
+// An Exporter writes exported tables somewhere: CSV files or a Google Sheet.
+type Exporter interface {
+	Export(ctx context.Context, tables []ExportTable) error
+	// String describes the destination in logs.
+	String() string
+}
+
+// ExportTables reads the live projects, maintainers and their memberships, companies, service teams and service users
+// of conn into tables, in that order. Rows are ordered by name rather than ID, so they stay put as data is added.
+func ExportTables(ctx context.Context, conn *gorm.DB, opts ExportOptions) ([]ExportTable, error) {
+	conn = conn.WithContext(ctx)
+	var projects []model.Project
+	if err := conn.Order("name").Find(&projects).Error; err != nil {
+		return nil, fmt.Errorf("export projects: %w", err)
+	}
+	projectNames := make(map[uint]string, len(projects))
+	for _, p := range projects {
+		projectNames[p.ID] = p.Name
+	}
+	var maintainers []model.Maintainer
+	if err := conn.Preload("Company").Find(&maintainers).Error; err != nil {
+		return nil, fmt.Errorf("export maintainers: %w", err)
+	}
+	var memberships []model.MaintainerProject
+	if err := conn.Find(&memberships).Error; err != nil {
+		return nil, fmt.Errorf("export memberships: %w", err)
+	}
+	var companies []model.Company
+	if err := conn.Order("name").Find(&companies).Error; err != nil {
+		return nil, fmt.Errorf("export companies: %w", err)
+	}
+	var services []model.Service
+	if err := conn.Find(&services).Error; err != nil {
+		return nil, fmt.Errorf("export services: %w", err)
+	}
+	serviceNames := make(map[uint]string, len(services))
+	for _, s := range services {
+		serviceNames[s.ID] = s.Name
+	}
+	var teams []model.ServiceTeam
+	if err := conn.Find(&teams).Error; err != nil {
+		return nil, fmt.Errorf("export service teams: %w", err)
+	}
+	var users []model.ServiceUser
+	if err := conn.Find(&users).Error; err != nil {
+		return nil, fmt.Errorf("export service users: %w", err)
+	}
+	var links []model.ServiceUserTeams
+	if err := conn.Find(&links).Error; err != nil {
+		return nil, fmt.Errorf("export service user teams: %w", err)
+	}
+	var collaborators []model.Collaborator
+	if err := conn.Find(&collaborators).Error; err != nil {
+		return nil, fmt.Errorf("export collaborators: %w", err)
+	}
+
+	byID := make(map[uint]model.Maintainer, len(maintainers))
+	for _, m := range maintainers {
+		byID[m.ID] = m
+	}
+	counts := map[uint]int{}
+	for _, mp := range memberships {
+		if _, ok := byID[mp.MaintainerID]; ok {
+			counts[mp.ProjectID]++
+		}
+	}
+
+	projectTable := ExportTable{
+		Name:   "Projects",
+		Header: []string{"Project", "Maturity", "Parent Project", "OWNERS/MAINTAINERS", "Mailing List Address", "Maintainers"},
+	}
+	for _, p := range projects {
+		var parent string
+		if p.ParentProjectID != nil {
+			parent = projectNames[*p.ParentProjectID]
+		}
+		projectTable.Rows = append(projectTable.Rows, []string{p.Name, string(p.Maturity), parent, p.MaintainerRef,
+			exportValue(p.MailingList), strconv.Itoa(counts[p.ID])})
+	}
+
+	maintainerTable := ExportTable{
+		Name:   "Maintainers",
+		Header: []string{"Project", "Maintainer Name", "GitHub", "Company", "Roles", "Status", "Joined"},
+	}
+	if opts.IncludeEmails {
+		maintainerTable.Header = append(maintainerTable.Header, "Email", "GitHub Email")
+	}
+	companyCounts := map[uint]int{}
+	listed := map[uint]bool{}
+	maintainerRow := func(m model.Maintainer, project string, mp *model.MaintainerProject) []string {
+		var roles, joined string
+		if mp != nil {
+			roles = strings.Join(mp.Roles.Strings(), ",")
+			joined = mp.JoinedAt.UTC().Format("2006-01-02")
+		}
+		row := []string{project, m.Name, exportValue(&m.GitHubAccount), m.Company.Name, roles, string(m.MaintainerStatus), joined}
+		if opts.IncludeEmails {
+			row = append(row, exportValue(&m.Email), exportValue(&m.GitHubEmail))
+		}
+		return row
+	}
+	for i, mp := range memberships {
+		m, ok := byID[mp.MaintainerID]
+		project, live := projectNames[mp.ProjectID]
+		if !ok || !live {
+			continue
+		}
+		listed[m.ID] = true
+		maintainerTable.Rows = append(maintainerTable.Rows, maintainerRow(m, project, &memberships[i]))
+	}
+	for _, m := range maintainers {
+		if m.CompanyID != nil {
+			companyCounts[*m.CompanyID]++
+		}
+		if !listed[m.ID] {
+			maintainerTable.Rows = append(maintainerTable.Rows, maintainerRow(m, "", nil))
+		}
+	}
+
+	companyTable := ExportTable{Name: "Companies", Header: []string{"Company", "Maintainers"}}
+	for _, c := range companies {
+		companyTable.Rows = append(companyTable.Rows, []string{c.Name, strconv.Itoa(companyCounts[c.ID])})
+	}
+
+	teamTable := ExportTable{Name: "Service Teams", Header: []string{"Service", "Team", "Team ID", "Project"}}
+	teamsByID := make(map[uint]model.ServiceTeam, len(teams))
+	for _, st := range teams {
+		teamsByID[st.ID] = st
+		teamTable.Rows = append(teamTable.Rows, []string{serviceNames[st.ServiceID], exportValue(st.ServiceTeamName),
+			strconv.Itoa(st.ServiceTeamID), projectNames[st.ProjectID]})
+	}
+
+	userTable := ExportTable{Name: "Service Users", Header: []string{"Service", "GitHub", "User ID", "Team", "Maintainer", "Collaborator"}}
+	if opts.IncludeEmails {
+		userTable.Header = append(userTable.Header, "Email")
+	}
+	collaboratorNames := make(map[uint]string, len(collaborators))
+	for _, c := range collaborators {
+		collaboratorNames[c.ID] = c.Name
+		if !model.IsMissing(exportValue(c.GitHubAccount)) {
+			collaboratorNames[c.ID] = "@" + *c.GitHubAccount
+		}
+	}
+	userLinks := map[int][]model.ServiceUserTeams{}
+	for _, l := range links {
+		userLinks[l.ServiceUserID] = append(userLinks[l.ServiceUserID], l)
+	}
+	for _, su := range users {
+		userRow := func(team, maintainer, collaborator string) []string {
+			row := []string{serviceNames[su.ServiceID], exportValue(su.ServiceGitHubName), strconv.Itoa(su.ServiceUserID),
+				team, maintainer, collaborator}
+			if opts.IncludeEmails {
+				row = append(row, exportValue(&su.ServiceEmail))
+			}
+			return row
+		}
+		if len(userLinks[su.ServiceUserID]) == 0 {
+			userTable.Rows = append(userTable.Rows, userRow("", "", ""))
+		}
+		for _, l := range userLinks[su.ServiceUserID] {
+			var maintainer, collaborator string
+			if l.MaintainerID != nil {
+				if m, ok := byID[*l.MaintainerID]; ok {
+					maintainer = m.Name
+					if !model.IsMissing(m.GitHubAccount) {
+						maintainer = "@" + m.GitHubAccount
+					}
+				}
+			}
+			if l.CollaboratorID != nil {
+				collaborator = collaboratorNames[*l.CollaboratorID]
+			}
+			userTable.Rows = append(userTable.Rows, userRow(exportValue(teamsByID[l.ServiceTeamID].ServiceTeamName), maintainer, collaborator))
+		}
+	}
+
+	tables := []ExportTable{projectTable, maintainerTable, companyTable, teamTable, userTable}
+	for _, t := range tables {
+		sortRows(t.Rows)
+	}
+	return tables, nil
+}
+
+// exportValue returns *v, or "" for nil and the placeholders of missing values.
+func exportValue(v *string) string {
+	if v == nil || model.IsMissing(*v) || *v == "MML_MISSING" {
+		return ""
+	}
+	return *v
+}
+
+// sortRows orders rows by their cells, left to right, case-insensitively and then exactly.
+func sortRows(rows [][]string) {
+	sort.SliceStable(rows, func(i, j int) bool {
+		a, b := rows[i], rows[j]
+		for k := 0; k < len(a) && k < len(b); k++ {
+			if x, y := strings.ToLower(a[k]), strings.ToLower(b[k]); x != y {
+				return x < y
+			}
+			if a[k] != b[k] {
+				return a[k] < b[k]
+			}
+		}
+		return len(a) < len(b)
+	})
+}
+
+// CSVExporter writes each table to its own file in Dir, named after the table, e.g. "Service Teams.csv".
+type CSVExporter struct {
+	Dir string
+}
+
+func (e CSVExporter) String() string { return "CSV files in " + e.Dir }
+
+func (e CSVExporter) Export(_ context.Context, tables []ExportTable) error {
+	if err := os.MkdirAll(e.Dir, 0o750); err != nil {
+		return err
+	}
+	for _, t := range tables {
+		path := filepath.Join(e.Dir, t.Name+".csv")
+		var b strings.Builder
+		w := csv.NewWriter(&b)
+		if err := w.Write(t.Header); err != nil {
+			return err
+		}
+		if err := w.WriteAll(t.Rows); err != nil {
+			return fmt.Errorf("export %s: %w", path, err)
+		}
+		if err := os.WriteFile(path, []byte(b.String()), 0o600); err != nil {
+			return fmt.Errorf("export %s: %w", path, err)
+		}
+	}
+	return nil
+}
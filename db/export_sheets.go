@@ -0,0 +1,108 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"google.golang.org/api/option"
+	"google.golang.org/api/sheets/v4"
+)
+
+// ErrSourceWorksheet is returned when an export would replace a worksheet the sheet import reads.
+var ErrSourceWorksheet = errors.New("worksheet is read by the sheet import")
+
+// SheetsExporter writes the tables to the Tab worksheet of a Google Sheet, one below the other, each under a row
+// with its name. The worksheet is created if needed and cleared before it is written, so it only ever holds the last
+// export; the sheet's version history shows what changed. The service account needs edit access to the sheet.
+//
+// The export may share the maintainer sheet, so Tab must not be one of the worksheets of Layout, DefaultSheetLayout
+// if nil: exporting to it would wipe the sheet the registry is imported from.
+type SheetsExporter struct {
+	SpreadsheetID   string
+	CredentialsPath string
+	Tab             string
+	Layout          *SheetLayout
+}
+
+func (e SheetsExporter) String() string {
+	return fmt.Sprintf("Google Sheet %s worksheet %s", e.SpreadsheetID, e.Tab)
+}
+
+func (e SheetsExporter) Export(ctx context.Context, tables []ExportTable) error {
+	if strings.TrimSpace(e.Tab) == "" {
+		return errors.New("db: a worksheet to export to is required")
+	}
+	layout := DefaultSheetLayout()
+	if e.Layout != nil {
+		layout = *e.Layout
+	}
+	for _, w := range []WorksheetLayout{layout.Active, layout.Staff} {
+		// Worksheet titles are unique regardless of case.
+		if strings.EqualFold(strings.TrimSpace(e.Tab), w.Name) {
+			return fmt.Errorf("db: %w: %s", ErrSourceWorksheet, e.Tab)
+		}
+	}
+	srv, err := sheets.NewService(
+		ctx,
+		option.WithCredentialsFile(e.CredentialsPath),
+		option.WithScopes(sheets.SpreadsheetsScope),
+	)
+	if err != nil {
+		return fmt.Errorf("unable to retrieve Sheets client: %w", err)
+	}
+
+	spreadsheet, err := srv.Spreadsheets.Get(e.SpreadsheetID).Fields("sheets.properties.title").Context(ctx).Do()
+	if err != nil {
+		return fmt.Errorf("db: Using %s unable to read the spreadsheet: %w", e.SpreadsheetID, err)
+	}
+	exists := false
+	for _, s := range spreadsheet.Sheets {
+		if s.Properties != nil && s.Properties.Title == e.Tab {
+			exists = true
+		}
+	}
+	if !exists {
+		_, err := srv.Spreadsheets.BatchUpdate(e.SpreadsheetID, &sheets.BatchUpdateSpreadsheetRequest{
+			Requests: []*sheets.Request{{AddSheet: &sheets.AddSheetRequest{Properties: &sheets.SheetProperties{Title: e.Tab}}}},
+		}).Context(ctx).Do()
+		if err != nil {
+			return fmt.Errorf("db: Using %s unable to add worksheet %s: %w", e.SpreadsheetID, e.Tab, err)
+		}
+	}
+
+	if _, err := srv.Spreadsheets.Values.Clear(e.SpreadsheetID, e.Tab, &sheets.ClearValuesRequest{}).Context(ctx).Do(); err != nil {
+		return fmt.Errorf("db: Using %s unable to clear worksheet %s: %w", e.SpreadsheetID, e.Tab, err)
+	}
+	_, err = srv.Spreadsheets.Values.Update(e.SpreadsheetID, e.Tab+"!A1", &sheets.ValueRange{Values: stackTables(tables)}).
+		ValueInputOption("RAW").
+		Context(ctx).
+		Do()
+	if err != nil {
+		return fmt.Errorf("db: Using %s unable to write worksheet %s: %w", e.SpreadsheetID, e.Tab, err)
+	}
+	return nil
+}
+
+// stackTables lays tables out one below the other: the table's name, its header and rows, and a blank row.
+func stackTables(tables []ExportTable) [][]interface{} {
+	var values [][]interface{}
+	row := func(cells []string) []interface{} {
+		r := make([]interface{}, len(cells))
+		for i, c := range cells {
+			r[i] = c
+		}
+		return r
+	}
+	for i, t := range tables {
+		if i > 0 {
+			values = append(values, []interface{}{})
+		}
+		values = append(values, []interface{}{t.Name}, row(t.Header))
+		for _, r := range t.Rows {
+			values = append(values, row(r))
+		}
+	}
+	return values
+}
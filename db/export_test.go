@@ -0,0 +1,106 @@
+package db
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"maintainerd/model"
+)
+
+func TestExportTables(t *testing.T) {
+	conn := openEmptyDB(t)
+	require.NoError(t, Migrate(conn))
+	ctx := t.Context()
+	_, err := SyncSheet(ctx, conn, Records{
+		Projects: []ProjectRecord{{Name: "zot", Maturity: model.Sandbox}, {Name: "argo", Maturity: model.Graduated, MailingList: "argo@lists.cncf.io"}},
+		Maintainers: []MaintainerRecord{
+			{Project: "zot", Name: "Bob", Email: "bob@example.com", GitHub: "bob"},
+			{Project: "argo", Name: "Bob", Email: "bob@example.com", GitHub: "bob"},
+			{Project: "argo", Name: "Alice", Company: "Acme", Email: "alice@example.com", GitHub: "Alice", Roles: model.MembershipRoles{model.RoleLead}},
+		},
+	}, false)
+	require.NoError(t, err)
+	require.NoError(t, conn.Create(&model.Maintainer{Name: "Unlisted", Email: "unlisted@example.com", MaintainerStatus: model.EmeritusMaintainer}).Error)
+	service := model.Service{Name: "FOSSA"}
+	require.NoError(t, conn.Create(&service).Error)
+	var argo model.Project
+	require.NoError(t, conn.Where("name = ?", "argo").First(&argo).Error)
+	team := model.ServiceTeam{ServiceID: service.ID, ServiceTeamID: 42, ServiceTeamName: &argo.Name, ProjectID: argo.ID}
+	require.NoError(t, conn.Create(&team).Error)
+	login := "bob"
+	require.NoError(t, conn.Create(&model.ServiceUser{ServiceID: service.ID, ServiceUserID: 7, ServiceEmail: "bob@fossa.example", ServiceGitHubName: &login}).Error)
+	bob, err := NewSQLStore(conn).FindMaintainerByIdentity(ctx, model.MaintainerIdentity{Kind: model.IdentityGitHub, Value: "bob"})
+	require.NoError(t, err)
+	require.NoError(t, conn.Create(&model.ServiceUserTeams{ServiceID: service.ID, ServiceUserID: 7, ServiceTeamID: team.ID, MaintainerID: &bob.ID}).Error)
+
+	tables, err := ExportTables(ctx, conn, ExportOptions{})
+	require.NoError(t, err)
+	again, err := ExportTables(ctx, conn, ExportOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, tables, again, "exports of the same data are identical")
+
+	byName := map[string]ExportTable{}
+	for _, table := range tables {
+		byName[table.Name] = table
+		assert.NotContains(t, strings.Join(flatten(table), ","), "@example.com", "%s has emails", table.Name)
+	}
+	assert.Equal(t, [][]string{
+		{"argo", "Graduated", "", "", "argo@lists.cncf.io", "2"},
+		{"zot", "Sandbox", "", "", "", "1"},
+	}, byName["Projects"].Rows)
+	var maintainers []string
+	for _, row := range byName["Maintainers"].Rows {
+		maintainers = append(maintainers, strings.Join(row[:6], "|"))
+	}
+	assert.Equal(t, []string{
+		"|Unlisted||||Emeritus",
+		"argo|Alice|Alice|Acme|lead|Active",
+		"argo|Bob|bob||maintainer|Active",
+		"zot|Bob|bob||maintainer|Active",
+	}, maintainers)
+	assert.Equal(t, [][]string{{"Acme", "1"}}, byName["Companies"].Rows)
+	assert.Equal(t, [][]string{{"FOSSA", "argo", "42", "argo"}}, byName["Service Teams"].Rows)
+	assert.Equal(t, [][]string{{"FOSSA", "bob", "7", "argo", "@bob", ""}}, byName["Service Users"].Rows)
+
+	withEmails, err := ExportTables(ctx, conn, ExportOptions{IncludeEmails: true})
+	require.NoError(t, err)
+	assert.Contains(t, withEmails[1].Header, "Email")
+	assert.Contains(t, strings.Join(flatten(withEmails[1]), ","), "alice@example.com")
+
+	dir := t.TempDir()
+	require.NoError(t, CSVExporter{Dir: dir}.Export(ctx, tables))
+	data, err := os.ReadFile(filepath.Join(dir, "Projects.csv"))
+	require.NoError(t, err)
+	assert.Equal(t, "Project,Maturity,Parent Project,OWNERS/MAINTAINERS,Mailing List Address,Maintainers\n"+
+		"argo,Graduated,,,argo@lists.cncf.io,2\nzot,Sandbox,,,,1\n", string(data))
+
+	stacked := stackTables(tables[:2])
+	assert.Equal(t, []interface{}{"Projects"}, stacked[0])
+	assert.Equal(t, []interface{}{}, stacked[4], "tables are separated by a blank row")
+	assert.Equal(t, []interface{}{"Maintainers"}, stacked[5])
+}
+
+func flatten(table ExportTable) []string {
+	cells := append([]string(nil), table.Header...)
+	for _, row := range table.Rows {
+		cells = append(cells, row...)
+	}
+	return cells
+}
+
+func TestSheetsExporterRefusesSourceWorksheets(t *testing.T) {
+	for _, tab := range []string{"Active", "staff", " Active "} {
+		err := SheetsExporter{SpreadsheetID: "sheet", Tab: tab}.Export(t.Context(), nil)
+		assert.ErrorIs(t, err, ErrSourceWorksheet, tab)
+	}
+	layout := DefaultSheetLayout()
+	layout.Active.Name = "Maintainers"
+	err := SheetsExporter{SpreadsheetID: "sheet", Tab: "maintainers", Layout: &layout}.Export(t.Context(), nil)
+	assert.ErrorIs(t, err, ErrSourceWorksheet)
+	assert.Error(t, SheetsExporter{SpreadsheetID: "sheet"}.Export(t.Context(), nil))
+}
@@ -0,0 +1,63 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"maintainerd/model"
+)
+
+// ReplaceFindings stores findings in place of the previous run's, in one transaction with the ImportWarnings they
+// imply.
+func (s *SQLStore) ReplaceFindings(ctx context.Context, findings []model.Finding) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("1 = 1").Delete(&model.Finding{}).Error; err != nil {
+			return fmt.Errorf("clear findings: %w", err)
+		}
+		if len(findings) > 0 {
+			rows := make([]model.Finding, len(findings))
+			for i, f := range findings {
+				f.ID = 0
+				rows[i] = f
+			}
+			if err := tx.CreateInBatches(&rows, 100).Error; err != nil {
+				return fmt.Errorf("store findings: %w", err)
+			}
+		}
+		if err := tx.Model(&model.Maintainer{}).Where("import_warnings <> ''").Update("import_warnings", "").Error; err != nil {
+			return fmt.Errorf("clear import warnings: %w", err)
+		}
+		for id, warnings := range ImportWarnings(findings) {
+			if err := tx.Model(&model.Maintainer{}).Where("id = ?", id).Update("import_warnings", warnings).Error; err != nil {
+				return fmt.Errorf("set import warnings of maintainer %d: %w", id, err)
+			}
+		}
+		return nil
+	})
+}
+
+// ListFindings returns the findings of the last validation run.
+func (s *SQLStore) ListFindings(ctx context.Context) ([]model.Finding, error) {
+	var findings []model.Finding
+	err := s.db.WithContext(ctx).Order("id").Find(&findings).Error
+	return findings, err
+}
+
+// ImportWarnings returns the ImportWarnings each maintainer with findings gets: the rule and message of each finding,
+// one per line.
+func ImportWarnings(findings []model.Finding) map[uint]string {
+	lines := map[uint][]string{}
+	for _, f := range findings {
+		if f.SubjectKind == string(PersonMaintainer) {
+			lines[f.SubjectID] = append(lines[f.SubjectID], fmt.Sprintf("%s: %s", f.Rule, f.Message))
+		}
+	}
+	warnings := make(map[uint]string, len(lines))
+	for id, l := range lines {
+		warnings[id] = strings.Join(l, "\n")
+	}
+	return warnings
+}
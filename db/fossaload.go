@@ -0,0 +1,201 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"maintainerd/model"
+	"maintainerd/plugins/fossa"
+)
+
+// fossaStage names the FOSSA import in import checkpoints.
+const fossaStage = "fossa"
+
+// A FossaUserError is a FOSSA user the import could not fully load. Subject is the user's GitHub login, or their
+// FOSSA user ID; errors never name the user's email.
+type FossaUserError struct {
+	UserID  int    `json:"userID"`
+	Subject string `json:"subject"`
+	Err     string `json:"error"`
+}
+
+func (e FossaUserError) String() string { return e.Subject + ": " + e.Err }
+
+// A FossaReport is the outcome of a FOSSA import.
+type FossaReport struct {
+	// Users is how many users FOSSA returned.
+	Users int `json:"users"`
+	// Loaded is how many users were loaded without errors.
+	Loaded int `json:"loaded"`
+	// Resumed is how many users were skipped because the run resumed after them.
+	Resumed int              `json:"resumed"`
+	Errors  []FossaUserError `json:"errors,omitempty"`
+}
+
+// FetchFossaData returns the users and teams of the FOSSA organization token belongs to.
+func FetchFossaData(token string) ([]fossa.User, []fossa.Team, error) {
+	return fetchFossaData(fossa.NewClient(token))
+}
+
+func fetchFossaData(client *fossa.Client) ([]fossa.User, []fossa.Team, error) {
+	users, err := client.FetchUsers()
+	if err != nil {
+		return nil, nil, err
+	}
+	teams, err := client.FetchTeams()
+	if err != nil {
+		return nil, nil, err
+	}
+	return users, teams, nil
+}
+
+// LoadFossa loads the FOSSA users of client, in user ID order, as service users linked to the maintainer or
+// collaborator they are and to the service teams of their projects. A user that cannot be loaded is reported in the
+// FossaReport and the import moves on; only failing to read FOSSA, or to record progress, stops it.
+//
+// Progress is checkpointed after each user, up to the first user that fails. With resume, the users up to the
+// checkpoint of an earlier run that failed or was interrupted are skipped; a run that loads every user clears it.
+func LoadFossa(ctx context.Context, conn *gorm.DB, client *fossa.Client, resume bool) (FossaReport, error) {
+	users, teams, err := fetchFossaData(client)
+	if err != nil {
+		return FossaReport{}, fmt.Errorf("LoadFossa: fetching FOSSA data: %w", err)
+	}
+	log.Printf("LoadFossa: INF, FetchFossaData found %d users, and %d teams", len(users), len(teams))
+	return loadFossaUsers(ctx, conn.WithContext(ctx), users, resume)
+}
+
+func loadFossaUsers(ctx context.Context, db *gorm.DB, users []fossa.User, resume bool) (FossaReport, error) {
+	report := FossaReport{Users: len(users)}
+	users = append([]fossa.User(nil), users...)
+	sort.Slice(users, func(i, j int) bool { return users[i].ID < users[j].ID })
+
+	after := 0
+	if resume {
+		last, err := importCheckpoint(db, fossaStage)
+		if err != nil {
+			return report, err
+		}
+		if last != "" {
+			if after, err = strconv.Atoi(last); err != nil {
+				return report, fmt.Errorf("LoadFossa: bad checkpoint %q: %w", last, err)
+			}
+			log.Printf("LoadFossa: INF, resuming after FOSSA user %d", after)
+		}
+	}
+
+	failed := false
+	for _, user := range users {
+		if err := ctx.Err(); err != nil {
+			return report, fmt.Errorf("LoadFossa: %w", err)
+		}
+		if user.ID <= after {
+			report.Resumed++
+			continue
+		}
+		if err := loadFossaUser(ctx, db, user); err != nil {
+			subject := "FOSSA user " + strconv.Itoa(user.ID)
+			if name := safeGitHubName(user.GitHub.Name); name != "" {
+				subject = "@" + name
+			}
+			userErr := FossaUserError{UserID: user.ID, Subject: subject, Err: err.Error()}
+			log.Printf("LoadFossa: WRN, %s", userErr)
+			report.Errors = append(report.Errors, userErr)
+			failed = true
+			continue
+		}
+		report.Loaded++
+		if !failed {
+			if err := saveImportCheckpoint(db, fossaStage, strconv.Itoa(user.ID)); err != nil {
+				return report, err
+			}
+		}
+	}
+	if !failed {
+		if err := saveImportCheckpoint(db, fossaStage, ""); err != nil {
+			return report, err
+		}
+	}
+	log.Printf("LoadFossa: INF, %d users loaded, %d resumed, %d failed", report.Loaded, report.Resumed, len(report.Errors))
+	return report, nil
+}
+
+// loadFossaUser records user as a service user, links it to the maintainer or collaborator it is, and to the service
+// teams of its FOSSA teams. It carries on past a step that fails and returns the errors of all of them.
+func loadFossaUser(ctx context.Context, db *gorm.DB, user fossa.User) error {
+	su, err := FirstOrCreateServiceUser(db, user)
+	if err != nil {
+		return err // nothing can be linked without the service user
+	}
+
+	var errs []error
+	var maintainer *model.Maintainer     // A registered maintainer
+	var collaborator *model.Collaborator // A contributor who has been signed up
+	ghName := safeGitHubName(user.GitHub.Name)
+	fossaID := model.MaintainerIdentity{Kind: model.IdentityService, Service: "FOSSA", Value: strconv.Itoa(user.ID)}
+	if maintainer, _ = maintainerByIdentity(db, fossaID); maintainer == nil {
+		maintainer = MapFossaUserToMaintainer(db, user.Email, ghName)
+	}
+	if maintainer != nil {
+		// Remember the FOSSA user, and the email they signed up with, so later imports match them directly.
+		fossaID.MaintainerID = maintainer.ID
+		fossaEmail := model.MaintainerIdentity{MaintainerID: maintainer.ID, Kind: model.IdentityEmail, Value: user.Email}
+		if err := recordIdentities(db, fossaID, fossaEmail); err != nil {
+			errs = append(errs, fmt.Errorf("recording identities of maintainer %d: %w", maintainer.ID, err))
+		}
+	} else if collaborator = MapFossaUserCollaborator(db, user.Email, ghName, user); collaborator == nil {
+		errs = append(errs, errors.New("not a maintainer, and could not be recorded as a collaborator"))
+	}
+
+	if len(user.TeamUsers) == 0 {
+		return errors.Join(errs...)
+	}
+	st, err := CreateServiceTeamsForUser(ctx, db, user.TeamUsers)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	if len(st) > 0 {
+		if err := LinkServiceUserToTeam(db, su, st, maintainer, collaborator); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// importCheckpoint returns the last key stage checkpointed, or "" if it has none.
+func importCheckpoint(db *gorm.DB, stage string) (string, error) {
+	var cp model.ImportCheckpoint
+	err := db.Where("stage = ?", stage).First(&cp).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("read %s checkpoint: %w", stage, err)
+	}
+	return cp.LastKey, nil
+}
+
+// saveImportCheckpoint records key as the last one stage completed; an empty key clears the checkpoint.
+func saveImportCheckpoint(db *gorm.DB, stage, key string) error {
+	err := db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "stage"}},
+		DoUpdates: clause.AssignmentColumns([]string{"last_key", "updated_at"}),
+	}).Create(&model.ImportCheckpoint{Stage: stage, LastKey: key}).Error
+	if err != nil {
+		return fmt.Errorf("save %s checkpoint: %w", stage, err)
+	}
+	return nil
+}
+
+func safeGitHubName(ghName *string) string {
+	if ghName != nil {
+		return *ghName
+	}
+	return ""
+}
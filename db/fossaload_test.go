@@ -0,0 +1,91 @@
+package db
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"maintainerd/model"
+	"maintainerd/plugins/fossa"
+	"maintainerd/plugins/fossa/fossatest"
+)
+
+// fossaUser returns a FOSSA user on the named teams.
+func fossaUser(id int, email, login string, teams ...string) fossa.User {
+	u := fossa.User{ID: id, Email: email, FullName: login}
+	if login != "" {
+		u.GitHub.Name = &login
+	}
+	for i, name := range teams {
+		u.TeamUsers = append(u.TeamUsers, struct {
+			RoleID int `json:"roleId"`
+			Team   struct {
+				ID   int    `json:"id"`
+				Name string `json:"name"`
+			} `json:"team"`
+		}{})
+		u.TeamUsers[i].Team.ID = 100 + len(name)
+		u.TeamUsers[i].Team.Name = name
+	}
+	return u
+}
+
+func TestLoadFossaUsers(t *testing.T) {
+	conn := openEmptyDB(t)
+	require.NoError(t, Migrate(conn))
+	ctx := t.Context()
+	require.NoError(t, conn.Create(&model.Service{Name: "FOSSA"}).Error)
+	_, err := SyncSheet(ctx, conn, Records{
+		Projects:    []ProjectRecord{{Name: "argo", Maturity: model.Graduated}},
+		Maintainers: []MaintainerRecord{{Project: "argo", Name: "Alice", Email: "alice@example.com", GitHub: "alice"}},
+	}, false)
+	require.NoError(t, err)
+
+	users := []fossa.User{
+		fossaUser(3, "carol@example.com", "carol"),
+		fossaUser(1, "alice@fossa.example", "alice", "argo"),
+		fossaUser(2, "bob@example.com", "", "no-such-project"),
+	}
+	report, err := loadFossaUsers(ctx, conn, users, false)
+	require.NoError(t, err, "a user that fails does not stop the import")
+	assert.Equal(t, 2, report.Loaded)
+	require.Len(t, report.Errors, 1)
+	assert.Equal(t, "FOSSA user 2", report.Errors[0].Subject)
+	assert.Contains(t, report.Errors[0].Err, "no-such-project is NOT A registered project")
+	last, err := importCheckpoint(conn, fossaStage)
+	require.NoError(t, err)
+	assert.Equal(t, "1", last, "the checkpoint stops at the first failure")
+
+	alice, err := NewSQLStore(conn).FindMaintainerByIdentity(ctx, model.MaintainerIdentity{Kind: model.IdentityService, Service: "FOSSA", Value: "1"})
+	require.NoError(t, err)
+	assert.Equal(t, "alice", alice.GitHubAccount)
+	var links []model.ServiceUserTeams
+	require.NoError(t, conn.Find(&links).Error)
+	require.Len(t, links, 1)
+	assert.Equal(t, alice.ID, *links[0].MaintainerID)
+
+	// Bob's team is fixed and the import resumed after Alice.
+	users[2] = fossaUser(2, "bob@example.com", "", "argo")
+	report, err = loadFossaUsers(ctx, conn, users, true)
+	require.NoError(t, err)
+	assert.Equal(t, FossaReport{Users: 3, Loaded: 2, Resumed: 1}, report)
+	last, err = importCheckpoint(conn, fossaStage)
+	require.NoError(t, err)
+	assert.Empty(t, last, "a complete run clears the checkpoint")
+
+	report, err = loadFossaUsers(ctx, conn, users, true)
+	require.NoError(t, err)
+	assert.Equal(t, 3, report.Loaded, "without a checkpoint every user is loaded again")
+}
+
+func TestLoadFossaFetchError(t *testing.T) {
+	conn := openEmptyDB(t)
+	require.NoError(t, Migrate(conn))
+	server := fossatest.NewServer(t)
+	server.Fail("GET", "/users", fossatest.Fault{Status: http.StatusInternalServerError})
+
+	_, err := LoadFossa(t.Context(), conn, server.NewClient(), false)
+	assert.Error(t, err)
+}
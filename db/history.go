@@ -0,0 +1,201 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"maintainerd/model"
+)
+
+// StartMembership adds the maintainer to the project with roles and opens a membership period for each of them
+// starting at at.
+func (s *SQLStore) StartMembership(ctx context.Context, maintainerID, projectID uint, roles model.MembershipRoles, at time.Time) error {
+	roles, err := membershipRoles(roles)
+	if err != nil {
+		return err
+	}
+	at = historyTime(at)
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := exists(tx, &model.Maintainer{}, maintainerID, ErrMaintainerNotFound); err != nil {
+			return err
+		}
+		if err := exists(tx, &model.Project{}, projectID, ErrProjectNotFound); err != nil {
+			return err
+		}
+		var n int64
+		err := tx.Unscoped().Model(&model.MaintainerProject{}).
+			Where("maintainer_id = ? AND project_id = ?", maintainerID, projectID).
+			Count(&n).Error
+		if err != nil {
+			return fmt.Errorf("start membership of maintainer %d on project %d: %w", maintainerID, projectID, err)
+		}
+		if n > 0 {
+			return fmt.Errorf("%w: maintainer %d, project %d", ErrAlreadyMember, maintainerID, projectID)
+		}
+		mp := model.MaintainerProject{MaintainerID: maintainerID, ProjectID: projectID, JoinedAt: at, Roles: roles}
+		if err := tx.Omit(clause.Associations).Create(&mp).Error; err != nil {
+			return fmt.Errorf("start membership of maintainer %d on project %d: %w", maintainerID, projectID, err)
+		}
+		for _, role := range roles {
+			if err := openPeriod(tx, maintainerID, projectID, role, at); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// EndMembership removes the maintainer from the project and closes their open membership periods at at. Memberships
+// that predate membership history have no open periods; they are recorded from the day the membership was joined.
+func (s *SQLStore) EndMembership(ctx context.Context, maintainerID, projectID uint, reason string, at time.Time) error {
+	at = historyTime(at)
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return endMembership(tx, maintainerID, projectID, reason, at)
+	})
+}
+
+// endMembership is EndMembership within tx.
+func endMembership(tx *gorm.DB, maintainerID, projectID uint, reason string, at time.Time) error {
+	var mp model.MaintainerProject
+	err := tx.Where("maintainer_id = ? AND project_id = ?", maintainerID, projectID).First(&mp).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("%w: maintainer %d, project %d", ErrNotMember, maintainerID, projectID)
+	}
+	if err != nil {
+		return fmt.Errorf("end membership of maintainer %d on project %d: %w", maintainerID, projectID, err)
+	}
+	err = tx.Unscoped().
+		Where("maintainer_id = ? AND project_id = ?", maintainerID, projectID).
+		Delete(&model.MaintainerProject{}).Error
+	if err != nil {
+		return fmt.Errorf("end membership of maintainer %d on project %d: %w", maintainerID, projectID, err)
+	}
+	res := tx.Model(&model.MembershipPeriod{}).
+		Where("maintainer_id = ? AND project_id = ? AND ended_at IS NULL", maintainerID, projectID).
+		Updates(map[string]interface{}{"ended_at": at, "reason": reason})
+	if res.Error != nil {
+		return fmt.Errorf("end membership of maintainer %d on project %d: %w", maintainerID, projectID, res.Error)
+	}
+	if res.RowsAffected > 0 {
+		return nil
+	}
+	roles, err := membershipRoles(mp.Roles)
+	if err != nil {
+		return err
+	}
+	for _, role := range roles {
+		period := model.MembershipPeriod{
+			MaintainerID: maintainerID,
+			ProjectID:    projectID,
+			Role:         role,
+			StartedAt:    historyTime(mp.JoinedAt),
+			EndedAt:      &at,
+			Reason:       reason,
+		}
+		if err := tx.Omit(clause.Associations).Create(&period).Error; err != nil {
+			return fmt.Errorf("end membership of maintainer %d on project %d: %w", maintainerID, projectID, err)
+		}
+	}
+	return nil
+}
+
+// MaintainersOnDate returns the maintainers of the project on the given date, with their companies.
+func (s *SQLStore) MaintainersOnDate(ctx context.Context, projectID uint, on time.Time) ([]model.Maintainer, error) {
+	on = historyTime(on)
+	tx := s.db.WithContext(ctx).Unscoped().Session(&gorm.Session{})
+	if err := exists(tx, &model.Project{}, projectID, ErrProjectNotFound); err != nil {
+		return nil, err
+	}
+	covering := tx.Model(&model.MembershipPeriod{}).
+		Select("maintainer_id").
+		Where("project_id = ? AND started_at <= ? AND (ended_at IS NULL OR ended_at > ?)", projectID, on, on)
+	var maintainers []model.Maintainer
+	err := tx.Preload("Company").Preload("Identities").Where("id IN (?)", covering).Order("id").Find(&maintainers).Error
+	return maintainers, err
+}
+
+// MaintainerTenure returns the maintainer's membership periods with their projects, archived ones included.
+func (s *SQLStore) MaintainerTenure(ctx context.Context, maintainerID uint) ([]model.MembershipPeriod, error) {
+	tx := s.db.WithContext(ctx).Unscoped().Session(&gorm.Session{})
+	if err := exists(tx, &model.Maintainer{}, maintainerID, ErrMaintainerNotFound); err != nil {
+		return nil, err
+	}
+	var periods []model.MembershipPeriod
+	err := tx.Preload("Project", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }).
+		Where("maintainer_id = ?", maintainerID).
+		Order("started_at, id").
+		Find(&periods).Error
+	return periods, err
+}
+
+// SetMaintainerStatus updates the maintainer's status and records the transition.
+func (s *SQLStore) SetMaintainerStatus(ctx context.Context, maintainerID uint, status model.MaintainerStatus, reason string, at time.Time) error {
+	if !status.IsValid() {
+		return fmt.Errorf("invalid maintainer status %q", status)
+	}
+	at = historyTime(at)
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var m model.Maintainer
+		if err := tx.First(&m, maintainerID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("%w: %d", ErrMaintainerNotFound, maintainerID)
+			}
+			return fmt.Errorf("set status of maintainer %d: %w", maintainerID, err)
+		}
+		from := m.MaintainerStatus
+		if from == status {
+			return nil
+		}
+		if err := tx.Model(&m).Update("maintainer_status", status).Error; err != nil {
+			return fmt.Errorf("set status of maintainer %d: %w", maintainerID, err)
+		}
+		change := model.MaintainerStatusChange{MaintainerID: maintainerID, ToStatus: status, ChangedAt: at, Reason: reason}
+		if from.IsValid() {
+			change.FromStatus = &from
+		}
+		return tx.Create(&change).Error
+	})
+}
+
+// MaintainerStatusHistory returns the recorded status changes of the maintainer, archived or not.
+func (s *SQLStore) MaintainerStatusHistory(ctx context.Context, maintainerID uint) ([]model.MaintainerStatusChange, error) {
+	tx := s.db.WithContext(ctx).Unscoped().Session(&gorm.Session{})
+	if err := exists(tx, &model.Maintainer{}, maintainerID, ErrMaintainerNotFound); err != nil {
+		return nil, err
+	}
+	var changes []model.MaintainerStatusChange
+	err := tx.Where("maintainer_id = ?", maintainerID).Order("changed_at, id").Find(&changes).Error
+	return changes, err
+}
+
+// openPeriod records that the maintainer took role on the project at at.
+func openPeriod(tx *gorm.DB, maintainerID, projectID uint, role model.MembershipRole, at time.Time) error {
+	period := model.MembershipPeriod{MaintainerID: maintainerID, ProjectID: projectID, Role: role, StartedAt: at}
+	if err := tx.Omit(clause.Associations).Create(&period).Error; err != nil {
+		return fmt.Errorf("open membership period of maintainer %d on project %d: %w", maintainerID, projectID, err)
+	}
+	return nil
+}
+
+// exists returns notFound unless tx finds the record of m with id.
+func exists(tx *gorm.DB, m interface{}, id uint, notFound error) error {
+	var n int64
+	if err := tx.Model(m).Where("id = ?", id).Count(&n).Error; err != nil {
+		return err
+	}
+	if n == 0 {
+		return fmt.Errorf("%w: %d", notFound, id)
+	}
+	return nil
+}
+
+// historyTime normalises t the way archive does, so that SQLite's textual timestamps compare in time order and
+// PostgreSQL round trips them unchanged.
+func historyTime(t time.Time) time.Time {
+	return t.UTC().Truncate(time.Microsecond)
+}
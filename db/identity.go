@@ -0,0 +1,170 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"maintainerd/model"
+)
+
+// AddMaintainerIdentity records identity for its maintainer.
+func (s *SQLStore) AddMaintainerIdentity(ctx context.Context, identity model.MaintainerIdentity) (model.MaintainerIdentity, error) {
+	var recorded model.MaintainerIdentity
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := exists(tx, &model.Maintainer{}, identity.MaintainerID, ErrMaintainerNotFound); err != nil {
+			return err
+		}
+		var err error
+		recorded, err = recordIdentity(tx, identity)
+		return err
+	})
+	return recorded, err
+}
+
+// ListMaintainerIdentities returns the identities of the maintainer, archived or not.
+func (s *SQLStore) ListMaintainerIdentities(ctx context.Context, maintainerID uint) ([]model.MaintainerIdentity, error) {
+	tx := s.db.WithContext(ctx).Session(&gorm.Session{})
+	if err := exists(tx.Unscoped(), &model.Maintainer{}, maintainerID, ErrMaintainerNotFound); err != nil {
+		return nil, err
+	}
+	var identities []model.MaintainerIdentity
+	err := tx.Where("maintainer_id = ?", maintainerID).Order("id").Find(&identities).Error
+	return identities, err
+}
+
+// FindMaintainerByIdentity returns the live maintainer known by identity, with their company and identities.
+func (s *SQLStore) FindMaintainerByIdentity(ctx context.Context, identity model.MaintainerIdentity) (*model.Maintainer, error) {
+	return maintainerByIdentity(s.db.WithContext(ctx).Preload("Company").Preload("Identities"), identity)
+}
+
+// maintainerByIdentity returns the live maintainer known by identity. Emails and GitHub logins are also matched
+// against the maintainer's own columns, so maintainers written without their identities are still found.
+func maintainerByIdentity(tx *gorm.DB, identity model.MaintainerIdentity) (*model.Maintainer, error) {
+	identity = identity.Normalize()
+	if identity.Value == "" {
+		return nil, fmt.Errorf("%w: empty %s identity", ErrMaintainerNotFound, identity.Kind)
+	}
+	known := tx.Session(&gorm.Session{NewDB: true}).
+		Model(&model.MaintainerIdentity{}).
+		Select("maintainer_id").
+		Where("kind = ? AND service = ? AND value = ?", identity.Kind, identity.Service, identity.Value)
+	query := "id IN (?)"
+	args := []interface{}{known}
+	switch identity.Kind {
+	case model.IdentityEmail:
+		query += " OR LOWER(email) = ? OR LOWER(git_hub_email) = ?"
+		args = append(args, identity.Value, identity.Value)
+	case model.IdentityGitHub:
+		query += " OR LOWER(git_hub_account) = ?"
+		args = append(args, identity.Value)
+	}
+	var m model.Maintainer
+	err := tx.Where(query, args...).Order("id").First(&m).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("%w: %s %s", ErrMaintainerNotFound, identity.Kind, identity.Value)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// recordIdentity adds identity to its maintainer, or merges its flags into the identity already recorded.
+func recordIdentity(tx *gorm.DB, identity model.MaintainerIdentity) (model.MaintainerIdentity, error) {
+	identity = identity.Normalize()
+	if !identity.Kind.IsValid() {
+		return identity, fmt.Errorf("invalid identity kind %q", identity.Kind)
+	}
+	if identity.Value == "" || (identity.Kind == model.IdentityService && identity.Service == "") {
+		return identity, fmt.Errorf("incomplete %s identity %+v", identity.Kind, identity)
+	}
+
+	var recorded model.MaintainerIdentity
+	err := tx.Where("kind = ? AND service = ? AND value = ?", identity.Kind, identity.Service, identity.Value).
+		First(&recorded).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		// Maintainers written without their identities are known by their own columns.
+		owner, err := maintainerByIdentity(tx, identity)
+		if err == nil && owner.ID != identity.MaintainerID {
+			return identity, fmt.Errorf("%w: %s %s is maintainer %d's", ErrIdentityTaken, identity.Kind, identity.Value, owner.ID)
+		}
+		if err != nil && !errors.Is(err, ErrMaintainerNotFound) {
+			return identity, fmt.Errorf("record %s identity of maintainer %d: %w", identity.Kind, identity.MaintainerID, err)
+		}
+	}
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		recorded = identity
+		recorded.ID = 0
+		if err := tx.Create(&recorded).Error; err != nil {
+			return recorded, fmt.Errorf("record %s identity of maintainer %d: %w", identity.Kind, identity.MaintainerID, err)
+		}
+	case err != nil:
+		return recorded, fmt.Errorf("record %s identity of maintainer %d: %w", identity.Kind, identity.MaintainerID, err)
+	case recorded.MaintainerID != identity.MaintainerID:
+		return recorded, fmt.Errorf("%w: %s %s is maintainer %d's", ErrIdentityTaken, identity.Kind, identity.Value, recorded.MaintainerID)
+	case (identity.Verified && !recorded.Verified) || (identity.IsPrimary && !recorded.IsPrimary):
+		recorded.Verified = recorded.Verified || identity.Verified
+		recorded.IsPrimary = recorded.IsPrimary || identity.IsPrimary
+		if err := tx.Save(&recorded).Error; err != nil {
+			return recorded, fmt.Errorf("record %s identity of maintainer %d: %w", identity.Kind, identity.MaintainerID, err)
+		}
+	}
+	if !recorded.IsPrimary {
+		return recorded, nil
+	}
+
+	err = tx.Model(&model.MaintainerIdentity{}).
+		Where("maintainer_id = ? AND kind = ? AND service = ? AND id <> ?", recorded.MaintainerID, recorded.Kind, recorded.Service, recorded.ID).
+		Update("is_primary", false).Error
+	if err != nil {
+		return recorded, fmt.Errorf("record %s identity of maintainer %d: %w", identity.Kind, identity.MaintainerID, err)
+	}
+	column := map[model.IdentityKind]string{model.IdentityEmail: "email", model.IdentityGitHub: "git_hub_account"}[recorded.Kind]
+	if column == "" {
+		return recorded, nil
+	}
+	var m model.Maintainer
+	if err := tx.Unscoped().First(&m, recorded.MaintainerID).Error; err != nil {
+		return recorded, fmt.Errorf("record %s identity of maintainer %d: %w", identity.Kind, identity.MaintainerID, err)
+	}
+	// The maintainer's column may hold the same value in another case; keep it as written. A value it replaces is
+	// kept as a secondary identity, so the maintainer is still found by it.
+	for _, previous := range m.DeclaredIdentities() {
+		if previous.Kind != recorded.Kind || !previous.IsPrimary || previous.Value == recorded.Value {
+			continue
+		}
+		previous.IsPrimary = false
+		if err := recordIdentities(tx, previous); err != nil {
+			return recorded, err
+		}
+	}
+	current := map[string]string{"email": m.Email, "git_hub_account": m.GitHubAccount}[column]
+	if strings.EqualFold(current, recorded.Value) {
+		return recorded, nil
+	}
+	if err := tx.Unscoped().Model(&m).Update(column, recorded.Value).Error; err != nil {
+		return recorded, fmt.Errorf("record %s identity of maintainer %d: %w", identity.Kind, identity.MaintainerID, err)
+	}
+	return recorded, nil
+}
+
+// recordIdentities records each of identities, logging instead of failing on those that belong to another maintainer;
+// the duplicate is left for an operator to resolve.
+func recordIdentities(tx *gorm.DB, identities ...model.MaintainerIdentity) error {
+	for _, identity := range identities {
+		if _, err := recordIdentity(tx, identity); err != nil {
+			if errors.Is(err, ErrIdentityTaken) {
+				log.Printf("recordIdentities: WRN, maintainer %d: %v", identity.MaintainerID, err)
+				continue
+			}
+			return err
+		}
+	}
+	return nil
+}
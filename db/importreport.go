@@ -0,0 +1,211 @@
+package db
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"maintainerd/model"
+)
+
+// A RowRef locates a record in its source: the worksheet or file it was read from, and its row there counting the
+// header row as 1, or 0 if the source has no rows.
+type RowRef struct {
+	Origin string `json:"origin"`
+	Row    int    `json:"row,omitempty"`
+}
+
+func (r RowRef) String() string {
+	if r.Row == 0 {
+		return r.Origin
+	}
+	return fmt.Sprintf("%s row %d", r.Origin, r.Row)
+}
+
+// An ImportOutcome says what an import did with a source row.
+type ImportOutcome string
+
+const (
+	ImportCreated   ImportOutcome = "created"
+	ImportUpdated   ImportOutcome = "updated"
+	ImportUnchanged ImportOutcome = "unchanged"
+	// ImportSkipped rows were not applied because of what they hold, e.g. a maintainer without an email.
+	ImportSkipped ImportOutcome = "skipped"
+	// ImportFailed rows could not be applied, e.g. because their email belongs to another maintainer.
+	ImportFailed ImportOutcome = "failed"
+)
+
+// importOutcomes lists the outcomes in the order reports count them.
+var importOutcomes = []ImportOutcome{ImportCreated, ImportUpdated, ImportUnchanged, ImportSkipped, ImportFailed}
+
+// An ImportRow is the outcome of one source row. Subject names the project or person of the row, people by GitHub
+// login where the row has one; like the reason, it never includes an email.
+type ImportRow struct {
+	RowRef
+	Subject string        `json:"subject"`
+	Outcome ImportOutcome `json:"outcome"`
+	Reason  string        `json:"reason,omitempty"`
+}
+
+// An ImportReport lists the outcome of every row a bootstrap run read from its source.
+type ImportReport struct {
+	RunID      string      `json:"runID"`
+	Source     string      `json:"source"`
+	StartedAt  time.Time   `json:"startedAt"`
+	FinishedAt time.Time   `json:"finishedAt"`
+	Rows       []ImportRow `json:"rows"`
+}
+
+// NewImportReport starts the report of a run reading source, with a new run ID.
+func NewImportReport(source string) *ImportReport {
+	now := time.Now().UTC()
+	suffix := make([]byte, 4)
+	_, _ = rand.Read(suffix)
+	return &ImportReport{
+		RunID:     now.Format("20060102T150405Z") + "-" + hex.EncodeToString(suffix),
+		Source:    source,
+		StartedAt: now,
+	}
+}
+
+// Add appends rows to the report.
+func (r *ImportReport) Add(rows ...ImportRow) {
+	r.Rows = append(r.Rows, rows...)
+}
+
+// Finish sorts the rows into source order, origins in the order they were first reported, and stamps the report.
+func (r *ImportReport) Finish() {
+	origins := map[string]int{}
+	for _, row := range r.Rows {
+		if _, ok := origins[row.Origin]; !ok {
+			origins[row.Origin] = len(origins)
+		}
+	}
+	sort.SliceStable(r.Rows, func(i, j int) bool {
+		a, b := r.Rows[i], r.Rows[j]
+		if a.Origin != b.Origin {
+			return origins[a.Origin] < origins[b.Origin]
+		}
+		return a.Row < b.Row
+	})
+	r.FinishedAt = time.Now().UTC()
+}
+
+// Counts returns how many rows had each outcome.
+func (r ImportReport) Counts() map[ImportOutcome]int {
+	counts := map[ImportOutcome]int{}
+	for _, row := range r.Rows {
+		counts[row.Outcome]++
+	}
+	return counts
+}
+
+// Problems returns the rows that were skipped or failed, the ones to fix in the source.
+func (r ImportReport) Problems() []ImportRow {
+	var problems []ImportRow
+	for _, row := range r.Rows {
+		if row.Outcome == ImportSkipped || row.Outcome == ImportFailed {
+			problems = append(problems, row)
+		}
+	}
+	return problems
+}
+
+// Summary counts the rows by outcome, e.g. "3 created, 0 updated, 10 unchanged, 1 skipped, 0 failed".
+func (r ImportReport) Summary() string {
+	counts := r.Counts()
+	parts := make([]string, len(importOutcomes))
+	for i, o := range importOutcomes {
+		parts[i] = fmt.Sprintf("%d %s", counts[o], o)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Markdown renders the report for people: a summary, the rows to fix, and every row in a collapsed section.
+func (r ImportReport) Markdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Import %s\n\n", r.RunID)
+	fmt.Fprintf(&b, "Read from %s on %s: %s.\n", r.Source, r.StartedAt.Format(time.RFC3339), r.Summary())
+	table := func(rows []ImportRow) {
+		b.WriteString("| Source | Row | Subject | Outcome | Reason |\n|---|---|---|---|---|\n")
+		for _, row := range rows {
+			line := ""
+			if row.Row > 0 {
+				line = fmt.Sprint(row.Row)
+			}
+			fmt.Fprintf(&b, "| %s | %s | %s | %s | %s |\n", markdownCell(row.Origin), line, markdownCell(row.Subject),
+				row.Outcome, markdownCell(row.Reason))
+		}
+	}
+	if problems := r.Problems(); len(problems) > 0 {
+		b.WriteString("\n## Rows to fix\n\n")
+		table(problems)
+	}
+	if len(r.Rows) > 0 {
+		b.WriteString("\n<details><summary>All rows</summary>\n\n")
+		table(r.Rows)
+		b.WriteString("\n</details>\n")
+	}
+	return b.String()
+}
+
+func markdownCell(s string) string {
+	return strings.NewReplacer("|", `\|`, "\n", " ").Replace(s)
+}
+
+// SaveImportReport stores report as an ImportRun.
+func SaveImportReport(ctx context.Context, conn *gorm.DB, report ImportReport) error {
+	data, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+	counts := report.Counts()
+	run := model.ImportRun{
+		RunID:      report.RunID,
+		Source:     report.Source,
+		StartedAt:  report.StartedAt,
+		FinishedAt: report.FinishedAt,
+		Created:    counts[ImportCreated],
+		Updated:    counts[ImportUpdated],
+		Unchanged:  counts[ImportUnchanged],
+		Skipped:    counts[ImportSkipped],
+		Failed:     counts[ImportFailed],
+		Report:     string(data),
+	}
+	if err := conn.WithContext(ctx).Create(&run).Error; err != nil {
+		return fmt.Errorf("store import report %s: %w", report.RunID, err)
+	}
+	return nil
+}
+
+// ErrImportRunNotFound is returned by LoadImportReport when there is no such run.
+var ErrImportRunNotFound = errors.New("import run not found")
+
+// LoadImportReport returns the report of the import run runID, or of the latest run if runID is empty.
+func LoadImportReport(ctx context.Context, conn *gorm.DB, runID string) (ImportReport, error) {
+	var report ImportReport
+	var run model.ImportRun
+	q := conn.WithContext(ctx).Order("started_at DESC, id DESC")
+	if runID != "" {
+		q = q.Where("run_id = ?", runID)
+	}
+	err := q.First(&run).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return report, fmt.Errorf("%w: %q", ErrImportRunNotFound, runID)
+	}
+	if err != nil {
+		return report, err
+	}
+	if err := json.Unmarshal([]byte(run.Report), &report); err != nil {
+		return report, fmt.Errorf("import run %s: %w", run.RunID, err)
+	}
+	return report, nil
+}
@@ -0,0 +1,53 @@
+package db
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"maintainerd/model"
+)
+
+func TestImportReport(t *testing.T) {
+	report := NewImportReport("CSV export testdata")
+	assert.Regexp(t, `^\d{8}T\d{6}Z-[0-9a-f]{8}$`, report.RunID)
+	report.Add(
+		ImportRow{RowRef: RowRef{Origin: "Active", Row: 4}, Subject: "@bob on project argo", Outcome: ImportFailed, Reason: "the row's email | belongs to another maintainer"},
+		ImportRow{RowRef: RowRef{Origin: "Active", Row: 2}, Subject: "project argo", Outcome: ImportCreated},
+		ImportRow{RowRef: RowRef{Origin: "Staff", Row: 2}, Subject: "@staffer", Outcome: ImportUnchanged},
+		ImportRow{RowRef: RowRef{Origin: "Active", Row: 3}, Subject: "Carol on project argo", Outcome: ImportSkipped, Reason: "no email"},
+	)
+	report.Finish()
+
+	var refs []string
+	for _, row := range report.Rows {
+		refs = append(refs, row.RowRef.String())
+	}
+	assert.Equal(t, []string{"Active row 2", "Active row 3", "Active row 4", "Staff row 2"}, refs, "rows are in source order")
+	assert.Equal(t, "1 created, 0 updated, 1 unchanged, 1 skipped, 1 failed", report.Summary())
+	assert.Len(t, report.Problems(), 2)
+
+	md := report.Markdown()
+	assert.Contains(t, md, "## Rows to fix")
+	assert.Contains(t, md, "| Active | 4 | @bob on project argo | failed | the row's email \\| belongs to another maintainer |")
+	assert.Contains(t, md, "| Staff | 2 | @staffer | unchanged |  |")
+
+	conn := openEmptyDB(t)
+	require.NoError(t, Migrate(conn))
+	require.NoError(t, SaveImportReport(t.Context(), conn, *report))
+	var run model.ImportRun
+	require.NoError(t, conn.First(&run).Error)
+	assert.Equal(t, 1, run.Failed)
+	assert.Equal(t, 1, run.Skipped)
+	var stored ImportReport
+	require.NoError(t, json.Unmarshal([]byte(run.Report), &stored))
+	assert.Equal(t, report.Rows, stored.Rows)
+
+	_, err := LoadImportReport(t.Context(), conn, "no-such-run")
+	assert.ErrorIs(t, err, ErrImportRunNotFound)
+	loaded, err := LoadImportReport(t.Context(), conn, report.RunID)
+	require.NoError(t, err)
+	assert.Equal(t, report.Source, loaded.Source)
+}
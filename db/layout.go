@@ -0,0 +1,259 @@
+package db
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// A SheetField is a field of a sheet record that a worksheet column is read into.
+type SheetField string
+
+const (
+	FieldProject       SheetField = "project"
+	FieldStatus        SheetField = "status"
+	FieldParentProject SheetField = "parentProject"
+	FieldMaintainerRef SheetField = "maintainerRef"
+	FieldMailingList   SheetField = "mailingList"
+	FieldName          SheetField = "name"
+	FieldCompany       SheetField = "company"
+	FieldEmail         SheetField = "email"
+	FieldGitHub        SheetField = "github"
+	FieldGitHubEmail   SheetField = "githubEmail"
+	FieldRoles         SheetField = "roles"
+	FieldFoundation    SheetField = "foundation"
+)
+
+// activeFields and staffFields are the fields each worksheet is read into.
+var (
+	activeFields = []SheetField{FieldProject, FieldStatus, FieldParentProject, FieldMaintainerRef, FieldMailingList,
+		FieldName, FieldCompany, FieldEmail, FieldGitHub, FieldGitHubEmail, FieldRoles}
+	staffFields = []SheetField{FieldFoundation, FieldName, FieldEmail, FieldGitHub, FieldGitHubEmail}
+)
+
+// Headers are the headers of the columns a field is read from; the first non-empty cell wins. In YAML a single
+// header may be given as a string.
+type Headers []string
+
+func (h *Headers) UnmarshalJSON(data []byte) error {
+	var one string
+	if err := json.Unmarshal(data, &one); err == nil {
+		*h = Headers{one}
+		return nil
+	}
+	var many []string
+	if err := json.Unmarshal(data, &many); err != nil {
+		return fmt.Errorf("headers must be a string or a list of strings: %w", err)
+	}
+	*h = many
+	return nil
+}
+
+// A WorksheetLayout says where a worksheet is and which columns hold which fields.
+type WorksheetLayout struct {
+	// Name is the worksheet's tab, and the name of its file in a CSV export.
+	Name string `json:"name"`
+	// Range is the A1 notation of the columns read from Google Sheets, e.g. A:K. It must include the header row.
+	Range   string                 `json:"range"`
+	Columns map[SheetField]Headers `json:"columns"`
+	// CarryForward are the fields whose blank cells take the last value above them.
+	CarryForward []SheetField `json:"carryForward,omitempty"`
+	// Required are the fields without which nothing is imported; their columns must be present.
+	Required []SheetField `json:"required,omitempty"`
+}
+
+// A SheetLayout maps the worksheets of the maintainer sheet to records. Loaders reject a worksheet that lacks the
+// columns of its required fields before they import anything, so a renamed column fails loudly.
+type SheetLayout struct {
+	Active WorksheetLayout `json:"active"`
+	Staff  WorksheetLayout `json:"staff"`
+}
+
+// DefaultSheetLayout returns the layout of the CNCF maintainer sheet.
+func DefaultSheetLayout() SheetLayout {
+	return SheetLayout{
+		Active: WorksheetLayout{
+			Name:  "Active",
+			Range: "A:K",
+			Columns: map[SheetField]Headers{
+				FieldProject:       {ProjectHdr},
+				FieldStatus:        {StatusHdr},
+				FieldParentProject: {ParentProjectHdr},
+				FieldMaintainerRef: {MaintainerFileRefHdr},
+				FieldMailingList:   {MailingListAddrHdr},
+				FieldName:          {MaintainerNameHdr},
+				FieldCompany:       {CompanyNameHdr},
+				FieldEmail:         {EmailHdr},
+				FieldGitHub:        {GitHubHdr},
+				FieldGitHubEmail:   {GitHubEmail},
+				FieldRoles:         {RolesHdr},
+			},
+			CarryForward: []SheetField{FieldProject, FieldStatus, FieldMaintainerRef, FieldMailingList},
+			Required:     []SheetField{FieldProject, FieldStatus, FieldName, FieldEmail, FieldGitHub},
+		},
+		Staff: WorksheetLayout{
+			Name:  "Staff",
+			Range: "A:F",
+			Columns: map[SheetField]Headers{
+				FieldFoundation: {FoundationHdr},
+				// Backwards-compat if the sheet reuses the maintainer header.
+				FieldName:        {StaffMemberNameHdr, MaintainerNameHdr},
+				FieldEmail:       {EmailHdr},
+				FieldGitHub:      {GitHubHdr},
+				FieldGitHubEmail: {GitHubEmail},
+			},
+			CarryForward: []SheetField{FieldFoundation},
+			Required:     []SheetField{FieldFoundation, FieldEmail},
+		},
+	}
+}
+
+// LoadSheetLayout reads a YAML sheet layout from path. Worksheets and columns it does not mention keep their
+// defaults; lists it sets replace the default lists.
+func LoadSheetLayout(path string) (SheetLayout, error) {
+	layout := DefaultSheetLayout()
+	if err := readYAML(path, &layout); err != nil {
+		return layout, err
+	}
+	if err := layout.Validate(); err != nil {
+		return layout, fmt.Errorf("%s: %w", path, err)
+	}
+	return layout, nil
+}
+
+// Validate checks that each worksheet is named, only maps its own fields, and maps the fields it requires and
+// carries forward.
+func (l SheetLayout) Validate() error {
+	if err := l.Active.validate(activeFields); err != nil {
+		return fmt.Errorf("active: %w", err)
+	}
+	if err := l.Staff.validate(staffFields); err != nil {
+		return fmt.Errorf("staff: %w", err)
+	}
+	return nil
+}
+
+func (w WorksheetLayout) validate(fields []SheetField) error {
+	if w.Name == "" || w.Range == "" {
+		return fmt.Errorf("name and range are required")
+	}
+	for field, headers := range w.Columns {
+		if !slices.Contains(fields, field) {
+			return fmt.Errorf("unknown field %q, want one of %v", field, fields)
+		}
+		if len(headers) == 0 || slices.Contains(headers, "") {
+			return fmt.Errorf("field %q has an empty header", field)
+		}
+	}
+	for _, field := range append(slices.Clone(w.Required), w.CarryForward...) {
+		if len(w.Columns[field]) == 0 {
+			return fmt.Errorf("field %q is required or carried forward but has no column", field)
+		}
+	}
+	return nil
+}
+
+// A SheetRow is a worksheet row read into fields.
+type SheetRow struct {
+	Ref    RowRef
+	Fields map[SheetField]string
+}
+
+// rowMaps reads every row after the header row into the fields of layout, referenced by worksheet and row, carrying
+// forward the last non-empty value of its CarryForward fields into blank or missing cells. Cells are trimmed and fully
+// empty rows skipped. It fails, before reading any row, if the header row lacks the columns of a required field, and
+// logs the columns it ignores.
+func rowMaps(values [][]string, layout WorksheetLayout) ([]SheetRow, error) {
+	if len(values) == 0 {
+		return nil, fmt.Errorf("worksheet is empty")
+	}
+
+	// First row → headers
+	columns := map[string]int{}
+	for i, cell := range values[0] {
+		if h := strings.TrimSpace(cell); h != "" {
+			if _, dup := columns[h]; !dup {
+				columns[h] = i
+			}
+		}
+	}
+	fieldColumns := map[SheetField][]int{}
+	mapped := map[string]bool{}
+	for field, headers := range layout.Columns {
+		for _, h := range headers {
+			if i, ok := columns[h]; ok {
+				fieldColumns[field] = append(fieldColumns[field], i)
+				mapped[h] = true
+			}
+		}
+	}
+	var missing []string
+	for _, field := range layout.Required {
+		if len(fieldColumns[field]) == 0 {
+			missing = append(missing, fmt.Sprintf("%s (%s)", strings.Join(layout.Columns[field], " or "), field))
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("worksheet %s lacks the required columns %s", layout.Name, strings.Join(missing, ", "))
+	}
+	var ignored []string
+	for h := range columns {
+		if !mapped[h] {
+			ignored = append(ignored, h)
+		}
+	}
+	if len(ignored) > 0 {
+		sort.Strings(ignored)
+		log.Printf("rowMaps: INF, worksheet %s: ignoring columns not in the sheet layout: %q", layout.Name, ignored)
+	}
+
+	var rows []SheetRow
+	lastVals := make(map[SheetField]string, len(layout.CarryForward))
+
+	// Remaining rows → fields
+	for n, r := range values[1:] {
+		hasAnyValue := false
+		for _, cell := range r {
+			if strings.TrimSpace(cell) != "" {
+				hasAnyValue = true
+				break
+			}
+		}
+		// Skip fully empty rows.
+		if !hasAnyValue {
+			continue
+		}
+
+		row := make(map[SheetField]string, len(fieldColumns))
+		for field, cols := range fieldColumns {
+			for _, i := range cols {
+				if i < len(r) {
+					if cellVal := strings.TrimSpace(r[i]); cellVal != "" {
+						row[field] = cellVal
+						break
+					}
+				}
+			}
+		}
+		for _, field := range layout.CarryForward {
+			if row[field] != "" {
+				lastVals[field] = row[field]
+			}
+			row[field] = lastVals[field]
+		}
+		rows = append(rows, SheetRow{Ref: RowRef{Origin: layout.Name, Row: n + 2}, Fields: row})
+	}
+
+	return rows, nil
+}
+
+// sheetLayout returns layout, or the default layout if it is nil.
+func sheetLayout(layout *SheetLayout) SheetLayout {
+	if layout == nil {
+		return DefaultSheetLayout()
+	}
+	return *layout
+}
@@ -0,0 +1,75 @@
+package db
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRowMapsRequiresColumns(t *testing.T) {
+	layout := DefaultSheetLayout().Active
+	_, err := rowMaps([][]string{
+		{ProjectHdr, StatusHdr, MaintainerNameHdr, EmailHdr, "GitHub Handle"},
+		{"argo", "Graduated", "Alice", "alice@example.com", "alice"},
+	}, layout)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "(github)")
+
+	_, err = rowMaps(nil, layout)
+	assert.Error(t, err)
+}
+
+func TestRowMapsStaffNameFallback(t *testing.T) {
+	rows, err := rowMaps([][]string{
+		{FoundationHdr, MaintainerNameHdr, EmailHdr},
+		{"CNCF", "Staff", "staff@cncf.io"},
+		{"", "", ""},
+		{"", "Other", "other@cncf.io"},
+	}, DefaultSheetLayout().Staff)
+	require.NoError(t, err)
+	assert.Equal(t, []SheetRow{
+		{Ref: RowRef{Origin: "Staff", Row: 2}, Fields: map[SheetField]string{FieldFoundation: "CNCF", FieldName: "Staff", FieldEmail: "staff@cncf.io"}},
+		{Ref: RowRef{Origin: "Staff", Row: 4}, Fields: map[SheetField]string{FieldFoundation: "CNCF", FieldName: "Other", FieldEmail: "other@cncf.io"}},
+	}, rows, "empty rows are skipped but counted")
+}
+
+func TestLoadSheetLayout(t *testing.T) {
+	dir := t.TempDir()
+	write := func(content string) string {
+		path := filepath.Join(dir, "layout.yaml")
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+		return path
+	}
+
+	layout, err := LoadSheetLayout(write(`
+active:
+  range: A:M
+  columns:
+    github: GitHub Handle
+    roles: [Roles, Role]
+`))
+	require.NoError(t, err)
+	assert.Equal(t, "A:M", layout.Active.Range)
+	assert.Equal(t, "Active", layout.Active.Name, "unset fields keep their defaults")
+	assert.Equal(t, Headers{"GitHub Handle"}, layout.Active.Columns[FieldGitHub])
+	assert.Equal(t, Headers{"Roles", "Role"}, layout.Active.Columns[FieldRoles])
+	assert.Equal(t, Headers{EmailHdr}, layout.Active.Columns[FieldEmail])
+	assert.Equal(t, DefaultSheetLayout().Staff, layout.Staff)
+
+	rows, err := rowMaps([][]string{
+		{ProjectHdr, StatusHdr, MaintainerNameHdr, EmailHdr, "GitHub Handle", "Role"},
+		{"argo", "Graduated", "Alice", "alice@example.com", "alice", "lead"},
+	}, layout.Active)
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	assert.Equal(t, "alice", rows[0].Fields[FieldGitHub])
+	assert.Equal(t, "lead", rows[0].Fields[FieldRoles])
+
+	_, err = LoadSheetLayout(write("active:\n  columns:\n    foundation: Foundation\n"))
+	assert.ErrorContains(t, err, `unknown field "foundation"`)
+	_, err = LoadSheetLayout(write("staff:\n  carryForward: [github]\n  columns:\n    github: []\n"))
+	assert.Error(t, err)
+}
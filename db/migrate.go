@@ -0,0 +1,531 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"maintainerd/model"
+)
+
+// ErrSchemaVersionMismatch is returned by CheckSchemaVersion when a database is not at the schema version this build
+// of maintainer-d expects.
+var ErrSchemaVersionMismatch = errors.New("unexpected database schema version")
+
+// A Migration is one numbered, reversible change to the schema. Up and Down run inside a transaction together with
+// the update of the schema_migrations table.
+//
+// Additive migrations may use AutoMigrate on frozen copies of the models they introduce (see schema_history.go), it is
+// idempotent and portable between SQLite and PostgreSQL. Destructive changes (dropping or renaming columns, rewriting
+// data) go through tx.Migrator() or explicit SQL that works on both backends.
+type Migration struct {
+	Version int
+	Name    string
+	Up      func(tx *gorm.DB) error
+	Down    func(tx *gorm.DB) error
+}
+
+// SchemaMigration records a Migration applied to the database.
+type SchemaMigration struct {
+	Version   int `gorm:"primaryKey;autoIncrement:false"`
+	Name      string
+	AppliedAt time.Time
+}
+
+func (SchemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// MigrationState reports whether a Migration has been applied.
+type MigrationState struct {
+	Version   int
+	Name      string
+	Applied   bool
+	AppliedAt *time.Time
+}
+
+// Models returns every model persisted by maintainer-d, in dependency order. Migrations create their tables; Models is
+// what the schema amounts to once every migration has been applied.
+func Models() []interface{} {
+	return []interface{}{
+		&model.Company{},
+		&model.Foundation{},
+		&model.Project{},
+		&model.Maintainer{},
+		&model.StaffMember{},
+		&model.FoundationOfficer{},
+		&model.Collaborator{},
+		&model.MaintainerProject{},
+		&model.Service{},
+		&model.ServiceTeam{},
+		&model.ServiceUser{},
+		&model.ServiceUserTeams{},
+		&model.ServiceTeamRolePolicy{},
+		&model.AuditLog{},
+		&model.MembershipPeriod{},
+		&model.MaintainerStatusChange{},
+		&model.MaintainerIdentity{},
+		&model.Finding{},
+		&model.ImportRun{},
+		&model.ImportCheckpoint{},
+		&model.CompanyAlias{},
+		&model.AffiliationPeriod{},
+	}
+}
+
+// caseInsensitiveIndexes back the LOWER(column) = ? lookups used to match people by email and GitHub handle. A plain
+// column index cannot serve those lookups on either backend; an expression index on LOWER(column) can, and the
+// statement below is valid on both SQLite and PostgreSQL.
+var caseInsensitiveIndexes = []struct {
+	table, column string
+}{
+	{"maintainers", "email"},
+	{"maintainers", "git_hub_email"},
+	{"maintainers", "git_hub_account"},
+	{"collaborators", "email"},
+	{"collaborators", "git_hub_account"},
+	{"staff_members", "email"},
+	{"staff_members", "git_hub_email"},
+	{"staff_members", "git_hub_account"},
+}
+
+// migrations is the ordered history of the schema. Never edit or renumber a released migration, add a new one.
+var migrations = []Migration{
+	{
+		Version: 1,
+		Name:    "initial schema",
+		// Databases created before versioned migrations were built by AutoMigrate on the same tables, so applying
+		// this migration to them adopts the existing tables. The tables are frozen in schema_v1.go.
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(v1Tables()...)
+		},
+		Down: func(tx *gorm.DB) error {
+			tables := v1Tables()
+			slices.Reverse(tables)
+			return tx.Migrator().DropTable(tables...)
+		},
+	},
+	{
+		Version: 2,
+		Name:    "case-insensitive email and GitHub indexes",
+		Up: func(tx *gorm.DB) error {
+			for _, idx := range caseInsensitiveIndexes {
+				stmt := fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_%s_lower_%s ON %s (LOWER(%s))",
+					idx.table, idx.column, idx.table, idx.column)
+				if err := tx.Exec(stmt).Error; err != nil {
+					return fmt.Errorf("create index on LOWER(%s.%s): %w", idx.table, idx.column, err)
+				}
+			}
+			return nil
+		},
+		Down: func(tx *gorm.DB) error {
+			for _, idx := range caseInsensitiveIndexes {
+				if err := tx.Exec(fmt.Sprintf("DROP INDEX IF EXISTS idx_%s_lower_%s", idx.table, idx.column)).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	},
+	{
+		Version: 3,
+		Name:    "archivable project memberships",
+		Up: func(tx *gorm.DB) error {
+			if !tx.Migrator().HasColumn(&v3MaintainerProject{}, "DeletedAt") {
+				if err := tx.Migrator().AddColumn(&v3MaintainerProject{}, "DeletedAt"); err != nil {
+					return err
+				}
+			}
+			if tx.Migrator().HasIndex(&v3MaintainerProject{}, "DeletedAt") {
+				return nil
+			}
+			return tx.Migrator().CreateIndex(&v3MaintainerProject{}, "DeletedAt")
+		},
+		Down: func(tx *gorm.DB) error {
+			// Archived memberships would otherwise come back as live ones.
+			if err := tx.Exec("DELETE FROM maintainer_projects WHERE deleted_at IS NOT NULL").Error; err != nil {
+				return err
+			}
+			if err := tx.Migrator().DropIndex(&v3MaintainerProject{}, "DeletedAt"); err != nil {
+				return err
+			}
+			return tx.Migrator().DropColumn(&v3MaintainerProject{}, "DeletedAt")
+		},
+	},
+	{
+		Version: 4,
+		Name:    "membership periods and maintainer status history",
+		Up: func(tx *gorm.DB) error {
+			if err := tx.AutoMigrate(&v4MembershipPeriod{}, &v4MaintainerStatusChange{}); err != nil {
+				return err
+			}
+			// Every existing membership, archived or not, is still current: open a period from the day it was joined.
+			return tx.Exec(`INSERT INTO membership_periods (maintainer_id, project_id, role, started_at, created_at)
+				SELECT mp.maintainer_id, mp.project_id, 'maintainer', COALESCE(mp.joined_at, CURRENT_TIMESTAMP), CURRENT_TIMESTAMP
+				FROM maintainer_projects mp
+				WHERE NOT EXISTS (SELECT 1 FROM membership_periods p
+					WHERE p.maintainer_id = mp.maintainer_id AND p.project_id = mp.project_id AND p.ended_at IS NULL)`).Error
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&v4MaintainerStatusChange{}, &v4MembershipPeriod{})
+		},
+	},
+	{
+		Version: 5,
+		Name:    "membership roles",
+		// Existing memberships take the column default, maintainer, which is the role their open periods record.
+		Up: func(tx *gorm.DB) error {
+			if tx.Migrator().HasColumn(&v5MaintainerProject{}, "Roles") {
+				return nil
+			}
+			return tx.Migrator().AddColumn(&v5MaintainerProject{}, "Roles")
+		},
+		Down: func(tx *gorm.DB) error {
+			if err := tx.Migrator().DropColumn(&v5MaintainerProject{}, "Roles"); err != nil {
+				return err
+			}
+			// SQLite drops a column by rebuilding the table, which loses its indexes.
+			for _, field := range []string{"MaintainerID", "ProjectID", "DeletedAt"} {
+				if tx.Migrator().HasIndex(&v5MaintainerProject{}, field) {
+					continue
+				}
+				if err := tx.Migrator().CreateIndex(&v5MaintainerProject{}, field); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	},
+	{
+		Version: 6,
+		Name:    "maintainer identities",
+		Up: func(tx *gorm.DB) error {
+			if err := tx.AutoMigrate(&v6MaintainerIdentity{}); err != nil {
+				return err
+			}
+			// Seed each maintainer's identities, archived or not, from the columns they were known by until now. An
+			// identity another maintainer already has stays theirs.
+			var maintainers []v6Maintainer
+			if err := tx.Order("id").Find(&maintainers).Error; err != nil {
+				return err
+			}
+			for _, m := range maintainers {
+				for _, id := range v6Identities(m) {
+					result := tx.Clauses(clause.OnConflict{
+						Columns:   []clause.Column{{Name: "kind"}, {Name: "service"}, {Name: "value"}},
+						DoNothing: true,
+					}).Create(&id)
+					if result.Error != nil {
+						return result.Error
+					}
+					if result.RowsAffected == 0 {
+						log.Printf("MigrateTo: WRN, migration 6, maintainer %d: %s identity is already another maintainer's", m.ID, id.Kind)
+					}
+				}
+			}
+			return nil
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&v6MaintainerIdentity{})
+		},
+	},
+	{
+		Version: 7,
+		Name:    "data quality findings",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&v7Finding{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&v7Finding{})
+		},
+	},
+	{
+		Version: 8,
+		Name:    "import runs",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&v8ImportRun{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&v8ImportRun{})
+		},
+	},
+	{
+		Version: 9,
+		Name:    "import checkpoints",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&v9ImportCheckpoint{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&v9ImportCheckpoint{})
+		},
+	},
+	{
+		Version: 10,
+		Name:    "company aliases and affiliation periods",
+		Up: func(tx *gorm.DB) error {
+			if err := tx.AutoMigrate(&v10CompanyAlias{}, &v10AffiliationPeriod{}); err != nil {
+				return err
+			}
+			// Each company's name becomes its alias; of companies spelt alike, the oldest takes the key until
+			// NormalizeCompanies merges them.
+			var companies []v10Company
+			if err := tx.Order("id").Find(&companies).Error; err != nil {
+				return err
+			}
+			for _, c := range companies {
+				key := v10CompanyKey(c.Name)
+				if key == "" {
+					continue
+				}
+				alias := v10CompanyAlias{CompanyID: c.ID, Key: key, Name: c.Name}
+				err := tx.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "key"}}, DoNothing: true}).
+					Omit(clause.Associations).Create(&alias).Error
+				if err != nil {
+					return fmt.Errorf("add alias %q to company %d: %w", c.Name, c.ID, err)
+				}
+			}
+			// Every maintainer, archived or not, has worked for their company since they were recorded.
+			return tx.Exec(`INSERT INTO affiliation_periods (maintainer_id, company_id, started_at, created_at)
+				SELECT m.id, m.company_id, COALESCE(m.created_at, CURRENT_TIMESTAMP), CURRENT_TIMESTAMP
+				FROM maintainers m
+				WHERE m.company_id IS NOT NULL AND NOT EXISTS (SELECT 1 FROM affiliation_periods p
+					WHERE p.maintainer_id = m.id AND p.ended_at IS NULL)`).Error
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&v10AffiliationPeriod{}, &v10CompanyAlias{})
+		},
+	},
+	{
+		Version: 11,
+		Name:    "collaborator projects join table",
+		// Collaborators used to share maintainer_projects with maintainers, their IDs in maintainer_id, which on
+		// PostgreSQL tied maintainer_id to both tables. Nothing recorded collaborator projects, so none are moved.
+		Up: func(tx *gorm.DB) error {
+			type collaboratorProject struct {
+				CollaboratorID uint `gorm:"primaryKey"`
+				ProjectID      uint `gorm:"primaryKey"`
+			}
+			if err := tx.Table("collaborator_projects").AutoMigrate(&collaboratorProject{}); err != nil {
+				return err
+			}
+			const shared = "fk_maintainer_projects_collaborator"
+			if tx.Migrator().HasConstraint("maintainer_projects", shared) {
+				return tx.Migrator().DropConstraint("maintainer_projects", shared)
+			}
+			return nil
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable("collaborator_projects")
+		},
+	},
+	{
+		Version: 12,
+		Name:    "service team role policies",
+		// Migration 1 used to create this table, so databases migrated before it was frozen already have it.
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&v12ServiceTeamRolePolicy{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&v12ServiceTeamRolePolicy{})
+		},
+	},
+	{
+		Version: 13,
+		Name:    "close membership periods of archived memberships",
+		// Archiving used to leave membership periods open, so archived maintainers still counted as maintainers.
+		// Close them when their membership was archived, as archiving now does.
+		Up: func(tx *gorm.DB) error {
+			return tx.Exec(`UPDATE membership_periods SET reason = ?, ended_at = (SELECT mp.deleted_at
+					FROM maintainer_projects mp
+					WHERE mp.maintainer_id = membership_periods.maintainer_id
+					AND mp.project_id = membership_periods.project_id)
+				WHERE ended_at IS NULL AND EXISTS (SELECT 1 FROM maintainer_projects mp
+					WHERE mp.maintainer_id = membership_periods.maintainer_id
+					AND mp.project_id = membership_periods.project_id AND mp.deleted_at IS NOT NULL)`,
+				"archived").Error
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Exec(`UPDATE membership_periods SET reason = '', ended_at = NULL
+				WHERE reason = ? AND EXISTS (SELECT 1 FROM maintainer_projects mp
+					WHERE mp.maintainer_id = membership_periods.maintainer_id
+					AND mp.project_id = membership_periods.project_id AND mp.deleted_at = membership_periods.ended_at)`,
+				"archived").Error
+		},
+	},
+	{
+		Version: 14,
+		Name:    "staff GitHub user IDs",
+		Up: func(tx *gorm.DB) error {
+			if tx.Migrator().HasColumn(&v14StaffMember{}, "GitHubID") {
+				return nil
+			}
+			if err := tx.Migrator().AddColumn(&v14StaffMember{}, "GitHubID"); err != nil {
+				return err
+			}
+			return tx.Migrator().CreateIndex(&v14StaffMember{}, "GitHubID")
+		},
+		Down: func(tx *gorm.DB) error {
+			if tx.Migrator().HasIndex(&v14StaffMember{}, "GitHubID") {
+				if err := tx.Migrator().DropIndex(&v14StaffMember{}, "GitHubID"); err != nil {
+					return err
+				}
+			}
+			return tx.Migrator().DropColumn(&v14StaffMember{}, "GitHubID")
+		},
+	},
+}
+
+// Migrations returns the schema history, oldest first.
+func Migrations() []Migration {
+	return append([]Migration(nil), migrations...)
+}
+
+// LatestVersion is the schema version this build of maintainer-d expects.
+func LatestVersion() int {
+	return migrations[len(migrations)-1].Version
+}
+
+// SchemaVersion returns the highest migration applied to db, or 0 if none has been.
+func SchemaVersion(db *gorm.DB) (int, error) {
+	if !db.Migrator().HasTable(&SchemaMigration{}) {
+		return 0, nil
+	}
+	var version int
+	if err := db.Model(&SchemaMigration{}).Select("COALESCE(MAX(version), 0)").Scan(&version).Error; err != nil {
+		return 0, fmt.Errorf("read schema version: %w", err)
+	}
+	return version, nil
+}
+
+// CheckSchemaVersion returns ErrSchemaVersionMismatch unless db is at LatestVersion.
+func CheckSchemaVersion(db *gorm.DB) error {
+	version, err := SchemaVersion(db)
+	if err != nil {
+		return err
+	}
+	if version != LatestVersion() {
+		return fmt.Errorf("%w: database is at version %d, expected %d; run 'bootstrap migrate up'",
+			ErrSchemaVersionMismatch, version, LatestVersion())
+	}
+	return nil
+}
+
+// Migrate applies every pending migration.
+func Migrate(db *gorm.DB) error {
+	_, err := MigrateTo(db, LatestVersion())
+	return err
+}
+
+// MigrateTo applies or reverts migrations until db is at target, returning the migrations that ran in the order
+// they ran. A target of 0 reverts every migration.
+func MigrateTo(db *gorm.DB, target int) ([]Migration, error) {
+	if target < 0 || target > LatestVersion() {
+		return nil, fmt.Errorf("no schema version %d, versions run from 0 to %d", target, LatestVersion())
+	}
+	if err := SetupJoinTables(db); err != nil {
+		return nil, err
+	}
+	if err := db.AutoMigrate(&SchemaMigration{}); err != nil {
+		return nil, fmt.Errorf("create schema_migrations: %w", err)
+	}
+	applied, err := appliedVersions(db)
+	if err != nil {
+		return nil, err
+	}
+
+	var ran []Migration
+	for _, m := range migrations {
+		if m.Version > target || applied[m.Version] != nil {
+			continue
+		}
+		if err := db.Transaction(func(tx *gorm.DB) error {
+			if err := m.Up(tx); err != nil {
+				return err
+			}
+			return tx.Create(&SchemaMigration{Version: m.Version, Name: m.Name, AppliedAt: time.Now().UTC()}).Error
+		}); err != nil {
+			return ran, fmt.Errorf("migration %d (%s) up: %w", m.Version, m.Name, err)
+		}
+		log.Printf("MigrateTo: INF, applied migration %d (%s)", m.Version, m.Name)
+		ran = append(ran, m)
+	}
+	for i := len(migrations) - 1; i >= 0; i-- {
+		m := migrations[i]
+		if m.Version <= target || applied[m.Version] == nil {
+			continue
+		}
+		if err := db.Transaction(func(tx *gorm.DB) error {
+			if err := m.Down(tx); err != nil {
+				return err
+			}
+			return tx.Delete(&SchemaMigration{}, m.Version).Error
+		}); err != nil {
+			return ran, fmt.Errorf("migration %d (%s) down: %w", m.Version, m.Name, err)
+		}
+		log.Printf("MigrateTo: INF, reverted migration %d (%s)", m.Version, m.Name)
+		ran = append(ran, m)
+	}
+	return ran, nil
+}
+
+// Rollback reverts the most recent steps migrations.
+func Rollback(db *gorm.DB, steps int) ([]Migration, error) {
+	if steps < 1 {
+		return nil, fmt.Errorf("rollback needs at least one step, got %d", steps)
+	}
+	current, err := SchemaVersion(db)
+	if err != nil {
+		return nil, err
+	}
+	target := 0
+	for i := len(migrations) - 1; i >= 0; i-- {
+		if migrations[i].Version <= current {
+			if i-steps+1 > 0 {
+				target = migrations[i-steps].Version
+			}
+			break
+		}
+	}
+	return MigrateTo(db, target)
+}
+
+// MigrationStatus lists every known migration and whether it has been applied to db.
+func MigrationStatus(db *gorm.DB) ([]MigrationState, error) {
+	applied := map[int]*SchemaMigration{}
+	if db.Migrator().HasTable(&SchemaMigration{}) {
+		var err error
+		if applied, err = appliedVersions(db); err != nil {
+			return nil, err
+		}
+	}
+	states := make([]MigrationState, 0, len(migrations))
+	for _, m := range migrations {
+		state := MigrationState{Version: m.Version, Name: m.Name}
+		if a := applied[m.Version]; a != nil {
+			state.Applied = true
+			at := a.AppliedAt
+			state.AppliedAt = &at
+		}
+		states = append(states, state)
+	}
+	sort.Slice(states, func(i, j int) bool { return states[i].Version < states[j].Version })
+	return states, nil
+}
+
+func appliedVersions(db *gorm.DB) (map[int]*SchemaMigration, error) {
+	var rows []SchemaMigration
+	if err := db.Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("read schema_migrations: %w", err)
+	}
+	applied := make(map[int]*SchemaMigration, len(rows))
+	for i := range rows {
+		applied[rows[i].Version] = &rows[i]
+	}
+	return applied, nil
+}
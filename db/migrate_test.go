@@ -0,0 +1,298 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"maintainerd/model"
+)
+
+func openEmptyDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	conn, err := Open(":memory:", &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+	return conn
+}
+
+func TestMigrationsAreNumberedInOrder(t *testing.T) {
+	for i, m := range Migrations() {
+		assert.Equal(t, i+1, m.Version, m.Name)
+		assert.NotNil(t, m.Up, m.Name)
+		assert.NotNil(t, m.Down, m.Name)
+	}
+}
+
+func TestMigrateUpDownStatus(t *testing.T) {
+	conn := openEmptyDB(t)
+
+	version, err := SchemaVersion(conn)
+	require.NoError(t, err)
+	assert.Equal(t, 0, version)
+	assert.ErrorIs(t, CheckSchemaVersion(conn), ErrSchemaVersionMismatch)
+
+	require.NoError(t, Migrate(conn))
+	require.NoError(t, CheckSchemaVersion(conn))
+	assert.True(t, conn.Migrator().HasTable(&model.Maintainer{}))
+
+	ran, err := MigrateTo(conn, LatestVersion())
+	require.NoError(t, err)
+	assert.Empty(t, ran, "migrating to the current version is a no-op")
+
+	ran, err = Rollback(conn, 1)
+	require.NoError(t, err)
+	require.Len(t, ran, 1)
+	assert.Equal(t, LatestVersion(), ran[0].Version)
+	assert.False(t, conn.Migrator().HasColumn(&model.StaffMember{}, "GitHubID"))
+
+	ran, err = Rollback(conn, 1)
+	require.NoError(t, err)
+	require.Len(t, ran, 1)
+
+	ran, err = Rollback(conn, 1)
+	require.NoError(t, err)
+	require.Len(t, ran, 1)
+	assert.False(t, conn.Migrator().HasTable(&model.ServiceTeamRolePolicy{}))
+
+	ran, err = Rollback(conn, 1)
+	require.NoError(t, err)
+	require.Len(t, ran, 1)
+	assert.False(t, conn.Migrator().HasTable("collaborator_projects"))
+
+	ran, err = Rollback(conn, 1)
+	require.NoError(t, err)
+	require.Len(t, ran, 1)
+	assert.False(t, conn.Migrator().HasTable(&model.AffiliationPeriod{}))
+	assert.False(t, conn.Migrator().HasTable(&model.CompanyAlias{}))
+
+	ran, err = Rollback(conn, 1)
+	require.NoError(t, err)
+	require.Len(t, ran, 1)
+	assert.False(t, conn.Migrator().HasTable(&model.ImportCheckpoint{}))
+
+	ran, err = Rollback(conn, 1)
+	require.NoError(t, err)
+	require.Len(t, ran, 1)
+	assert.False(t, conn.Migrator().HasTable(&model.ImportRun{}))
+
+	ran, err = Rollback(conn, 1)
+	require.NoError(t, err)
+	require.Len(t, ran, 1)
+	assert.False(t, conn.Migrator().HasTable(&model.Finding{}))
+
+	ran, err = Rollback(conn, 1)
+	require.NoError(t, err)
+	require.Len(t, ran, 1)
+	assert.False(t, conn.Migrator().HasTable(&model.MaintainerIdentity{}))
+
+	ran, err = Rollback(conn, 1)
+	require.NoError(t, err)
+	require.Len(t, ran, 1)
+	assert.False(t, conn.Migrator().HasColumn(&model.MaintainerProject{}, "Roles"))
+	assert.True(t, conn.Migrator().HasIndex(&model.MaintainerProject{}, "DeletedAt"))
+
+	ran, err = Rollback(conn, 1)
+	require.NoError(t, err)
+	require.Len(t, ran, 1)
+	assert.False(t, conn.Migrator().HasTable(&model.MembershipPeriod{}))
+
+	ran, err = MigrateTo(conn, 1)
+	require.NoError(t, err)
+	require.Len(t, ran, 2)
+	assert.Equal(t, 2, ran[len(ran)-1].Version)
+	assert.False(t, conn.Migrator().HasColumn(&model.MaintainerProject{}, "DeletedAt"))
+	assert.False(t, conn.Migrator().HasIndex("maintainers", "idx_maintainers_lower_email"))
+
+	states, err := MigrationStatus(conn)
+	require.NoError(t, err)
+	require.Len(t, states, LatestVersion())
+	assert.True(t, states[0].Applied)
+	assert.NotNil(t, states[0].AppliedAt)
+	assert.False(t, states[len(states)-1].Applied)
+
+	_, err = MigrateTo(conn, 0)
+	require.NoError(t, err)
+	assert.False(t, conn.Migrator().HasTable(&model.Maintainer{}))
+	assert.False(t, conn.Migrator().HasTable("maintainer_projects"))
+
+	require.NoError(t, Migrate(conn))
+	version, err = SchemaVersion(conn)
+	require.NoError(t, err)
+	assert.Equal(t, LatestVersion(), version)
+
+	_, err = MigrateTo(conn, LatestVersion()+1)
+	assert.Error(t, err)
+}
+
+func TestMigrateAdoptsAutoMigratedDatabase(t *testing.T) {
+	conn := openEmptyDB(t)
+	require.NoError(t, conn.AutoMigrate(v1Tables()...))
+	require.NoError(t, conn.Create(&model.Company{Name: "Existing"}).Error)
+
+	require.NoError(t, Migrate(conn))
+
+	var count int64
+	require.NoError(t, conn.Model(&model.Company{}).Count(&count).Error)
+	assert.Equal(t, int64(1), count)
+}
+
+func TestInitialSchemaIsFrozen(t *testing.T) {
+	conn := openEmptyDB(t)
+	_, err := MigrateTo(conn, 1)
+	require.NoError(t, err)
+	assert.True(t, conn.Migrator().HasTable(&model.Maintainer{}))
+	for _, table := range []interface{}{&model.MembershipPeriod{}, &model.MaintainerIdentity{}, &model.Finding{},
+		&model.ImportRun{}, &model.CompanyAlias{}, &model.AffiliationPeriod{}, &model.ServiceTeamRolePolicy{}} {
+		assert.False(t, conn.Migrator().HasTable(table), "%T belongs to a later migration", table)
+	}
+	assert.False(t, conn.Migrator().HasColumn(&model.MaintainerProject{}, "Roles"))
+	assert.False(t, conn.Migrator().HasColumn(&model.MaintainerProject{}, "DeletedAt"))
+}
+
+func TestMigrationsBuildEveryModel(t *testing.T) {
+	conn := openEmptyDB(t)
+	require.NoError(t, Migrate(conn))
+	for _, m := range Models() {
+		stmt := &gorm.Statement{DB: conn}
+		require.NoError(t, stmt.Parse(m))
+		require.True(t, conn.Migrator().HasTable(m), stmt.Schema.Table)
+		for _, field := range stmt.Schema.Fields {
+			if field.DBName != "" {
+				assert.True(t, conn.Migrator().HasColumn(m, field.DBName), "%s.%s", stmt.Schema.Table, field.DBName)
+			}
+		}
+	}
+}
+
+func TestCheckSchemaVersionRejectsNewerDatabase(t *testing.T) {
+	conn := openEmptyDB(t)
+	require.NoError(t, Migrate(conn))
+	require.NoError(t, conn.Create(&SchemaMigration{Version: LatestVersion() + 1, Name: "from the future"}).Error)
+
+	err := CheckSchemaVersion(conn)
+	assert.ErrorIs(t, err, ErrSchemaVersionMismatch)
+	assert.Contains(t, err.Error(), "expected")
+}
+
+func TestMigrationBackfillsMembershipPeriods(t *testing.T) {
+	conn := openEmptyDB(t)
+	_, err := MigrateTo(conn, 3)
+	require.NoError(t, err)
+	project := model.Project{Name: "argo", Maturity: model.Graduated}
+	require.NoError(t, conn.Create(&project).Error)
+	maintainer := model.Maintainer{Email: "alice@example.com", MaintainerStatus: model.ActiveMaintainer}
+	require.NoError(t, conn.Create(&maintainer).Error)
+	// The membership as version 3 stored it, before memberships had roles.
+	require.NoError(t, conn.Exec("INSERT INTO maintainer_projects (maintainer_id, project_id, joined_at) VALUES (?, ?, ?)",
+		maintainer.ID, project.ID, time.Now()).Error)
+
+	_, err = MigrateTo(conn, 4)
+	require.NoError(t, err)
+	var periods []model.MembershipPeriod
+	require.NoError(t, conn.Find(&periods).Error)
+	require.Len(t, periods, 1)
+	assert.Equal(t, maintainer.ID, periods[0].MaintainerID)
+	assert.Equal(t, project.ID, periods[0].ProjectID)
+	assert.Equal(t, model.RoleMaintainer, periods[0].Role)
+	assert.Nil(t, periods[0].EndedAt)
+	assert.False(t, periods[0].StartedAt.IsZero())
+}
+
+func TestMigrationClosesArchivedMembershipPeriods(t *testing.T) {
+	conn := openEmptyDB(t)
+	_, err := MigrateTo(conn, 12)
+	require.NoError(t, err)
+	ctx := t.Context()
+	store := NewSQLStore(conn)
+	project := model.Project{Name: "argo", Maturity: model.Graduated}
+	require.NoError(t, conn.Create(&project).Error)
+	var ids []uint
+	for _, email := range []string{"alice@example.com", "bob@example.com"} {
+		m := model.Maintainer{Email: email, MaintainerStatus: model.ActiveMaintainer}
+		require.NoError(t, conn.Create(&m).Error)
+		require.NoError(t, store.StartMembership(ctx, m.ID, project.ID, nil, time.Now().Add(-time.Hour)))
+		ids = append(ids, m.ID)
+	}
+	// Bob archived as version 12 did it, leaving his membership period open.
+	archived := historyTime(time.Now())
+	require.NoError(t, conn.Model(&model.Maintainer{}).Where("id = ?", ids[1]).Update("deleted_at", archived).Error)
+	require.NoError(t, conn.Model(&model.MaintainerProject{}).Where("maintainer_id = ?", ids[1]).
+		Update("deleted_at", archived).Error)
+
+	_, err = MigrateTo(conn, 13)
+	require.NoError(t, err)
+	var periods []model.MembershipPeriod
+	require.NoError(t, conn.Order("maintainer_id").Find(&periods).Error)
+	require.Len(t, periods, 2)
+	assert.Nil(t, periods[0].EndedAt)
+	require.NotNil(t, periods[1].EndedAt)
+	assert.True(t, archived.Equal(*periods[1].EndedAt))
+	assert.Equal(t, ArchiveReason, periods[1].Reason)
+
+	require.NoError(t, store.RestoreMaintainer(ctx, ids[1]))
+	require.NoError(t, conn.Order("maintainer_id").Find(&periods).Error)
+	assert.Nil(t, periods[1].EndedAt, "restoring reopens the periods the migration closed")
+}
+
+func TestMigrationSeedsMaintainerIdentities(t *testing.T) {
+	conn := openEmptyDB(t)
+	_, err := MigrateTo(conn, 5)
+	require.NoError(t, err)
+	alice := model.Maintainer{Email: "Alice@Example.com", GitHubEmail: "alice@users.noreply.github.com",
+		GitHubAccount: "@Alice", MaintainerStatus: model.ActiveMaintainer}
+	bob := model.Maintainer{Email: "EMAIL_MISSING", GitHubAccount: "bob", MaintainerStatus: model.EmeritusMaintainer}
+	require.NoError(t, conn.Create(&alice).Error)
+	require.NoError(t, conn.Create(&bob).Error)
+	require.NoError(t, conn.Delete(&bob).Error)
+
+	_, err = MigrateTo(conn, 6)
+	require.NoError(t, err)
+	var identities []model.MaintainerIdentity
+	require.NoError(t, conn.Order("id").Find(&identities).Error)
+	require.Len(t, identities, 4)
+	assert.Equal(t, alice.ID, identities[0].MaintainerID)
+	assert.Equal(t, model.IdentityEmail, identities[0].Kind)
+	assert.Equal(t, "alice@example.com", identities[0].Value)
+	assert.True(t, identities[0].IsPrimary)
+	assert.Equal(t, "alice@users.noreply.github.com", identities[1].Value)
+	assert.False(t, identities[1].IsPrimary)
+	assert.Equal(t, "alice", identities[2].Value)
+	assert.Equal(t, bob.ID, identities[3].MaintainerID, "archived maintainers keep their identities")
+
+	var reloaded model.Maintainer
+	require.NoError(t, conn.First(&reloaded, alice.ID).Error)
+	assert.Equal(t, "Alice@Example.com", reloaded.Email, "a primary identity differing only in case leaves the column alone")
+}
+
+func TestMigrationSeedsCompanyAliasesAndAffiliations(t *testing.T) {
+	conn := openEmptyDB(t)
+	_, err := MigrateTo(conn, 9)
+	require.NoError(t, err)
+	google := model.Company{Name: "Google"}
+	googleLLC := model.Company{Name: "Google LLC"}
+	require.NoError(t, conn.Create(&google).Error)
+	require.NoError(t, conn.Create(&googleLLC).Error)
+	alice := model.Maintainer{Email: "alice@example.com", MaintainerStatus: model.ActiveMaintainer, CompanyID: &googleLLC.ID}
+	bob := model.Maintainer{Email: "bob@example.com", MaintainerStatus: model.ActiveMaintainer}
+	require.NoError(t, conn.Create(&alice).Error)
+	require.NoError(t, conn.Create(&bob).Error)
+
+	_, err = MigrateTo(conn, 10)
+	require.NoError(t, err)
+	var aliases []model.CompanyAlias
+	require.NoError(t, conn.Find(&aliases).Error)
+	require.Len(t, aliases, 1, "companies spelt alike share a key")
+	assert.Equal(t, google.ID, aliases[0].CompanyID)
+	assert.Equal(t, "google", aliases[0].Key)
+	var periods []model.AffiliationPeriod
+	require.NoError(t, conn.Find(&periods).Error)
+	require.Len(t, periods, 1)
+	assert.Equal(t, alice.ID, periods[0].MaintainerID)
+	assert.Equal(t, googleLLC.ID, periods[0].CompanyID)
+	assert.Nil(t, periods[0].EndedAt)
+	assert.False(t, periods[0].StartedAt.IsZero())
+}
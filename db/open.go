@@ -0,0 +1,94 @@
+package db
+
+import (
+	"fmt"
+	"strings"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// DSNEnvVar names the environment variable that overrides the database DSN of every maintainer-d binary.
+const DSNEnvVar = "MD_DB_DSN"
+
+// Supported database backends.
+const (
+	DriverSQLite   = "sqlite"
+	DriverPostgres = "postgres"
+)
+
+// Driver returns the backend selected by dsn. PostgreSQL is selected by a postgres:// or postgresql:// URL, or by a
+// libpq key/value string such as "host=db user=maintainerd dbname=maintainerd". Anything else is a SQLite database:
+// a file path, optionally prefixed with sqlite://, a file: URI or ":memory:".
+func Driver(dsn string) string {
+	d := strings.TrimSpace(dsn)
+	lower := strings.ToLower(d)
+	switch {
+	case strings.HasPrefix(lower, "postgres://"), strings.HasPrefix(lower, "postgresql://"):
+		return DriverPostgres
+	case strings.HasPrefix(lower, "sqlite://"), strings.HasPrefix(lower, "file:"):
+		return DriverSQLite
+	case strings.Contains(d, "=") && (strings.Contains(lower, "host=") || strings.Contains(lower, "dbname=")):
+		return DriverPostgres
+	}
+	return DriverSQLite
+}
+
+// Dialector returns the gorm.Dialector for dsn, see Driver.
+func Dialector(dsn string) (gorm.Dialector, error) {
+	if strings.TrimSpace(dsn) == "" {
+		return nil, fmt.Errorf("empty database DSN")
+	}
+	switch Driver(dsn) {
+	case DriverPostgres:
+		return postgres.Open(strings.TrimSpace(dsn)), nil
+	default:
+		return sqlite.Open(strings.TrimPrefix(strings.TrimSpace(dsn), "sqlite://")), nil
+	}
+}
+
+// Open connects to the SQLite or PostgreSQL database identified by dsn. cfg may be nil.
+func Open(dsn string, cfg *gorm.Config) (*gorm.DB, error) {
+	dialector, err := Dialector(dsn)
+	if err != nil {
+		return nil, err
+	}
+	if cfg == nil {
+		cfg = &gorm.Config{}
+	}
+	conn, err := gorm.Open(dialector, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("open %s database: %w", Driver(dsn), err)
+	}
+	if err := SetupJoinTables(conn); err != nil {
+		return nil, err
+	}
+	return conn, nil
+}
+
+// RedactDSN returns dsn with any password removed, for logging.
+func RedactDSN(dsn string) string {
+	if Driver(dsn) != DriverPostgres {
+		return dsn
+	}
+	if i := strings.Index(dsn, "://"); i >= 0 {
+		rest := dsn[i+3:]
+		at := strings.LastIndex(rest, "@")
+		if at < 0 {
+			return dsn
+		}
+		userinfo := rest[:at]
+		if colon := strings.Index(userinfo, ":"); colon >= 0 {
+			userinfo = userinfo[:colon] + ":xxxxx"
+		}
+		return dsn[:i+3] + userinfo + rest[at:]
+	}
+	fields := strings.Fields(dsn)
+	for i, f := range fields {
+		if strings.HasPrefix(strings.ToLower(f), "password=") {
+			fields[i] = "password=xxxxx"
+		}
+	}
+	return strings.Join(fields, " ")
+}
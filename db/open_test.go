@@ -0,0 +1,107 @@
+package db
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"maintainerd/model"
+)
+
+// postgresDSNEnvVar points the tests at a PostgreSQL database, see make test-postgres.
+const postgresDSNEnvVar = "MD_TEST_POSTGRES_DSN"
+
+func TestDriver(t *testing.T) {
+	tests := map[string]string{
+		"maintainers.db":              DriverSQLite,
+		"/data/maintainers.db":        DriverSQLite,
+		":memory:":                    DriverSQLite,
+		"sqlite:///data/x.db":         DriverSQLite,
+		"file:test.db?cache=shared":   DriverSQLite,
+		"postgres://u:p@db:5432/md":   DriverPostgres,
+		"postgresql://db/md":          DriverPostgres,
+		"host=db user=md dbname=md":   DriverPostgres,
+		"  postgres://u@db/md?x=1  ":  DriverPostgres,
+		"/tmp/dir=with-equals/mnt.db": DriverSQLite,
+	}
+	for dsn, want := range tests {
+		assert.Equal(t, want, Driver(dsn), dsn)
+	}
+
+	_, err := Dialector("")
+	assert.Error(t, err)
+}
+
+func TestRedactDSN(t *testing.T) {
+	assert.Equal(t, "postgres://md:xxxxx@db:5432/md", RedactDSN("postgres://md:s3cret@db:5432/md"))
+	assert.Equal(t, "host=db user=md password=xxxxx dbname=md", RedactDSN("host=db user=md password=s3cret dbname=md"))
+	assert.Equal(t, "postgres://db/md", RedactDSN("postgres://db/md"))
+	assert.Equal(t, "/data/maintainers.db", RedactDSN("/data/maintainers.db"))
+}
+
+// openTestBackends returns a freshly migrated SQLite database and, when MD_TEST_POSTGRES_DSN is set, the PostgreSQL
+// database it names, keyed by driver.
+func openTestBackends(t *testing.T) map[string]*gorm.DB {
+	t.Helper()
+	cfg := &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)}
+	backends := map[string]string{DriverSQLite: ":memory:"}
+	if dsn := os.Getenv(postgresDSNEnvVar); dsn != "" {
+		backends[DriverPostgres] = dsn
+	}
+
+	dbs := map[string]*gorm.DB{}
+	for driver, dsn := range backends {
+		conn, err := Open(dsn, cfg)
+		require.NoError(t, err)
+		if driver == DriverPostgres {
+			_, err := MigrateTo(conn, 0)
+			require.NoError(t, err)
+		}
+		require.NoError(t, Migrate(conn))
+		require.NoError(t, Migrate(conn), "Migrate must be idempotent")
+		dbs[driver] = conn
+	}
+	return dbs
+}
+
+func TestMigrateAndQueryOnEveryBackend(t *testing.T) {
+	for driver, conn := range openTestBackends(t) {
+		t.Run(driver, func(t *testing.T) {
+			assert.True(t, conn.Migrator().HasIndex("maintainers", "idx_maintainers_lower_git_hub_account"))
+
+			_, project, _, alice, _, _ := seedTestData(t, conn)
+			staff := model.StaffMember{Name: "Staff", Email: "Staff@CNCF.io", GitHubAccount: "staffer"}
+			require.NoError(t, conn.Create(&staff).Error)
+			collaborator := model.Collaborator{Name: "Col", Email: "col@example.com"}
+			require.NoError(t, conn.Create(&collaborator).Error)
+			require.NoError(t, conn.Model(&collaborator).Association("Projects").Append(&project))
+
+			store := NewSQLStore(conn)
+			maintainers, err := store.GetMaintainersByProject(t.Context(), project.ID)
+			require.NoError(t, err)
+			assert.Len(t, maintainers, 2)
+
+			isStaff, err := store.IsStaffEmail(t.Context(), "staff@cncf.io")
+			require.NoError(t, err)
+			assert.True(t, isStaff)
+			isStaff, err = store.IsStaffGitHubAccount(t.Context(), "STAFFER")
+			require.NoError(t, err)
+			assert.True(t, isStaff)
+
+			m := MapFossaUserToMaintainer(conn, "ALICE@example.com", "")
+			require.NotNil(t, m)
+			assert.Equal(t, alice.ID, m.ID)
+
+			_, err = store.AddMaintainerIdentity(t.Context(), model.MaintainerIdentity{MaintainerID: alice.ID,
+				Kind: model.IdentityEmail, Value: "alice@work.example"})
+			require.NoError(t, err)
+			m = MapFossaUserToMaintainer(conn, "Alice@Work.example", "")
+			require.NotNil(t, m, "FOSSA users are matched by any of a maintainer's emails")
+			assert.Equal(t, alice.ID, m.ID)
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"maintainerd/model"
+)
+
+// SetMembershipRoles replaces the maintainer's roles on the project and keeps their membership periods in step.
+func (s *SQLStore) SetMembershipRoles(ctx context.Context, maintainerID, projectID uint, roles model.MembershipRoles, at time.Time) error {
+	roles, err := membershipRoles(roles)
+	if err != nil {
+		return err
+	}
+	at = historyTime(at)
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var mp model.MaintainerProject
+		err := tx.Where("maintainer_id = ? AND project_id = ?", maintainerID, projectID).First(&mp).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("%w: maintainer %d, project %d", ErrNotMember, maintainerID, projectID)
+		}
+		if err != nil {
+			return fmt.Errorf("set roles of maintainer %d on project %d: %w", maintainerID, projectID, err)
+		}
+		return changeRoles(tx, mp, roles, at)
+	})
+}
+
+// GetProjectMemberships returns the memberships of the project held by live maintainers.
+func (s *SQLStore) GetProjectMemberships(ctx context.Context, projectID uint) ([]model.MaintainerProject, error) {
+	tx := s.db.WithContext(ctx).Session(&gorm.Session{})
+	if err := exists(tx, &model.Project{}, projectID, ErrProjectNotFound); err != nil {
+		return nil, err
+	}
+	var memberships []model.MaintainerProject
+	err := tx.Preload("Maintainer.Company").Preload("Maintainer.Identities").
+		Joins("JOIN maintainers ON maintainers.id = maintainer_projects.maintainer_id AND maintainers.deleted_at IS NULL").
+		Where("maintainer_projects.project_id = ?", projectID).
+		Order("maintainer_projects.maintainer_id").
+		Find(&memberships).Error
+	return memberships, err
+}
+
+// changeRoles sets the roles of membership mp to roles, closing the periods of the roles it drops and opening periods
+// for the roles it adds.
+func changeRoles(tx *gorm.DB, mp model.MaintainerProject, roles model.MembershipRoles, at time.Time) error {
+	err := tx.Model(&model.MaintainerProject{}).
+		Where("maintainer_id = ? AND project_id = ?", mp.MaintainerID, mp.ProjectID).
+		Update("roles", roles).Error
+	if err != nil {
+		return fmt.Errorf("set roles of maintainer %d on project %d: %w", mp.MaintainerID, mp.ProjectID, err)
+	}
+	for _, role := range mp.Roles {
+		if roles.Has(role) {
+			continue
+		}
+		err := tx.Model(&model.MembershipPeriod{}).
+			Where("maintainer_id = ? AND project_id = ? AND role = ? AND ended_at IS NULL", mp.MaintainerID, mp.ProjectID, role).
+			Updates(map[string]interface{}{"ended_at": at, "reason": "role removed"}).Error
+		if err != nil {
+			return fmt.Errorf("close %s period of maintainer %d on project %d: %w", role, mp.MaintainerID, mp.ProjectID, err)
+		}
+	}
+	for _, role := range roles {
+		if mp.Roles.Has(role) {
+			continue
+		}
+		if err := openPeriod(tx, mp.MaintainerID, mp.ProjectID, role, at); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// membershipRoles validates roles, defaulting an empty set to maintainer.
+func membershipRoles(roles model.MembershipRoles) (model.MembershipRoles, error) {
+	for _, r := range roles {
+		if !r.IsValid() {
+			return nil, fmt.Errorf("invalid membership role %q", r)
+		}
+	}
+	if len(roles) == 0 {
+		return model.MembershipRoles{model.RoleMaintainer}, nil
+	}
+	return roles.Normalize(), nil
+}
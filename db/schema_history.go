@@ -0,0 +1,220 @@
+package db
+
+import (
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+
+	"gorm.io/gorm"
+)
+
+// The structs below are the tables and columns of migrations 3 to 14, each prefixed with the version of its migration.
+// Like the v1 structs of schema_v1.go they are frozen copies of the models and helpers of the time, so that later
+// changes to the models never change what an old migration does on a fresh database or on down. Do not edit them; add
+// a migration instead.
+
+// v3MaintainerProject is the column migration 3 adds to project memberships.
+type v3MaintainerProject struct {
+	DeletedAt gorm.DeletedAt `gorm:"index"`
+}
+
+func (v3MaintainerProject) TableName() string { return "maintainer_projects" }
+
+type v4MembershipPeriod struct {
+	ID           uint       `gorm:"primaryKey"`
+	MaintainerID uint       `gorm:"index"`
+	ProjectID    uint       `gorm:"index"`
+	Role         string     `gorm:"size:50;default:maintainer"`
+	StartedAt    time.Time  `gorm:"index"`
+	EndedAt      *time.Time `gorm:"index"`
+	Reason       string
+	CreatedAt    time.Time
+	Maintainer   v1Maintainer `gorm:"foreignKey:MaintainerID;constraint:OnDelete:CASCADE"`
+	Project      v1Project    `gorm:"foreignKey:ProjectID;constraint:OnDelete:CASCADE"`
+}
+
+func (v4MembershipPeriod) TableName() string { return "membership_periods" }
+
+type v4MaintainerStatusChange struct {
+	ID           uint      `gorm:"primaryKey"`
+	MaintainerID uint      `gorm:"index"`
+	FromStatus   *string   `gorm:"type:text"`
+	ToStatus     string    `gorm:"type:text"`
+	ChangedAt    time.Time `gorm:"index"`
+	Reason       string
+}
+
+func (v4MaintainerStatusChange) TableName() string { return "maintainer_status_changes" }
+
+// v5MaintainerProject is a project membership as migration 5 leaves it, for the column it adds and the indexes its
+// down rebuilds.
+type v5MaintainerProject struct {
+	MaintainerID uint           `gorm:"primaryKey;index"`
+	ProjectID    uint           `gorm:"primaryKey;index"`
+	Roles        string         `gorm:"type:text;default:'maintainer'"`
+	DeletedAt    gorm.DeletedAt `gorm:"index"`
+}
+
+func (v5MaintainerProject) TableName() string { return "maintainer_projects" }
+
+type v6MaintainerIdentity struct {
+	ID           uint `gorm:"primaryKey"`
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	MaintainerID uint   `gorm:"index"`
+	Kind         string `gorm:"size:20;uniqueIndex:idx_maintainer_identities_value"`
+	Service      string `gorm:"size:50;uniqueIndex:idx_maintainer_identities_value"`
+	Value        string `gorm:"size:254;uniqueIndex:idx_maintainer_identities_value"`
+	Verified     bool
+	IsPrimary    bool
+}
+
+func (v6MaintainerIdentity) TableName() string { return "maintainer_identities" }
+
+// v6Maintainer is the part of a maintainer migration 6 seeds their identities from.
+type v6Maintainer struct {
+	ID            uint
+	Email         string
+	GitHubAccount string
+	GitHubEmail   string
+}
+
+func (v6Maintainer) TableName() string { return "maintainers" }
+
+// v6Identities returns the identities migration 6 seeds for m: their email, their GitHub email if it differs, and
+// their GitHub login, normalized and without the placeholders of missing values.
+func v6Identities(m v6Maintainer) []v6MaintainerIdentity {
+	var ids []v6MaintainerIdentity
+	add := func(kind, value string, primary bool) {
+		value = strings.TrimSpace(value)
+		switch value {
+		case "", "EMAIL_MISSING", "GITHUB_MISSING", "GITHUB_EMAIL_MISSING":
+			return
+		}
+		if kind == "github" {
+			value = strings.TrimPrefix(value, "@")
+		}
+		ids = append(ids, v6MaintainerIdentity{
+			MaintainerID: m.ID, Kind: kind, Value: strings.ToLower(value), IsPrimary: primary,
+		})
+	}
+	add("email", m.Email, true)
+	if !strings.EqualFold(m.GitHubEmail, m.Email) {
+		add("email", m.GitHubEmail, false)
+	}
+	add("github", m.GitHubAccount, true)
+	return ids
+}
+
+type v7Finding struct {
+	ID          uint `gorm:"primaryKey"`
+	CreatedAt   time.Time
+	Rule        string `gorm:"size:50;index"`
+	Severity    string `gorm:"size:20"`
+	SubjectKind string `gorm:"size:20;index:idx_findings_subject"`
+	SubjectID   uint   `gorm:"index:idx_findings_subject"`
+	Subject     string
+	Message     string
+}
+
+func (v7Finding) TableName() string { return "findings" }
+
+type v8ImportRun struct {
+	ID         uint   `gorm:"primaryKey"`
+	RunID      string `gorm:"size:40;uniqueIndex"`
+	Source     string
+	StartedAt  time.Time `gorm:"index"`
+	FinishedAt time.Time
+	Created    int
+	Updated    int
+	Unchanged  int
+	Skipped    int
+	Failed     int
+	Report     string
+}
+
+func (v8ImportRun) TableName() string { return "import_runs" }
+
+type v9ImportCheckpoint struct {
+	Stage     string `gorm:"primaryKey;size:50"`
+	LastKey   string
+	UpdatedAt time.Time
+}
+
+func (v9ImportCheckpoint) TableName() string { return "import_checkpoints" }
+
+type v10CompanyAlias struct {
+	ID        uint   `gorm:"primaryKey"`
+	CompanyID uint   `gorm:"index"`
+	Key       string `gorm:"uniqueIndex"`
+	Name      string
+	CreatedAt time.Time
+	Company   v1Company `gorm:"foreignKey:CompanyID;constraint:OnDelete:CASCADE"`
+}
+
+func (v10CompanyAlias) TableName() string { return "company_aliases" }
+
+type v10AffiliationPeriod struct {
+	ID           uint       `gorm:"primaryKey"`
+	MaintainerID uint       `gorm:"index"`
+	CompanyID    uint       `gorm:"index"`
+	StartedAt    time.Time  `gorm:"index"`
+	EndedAt      *time.Time `gorm:"index"`
+	Reason       string
+	CreatedAt    time.Time
+	Maintainer   v1Maintainer `gorm:"foreignKey:MaintainerID;constraint:OnDelete:CASCADE"`
+	Company      v1Company    `gorm:"foreignKey:CompanyID;constraint:OnDelete:CASCADE"`
+}
+
+func (v10AffiliationPeriod) TableName() string { return "affiliation_periods" }
+
+// v10Company is the part of a company migration 10 seeds its alias from.
+type v10Company struct {
+	ID   uint
+	Name string
+}
+
+func (v10Company) TableName() string { return "companies" }
+
+// v10CompanyKey is the alias key migration 10 gives a company name: lower-cased, without dots and apostrophes, other
+// punctuation separating words, and legal forms removed from the end unless the name is only a legal form.
+func v10CompanyKey(name string) string {
+	name = strings.Map(func(r rune) rune {
+		switch {
+		case r == '.' || r == '\'' || r == '’':
+			return -1
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			return unicode.ToLower(r)
+		default:
+			return ' '
+		}
+	}, name)
+	suffixes := []string{
+		"ab", "ag", "bv", "co", "company", "corp", "corporation", "gmbh", "inc", "incorporated", "kk", "limited", "llc",
+		"ltd", "nv", "oy", "plc", "pte", "pty", "sa", "sarl", "se", "srl",
+	}
+	words := strings.Fields(name)
+	for len(words) > 1 && slices.Contains(suffixes, words[len(words)-1]) {
+		words = words[:len(words)-1]
+	}
+	return strings.Join(words, " ")
+}
+
+type v12ServiceTeamRolePolicy struct {
+	gorm.Model
+	ProjectID        uint `gorm:"uniqueIndex:idx_role_policy_project_service"`
+	ServiceID        uint `gorm:"uniqueIndex:idx_role_policy_project_service"`
+	MaintainerRole   int
+	CollaboratorRole int
+	StaffRole        int
+}
+
+func (v12ServiceTeamRolePolicy) TableName() string { return "service_team_role_policies" }
+
+// v14StaffMember is the column migration 14 adds to staff members.
+type v14StaffMember struct {
+	GitHubID *int64 `gorm:"index"`
+}
+
+func (v14StaffMember) TableName() string { return "staff_members" }
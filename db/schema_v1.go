@@ -0,0 +1,191 @@
+package db
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// The v1 structs are the schema of migration 1: the tables maintainer-d built with AutoMigrate before migrations were
+// versioned. They are frozen copies of the models of the time, without their Go associations, so that later changes
+// to the models never change what migration 1 creates. Do not edit them; add a migration instead.
+
+type v1Company struct {
+	gorm.Model
+	Name string `gorm:"uniqueIndex"`
+}
+
+func (v1Company) TableName() string { return "companies" }
+
+type v1Foundation struct {
+	gorm.Model
+	Name string `gorm:"uniqueIndex"`
+}
+
+func (v1Foundation) TableName() string { return "foundations" }
+
+type v1Project struct {
+	gorm.Model
+	Name            string `gorm:"uniqueIndex,not null;check:name <> ''"`
+	ParentProjectID *uint  `gorm:"index"`
+	Maturity        string
+	MaintainerRef   string
+	OnboardingIssue *string
+	MailingList     *string `gorm:"size:254;default:MML_MISSING"`
+}
+
+func (v1Project) TableName() string { return "projects" }
+
+type v1Maintainer struct {
+	gorm.Model
+	Name             string
+	Email            string `gorm:"size:254;default:EMAIL_MISSING"`
+	GitHubAccount    string `gorm:"size:100;default:GITHUB_MISSING"`
+	GitHubEmail      string `gorm:"size:100;default:GITHUB_MISSING"`
+	MaintainerStatus string `gorm:"type:text"`
+	ImportWarnings   string
+	RegisteredAt     *time.Time
+	CompanyID        *uint
+	Company          v1Company
+}
+
+func (v1Maintainer) TableName() string { return "maintainers" }
+
+type v1StaffMember struct {
+	gorm.Model
+	Name          string
+	Email         string `gorm:"size:254;default:EMAIL_MISSING"`
+	GitHubAccount string `gorm:"size:100;default:GITHUB_MISSING"`
+	GitHubEmail   string `gorm:"size:254;default:GITHUB_EMAIL_MISSING"`
+	RegisteredAt  *time.Time
+	FoundationID  *uint `gorm:"index"`
+	Foundation    v1Foundation
+}
+
+func (v1StaffMember) TableName() string { return "staff_members" }
+
+type v1FoundationOfficer struct {
+	gorm.Model
+	Name          string
+	Email         string `gorm:"size:254;default:EMAIL_MISSING"`
+	GitHubAccount string `gorm:"size:100;default:GITHUB_MISSING"`
+	RegisteredAt  *time.Time
+	CompanyID     *uint
+}
+
+func (v1FoundationOfficer) TableName() string { return "foundation_officers" }
+
+type v1Collaborator struct {
+	gorm.Model
+	Name          string
+	Email         string  `gorm:"size:254;default:EMAIL_MISSING"`
+	GitHubEmail   *string `gorm:"size:254;default:GITHUB_EMAIL_MISSING"`
+	GitHubAccount *string `gorm:"size:100;default:GITHUB_MISSING"`
+	LastLogin     time.Time
+	RegisteredAt  time.Time
+}
+
+func (v1Collaborator) TableName() string { return "collaborators" }
+
+type v1MaintainerProject struct {
+	MaintainerID uint         `gorm:"primaryKey;index"`
+	ProjectID    uint         `gorm:"primaryKey;index"`
+	JoinedAt     time.Time    `gorm:"autoCreateTime"`
+	Maintainer   v1Maintainer `gorm:"foreignKey:MaintainerID;constraint:OnDelete:CASCADE"`
+	Project      v1Project    `gorm:"foreignKey:ProjectID;constraint:OnDelete:CASCADE"`
+}
+
+func (v1MaintainerProject) TableName() string { return "maintainer_projects" }
+
+type v1Service struct {
+	gorm.Model
+	Name        string `gorm:"uniqueIndex"`
+	Description string
+}
+
+func (v1Service) TableName() string { return "services" }
+
+type v1ServiceProject struct {
+	ProjectID uint      `gorm:"primaryKey"`
+	ServiceID uint      `gorm:"primaryKey"`
+	Project   v1Project `gorm:"foreignKey:ProjectID"`
+	Service   v1Service `gorm:"foreignKey:ServiceID"`
+}
+
+func (v1ServiceProject) TableName() string { return "service_projects" }
+
+type v1ServiceTeam struct {
+	gorm.Model
+	ProjectID       uint `gorm:"index"`
+	ServiceID       uint `gorm:"index"`
+	ServiceTeamID   int
+	ServiceTeamName *string
+	ProjectName     *string
+}
+
+func (v1ServiceTeam) TableName() string { return "service_teams" }
+
+type v1ServiceUser struct {
+	gorm.Model
+	ServiceID         uint   `gorm:"index"`
+	ServiceUserID     int    `gorm:"index"`
+	ServiceEmail      string `gorm:"size:254;default:EMAIL_MISSING"`
+	ServiceRef        string `gorm:"size:512"`
+	ServiceGitHubName *string
+}
+
+func (v1ServiceUser) TableName() string { return "service_users" }
+
+type v1FoundationOfficerServiceUser struct {
+	FoundationOfficerID uint                `gorm:"primaryKey"`
+	ServiceUserID       uint                `gorm:"primaryKey"`
+	FoundationOfficer   v1FoundationOfficer `gorm:"foreignKey:FoundationOfficerID;constraint:OnDelete:CASCADE"`
+	ServiceUser         v1ServiceUser       `gorm:"foreignKey:ServiceUserID;constraint:OnDelete:CASCADE"`
+}
+
+func (v1FoundationOfficerServiceUser) TableName() string { return "foundation_officer_service_users" }
+
+type v1ServiceUserTeams struct {
+	gorm.Model
+	ServiceID      uint          `gorm:"index"`
+	ServiceUserID  int           `gorm:"index"`
+	ServiceTeamID  uint          `gorm:"index"`
+	ServiceTeam    v1ServiceTeam `gorm:"foreignKey:ServiceTeamID;constraint:OnDelete:CASCADE"`
+	MaintainerID   *uint         `gorm:"index"`
+	CollaboratorID *uint         `gorm:"index"`
+}
+
+func (v1ServiceUserTeams) TableName() string { return "service_user_teams" }
+
+type v1AuditLog struct {
+	gorm.Model
+	ProjectID    uint   `gorm:"index"`
+	MaintainerID *uint  `gorm:"index"`
+	ServiceID    *uint  `gorm:"index"`
+	Action       string `gorm:"index"`
+	Message      string
+	Metadata     string
+}
+
+func (v1AuditLog) TableName() string { return "audit_logs" }
+
+// v1Tables are the tables of migration 1 in dependency order.
+func v1Tables() []interface{} {
+	return []interface{}{
+		&v1Company{},
+		&v1Foundation{},
+		&v1Project{},
+		&v1Maintainer{},
+		&v1StaffMember{},
+		&v1FoundationOfficer{},
+		&v1Collaborator{},
+		&v1MaintainerProject{},
+		&v1Service{},
+		&v1ServiceProject{},
+		&v1ServiceTeam{},
+		&v1ServiceUser{},
+		&v1FoundationOfficerServiceUser{},
+		&v1ServiceUserTeams{},
+		&v1AuditLog{},
+	}
+}
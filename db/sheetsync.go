@@ -0,0 +1,628 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"maintainerd/model"
+)
+
+// A ProjectRecord is a project as the maintainer sheet lists it.
+type ProjectRecord struct {
+	Name     string
+	Maturity model.Maturity
+	// Parent names the parent project of a subproject, which takes the parent's maturity.
+	Parent        string
+	MaintainerRef string
+	MailingList   string
+	// Ref is the first row that names the project.
+	Ref RowRef
+}
+
+// A MaintainerRecord is one maintainer row of the sheet: a person and their membership of Project.
+type MaintainerRecord struct {
+	Project     string
+	Name        string
+	Company     string
+	Email       string
+	GitHub      string
+	GitHubEmail string
+	// Roles is empty when the row does not say, which leaves the roles of an existing membership alone.
+	Roles model.MembershipRoles
+	Ref   RowRef
+}
+
+// subject names the person of r in reports, by GitHub login where the row has one; reports never include emails.
+func (r MaintainerRecord) subject() string {
+	if !model.IsMissing(r.GitHub) {
+		return "@" + r.GitHub
+	}
+	return r.Name
+}
+
+// ParseActiveRows turns the rows of the Active worksheet, as rowMaps returns them, into records. A project's
+// fields are taken from the first of its rows that has them. Rows without a project, and rows naming a person without
+// an email, GitHub login or GitHub email, who cannot be matched to a maintainer, are skipped; the projects of the
+// latter are Incomplete.
+func ParseActiveRows(rows []SheetRow) Records {
+	var records Records
+	projects := map[string]int{}
+	for _, sheetRow := range rows {
+		row := sheetRow.Fields
+		projectName := row[FieldProject]
+		if projectName == "" {
+			subject := row[FieldName]
+			if !model.IsMissing(row[FieldGitHub]) {
+				subject = "@" + row[FieldGitHub]
+			}
+			records.skip(sheetRow.Ref, subject, fmt.Sprintf("no %s", FieldProject))
+			continue
+		}
+		i, ok := projects[projectName]
+		if !ok {
+			i = len(records.Projects)
+			projects[projectName] = i
+			records.Projects = append(records.Projects, ProjectRecord{Name: projectName, Ref: sheetRow.Ref})
+		}
+		p := &records.Projects[i]
+		fill := func(field *string, value string) {
+			if *field == "" {
+				*field = value
+			}
+		}
+		if p.Maturity == "" {
+			p.Maturity = model.Maturity(row[FieldStatus])
+		}
+		fill(&p.Parent, row[FieldParentProject])
+		fill(&p.MaintainerRef, row[FieldMaintainerRef])
+		fill(&p.MailingList, row[FieldMailingList])
+
+		r := MaintainerRecord{
+			Project:     projectName,
+			Name:        row[FieldName],
+			Company:     row[FieldCompany],
+			Email:       row[FieldEmail],
+			GitHub:      row[FieldGitHub],
+			GitHubEmail: row[FieldGitHubEmail],
+			Ref:         sheetRow.Ref,
+		}
+		// Some sheets include rows that only exist to carry project metadata.
+		if model.IsMissing(r.Email) && model.IsMissing(r.GitHub) && model.IsMissing(r.GitHubEmail) {
+			if r.Name != "" || r.Company != "" {
+				records.skip(r.Ref, r.Name+" on project "+projectName, "no email, GitHub login or GitHub email to match a maintainer by")
+				if !slices.Contains(records.Incomplete, projectName) {
+					records.Incomplete = append(records.Incomplete, projectName)
+				}
+			}
+			continue
+		}
+		roles, err := model.ParseMembershipRoles(row[FieldRoles])
+		if err != nil {
+			log.Printf("ParseActiveRows: WRN, ignoring %s of %s on project %q: %v", FieldRoles, r.subject(), projectName, err)
+			roles = nil
+		}
+		r.Roles = roles
+		records.Maintainers = append(records.Maintainers, r)
+	}
+	return records
+}
+
+// A SyncAction says what a sheet sync did to a record.
+type SyncAction string
+
+const (
+	SyncCreate SyncAction = "create"
+	SyncUpdate SyncAction = "update"
+	SyncRemove SyncAction = "remove"
+)
+
+// A SyncChange is one change a sheet sync made, or with a dry run would make.
+type SyncChange struct {
+	Action SyncAction `json:"action"`
+	// Kind is project, company, maintainer or membership.
+	Kind    string `json:"kind"`
+	Subject string `json:"subject"`
+	Detail  string `json:"detail,omitempty"`
+}
+
+func (c SyncChange) String() string {
+	s := fmt.Sprintf("%s %s %s", c.Action, c.Kind, c.Subject)
+	if c.Detail != "" {
+		s += ": " + c.Detail
+	}
+	return s
+}
+
+// A SyncReport lists what SyncSheet changed, or with DryRun would change, and the records it could not apply. Rows
+// holds the outcome of each record, and of the rows the source skipped.
+type SyncReport struct {
+	DryRun  bool         `json:"dryRun"`
+	Changes []SyncChange `json:"changes"`
+	Skipped []string     `json:"skipped,omitempty"`
+	Rows    []ImportRow  `json:"rows,omitempty"`
+}
+
+// SyncSheet brings the database in line with the sheet records, in one transaction:
+//   - projects and maintainers the sheet adds are created, and the fields it changes are updated; empty cells leave a
+//     field alone,
+//   - a maintainer's details are taken from the first row that names them, later rows only add memberships,
+//   - memberships the sheet adds are started, and memberships of a listed project that the sheet no longer lists are
+//     ended. A project with rows that were skipped or could not be applied keeps its memberships, so a bad row, or a
+//     blanked cell, never removes anyone.
+//
+// Records that cannot be applied, e.g. a project with an invalid maturity, are skipped and reported. With dryRun the
+// changes are reported and rolled back. Otherwise a sync that changed anything is written to the audit log as
+// SHEET_SYNC, with the report in its metadata. Cancelling ctx stops the sync between rows and rolls it back.
+func SyncSheet(ctx context.Context, conn *gorm.DB, records Records, dryRun bool) (SyncReport, error) {
+	report := SyncReport{DryRun: dryRun}
+	for _, row := range records.Skipped {
+		report.Skipped = append(report.Skipped, fmt.Sprintf("%s: %s", row.Subject, row.Reason))
+		report.Rows = append(report.Rows, row)
+	}
+	err := conn.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		s := &sheetSync{
+			tx:         tx,
+			report:     &report,
+			now:        historyTime(time.Now()),
+			projects:   map[string]model.Project{},
+			listed:     map[uint]map[uint]bool{},
+			incomplete: map[uint]bool{},
+			updated:    map[uint]bool{},
+		}
+		if err := s.syncProjects(ctx, records.Projects); err != nil {
+			return err
+		}
+		for _, name := range records.Incomplete {
+			if p, ok := s.projects[name]; ok {
+				s.incomplete[p.ID] = true
+			}
+		}
+		for _, r := range records.Maintainers {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			s.syncMaintainer(r)
+		}
+		if err := s.removeUnlisted(); err != nil {
+			return err
+		}
+		if len(report.Changes) > 0 {
+			metadata, err := json.Marshal(report)
+			if err != nil {
+				return err
+			}
+			err = tx.Create(&model.AuditLog{
+				Action:   "SHEET_SYNC",
+				Message:  fmt.Sprintf("Synced the maintainer sheet: %d changes", len(report.Changes)),
+				Metadata: string(metadata),
+			}).Error
+			if err != nil {
+				return fmt.Errorf("write audit log: %w", err)
+			}
+		}
+		if dryRun {
+			return errDryRun
+		}
+		return nil
+	})
+	if errors.Is(err, errDryRun) {
+		err = nil
+	}
+	return report, err
+}
+
+// sheetSync holds the state of one SyncSheet transaction.
+type sheetSync struct {
+	tx     *gorm.DB
+	report *SyncReport
+	now    time.Time
+	// projects holds the live projects of the sheet by name.
+	projects map[string]model.Project
+	// listed holds the maintainers the sheet lists by project ID.
+	listed map[uint]map[uint]bool
+	// incomplete holds the projects with rows that could not be applied.
+	incomplete map[uint]bool
+	// updated holds the maintainers whose details have been synced.
+	updated map[uint]bool
+}
+
+func (s *sheetSync) change(action SyncAction, kind, subject, detail string) {
+	s.report.Changes = append(s.report.Changes, SyncChange{Action: action, Kind: kind, Subject: subject, Detail: detail})
+}
+
+func (s *sheetSync) skip(format string, args ...interface{}) {
+	s.report.Skipped = append(s.report.Skipped, fmt.Sprintf(format, args...))
+}
+
+// outcome reports what the sync did with the record at ref. Records that were skipped or failed are also listed in
+// the report's Skipped.
+func (s *sheetSync) outcome(ref RowRef, subject string, outcome ImportOutcome, reason string) {
+	s.report.Rows = append(s.report.Rows, ImportRow{RowRef: ref, Subject: subject, Outcome: outcome, Reason: reason})
+	if outcome == ImportSkipped || outcome == ImportFailed {
+		s.skip("%s: %s", subject, reason)
+	}
+}
+
+// changeOutcome is the outcome of a record that made the changes of the report since the first: created if it
+// created a maintainer, project or membership, updated if it changed anything else.
+func (s *sheetSync) changeOutcome(first int) ImportOutcome {
+	outcome := ImportUnchanged
+	for _, c := range s.report.Changes[first:] {
+		if c.Action == SyncCreate && c.Kind != "company" {
+			return ImportCreated
+		}
+		outcome = ImportUpdated
+	}
+	return outcome
+}
+
+// syncProjects creates and updates the projects of records, parents before their subprojects.
+func (s *sheetSync) syncProjects(ctx context.Context, records []ProjectRecord) error {
+	records = slices.Clone(records)
+	sort.SliceStable(records, func(i, j int) bool { return records[i].Parent == "" && records[j].Parent != "" })
+	for _, r := range records {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if r.Parent != "" {
+			parent, err := s.project(r.Parent)
+			if errors.Is(err, ErrProjectNotFound) {
+				s.outcome(r.Ref, "project "+r.Name, ImportSkipped, fmt.Sprintf("parent project %s not found", r.Parent))
+				continue
+			} else if err != nil {
+				return err
+			}
+			r.Maturity = parent.Maturity
+		}
+		if !r.Maturity.IsValid() {
+			s.outcome(r.Ref, "project "+r.Name, ImportSkipped, fmt.Sprintf("maturity %q is not valid", r.Maturity))
+			continue
+		}
+		if err := s.syncProject(r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// project returns the live project name, from the sheet or the database.
+func (s *sheetSync) project(name string) (model.Project, error) {
+	if p, ok := s.projects[name]; ok {
+		return p, nil
+	}
+	var p model.Project
+	err := s.tx.Where("name = ?", name).First(&p).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return p, fmt.Errorf("%w: %s", ErrProjectNotFound, name)
+	}
+	return p, err
+}
+
+func (s *sheetSync) syncProject(r ProjectRecord) error {
+	var parentID *uint
+	if r.Parent != "" {
+		parent, err := s.project(r.Parent)
+		if err != nil {
+			return err
+		}
+		parentID = &parent.ID
+	}
+
+	changes := len(s.report.Changes)
+	var p model.Project
+	err := s.tx.Unscoped().Where("name = ?", r.Name).First(&p).Error
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		p = model.Project{Name: r.Name, Maturity: r.Maturity, MaintainerRef: r.MaintainerRef, ParentProjectID: parentID}
+		if r.MailingList != "" {
+			p.MailingList = &r.MailingList
+		}
+		if err := s.tx.Omit(clause.Associations).Create(&p).Error; err != nil {
+			return fmt.Errorf("create project %s: %w", r.Name, err)
+		}
+		s.change(SyncCreate, "project", p.Name, "")
+	case err != nil:
+		return fmt.Errorf("look up project %s: %w", r.Name, err)
+	case p.DeletedAt.Valid:
+		s.outcome(r.Ref, "project "+r.Name, ImportSkipped, "archived, its rows are ignored")
+		return nil
+	default:
+		updates := map[string]interface{}{}
+		var changed []string
+		if p.Maturity != r.Maturity {
+			updates["maturity"] = r.Maturity
+			changed = append(changed, fmt.Sprintf("maturity %s -> %s", p.Maturity, r.Maturity))
+		}
+		if r.MaintainerRef != "" && p.MaintainerRef != r.MaintainerRef {
+			updates["maintainer_ref"] = r.MaintainerRef
+			changed = append(changed, "maintainer file reference")
+		}
+		if r.MailingList != "" && (p.MailingList == nil || *p.MailingList != r.MailingList) {
+			updates["mailing_list"] = r.MailingList
+			changed = append(changed, "mailing list")
+		}
+		if parentID != nil && (p.ParentProjectID == nil || *p.ParentProjectID != *parentID) {
+			updates["parent_project_id"] = *parentID
+			changed = append(changed, "parent project "+r.Parent)
+		}
+		if len(updates) > 0 {
+			if err := s.tx.Model(&p).Updates(updates).Error; err != nil {
+				return fmt.Errorf("update project %s: %w", r.Name, err)
+			}
+			s.change(SyncUpdate, "project", p.Name, strings.Join(changed, ", "))
+		}
+	}
+	s.outcome(r.Ref, "project "+r.Name, s.changeOutcome(changes), "")
+	s.projects[p.Name] = p
+	return nil
+}
+
+// errMaintainerArchived is returned by applyMaintainer for a row that matches an archived maintainer.
+var errMaintainerArchived = errors.New("maintainer is archived")
+
+// syncMaintainer applies r in a nested transaction, so a row that fails is rolled back and reported on its own.
+func (s *sheetSync) syncMaintainer(r MaintainerRecord) {
+	subject := fmt.Sprintf("%s on project %s", r.subject(), r.Project)
+	project, ok := s.projects[r.Project]
+	if !ok {
+		// The project was skipped and reported; only its row is listed.
+		s.report.Rows = append(s.report.Rows, ImportRow{RowRef: r.Ref, Subject: subject, Outcome: ImportSkipped,
+			Reason: "project " + r.Project + " was not imported"})
+		return
+	}
+	changes := len(s.report.Changes)
+	var synced uint
+	err := s.tx.Transaction(func(tx *gorm.DB) error {
+		return s.applyMaintainer(tx, project, r, &synced)
+	})
+	if errors.Is(err, errMaintainerArchived) {
+		s.outcome(r.Ref, subject, ImportSkipped, "archived, its rows are ignored")
+		return
+	}
+	if err != nil {
+		s.report.Changes = s.report.Changes[:changes]
+		delete(s.updated, synced)
+		s.incomplete[project.ID] = true
+		s.outcome(r.Ref, subject, ImportFailed, err.Error())
+		return
+	}
+	s.outcome(r.Ref, subject, s.changeOutcome(changes), "")
+}
+
+// applyMaintainer finds or creates the maintainer of r and their membership of project. It sets synced to the
+// maintainer whose details it syncs.
+func (s *sheetSync) applyMaintainer(tx *gorm.DB, project model.Project, r MaintainerRecord, synced *uint) error {
+	var identities []model.MaintainerIdentity
+	for _, id := range []model.MaintainerIdentity{
+		{Kind: model.IdentityEmail, Value: r.Email},
+		{Kind: model.IdentityEmail, Value: r.GitHubEmail},
+		{Kind: model.IdentityGitHub, Value: r.GitHub},
+	} {
+		if !model.IsMissing(id.Value) {
+			identities = append(identities, id)
+		}
+	}
+	m, err := maintainerByAnyIdentity(tx.Preload("Company"), identities...)
+	if errors.Is(err, ErrMaintainerNotFound) {
+		// An archived maintainer still in the sheet stays archived, rather than being added again beside the
+		// archived row.
+		if _, archivedErr := maintainerByAnyIdentity(tx.Unscoped(), identities...); archivedErr == nil {
+			return errMaintainerArchived
+		} else if !errors.Is(archivedErr, ErrMaintainerNotFound) {
+			return archivedErr
+		}
+	}
+	switch {
+	case errors.Is(err, ErrMaintainerNotFound) && model.IsMissing(r.Email):
+		return errors.New("no maintainer matches the row, and it has no email to add one with")
+	case errors.Is(err, ErrMaintainerNotFound):
+		m = &model.Maintainer{
+			Name:             r.Name,
+			GitHubAccount:    r.GitHub,
+			GitHubEmail:      r.GitHubEmail,
+			Email:            r.Email,
+			MaintainerStatus: model.ActiveMaintainer,
+		}
+		if r.Company != "" {
+			company, err := s.company(tx, r.Company)
+			if err != nil {
+				return err
+			}
+			m.CompanyID = &company.ID
+		}
+		if err := tx.Omit(clause.Associations).Create(m).Error; err != nil {
+			return fmt.Errorf("create maintainer: %w", err)
+		}
+		if m.CompanyID != nil {
+			if err := openAffiliation(tx, m.ID, *m.CompanyID, s.now); err != nil {
+				return err
+			}
+		}
+		s.change(SyncCreate, "maintainer", r.subject(), "")
+		s.updated[m.ID], *synced = true, m.ID
+		for i := range identities {
+			identities[i].MaintainerID = m.ID
+		}
+		if err := recordIdentities(tx, append(m.DeclaredIdentities(), identities...)...); err != nil {
+			return err
+		}
+	case err != nil:
+		return err
+	case !s.updated[m.ID]:
+		s.updated[m.ID], *synced = true, m.ID
+		if err := s.updateMaintainer(tx, m, r); err != nil {
+			return err
+		}
+	}
+	return s.syncMembership(tx, m, project, r)
+}
+
+// updateMaintainer applies the details r gives for m. The row's email and GitHub login become m's primary ones; the
+// ones they replace are kept as secondary identities, so m is still found by them.
+func (s *sheetSync) updateMaintainer(tx *gorm.DB, m *model.Maintainer, r MaintainerRecord) error {
+	updates := map[string]interface{}{}
+	var changed []string
+	if r.Name != "" && r.Name != m.Name {
+		updates["name"] = r.Name
+		changed = append(changed, "name")
+	}
+	if r.Company != "" && (m.CompanyID == nil || model.CompanyKey(m.Company.Name) != model.CompanyKey(r.Company)) {
+		company, err := s.company(tx, r.Company)
+		if err != nil {
+			return err
+		}
+		if m.CompanyID == nil || *m.CompanyID != company.ID {
+			if err := setMaintainerCompany(tx, *m, &company.ID, "changed in the maintainer sheet", s.now); err != nil {
+				return err
+			}
+			changed = append(changed, fmt.Sprintf("company %q -> %q", m.Company.Name, company.Name))
+		}
+	}
+	if !model.IsMissing(r.GitHubEmail) && !strings.EqualFold(r.GitHubEmail, m.GitHubEmail) {
+		updates["git_hub_email"] = r.GitHubEmail
+		changed = append(changed, "GitHub email")
+	}
+	if len(updates) > 0 {
+		if err := tx.Model(&model.Maintainer{}).Where("id = ?", m.ID).Updates(updates).Error; err != nil {
+			return fmt.Errorf("update maintainer %d: %w", m.ID, err)
+		}
+	}
+
+	primary := func(kind model.IdentityKind, value, current, label string) error {
+		if model.IsMissing(value) || strings.EqualFold(value, current) {
+			return nil
+		}
+		_, err := recordIdentity(tx, model.MaintainerIdentity{MaintainerID: m.ID, Kind: kind, Value: value, IsPrimary: true})
+		if errors.Is(err, ErrIdentityTaken) {
+			// The error names the email; reports do not.
+			return fmt.Errorf("the row's %s belongs to another maintainer", label)
+		}
+		if err != nil {
+			return err
+		}
+		changed = append(changed, label)
+		return nil
+	}
+	if err := primary(model.IdentityEmail, r.Email, m.Email, "email"); err != nil {
+		return err
+	}
+	if err := primary(model.IdentityGitHub, r.GitHub, m.GitHubAccount, "GitHub login"); err != nil {
+		return err
+	}
+	if !model.IsMissing(r.GitHubEmail) {
+		id := model.MaintainerIdentity{MaintainerID: m.ID, Kind: model.IdentityEmail, Value: r.GitHubEmail}
+		if err := recordIdentities(tx, id); err != nil {
+			return err
+		}
+	}
+	if len(changed) > 0 {
+		s.change(SyncUpdate, "maintainer", r.subject(), strings.Join(changed, ", "))
+	}
+	return nil
+}
+
+// company returns the live company known by name or one of its aliases, creating it if needed.
+func (s *sheetSync) company(tx *gorm.DB, name string) (model.Company, error) {
+	company, created, err := resolveCompany(tx, name)
+	if err != nil {
+		return company, err
+	}
+	if created {
+		s.change(SyncCreate, "company", company.Name, "")
+	}
+	return company, nil
+}
+
+// syncMembership starts m's membership of project, or updates its roles. Memberships archived with the project or
+// maintainer are left to be restored with them.
+func (s *sheetSync) syncMembership(tx *gorm.DB, m *model.Maintainer, project model.Project, r MaintainerRecord) error {
+	subject := fmt.Sprintf("%s on %s", r.subject(), project.Name)
+	var mp model.MaintainerProject
+	err := tx.Unscoped().Where("maintainer_id = ? AND project_id = ?", m.ID, project.ID).First(&mp).Error
+	switch {
+	case err == nil && mp.DeletedAt.Valid:
+	case err == nil:
+		if len(r.Roles) > 0 && !slices.Equal(mp.Roles, r.Roles) {
+			if err := changeRoles(tx, mp, r.Roles, s.now); err != nil {
+				return err
+			}
+			s.change(SyncUpdate, "membership", subject, fmt.Sprintf("roles %s -> %s",
+				strings.Join(mp.Roles.Strings(), ","), strings.Join(r.Roles.Strings(), ",")))
+		}
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		roles, err := membershipRoles(r.Roles)
+		if err != nil {
+			return err
+		}
+		mp = model.MaintainerProject{MaintainerID: m.ID, ProjectID: project.ID, JoinedAt: s.now, Roles: roles}
+		if err := tx.Omit(clause.Associations).Create(&mp).Error; err != nil {
+			return fmt.Errorf("add maintainer %d to project %d: %w", m.ID, project.ID, err)
+		}
+		for _, role := range roles {
+			if err := openPeriod(tx, m.ID, project.ID, role, s.now); err != nil {
+				return err
+			}
+		}
+		s.change(SyncCreate, "membership", subject, "roles "+strings.Join(roles.Strings(), ","))
+	default:
+		return fmt.Errorf("look up membership of maintainer %d on project %d: %w", m.ID, project.ID, err)
+	}
+	if s.listed[project.ID] == nil {
+		s.listed[project.ID] = map[uint]bool{}
+	}
+	s.listed[project.ID][m.ID] = true
+	return nil
+}
+
+// removeUnlisted ends the memberships of the sheet's projects that the sheet no longer lists.
+func (s *sheetSync) removeUnlisted() error {
+	names := make([]string, 0, len(s.projects))
+	for name := range s.projects {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		project := s.projects[name]
+		var memberships []model.MaintainerProject
+		if err := s.tx.Preload("Maintainer").Where("project_id = ?", project.ID).Order("maintainer_id").Find(&memberships).Error; err != nil {
+			return fmt.Errorf("list memberships of project %s: %w", name, err)
+		}
+		var unlisted []model.MaintainerProject
+		for _, mp := range memberships {
+			if !s.listed[project.ID][mp.MaintainerID] {
+				unlisted = append(unlisted, mp)
+			}
+		}
+		switch {
+		case len(unlisted) == 0:
+			continue
+		case s.incomplete[project.ID]:
+			s.skip("project %s: %d memberships not removed, some of its rows could not be applied", name, len(unlisted))
+			continue
+		case len(s.listed[project.ID]) == 0:
+			s.skip("project %s: no maintainer rows, its %d memberships are left alone", name, len(unlisted))
+			continue
+		}
+		for _, mp := range unlisted {
+			if err := endMembership(s.tx, mp.MaintainerID, project.ID, "removed from the maintainer sheet", s.now); err != nil {
+				return err
+			}
+			subject := mp.Maintainer.Name
+			if !model.IsMissing(mp.Maintainer.GitHubAccount) {
+				subject = "@" + mp.Maintainer.GitHubAccount
+			}
+			s.change(SyncRemove, "membership", fmt.Sprintf("%s on %s", subject, name), "")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,233 @@
+package db
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"maintainerd/model"
+)
+
+func TestParseActiveRows(t *testing.T) {
+	row := func(n int, fields map[SheetField]string) SheetRow {
+		return SheetRow{Ref: RowRef{Origin: "Active", Row: n}, Fields: fields}
+	}
+	records := ParseActiveRows([]SheetRow{
+		row(2, map[SheetField]string{FieldProject: "argo", FieldStatus: "Graduated", FieldMailingList: "argo@lists.cncf.io"}),
+		row(3, map[SheetField]string{FieldProject: "argo", FieldStatus: "Graduated", FieldName: "Alice", FieldEmail: "alice@example.com", FieldGitHub: "alice", FieldRoles: "lead"}),
+		row(4, map[SheetField]string{FieldProject: "argo", FieldName: "No Contact"}),
+		row(5, map[SheetField]string{FieldProject: "argo-cd", FieldParentProject: "argo", FieldGitHub: "bob", FieldRoles: "chief"}),
+		row(6, map[SheetField]string{FieldName: "Orphan", FieldEmail: "orphan@example.com"}),
+	})
+	assert.Equal(t, []ProjectRecord{
+		{Name: "argo", Maturity: model.Graduated, MailingList: "argo@lists.cncf.io", Ref: RowRef{Origin: "Active", Row: 2}},
+		{Name: "argo-cd", Parent: "argo", Ref: RowRef{Origin: "Active", Row: 5}},
+	}, records.Projects)
+	assert.Equal(t, []MaintainerRecord{
+		{Project: "argo", Name: "Alice", Email: "alice@example.com", GitHub: "alice", Roles: model.MembershipRoles{model.RoleLead}, Ref: RowRef{Origin: "Active", Row: 3}},
+		{Project: "argo-cd", GitHub: "bob", Ref: RowRef{Origin: "Active", Row: 5}},
+	}, records.Maintainers, "rows without identifiers are skipped and invalid roles ignored")
+	assert.Equal(t, []ImportRow{
+		{RowRef: RowRef{Origin: "Active", Row: 4}, Subject: "No Contact on project argo", Outcome: ImportSkipped,
+			Reason: "no email, GitHub login or GitHub email to match a maintainer by"},
+		{RowRef: RowRef{Origin: "Active", Row: 6}, Subject: "Orphan", Outcome: ImportSkipped, Reason: "no project"},
+	}, records.Skipped)
+	assert.Equal(t, []string{"argo"}, records.Incomplete, "a project with a skipped person row is incomplete")
+}
+
+func TestSyncSheet(t *testing.T) {
+	conn := openEmptyDB(t)
+	require.NoError(t, Migrate(conn))
+	ctx := t.Context()
+
+	initial := Records{
+		Projects: []ProjectRecord{
+			{Name: "argo-cd", Parent: "argo"},
+			{Name: "argo", Maturity: model.Graduated},
+			{Name: "broken", Maturity: "Beta"},
+		},
+		Maintainers: []MaintainerRecord{
+			{Project: "argo", Name: "Alice", Company: "Acme", Email: "alice@example.com", GitHub: "alice", Roles: model.MembershipRoles{model.RoleLead}},
+			{Project: "argo", Name: "Bob", Email: "bob@example.com", GitHub: "bob"},
+			{Project: "argo-cd", Name: "Bob", Email: "bob@example.com", GitHub: "bob"},
+			{Project: "broken", Name: "Carol", Email: "carol@example.com", GitHub: "carol"},
+		},
+	}
+	report, err := SyncSheet(ctx, conn, initial, true)
+	require.NoError(t, err)
+	assert.True(t, report.DryRun)
+	assert.Len(t, report.Changes, 8)
+	var n int64
+	require.NoError(t, conn.Model(&model.Project{}).Count(&n).Error)
+	assert.Zero(t, n, "a dry run changes nothing")
+
+	report, err = SyncSheet(ctx, conn, initial, false)
+	require.NoError(t, err)
+	var changes []string
+	for _, c := range report.Changes {
+		changes = append(changes, c.String())
+	}
+	assert.Equal(t, []string{
+		"create project argo",
+		"create project argo-cd",
+		"create company Acme",
+		"create maintainer @alice",
+		"create membership @alice on argo: roles lead",
+		"create maintainer @bob",
+		"create membership @bob on argo: roles maintainer",
+		"create membership @bob on argo-cd: roles maintainer",
+	}, changes)
+	assert.Equal(t, []string{`project broken: maturity "Beta" is not valid`}, report.Skipped)
+	var argoCD model.Project
+	require.NoError(t, conn.Where("name = ?", "argo-cd").First(&argoCD).Error)
+	assert.Equal(t, model.Graduated, argoCD.Maturity, "subprojects take their parent's maturity")
+	assert.NotNil(t, argoCD.ParentProjectID)
+
+	outcomes := func(rows []ImportRow) []string {
+		var out []string
+		for _, row := range rows {
+			out = append(out, fmt.Sprintf("%s %s", row.Subject, row.Outcome))
+		}
+		return out
+	}
+	assert.Equal(t, []string{
+		"project argo created",
+		"project broken skipped",
+		"project argo-cd created",
+		"@alice on project argo created",
+		"@bob on project argo created",
+		"@bob on project argo-cd created",
+		"@carol on project broken skipped",
+	}, outcomes(report.Rows))
+
+	// A second run of the same sheet changes nothing.
+	report, err = SyncSheet(ctx, conn, initial, false)
+	require.NoError(t, err)
+	assert.Empty(t, report.Changes)
+	assert.Equal(t, "@alice on project argo unchanged", outcomes(report.Rows)[3])
+
+	// Alice renamed her GitHub account and moved company, Bob left argo and Dave joined it.
+	edited := Records{
+		Projects: []ProjectRecord{{Name: "argo", Maturity: model.Graduated, MailingList: "argo@lists.cncf.io"}, {Name: "argo-cd", Parent: "argo"}},
+		Maintainers: []MaintainerRecord{
+			{Project: "argo", Name: "Alice", Company: "Initech", Email: "alice@example.com", GitHub: "alice-new"},
+			{Project: "argo", Name: "Dave", Email: "dave@example.com", GitHub: "dave"},
+			{Project: "argo-cd", Name: "Bob", Email: "bob@example.com", GitHub: "bob"},
+		},
+	}
+	report, err = SyncSheet(ctx, conn, edited, false)
+	require.NoError(t, err)
+	changes = nil
+	for _, c := range report.Changes {
+		changes = append(changes, c.String())
+	}
+	assert.Equal(t, []string{
+		"update project argo: mailing list",
+		"create company Initech",
+		`update maintainer @alice-new: company "Acme" -> "Initech", GitHub login`,
+		"create maintainer @dave",
+		"create membership @dave on argo: roles maintainer",
+		"remove membership @bob on argo",
+	}, changes)
+	assert.Empty(t, report.Skipped)
+	assert.Equal(t, []string{
+		"project argo updated",
+		"project argo-cd unchanged",
+		"@alice-new on project argo updated",
+		"@dave on project argo created",
+		"@bob on project argo-cd unchanged",
+	}, outcomes(report.Rows))
+
+	store := NewSQLStore(conn)
+	alice, err := store.FindMaintainerByIdentity(ctx, model.MaintainerIdentity{Kind: model.IdentityGitHub, Value: "alice"})
+	require.NoError(t, err, "the old login is kept as an identity")
+	assert.Equal(t, "alice-new", alice.GitHubAccount)
+	assert.Equal(t, "Initech", alice.Company.Name)
+	memberships, err := store.GetProjectMemberships(ctx, *argoCD.ParentProjectID)
+	require.NoError(t, err)
+	assert.Len(t, memberships, 2)
+	for _, mp := range memberships {
+		if mp.MaintainerID == alice.ID {
+			assert.Equal(t, model.MembershipRoles{model.RoleLead}, mp.Roles, "a row without roles leaves them alone")
+		}
+	}
+	var audit []model.AuditLog
+	require.NoError(t, conn.Where("action = ?", "SHEET_SYNC").Find(&audit).Error)
+	assert.Len(t, audit, 2, "only syncs that change something are audit logged")
+
+	// A row that cannot be applied keeps the project's other memberships.
+	report, err = SyncSheet(ctx, conn, Records{
+		Projects:    []ProjectRecord{{Name: "argo", Maturity: model.Graduated}},
+		Maintainers: []MaintainerRecord{{Project: "argo", Name: "Erin", GitHub: "erin"}},
+	}, false)
+	require.NoError(t, err)
+	assert.Empty(t, report.Changes)
+	assert.Equal(t, []string{
+		"@erin on project argo: no maintainer matches the row, and it has no email to add one with",
+		"project argo: 2 memberships not removed, some of its rows could not be applied",
+	}, report.Skipped)
+	assert.Equal(t, []string{"project argo unchanged", "@erin on project argo failed"}, outcomes(report.Rows))
+	// Blanking the contact cells of Dave's row skips it, which keeps his membership rather than ending it.
+	report, err = SyncSheet(ctx, conn, ParseActiveRows([]SheetRow{
+		{Ref: RowRef{Origin: "Active", Row: 2}, Fields: map[SheetField]string{FieldProject: "argo", FieldStatus: "Graduated",
+			FieldName: "Alice", FieldEmail: "alice@example.com", FieldGitHub: "alice-new"}},
+		{Ref: RowRef{Origin: "Active", Row: 3}, Fields: map[SheetField]string{FieldProject: "argo", FieldName: "Dave"}},
+	}), false)
+	require.NoError(t, err)
+	assert.Empty(t, report.Changes)
+	assert.Equal(t, []string{
+		"Dave on project argo: no email, GitHub login or GitHub email to match a maintainer by",
+		"project argo: 1 memberships not removed, some of its rows could not be applied",
+	}, report.Skipped)
+	memberships, err = store.GetProjectMemberships(ctx, *argoCD.ParentProjectID)
+	require.NoError(t, err)
+	assert.Len(t, memberships, 2)
+
+	// An archived maintainer still in the sheet is skipped rather than added again.
+	dave, err := store.FindMaintainerByIdentity(ctx, model.MaintainerIdentity{Kind: model.IdentityGitHub, Value: "dave"})
+	require.NoError(t, err)
+	require.NoError(t, store.ArchiveMaintainer(ctx, dave.ID))
+	report, err = SyncSheet(ctx, conn, edited, false)
+	require.NoError(t, err)
+	assert.Empty(t, report.Changes)
+	assert.Equal(t, []string{"@dave on project argo: archived, its rows are ignored"}, report.Skipped)
+	assert.Equal(t, "@dave on project argo skipped", outcomes(report.Rows)[3])
+	require.NoError(t, conn.Unscoped().Model(&model.Maintainer{}).Where("git_hub_account = ?", "dave").Count(&n).Error)
+	assert.EqualValues(t, 1, n)
+}
+
+func TestSyncSheetKeepsArchivedMaintainerRestorable(t *testing.T) {
+	conn := openEmptyDB(t)
+	require.NoError(t, Migrate(conn))
+	ctx := t.Context()
+	store := NewSQLStore(conn)
+
+	records := Records{
+		Projects:    []ProjectRecord{{Name: "argo", Maturity: model.Graduated}},
+		Maintainers: []MaintainerRecord{{Project: "argo", Name: "Alice", Email: "alice@example.com", GitHub: "alice"}},
+	}
+	_, err := SyncSheet(ctx, conn, records, false)
+	require.NoError(t, err)
+	alice, err := store.FindMaintainerByIdentity(ctx, model.MaintainerIdentity{Kind: model.IdentityGitHub, Value: "alice"})
+	require.NoError(t, err)
+
+	require.NoError(t, store.ArchiveMaintainer(ctx, alice.ID))
+	_, err = SyncSheet(ctx, conn, records, false)
+	require.NoError(t, err)
+	require.NoError(t, store.RestoreMaintainer(ctx, alice.ID))
+
+	var n int64
+	require.NoError(t, conn.Unscoped().Model(&model.Maintainer{}).Count(&n).Error)
+	assert.EqualValues(t, 1, n, "the sync does not add a second Alice beside the archived one")
+	restored, err := store.FindMaintainerByIdentity(ctx, model.MaintainerIdentity{Kind: model.IdentityEmail, Value: "alice@example.com"})
+	require.NoError(t, err)
+	assert.Equal(t, alice.ID, restored.ID)
+	var project model.Project
+	require.NoError(t, conn.Where("name = ?", "argo").First(&project).Error)
+	memberships, err := store.GetProjectMemberships(ctx, project.ID)
+	require.NoError(t, err)
+	require.Len(t, memberships, 1)
+	assert.Equal(t, alice.ID, memberships[0].MaintainerID)
+}
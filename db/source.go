@@ -0,0 +1,84 @@
+package db
+
+import (
+	"context"
+
+	"maintainerd/model"
+)
+
+// A Source reads the records bootstrap loads: the Google Sheet, a CSV or XLSX export of it, or a directory of
+// maintainer.yaml files. Sources other than the Google Sheet need no credentials, so bootstrap runs offline and in
+// tests.
+type Source interface {
+	// Records returns the projects and their maintainers.
+	Records(ctx context.Context) (Records, error)
+	// Staff returns the foundation staff members.
+	Staff(ctx context.Context) ([]StaffRecord, error)
+	// String describes the source in logs.
+	String() string
+}
+
+// Records are the projects and maintainers a Source holds, and the rows it skipped reading them.
+type Records struct {
+	Projects    []ProjectRecord
+	Maintainers []MaintainerRecord
+	Skipped     []ImportRow
+	// Incomplete names the projects with maintainer rows that were skipped. A sync cannot tell who those rows
+	// listed, so it leaves the projects' memberships alone.
+	Incomplete []string
+}
+
+func (r *Records) skip(ref RowRef, subject, reason string) {
+	r.Skipped = append(r.Skipped, ImportRow{RowRef: ref, Subject: subject, Outcome: ImportSkipped, Reason: reason})
+}
+
+// A StaffRecord is a foundation staff member.
+type StaffRecord struct {
+	Foundation  string
+	Name        string
+	Email       string
+	GitHub      string
+	GitHubEmail string
+	Ref         RowRef
+}
+
+// subject names the staff member of r in reports, by GitHub login where the row has one.
+func (r StaffRecord) subject() string {
+	if !model.IsMissing(r.GitHub) {
+		return "@" + r.GitHub
+	}
+	return r.Name
+}
+
+// missing returns the fields r lacks to be loaded: its foundation and email.
+func (r StaffRecord) missing() []SheetField {
+	var missing []SheetField
+	if r.Foundation == "" {
+		missing = append(missing, FieldFoundation)
+	}
+	if r.Email == "" {
+		missing = append(missing, FieldEmail)
+	}
+	return missing
+}
+
+// ParseStaffRows turns the rows of the Staff worksheet, as rowMaps returns them, into records. Rows without a
+// foundation or email are kept, for the loader to report.
+func ParseStaffRows(rows []SheetRow) []StaffRecord {
+	var staff []StaffRecord
+	for _, row := range rows {
+		r := StaffRecord{
+			Foundation:  row.Fields[FieldFoundation],
+			Name:        row.Fields[FieldName],
+			Email:       row.Fields[FieldEmail],
+			GitHub:      row.Fields[FieldGitHub],
+			GitHubEmail: row.Fields[FieldGitHubEmail],
+		}
+		if r == (StaffRecord{}) {
+			continue // only cells the layout does not read
+		}
+		r.Ref = row.Ref
+		staff = append(staff, r)
+	}
+	return staff
+}
@@ -0,0 +1,107 @@
+package db
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+)
+
+// CSVSource reads a CSV export of the maintainer sheet: Active.csv and Staff.csv, one file per worksheet, in Dir.
+// Staff.csv is optional. Files are named after the worksheets of Layout, the default layout if nil.
+type CSVSource struct {
+	Dir    string
+	Layout *SheetLayout
+}
+
+func (s CSVSource) String() string { return "CSV export " + s.Dir }
+
+func (s CSVSource) Records(_ context.Context) (Records, error) {
+	layout := sheetLayout(s.Layout).Active
+	path := filepath.Join(s.Dir, layout.Name+".csv")
+	values, err := readCSV(path)
+	if err != nil {
+		return Records{}, err
+	}
+	rows, err := rowMaps(values, layout)
+	if err != nil {
+		return Records{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return ParseActiveRows(rows), nil
+}
+
+func (s CSVSource) Staff(_ context.Context) ([]StaffRecord, error) {
+	layout := sheetLayout(s.Layout).Staff
+	path := filepath.Join(s.Dir, layout.Name+".csv")
+	values, err := readCSV(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		log.Printf("CSVSource: INF, no %s, no staff loaded", path)
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	rows, err := rowMaps(values, layout)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return ParseStaffRows(rows), nil
+}
+
+func readCSV(path string) ([][]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close() //nolint:errcheck
+	r := csv.NewReader(f)
+	r.FieldsPerRecord = -1 // exports drop trailing empty cells
+	values, err := r.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", path, err)
+	}
+	return values, nil
+}
+
+// XLSXSource reads an XLSX export of the maintainer sheet, a workbook with the Active and, optionally, Staff
+// worksheets of Layout, the default layout if nil.
+type XLSXSource struct {
+	Path   string
+	Layout *SheetLayout
+}
+
+func (s XLSXSource) String() string { return "XLSX export " + s.Path }
+
+func (s XLSXSource) Records(_ context.Context) (Records, error) {
+	layout := sheetLayout(s.Layout).Active
+	values, err := readXLSXSheet(s.Path, layout.Name)
+	if err != nil {
+		return Records{}, err
+	}
+	rows, err := rowMaps(values, layout)
+	if err != nil {
+		return Records{}, fmt.Errorf("%s: %w", s.Path, err)
+	}
+	return ParseActiveRows(rows), nil
+}
+
+func (s XLSXSource) Staff(_ context.Context) ([]StaffRecord, error) {
+	layout := sheetLayout(s.Layout).Staff
+	values, err := readXLSXSheet(s.Path, layout.Name)
+	if errors.Is(err, errNoWorksheet) {
+		log.Printf("XLSXSource: INF, %s has no %s worksheet, no staff loaded", s.Path, layout.Name)
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	rows, err := rowMaps(values, layout)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", s.Path, err)
+	}
+	return ParseStaffRows(rows), nil
+}
@@ -0,0 +1,72 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/api/option"
+	"google.golang.org/api/sheets/v4"
+)
+
+// SheetsSource reads the maintainer sheet from Google Sheets with a service account allowed to read it. Layout
+// locates the worksheets and their columns, the default layout if nil.
+type SheetsSource struct {
+	SpreadsheetID   string
+	CredentialsPath string
+	Layout          *SheetLayout
+}
+
+func (s SheetsSource) String() string { return "Google Sheet " + s.SpreadsheetID }
+
+func (s SheetsSource) Records(ctx context.Context) (Records, error) {
+	rows, err := s.read(ctx, sheetLayout(s.Layout).Active)
+	if err != nil {
+		return Records{}, err
+	}
+	return ParseActiveRows(rows), nil
+}
+
+func (s SheetsSource) Staff(ctx context.Context) ([]StaffRecord, error) {
+	rows, err := s.read(ctx, sheetLayout(s.Layout).Staff)
+	if err != nil {
+		return nil, err
+	}
+	return ParseStaffRows(rows), nil
+}
+
+func (s SheetsSource) read(ctx context.Context, layout WorksheetLayout) ([]SheetRow, error) {
+	srv, err := sheets.NewService(
+		ctx,
+		option.WithCredentialsFile(s.CredentialsPath),
+		option.WithScopes(sheets.SpreadsheetsReadonlyScope),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve Sheets client: %w", err)
+	}
+	return readSheetRows(ctx, srv, s.SpreadsheetID, layout)
+}
+
+// readSheetRows returns the rows of the worksheet of layout as rowMaps does, failing before it reads any row if a
+// required column is missing.
+func readSheetRows(ctx context.Context, srv *sheets.Service, spreadsheetID string, layout WorksheetLayout) ([]SheetRow, error) {
+	readRange := layout.Name + "!" + layout.Range
+	resp, err := srv.Spreadsheets.Values.
+		Get(spreadsheetID, readRange).
+		Context(ctx).
+		Do()
+	if err != nil {
+		return nil, fmt.Errorf("db: Using %s unable to retrieve worksheet data (%s): %w", spreadsheetID, readRange, err)
+	}
+	values := make([][]string, len(resp.Values))
+	for i, r := range resp.Values {
+		values[i] = make([]string, len(r))
+		for j, cell := range r {
+			values[i][j] = fmt.Sprint(cell)
+		}
+	}
+	rows, err := rowMaps(values, layout)
+	if err != nil {
+		return nil, fmt.Errorf("db: %s %s: %w", spreadsheetID, readRange, err)
+	}
+	return rows, nil
+}
@@ -0,0 +1,285 @@
+package db
+
+import (
+	"archive/zip"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"maintainerd/model"
+)
+
+// The Active and Staff worksheets every file source test reads, as exported.
+var (
+	activeExport = [][]string{
+		{ProjectHdr, StatusHdr, MaintainerNameHdr, CompanyNameHdr, EmailHdr, GitHubHdr, RolesHdr},
+		{"argo", "Graduated", "Alice", "Acme", "alice@example.com", "alice", "lead"},
+		{"", "", "Bob", "", "bob@example.com", "bob"},
+	}
+	staffExport = [][]string{
+		{FoundationHdr, StaffMemberNameHdr, EmailHdr, GitHubHdr},
+		{"CNCF", "Staff", "staff@cncf.io", "staffer"},
+	}
+	wantRecords = Records{
+		Projects: []ProjectRecord{{Name: "argo", Maturity: model.Graduated}},
+		Maintainers: []MaintainerRecord{
+			{Project: "argo", Name: "Alice", Company: "Acme", Email: "alice@example.com", GitHub: "alice", Roles: model.MembershipRoles{model.RoleLead}},
+			{Project: "argo", Name: "Bob", Email: "bob@example.com", GitHub: "bob"},
+		},
+	}
+	wantStaff = []StaffRecord{{Foundation: "CNCF", Name: "Staff", Email: "staff@cncf.io", GitHub: "staffer"}}
+)
+
+// withoutRefs returns records and staff without the row references each source sets its own way.
+func withoutRefs(records Records, staff []StaffRecord) (Records, []StaffRecord) {
+	for i := range records.Projects {
+		records.Projects[i].Ref = RowRef{}
+	}
+	for i := range records.Maintainers {
+		records.Maintainers[i].Ref = RowRef{}
+	}
+	for i := range staff {
+		staff[i].Ref = RowRef{}
+	}
+	return records, staff
+}
+
+func TestCSVSource(t *testing.T) {
+	dir := t.TempDir()
+	writeCSV := func(name string, values [][]string) {
+		var b strings.Builder
+		for _, row := range values {
+			b.WriteString(strings.Join(row, ",") + "\n")
+		}
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(b.String()), 0o600))
+	}
+	writeCSV("Active.csv", activeExport)
+	source := CSVSource{Dir: dir}
+
+	staff, err := source.Staff(t.Context())
+	require.NoError(t, err)
+	assert.Empty(t, staff, "Staff.csv is optional")
+
+	writeCSV("Staff.csv", staffExport)
+	records, err := source.Records(t.Context())
+	require.NoError(t, err)
+	staff, err = source.Staff(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, RowRef{Origin: "Active", Row: 3}, records.Maintainers[1].Ref)
+	assert.Equal(t, RowRef{Origin: "Staff", Row: 2}, staff[0].Ref)
+	records, staff = withoutRefs(records, staff)
+	assert.Equal(t, wantRecords, records)
+	assert.Equal(t, wantStaff, staff)
+}
+
+// writeZip writes a zip archive of files, by name, to path.
+func writeZip(t *testing.T, path string, files map[string]string) {
+	t.Helper()
+	f, err := os.Create(path)
+	require.NoError(t, err)
+	defer f.Close() //nolint:errcheck
+	zw := zip.NewWriter(f)
+	for name, content := range files {
+		w, err := zw.Create(name)
+		require.NoError(t, err)
+		_, err = w.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+}
+
+// writeXLSX writes a minimal workbook with a worksheet of each of sheets, using shared strings for the first
+// worksheet and inline strings for the others. Like Excel, it leaves empty rows and cells out.
+func writeXLSX(t *testing.T, path string, names []string, sheets [][][]string) {
+	t.Helper()
+	files := map[string]string{}
+	write := func(name, content string) { files[name] = content }
+
+	var workbook, rels, shared strings.Builder
+	workbook.WriteString(`<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets>`)
+	rels.WriteString(`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`)
+	var strs []string
+	for i, name := range names {
+		fmt.Fprintf(&workbook, `<sheet name="%s" sheetId="%d" r:id="rId%d"/>`, name, i+1, i+1)
+		fmt.Fprintf(&rels, `<Relationship Id="rId%d" Target="worksheets/sheet%d.xml"/>`, i+1, i+1)
+		var sheet strings.Builder
+		sheet.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+		for r, row := range sheets[i] {
+			if strings.Join(row, "") == "" {
+				continue
+			}
+			fmt.Fprintf(&sheet, `<row r="%d">`, r+1)
+			for c, v := range row {
+				if v == "" {
+					continue // exports leave empty cells out
+				}
+				ref := fmt.Sprintf("%c%d", 'A'+c, r+1)
+				if i == 0 {
+					fmt.Fprintf(&sheet, `<c r="%s" t="s"><v>%d</v></c>`, ref, len(strs))
+					strs = append(strs, v)
+				} else {
+					fmt.Fprintf(&sheet, `<c r="%s" t="inlineStr"><is><t>%s</t></is></c>`, ref, v)
+				}
+			}
+			sheet.WriteString(`</row>`)
+		}
+		sheet.WriteString(`</sheetData></worksheet>`)
+		write(fmt.Sprintf("xl/worksheets/sheet%d.xml", i+1), sheet.String())
+	}
+	workbook.WriteString(`</sheets></workbook>`)
+	rels.WriteString(`</Relationships>`)
+	shared.WriteString(`<sst xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">`)
+	for _, s := range strs {
+		fmt.Fprintf(&shared, `<si><t>%s</t></si>`, s)
+	}
+	shared.WriteString(`</sst>`)
+	write("xl/workbook.xml", workbook.String())
+	write("xl/_rels/workbook.xml.rels", rels.String())
+	write("xl/sharedStrings.xml", shared.String())
+	writeZip(t, path, files)
+}
+
+func TestXLSXSource(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "maintainers.xlsx")
+	writeXLSX(t, path, []string{"Active", "Staff"}, [][][]string{activeExport, staffExport})
+	source := XLSXSource{Path: path}
+
+	records, err := source.Records(t.Context())
+	require.NoError(t, err)
+	staff, err := source.Staff(t.Context())
+	require.NoError(t, err)
+	records, staff = withoutRefs(records, staff)
+	assert.Equal(t, wantRecords, records)
+	assert.Equal(t, wantStaff, staff)
+
+	writeXLSX(t, path, []string{"Active"}, [][][]string{activeExport})
+	staff, err = source.Staff(t.Context())
+	require.NoError(t, err)
+	assert.Empty(t, staff, "the Staff worksheet is optional")
+	_, err = XLSXSource{Path: path + ".missing"}.Records(t.Context())
+	assert.Error(t, err)
+
+	// A blank row is left out of the file; the rows after it keep their row numbers.
+	withBlank := append(append([][]string{}, activeExport[:2]...), []string{}, activeExport[2])
+	writeXLSX(t, path, []string{"Active"}, [][][]string{withBlank})
+	records, err = source.Records(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, RowRef{Origin: "Active", Row: 4}, records.Maintainers[1].Ref)
+	records, _ = withoutRefs(records, nil)
+	assert.Equal(t, wantRecords, records)
+}
+
+func TestReadXLSXSheet(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sparse.xlsx")
+	write := func(sheetData string) {
+		writeZip(t, path, map[string]string{
+			"xl/workbook.xml": `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" ` +
+				`xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+				`<sheets><sheet name="Active" sheetId="1" r:id="rId1"/></sheets></workbook>`,
+			"xl/_rels/workbook.xml.rels": `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+				`<Relationship Id="rId1" Target="worksheets/sheet1.xml"/></Relationships>`,
+			"xl/worksheets/sheet1.xml": `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+				`<sheetData>` + sheetData + `</sheetData></worksheet>`,
+		})
+	}
+	cell := func(ref, v string) string {
+		if ref != "" {
+			ref = fmt.Sprintf(` r="%s"`, ref)
+		}
+		return fmt.Sprintf(`<c%s t="inlineStr"><is><t>%s</t></is></c>`, ref, v)
+	}
+
+	write(`<row r="1">` + cell("A1", "a") + cell("B1", "b") + cell("C1", "c") + `</row>` +
+		// Row 2 is blank and left out; row 3 has no B3.
+		`<row r="3">` + cell("A3", "a3") + cell("C3", "c3") + `</row>` +
+		// Rows and cells without references follow the ones before them.
+		`<row>` + cell("B4", "b4") + cell("", "c4") + `</row>`)
+	values, err := readXLSXSheet(path, "Active")
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"a", "b", "c"},
+		nil,
+		{"a3", "", "c3"},
+		{"", "b4", "c4"},
+	}, values)
+
+	write(`<row r="2">` + cell("A2", "a") + `</row><row r="1">` + cell("A1", "b") + `</row>`)
+	_, err = readXLSXSheet(path, "Active")
+	assert.ErrorContains(t, err, "row 1 is out of order")
+}
+
+func TestYAMLSource(t *testing.T) {
+	dir := t.TempDir()
+	write := func(path, content string) {
+		path = filepath.Join(dir, path)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o700))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	}
+	write("argo/maintainer.yaml", `
+project: argo
+maturity: Graduated
+maintainers:
+  - name: Alice
+    company: Acme
+    email: alice@example.com
+    github: alice
+    roles: [lead]
+  - name: Bob
+    email: bob@example.com
+    github: bob
+`)
+	write("broken/maintainer.yaml", "project: broken\nunknownField: true\n")
+	write("notes.yaml", "not: read")
+	write("staff.yaml", `
+staff:
+  - foundation: CNCF
+    name: Staff
+    email: staff@cncf.io
+    github: staffer
+`)
+	source := YAMLSource{Dir: dir}
+
+	records, err := source.Records(t.Context())
+	require.NoError(t, err)
+	staff, err := source.Staff(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, RowRef{Origin: filepath.Join("argo", MaintainerFileName)}, records.Maintainers[0].Ref)
+	require.Len(t, records.Skipped, 1, "files that do not parse are skipped")
+	assert.Equal(t, filepath.Join("broken", MaintainerFileName), records.Skipped[0].Origin)
+	records.Skipped = nil
+	records, staff = withoutRefs(records, staff)
+	assert.Equal(t, wantRecords, records)
+	assert.Equal(t, wantStaff, staff)
+}
+
+func TestBootstrapFromCSVWithoutCredentials(t *testing.T) {
+	dir := t.TempDir()
+	var b strings.Builder
+	for _, row := range activeExport {
+		b.WriteString(strings.Join(row, ",") + "\n")
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "Active.csv"), []byte(b.String()), 0o600))
+
+	conn, report, err := Bootstrap(t.Context(), filepath.Join(dir, "maintainers.db"), CSVSource{Dir: dir}, "", true)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		if sqlDB, err := conn.DB(); err == nil {
+			_ = sqlDB.Close()
+		}
+	})
+	maintainers, err := NewSQLStore(conn).GetMaintainersByProject(t.Context(), 1)
+	require.NoError(t, err)
+	assert.Len(t, maintainers, 2)
+
+	require.NotNil(t, report)
+	assert.Equal(t, "3 created, 0 updated, 0 unchanged, 0 skipped, 0 failed", report.Summary())
+	stored, err := LoadImportReport(t.Context(), conn, "")
+	require.NoError(t, err)
+	assert.Equal(t, report.RunID, stored.RunID)
+	assert.Equal(t, report.Rows, stored.Rows)
+}
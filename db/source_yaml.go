@@ -0,0 +1,156 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+
+	"maintainerd/model"
+)
+
+// MaintainerFileName is the name of the per-project files YAMLSource reads.
+const MaintainerFileName = "maintainer.yaml"
+
+// MaintainerFile is the content of a maintainer.yaml file: one project and its maintainers.
+type MaintainerFile struct {
+	Project       string         `json:"project"`
+	Maturity      model.Maturity `json:"maturity,omitempty"`
+	Parent        string         `json:"parent,omitempty"`
+	MaintainerRef string         `json:"maintainerRef,omitempty"`
+	MailingList   string         `json:"mailingList,omitempty"`
+	Maintainers   []struct {
+		Name        string   `json:"name"`
+		Company     string   `json:"company,omitempty"`
+		Email       string   `json:"email,omitempty"`
+		GitHub      string   `json:"github,omitempty"`
+		GitHubEmail string   `json:"githubEmail,omitempty"`
+		Roles       []string `json:"roles,omitempty"`
+	} `json:"maintainers"`
+}
+
+// StaffFile is the content of the staff.yaml file of a YAMLSource.
+type StaffFile struct {
+	Staff []struct {
+		Foundation  string `json:"foundation"`
+		Name        string `json:"name"`
+		Email       string `json:"email"`
+		GitHub      string `json:"github,omitempty"`
+		GitHubEmail string `json:"githubEmail,omitempty"`
+	} `json:"staff"`
+}
+
+// YAMLSource reads a directory tree of maintainer.yaml files, one per project, and an optional staff.yaml at its
+// root. Files that cannot be read are skipped and reported, which leaves their project alone. Records refer to their
+// file by its path under Dir.
+type YAMLSource struct {
+	Dir string
+}
+
+func (s YAMLSource) String() string { return "maintainer.yaml files in " + s.Dir }
+
+func (s YAMLSource) Records(ctx context.Context) (Records, error) {
+	var records Records
+	err := filepath.WalkDir(s.Dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if d.IsDir() || d.Name() != MaintainerFileName {
+			return nil
+		}
+		ref := RowRef{Origin: s.rel(path)}
+		var f MaintainerFile
+		if err := readYAML(path, &f); err != nil {
+			records.skip(ref, ref.Origin, err.Error())
+			return nil
+		}
+		if strings.TrimSpace(f.Project) == "" {
+			records.skip(ref, ref.Origin, "no project")
+			return nil
+		}
+		records.Projects = append(records.Projects, ProjectRecord{
+			Name:          strings.TrimSpace(f.Project),
+			Maturity:      f.Maturity,
+			Parent:        strings.TrimSpace(f.Parent),
+			MaintainerRef: f.MaintainerRef,
+			MailingList:   f.MailingList,
+			Ref:           ref,
+		})
+		for _, m := range f.Maintainers {
+			r := MaintainerRecord{
+				Project:     strings.TrimSpace(f.Project),
+				Name:        m.Name,
+				Company:     m.Company,
+				Email:       m.Email,
+				GitHub:      m.GitHub,
+				GitHubEmail: m.GitHubEmail,
+				Ref:         ref,
+			}
+			if model.IsMissing(r.Email) && model.IsMissing(r.GitHub) && model.IsMissing(r.GitHubEmail) {
+				records.skip(ref, r.Name+" on project "+r.Project, "no email, GitHub login or GitHub email to match a maintainer by")
+				continue
+			}
+			roles, err := model.ParseMembershipRoles(strings.Join(m.Roles, ","))
+			if err != nil {
+				log.Printf("YAMLSource: WRN, %s: ignoring roles of %s: %v", path, r.subject(), err)
+			}
+			r.Roles = roles
+			records.Maintainers = append(records.Maintainers, r)
+		}
+		return nil
+	})
+	return records, err
+}
+
+func (s YAMLSource) Staff(_ context.Context) ([]StaffRecord, error) {
+	path := filepath.Join(s.Dir, "staff.yaml")
+	var f StaffFile
+	err := readYAML(path, &f)
+	if errors.Is(err, fs.ErrNotExist) {
+		log.Printf("YAMLSource: INF, no %s, no staff loaded", path)
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var staff []StaffRecord
+	for _, sm := range f.Staff {
+		staff = append(staff, StaffRecord{
+			Foundation:  sm.Foundation,
+			Name:        sm.Name,
+			Email:       sm.Email,
+			GitHub:      sm.GitHub,
+			GitHubEmail: sm.GitHubEmail,
+			Ref:         RowRef{Origin: s.rel(path)},
+		})
+	}
+	return staff, nil
+}
+
+// rel returns path relative to s.Dir, or path if it is not under it.
+func (s YAMLSource) rel(path string) string {
+	if rel, err := filepath.Rel(s.Dir, path); err == nil {
+		return rel
+	}
+	return path
+}
+
+func readYAML(path string, v interface{}) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if err := yaml.UnmarshalStrict(data, v); err != nil {
+		return fmt.Errorf("parse %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,186 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+
+	"gorm.io/gorm"
+
+	"maintainerd/model"
+	"maintainerd/plugins/fossa"
+)
+
+// A Stage is one step of a bootstrap run.
+type Stage string
+
+const (
+	// StageSchema migrates the schema and adds the services maintainer-d knows about.
+	StageSchema Stage = "schema"
+	// StageSheet syncs projects and maintainers from a Source, see SyncSheet.
+	StageSheet Stage = "sheet"
+	// StageStaff creates and updates the staff members of a Source.
+	StageStaff Stage = "staff"
+	// StageFossa loads the users and teams of FOSSA, see LoadFossa.
+	StageFossa Stage = "fossa"
+)
+
+// AllStages are the stages of a full bootstrap, in the order they run.
+var AllStages = []Stage{StageSchema, StageSheet, StageStaff, StageFossa}
+
+// services are the services StageSchema adds.
+var services = []model.Service{
+	{Name: "FOSSA", Description: "Static code check we use to ensure 3rd Party License Policy"},
+	{Name: "Service Desk", Description: "Jira"},
+	{Name: "cncf.groups.io", Description: "Mailing list channels"},
+	{Name: "Snyk", Description: "Static code checker for 3rd Party License Policy monitoring and compliance"},
+}
+
+// StageOptions select the stages RunStages runs and what they need: the sheet and staff stages read Source, the fossa
+// stage reads Fossa.
+type StageOptions struct {
+	Stages []Stage
+	Source Source
+	Fossa  *fossa.Client
+	// FossaResume skips the FOSSA users an earlier failed run loaded, see LoadFossa.
+	FossaResume bool
+	// FossaBestEffort logs a fossa stage that fails rather than returning its error, so FOSSA never holds up the
+	// stages before it.
+	FossaBestEffort bool
+	// DryRun reports what the stages would do without writing: pending migrations are listed rather than applied,
+	// and the other stages run in a transaction that is rolled back. Their import report is not stored.
+	DryRun bool
+}
+
+// A StageRun is what RunStages did, or with DryRun would do. The fields of stages that did not run are nil.
+type StageRun struct {
+	// Migrations are the migrations the schema stage applied, or with DryRun would apply.
+	Migrations []Migration
+	Sheet      *SyncReport
+	// Import holds the rows of the sheet and staff stages.
+	Import *ImportReport
+	Fossa  *FossaReport
+}
+
+// RunStages runs the selected stages against conn in the order of AllStages. The stages after schema need the schema
+// at LatestVersion, so with DryRun they only run against a database that is already migrated.
+func RunStages(ctx context.Context, conn *gorm.DB, opts StageOptions) (StageRun, error) {
+	var run StageRun
+	for _, s := range opts.Stages {
+		if !slices.Contains(AllStages, s) {
+			return run, fmt.Errorf("unknown bootstrap stage %q", s)
+		}
+	}
+	has := func(s Stage) bool { return slices.Contains(opts.Stages, s) }
+	if (has(StageSheet) || has(StageStaff)) && opts.Source == nil {
+		return run, errors.New("the sheet and staff stages need a source")
+	}
+	if has(StageFossa) && opts.Fossa == nil {
+		return run, errors.New("the fossa stage needs a FOSSA client")
+	}
+	conn = conn.WithContext(ctx)
+
+	if has(StageSchema) {
+		var err error
+		if run.Migrations, err = migrateSchema(conn, opts.DryRun); err != nil {
+			return run, err
+		}
+	}
+	if !has(StageSheet) && !has(StageStaff) && !has(StageFossa) {
+		return run, nil
+	}
+	if err := CheckSchemaVersion(conn); err != nil {
+		return run, err
+	}
+	if !opts.DryRun {
+		return run, runDataStages(ctx, conn, opts, &run)
+	}
+	err := conn.Transaction(func(tx *gorm.DB) error {
+		if err := runDataStages(ctx, tx, opts, &run); err != nil {
+			return err
+		}
+		return errDryRun
+	})
+	if errors.Is(err, errDryRun) {
+		err = nil
+	}
+	if run.Sheet != nil {
+		run.Sheet.DryRun = true
+	}
+	return run, err
+}
+
+// migrateSchema applies the pending migrations and adds the services, or with dryRun returns the pending migrations.
+func migrateSchema(conn *gorm.DB, dryRun bool) ([]Migration, error) {
+	if dryRun {
+		states, err := MigrationStatus(conn)
+		if err != nil {
+			return nil, err
+		}
+		var pending []Migration
+		for _, s := range states {
+			if !s.Applied {
+				pending = append(pending, Migration{Version: s.Version, Name: s.Name})
+			}
+		}
+		return pending, nil
+	}
+	ran, err := MigrateTo(conn, LatestVersion())
+	if err != nil {
+		return ran, err
+	}
+	return ran, conn.Transaction(func(tx *gorm.DB) error {
+		for _, service := range services {
+			if err := tx.FirstOrCreate(&service, model.Service{Name: service.Name}).Error; err != nil {
+				return fmt.Errorf("bootstrap: failed to insert service %s: %w", service.Name, err)
+			}
+		}
+		return nil
+	})
+}
+
+// runDataStages runs the sheet, staff and fossa stages of opts against db.
+func runDataStages(ctx context.Context, db *gorm.DB, opts StageOptions, run *StageRun) error {
+	has := func(s Stage) bool { return slices.Contains(opts.Stages, s) }
+	if has(StageSheet) || has(StageStaff) {
+		report := NewImportReport(opts.Source.String())
+		if has(StageSheet) {
+			sync, err := loadMaintainersAndProjects(ctx, db, opts.Source)
+			if err != nil {
+				return fmt.Errorf("bootstrap: failed to load maintainers and projects: %w", err)
+			}
+			run.Sheet = &sync
+			report.Add(sync.Rows...)
+		}
+		if has(StageStaff) {
+			rows, err := loadStaff(ctx, db, opts.Source)
+			if err != nil {
+				return fmt.Errorf("bootstrap: failed to load staff: %w", err)
+			}
+			report.Add(rows...)
+		}
+		report.Finish()
+		run.Import = report
+		if !opts.DryRun {
+			if err := SaveImportReport(ctx, db, *report); err != nil {
+				return fmt.Errorf("bootstrap: %w", err)
+			}
+		}
+		log.Printf("bootstrap: INF, import %s: %s", report.RunID, report.Summary())
+	}
+
+	if has(StageFossa) {
+		report, err := LoadFossa(ctx, db, opts.Fossa, opts.FossaResume)
+		run.Fossa = &report
+		if err != nil && !opts.FossaBestEffort {
+			return err
+		}
+		if err != nil {
+			// FOSSA problems do not undo the sheet import; the stage can be rerun with bootstrap fossa.
+			log.Printf("bootstrap: WRN, FOSSA teams and users not loaded, rerun with bootstrap fossa --resume: %v", err)
+		}
+	}
+	return nil
+}
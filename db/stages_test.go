@@ -0,0 +1,79 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"maintainerd/model"
+)
+
+// staticSource is a Source of fixed records.
+type staticSource struct {
+	records Records
+	staff   []StaffRecord
+}
+
+func (s staticSource) Records(context.Context) (Records, error)     { return s.records, nil }
+func (s staticSource) Staff(context.Context) ([]StaffRecord, error) { return s.staff, nil }
+func (s staticSource) String() string                               { return "static source" }
+
+func TestRunStages(t *testing.T) {
+	conn := openEmptyDB(t)
+	ctx := t.Context()
+	source := staticSource{
+		records: Records{
+			Projects:    []ProjectRecord{{Name: "argo", Maturity: model.Graduated}},
+			Maintainers: []MaintainerRecord{{Project: "argo", Name: "Alice", Email: "alice@example.com", GitHub: "alice"}},
+		},
+		staff: []StaffRecord{{Foundation: "CNCF", Name: "Staff", Email: "staff@cncf.io", GitHub: "staffer"}},
+	}
+
+	_, err := RunStages(ctx, conn, StageOptions{Stages: []Stage{StageSheet}})
+	assert.Error(t, err, "the sheet stage needs a source")
+	_, err = RunStages(ctx, conn, StageOptions{Stages: []Stage{StageSheet}, Source: source})
+	assert.ErrorIs(t, err, ErrSchemaVersionMismatch, "data stages need a migrated schema")
+
+	run, err := RunStages(ctx, conn, StageOptions{Stages: []Stage{StageSchema}, DryRun: true})
+	require.NoError(t, err)
+	assert.Len(t, run.Migrations, LatestVersion(), "a dry run lists every pending migration")
+	version, err := SchemaVersion(conn)
+	require.NoError(t, err)
+	assert.Zero(t, version, "and applies none")
+
+	run, err = RunStages(ctx, conn, StageOptions{Stages: []Stage{StageSchema}})
+	require.NoError(t, err)
+	assert.Len(t, run.Migrations, LatestVersion())
+	var services int64
+	require.NoError(t, conn.Model(&model.Service{}).Count(&services).Error)
+	assert.EqualValues(t, 4, services)
+
+	dataStages := []Stage{StageSheet, StageStaff}
+	run, err = RunStages(ctx, conn, StageOptions{Stages: dataStages, Source: source, DryRun: true})
+	require.NoError(t, err)
+	require.NotNil(t, run.Sheet)
+	assert.True(t, run.Sheet.DryRun)
+	assert.NotEmpty(t, run.Sheet.Changes)
+	require.NotNil(t, run.Import)
+	assert.Equal(t, "3 created, 0 updated, 0 unchanged, 0 skipped, 0 failed", run.Import.Summary())
+	assert.Nil(t, run.Fossa)
+	for _, m := range []interface{}{&model.Project{}, &model.Maintainer{}, &model.StaffMember{}, &model.ImportRun{}, &model.AuditLog{}} {
+		var n int64
+		require.NoError(t, conn.Model(m).Count(&n).Error)
+		assert.Zero(t, n, "a dry run writes no %T", m)
+	}
+
+	run, err = RunStages(ctx, conn, StageOptions{Stages: dataStages, Source: source})
+	require.NoError(t, err)
+	assert.False(t, run.Sheet.DryRun)
+	stored, err := LoadImportReport(ctx, conn, "")
+	require.NoError(t, err)
+	assert.Equal(t, run.Import.RunID, stored.RunID)
+
+	run, err = RunStages(ctx, conn, StageOptions{Stages: []Stage{StageStaff}, Source: source})
+	require.NoError(t, err)
+	assert.Nil(t, run.Sheet, "only the staff stage ran")
+	assert.Equal(t, "0 created, 0 updated, 1 unchanged, 0 skipped, 0 failed", run.Import.Summary())
+}
@@ -1,25 +1,150 @@
 package db
 
 import (
+	"context"
 	"errors"
+	"time"
 
 	"maintainerd/model"
 
 	"go.uber.org/zap"
 )
 
-var ErrProjectNotFound = errors.New("project not found")
+var (
+	ErrProjectNotFound      = errors.New("project not found")
+	ErrMaintainerNotFound   = errors.New("maintainer not found")
+	ErrCompanyNotFound      = errors.New("company not found")
+	ErrCompanyAliasTaken    = errors.New("company alias belongs to another company")
+	ErrAlreadyMember        = errors.New("maintainer is already a member of the project")
+	ErrNotMember            = errors.New("maintainer is not a member of the project")
+	ErrIdentityTaken        = errors.New("identity belongs to another maintainer")
+	ErrCollaboratorNotFound = errors.New("collaborator not found")
+	ErrStaffMemberNotFound  = errors.New("staff member not found")
+	ErrInvalidMerge         = errors.New("invalid merge")
+)
 
+// Store is every query the onboarding server, the CRD sync and the CLIs run against the maintainer-d database.
+// SQLStore implements it on SQLite and PostgreSQL; dbtest.MemStore is an in-memory fake for unit tests. Every method
+// stops work and returns the context's error once ctx is cancelled.
 type Store interface {
-	GetProjectsUsingService(serviceID uint) ([]model.Project, error)
-	GetProjectMapByName() (map[string]model.Project, error)
-	GetMaintainersByProject(projectID uint) ([]model.Maintainer, error)
-	GetProjectServiceTeamMap(serviceName string) (map[uint]*model.ServiceTeam, error)
-	GetMaintainerMapByEmail() (map[string]model.Maintainer, error)
-	GetServiceTeamByProject(projectID uint, serviceID uint) (*model.ServiceTeam, error)
-	LogAuditEvent(logger *zap.SugaredLogger, event model.AuditLog) error
-	GetMaintainerMapByGitHubAccount() (map[string]model.Maintainer, error)
-	CreateServiceTeamForUser(interface{ any }) (*model.ServiceTeam, error)
-	ListCompanies() ([]model.Company, error)
-	ListStaffMembers() ([]model.StaffMember, error)
+	// Ping verifies the store is reachable.
+	Ping(ctx context.Context) error
+
+	GetProjectsUsingService(ctx context.Context, serviceID uint) ([]model.Project, error)
+	// GetProjectMapByName returns every project, with its maintainers and their companies, keyed by name.
+	GetProjectMapByName(ctx context.Context) (map[string]model.Project, error)
+	// GetMaintainersByProject returns ErrProjectNotFound if there is no project with projectID.
+	GetMaintainersByProject(ctx context.Context, projectID uint) ([]model.Maintainer, error)
+	// ListMaintainers returns every maintainer, with their company and identities, ordered by ID.
+	ListMaintainers(ctx context.Context) ([]model.Maintainer, error)
+	// GetMaintainerMapByEmail returns every maintainer, with their company and identities, keyed by each of their
+	// email addresses in lower case.
+	GetMaintainerMapByEmail(ctx context.Context) (map[string]model.Maintainer, error)
+	// GetMaintainerMapByGitHubAccount returns every maintainer, with their company and identities, keyed by each of
+	// their GitHub logins in lower case.
+	GetMaintainerMapByGitHubAccount(ctx context.Context) (map[string]model.Maintainer, error)
+
+	// AddMaintainerIdentity records identity for the maintainer with identity.MaintainerID, or updates its verified
+	// and primary flags if it is already recorded. A primary email or GitHub identity also becomes the maintainer's
+	// Email or GitHubAccount. It returns ErrIdentityTaken if the identity belongs to another maintainer.
+	AddMaintainerIdentity(ctx context.Context, identity model.MaintainerIdentity) (model.MaintainerIdentity, error)
+	// ListMaintainerIdentities returns the maintainer's identities ordered by ID.
+	ListMaintainerIdentities(ctx context.Context, maintainerID uint) ([]model.MaintainerIdentity, error)
+	// FindMaintainerByIdentity returns the maintainer known by identity, ignoring its ID and flags, or
+	// ErrMaintainerNotFound.
+	FindMaintainerByIdentity(ctx context.Context, identity model.MaintainerIdentity) (*model.Maintainer, error)
+
+	GetProjectServiceTeamMap(ctx context.Context, serviceName string) (map[uint]*model.ServiceTeam, error)
+	// GetServiceTeamByProject returns nil, nil if the project has no team on the service.
+	GetServiceTeamByProject(ctx context.Context, projectID uint, serviceID uint) (*model.ServiceTeam, error)
+	// CreateServiceTeam records the team with ID serviceTeamID on a remote service for the project, or returns the
+	// existing record.
+	CreateServiceTeam(ctx context.Context, projectID uint, projectName string, serviceTeamID int, serviceTeamName string) (*model.ServiceTeam, error)
+	// GetServiceTeamRolePolicy returns nil, nil if the project uses the service's default role policy.
+	GetServiceTeamRolePolicy(ctx context.Context, projectID uint, serviceName string) (*model.ServiceTeamRolePolicy, error)
+	// SetServiceTeamRolePolicy stores policy as the project's overrides of the named service's default role policy,
+	// replacing any it had. A policy without roles removes the overrides.
+	SetServiceTeamRolePolicy(ctx context.Context, serviceName string, policy model.ServiceTeamRolePolicy) error
+
+	ListCompanies(ctx context.Context) ([]model.Company, error)
+	// FindCompany returns the live company one of whose aliases has the model.CompanyKey of name, so that "Google",
+	// "Google LLC" and "google" find the same company, or ErrCompanyNotFound.
+	FindCompany(ctx context.Context, name string) (*model.Company, error)
+	// AddCompanyAlias records alias as another spelling of the company's name. It returns ErrCompanyAliasTaken if
+	// the alias's key belongs to another company; merge the two companies instead.
+	AddCompanyAlias(ctx context.Context, companyID uint, alias string) error
+	// SetMaintainerCompany changes the maintainer's company, or removes it if companyID is nil, closing their open
+	// AffiliationPeriod at at with reason and opening one for the new company. Setting the current company again
+	// records nothing.
+	SetMaintainerCompany(ctx context.Context, maintainerID uint, companyID *uint, reason string, at time.Time) error
+	// MaintainerAffiliations returns every affiliation period of the maintainer, with its company, oldest first.
+	MaintainerAffiliations(ctx context.Context, maintainerID uint) ([]model.AffiliationPeriod, error)
+	ListStaffMembers(ctx context.Context) ([]model.StaffMember, error)
+	IsStaffEmail(ctx context.Context, email string) (bool, error)
+	// ListCollaborators returns every recorded collaborator.
+	ListCollaborators(ctx context.Context) ([]model.Collaborator, error)
+	IsStaffGitHubAccount(ctx context.Context, githubAccount string) (bool, error)
+	// IsStaffGitHubUser reports whether the GitHub user with githubID and login is a staff member. Staff linked to a
+	// GitHub user ID are matched on the ID alone; the others on their login, ignoring case.
+	IsStaffGitHubUser(ctx context.Context, githubID int64, login string) (bool, error)
+	// SetStaffGitHubID links the staff member to their numeric GitHub user ID, or returns ErrStaffMemberNotFound.
+	SetStaffGitHubID(ctx context.Context, staffID uint, githubID int64) error
+
+	// Archive* soft delete a record together with the rows that depend on it; Restore* bring them back. Archiving a
+	// record that is missing or already archived returns its not-found error, restoring a live one ErrNotArchived.
+	ArchiveMaintainer(ctx context.Context, id uint) error
+	RestoreMaintainer(ctx context.Context, id uint) error
+	ArchiveProject(ctx context.Context, id uint) error
+	RestoreProject(ctx context.Context, id uint) error
+	ArchiveCompany(ctx context.Context, id uint) error
+	RestoreCompany(ctx context.Context, id uint) error
+	ListArchivedMaintainers(ctx context.Context) ([]model.Maintainer, error)
+	ListArchivedProjects(ctx context.Context) ([]model.Project, error)
+	ListArchivedCompanies(ctx context.Context) ([]model.Company, error)
+
+	// StartMembership makes the maintainer a member of the project from at, opening a MembershipPeriod for each of
+	// roles, or for maintainer if roles is empty. It returns ErrAlreadyMember if they already are one.
+	StartMembership(ctx context.Context, maintainerID, projectID uint, roles model.MembershipRoles, at time.Time) error
+	// SetMembershipRoles replaces the roles the maintainer holds on the project, closing and opening membership
+	// periods at at for the roles dropped and added; an empty set means maintainer. It returns ErrNotMember if they
+	// are not a member.
+	SetMembershipRoles(ctx context.Context, maintainerID, projectID uint, roles model.MembershipRoles, at time.Time) error
+	// GetProjectMemberships returns the project's memberships, with their roles and maintainers, ordered by
+	// maintainer ID. It returns ErrProjectNotFound if there is no project with projectID.
+	GetProjectMemberships(ctx context.Context, projectID uint) ([]model.MaintainerProject, error)
+	// EndMembership removes the maintainer from the project and closes their open MembershipPeriod at at with reason.
+	// It returns ErrNotMember if they are not a member.
+	EndMembership(ctx context.Context, maintainerID, projectID uint, reason string, at time.Time) error
+	// MaintainersOnDate returns the maintainers, archived ones included, whose membership periods on the project
+	// cover on, ordered by ID.
+	MaintainersOnDate(ctx context.Context, projectID uint, on time.Time) ([]model.Maintainer, error)
+	// MaintainerTenure returns every membership period of the maintainer, with its project, oldest first.
+	MaintainerTenure(ctx context.Context, maintainerID uint) ([]model.MembershipPeriod, error)
+	// SetMaintainerStatus changes the maintainer's status, recording the change with reason at at. Setting the
+	// current status again records nothing.
+	SetMaintainerStatus(ctx context.Context, maintainerID uint, status model.MaintainerStatus, reason string, at time.Time) error
+	// MaintainerStatusHistory returns the maintainer's status changes, oldest first.
+	MaintainerStatusHistory(ctx context.Context, maintainerID uint) ([]model.MaintainerStatusChange, error)
+
+	// FindDuplicatePeople groups the live maintainers, collaborators and staff members that share an email or GitHub
+	// login, or whose names have the same words; see GroupDuplicates.
+	FindDuplicatePeople(ctx context.Context) ([]DuplicateGroup, error)
+	// MergePeople folds the duplicate maintainer or collaborator into the maintainer with ID survivorID: memberships,
+	// membership history, identities, service team links and audit log entries are repointed to the survivor, the
+	// duplicate's emails and logins become secondary identities of the survivor, the duplicate is deleted and the
+	// merge is audit logged. With dryRun nothing is written. It returns ErrInvalidMerge for staff members and for a
+	// maintainer merged into themselves.
+	MergePeople(ctx context.Context, survivorID uint, duplicate PersonRef, dryRun bool) (MergeReport, error)
+
+	// ReplaceFindings replaces the stored data quality findings with findings, and sets each maintainer's
+	// ImportWarnings to the messages of the findings about them, clearing it for the others.
+	ReplaceFindings(ctx context.Context, findings []model.Finding) error
+	// ListFindings returns the stored findings ordered by ID.
+	ListFindings(ctx context.Context) ([]model.Finding, error)
+
+	// LogAuditEvent records event. Failures are also logged to logger, so callers that cannot act on the error may
+	// ignore it.
+	LogAuditEvent(ctx context.Context, logger *zap.SugaredLogger, event model.AuditLog) error
 }
+
+var _ Store = (*SQLStore)(nil)
@@ -18,6 +18,9 @@ type SQLStore struct {
 }
 
 func NewSQLStore(db *gorm.DB) *SQLStore {
+	if err := SetupJoinTables(db); err != nil {
+		log.Printf("NewSQLStore: WRN, archived memberships may be returned: %v", err)
+	}
 	return &SQLStore{db: db}
 }
 
@@ -36,25 +39,25 @@ func (s *SQLStore) Ping(ctx context.Context) error {
 }
 
 // getServiceByName returns a &Service the service identified by name
-func (s *SQLStore) getServiceByName(name string) (*model.Service, error) {
+func (s *SQLStore) getServiceByName(ctx context.Context, name string) (*model.Service, error) {
 	var svc model.Service
-	err := s.db.Where("name = ?", name).First(&svc).Error
+	err := s.db.WithContext(ctx).Where("name = ?", name).First(&svc).Error
 	return &svc, err
 }
-func (s *SQLStore) GetProjectsUsingService(serviceID uint) ([]model.Project, error) {
+func (s *SQLStore) GetProjectsUsingService(ctx context.Context, serviceID uint) ([]model.Project, error) {
 	var projects []model.Project
-	err := s.db.
+	err := s.db.WithContext(ctx).
 		Joins("JOIN service_teams st ON st.project_id = projects.id").
 		Where("st.service_id = ?", serviceID).
-		Preload("Maintainers.Company").
+		Preload("Maintainers.Company").Preload("Maintainers.Identities").
 		Find(&projects).Error
 	return projects, err
 }
 
-func (s *SQLStore) GetMaintainersByProject(projectID uint) ([]model.Maintainer, error) {
+func (s *SQLStore) GetMaintainersByProject(ctx context.Context, projectID uint) ([]model.Maintainer, error) {
 	var project model.Project
-	err := s.db.
-		Preload("Maintainers.Company").
+	err := s.db.WithContext(ctx).
+		Preload("Maintainers.Company").Preload("Maintainers.Identities").
 		First(&project, projectID).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -66,9 +69,9 @@ func (s *SQLStore) GetMaintainersByProject(projectID uint) ([]model.Maintainer,
 
 }
 
-func (s *SQLStore) GetServiceTeamByProject(projectID, serviceID uint) (*model.ServiceTeam, error) {
+func (s *SQLStore) GetServiceTeamByProject(ctx context.Context, projectID, serviceID uint) (*model.ServiceTeam, error) {
 	var st model.ServiceTeam
-	err := s.db.
+	err := s.db.WithContext(ctx).
 		Where("project_id = ? AND service_id = ?", projectID, serviceID).
 		First(&st).Error
 	if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -77,44 +80,59 @@ func (s *SQLStore) GetServiceTeamByProject(projectID, serviceID uint) (*model.Se
 	return &st, err
 }
 
-// GetMaintainerMapByEmail returns a map of Maintainers keyed by email address
-func (s *SQLStore) GetMaintainerMapByEmail() (map[string]model.Maintainer, error) {
+// GetMaintainerMapByEmail returns a map of Maintainers keyed by each of their email addresses
+func (s *SQLStore) GetMaintainerMapByEmail(ctx context.Context) (map[string]model.Maintainer, error) {
 	var maintainers []model.Maintainer
-	err := s.db.Preload("Company").Find(&maintainers).Error
+	err := s.db.WithContext(ctx).Preload("Company").Preload("Identities").Order("id").Find(&maintainers).Error
 	if err != nil {
 		return nil, err
 	}
 	m := make(map[string]model.Maintainer)
 	for _, maintainer := range maintainers {
-		m[maintainer.Email] = maintainer
+		for _, email := range maintainer.Emails() {
+			if _, taken := m[email]; !taken {
+				m[email] = maintainer
+			}
+		}
 	}
 	return m, nil
 }
 
-// GetMaintainerMapByGitHubAccount returns a map of Maintainers keyed by GitHub Account
-func (s *SQLStore) GetMaintainerMapByGitHubAccount() (map[string]model.Maintainer, error) {
+// GetMaintainerMapByGitHubAccount returns a map of Maintainers keyed by each of their GitHub logins
+func (s *SQLStore) GetMaintainerMapByGitHubAccount(ctx context.Context) (map[string]model.Maintainer, error) {
 	var maintainers []model.Maintainer
-	err := s.db.Preload("Company").Find(&maintainers).Error
+	err := s.db.WithContext(ctx).Preload("Company").Preload("Identities").Order("id").Find(&maintainers).Error
 	if err != nil {
 		return nil, err
 	}
 	m := make(map[string]model.Maintainer)
 	for _, maintainer := range maintainers {
-		m[maintainer.GitHubAccount] = maintainer
+		for _, login := range maintainer.GitHubAccounts() {
+			if _, taken := m[login]; !taken {
+				m[login] = maintainer
+			}
+		}
 	}
 	return m, nil
 }
 
+// ListMaintainers returns every live maintainer, with their company and identities, ordered by ID.
+func (s *SQLStore) ListMaintainers(ctx context.Context) ([]model.Maintainer, error) {
+	var maintainers []model.Maintainer
+	err := s.db.WithContext(ctx).Preload("Company").Preload("Identities").Order("id").Find(&maintainers).Error
+	return maintainers, err
+}
+
 // GetProjectServiceTeamMap returns a map of projectID to ServiceTeams
 // for every Project that uses the service identified by serviceId
-func (s *SQLStore) GetProjectServiceTeamMap(serviceName string) (map[uint]*model.ServiceTeam, error) {
+func (s *SQLStore) GetProjectServiceTeamMap(ctx context.Context, serviceName string) (map[uint]*model.ServiceTeam, error) {
 	var serviceTeams []model.ServiceTeam
-	service, err := s.getServiceByName(serviceName)
+	service, err := s.getServiceByName(ctx, serviceName)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get service, %s, by name: %v", serviceName, err)
 	}
 	// Preload the many-to-many relationship
-	err = s.db.
+	err = s.db.WithContext(ctx).
 		Where("service_id = ? ", service.ID).
 		Find(&serviceTeams).Error
 	if err != nil {
@@ -131,11 +149,11 @@ func (s *SQLStore) GetProjectServiceTeamMap(serviceName string) (map[uint]*model
 	return result, nil
 
 }
-func (s *SQLStore) GetProjectMapByName() (map[string]model.Project, error) {
+func (s *SQLStore) GetProjectMapByName(ctx context.Context) (map[string]model.Project, error) {
 	var projects []model.Project
-	if err := s.db.
+	if err := s.db.WithContext(ctx).
 		Preload("Maintainers").
-		Preload("Maintainers.Company").
+		Preload("Maintainers.Company").Preload("Maintainers.Identities").
 		Find(&projects).Error; err != nil {
 		return nil, err
 	}
@@ -147,25 +165,26 @@ func (s *SQLStore) GetProjectMapByName() (map[string]model.Project, error) {
 	return projectsByName, nil
 }
 
-func (s *SQLStore) LogAuditEvent(logger *zap.SugaredLogger, event model.AuditLog) {
+// LogAuditEvent writes event to the audit log, defaulting its Message to its Action.
+func (s *SQLStore) LogAuditEvent(ctx context.Context, logger *zap.SugaredLogger, event model.AuditLog) error {
 	if event.Message == "" {
 		event.Message = event.Action
 	}
 
-	err := s.db.WithContext(context.Background()).Create(&event).Error
+	err := s.db.WithContext(ctx).Create(&event).Error
 	if err != nil {
 		logger.Errorf("failed to write %v audit log: %v", event, err)
+		return fmt.Errorf("write audit log: %w", err)
 	}
+	return nil
 }
 
 // CreateServiceTeam creates or retrieves a service team entry in the database based on the provided project and service details.
 // It accepts a project ID, project name, service ID, and service name as input and returns the service team or an error.
-func (s *SQLStore) CreateServiceTeam(
+func (s *SQLStore) CreateServiceTeam(ctx context.Context,
 	projectID uint, projectName string,
 	serviceID int, serviceName string) (*model.ServiceTeam, error) {
 
-	var errMessages []string
-
 	st := &model.ServiceTeam{
 		ServiceTeamID:   serviceID,
 		ServiceID:       1, // TODO : Hardcoded to FOSSA for now
@@ -173,42 +192,144 @@ func (s *SQLStore) CreateServiceTeam(
 		ProjectID:       projectID,
 		ProjectName:     &projectName,
 	}
-	err := s.db.Where("service_team_id = ?", serviceID).FirstOrCreate(st).Error
+	err := s.db.WithContext(ctx).Where("service_team_id = ?", serviceID).FirstOrCreate(st).Error
 	if err != nil {
-		msg := fmt.Sprintf("CreateServiceTeamsForUser: failed for team %d (%s): %v", serviceID, serviceName, err)
-		log.Println(msg)
-		return nil, fmt.Errorf("CreateServiceTeamsForUser had partial errors:\n%s", strings.Join(errMessages, "\n"))
+		log.Printf("CreateServiceTeam: ERR, failed for team %d (%s): %v", serviceID, serviceName, err)
+		return nil, fmt.Errorf("create service team %d (%s): %w", serviceID, serviceName, err)
 	}
 	return st, nil
 }
 
+// GetServiceTeamRolePolicy returns the role policy for the project's team on the named service, or nil if the
+// project uses the service's default policy.
+func (s *SQLStore) GetServiceTeamRolePolicy(ctx context.Context, projectID uint, serviceName string) (*model.ServiceTeamRolePolicy, error) {
+	service, err := s.getServiceByName(ctx, serviceName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get service, %s, by name: %v", serviceName, err)
+	}
+	var policy model.ServiceTeamRolePolicy
+	err = s.db.WithContext(ctx).
+		Where("project_id = ? AND service_id = ?", projectID, service.ID).
+		First(&policy).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &policy, err
+}
+
+// SetServiceTeamRolePolicy stores the role overrides of policy.ProjectID's team on the named service, or removes them
+// if policy has no roles.
+func (s *SQLStore) SetServiceTeamRolePolicy(ctx context.Context, serviceName string, policy model.ServiceTeamRolePolicy) error {
+	service, err := s.getServiceByName(ctx, serviceName)
+	if err != nil {
+		return fmt.Errorf("failed to get service, %s, by name: %v", serviceName, err)
+	}
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := exists(tx, &model.Project{}, policy.ProjectID, ErrProjectNotFound); err != nil {
+			return err
+		}
+		err := tx.Unscoped().
+			Where("project_id = ? AND service_id = ?", policy.ProjectID, service.ID).
+			Delete(&model.ServiceTeamRolePolicy{}).Error
+		if err != nil {
+			return fmt.Errorf("set %s role policy of project %d: %w", serviceName, policy.ProjectID, err)
+		}
+		if policy.MaintainerRole == 0 && policy.CollaboratorRole == 0 && policy.StaffRole == 0 {
+			return nil
+		}
+		policy.Model, policy.ServiceID = gorm.Model{}, service.ID
+		if err := tx.Create(&policy).Error; err != nil {
+			return fmt.Errorf("set %s role policy of project %d: %w", serviceName, policy.ProjectID, err)
+		}
+		return nil
+	})
+}
+
+// IsStaffEmail returns true if the email address belongs to a staff member.
+func (s *SQLStore) IsStaffEmail(ctx context.Context, email string) (bool, error) {
+	if email == "" {
+		return false, nil
+	}
+	var count int64
+	err := s.db.WithContext(ctx).
+		Model(&model.StaffMember{}).
+		Where("LOWER(email) = ? OR LOWER(git_hub_email) = ?", strings.ToLower(email), strings.ToLower(email)).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// ListCollaborators returns every recorded collaborator.
+func (s *SQLStore) ListCollaborators(ctx context.Context) ([]model.Collaborator, error) {
+	var collaborators []model.Collaborator
+	if err := s.db.WithContext(ctx).Order("id").Find(&collaborators).Error; err != nil {
+		return nil, err
+	}
+	return collaborators, nil
+}
+
 // ListCompanies returns all companies in the database.
-func (s *SQLStore) ListCompanies() ([]model.Company, error) {
+func (s *SQLStore) ListCompanies(ctx context.Context) ([]model.Company, error) {
 	var companies []model.Company
-	if err := s.db.Find(&companies).Error; err != nil {
+	if err := s.db.WithContext(ctx).Find(&companies).Error; err != nil {
 		return nil, err
 	}
 	return companies, nil
 }
 
 // ListStaffMembers returns all staff members in the database, including their foundations.
-func (s *SQLStore) ListStaffMembers() ([]model.StaffMember, error) {
+func (s *SQLStore) ListStaffMembers(ctx context.Context) ([]model.StaffMember, error) {
 	var staffMembers []model.StaffMember
-	if err := s.db.Preload("Foundation").Find(&staffMembers).Error; err != nil {
+	if err := s.db.WithContext(ctx).Preload("Foundation").Find(&staffMembers).Error; err != nil {
 		return nil, err
 	}
 	return staffMembers, nil
 }
 
 // IsStaffGitHubAccount returns true if the GitHub account belongs to a staff member.
-func (s *SQLStore) IsStaffGitHubAccount(githubAccount string) (bool, error) {
+func (s *SQLStore) IsStaffGitHubAccount(ctx context.Context, githubAccount string) (bool, error) {
 	if githubAccount == "" {
 		return false, nil
 	}
 	var count int64
-	err := s.db.
+	err := s.db.WithContext(ctx).
 		Model(&model.StaffMember{}).
 		Where("LOWER(git_hub_account) = ?", strings.ToLower(githubAccount)).
 		Count(&count).Error
 	return count > 0, err
 }
+
+// IsStaffGitHubUser returns true if the GitHub user belongs to a staff member, matching on the GitHub user ID for
+// staff linked to one and on the login otherwise.
+func (s *SQLStore) IsStaffGitHubUser(ctx context.Context, githubID int64, login string) (bool, error) {
+	var clauses []string
+	var args []any
+	if githubID != 0 {
+		clauses = append(clauses, "git_hub_id = ?")
+		args = append(args, githubID)
+	}
+	if login != "" {
+		clauses = append(clauses, "(git_hub_id IS NULL AND LOWER(git_hub_account) = ?)")
+		args = append(args, strings.ToLower(login))
+	}
+	if len(clauses) == 0 {
+		return false, nil
+	}
+	var count int64
+	err := s.db.WithContext(ctx).
+		Model(&model.StaffMember{}).
+		Where(strings.Join(clauses, " OR "), args...).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// SetStaffGitHubID records the staff member's GitHub user ID.
+func (s *SQLStore) SetStaffGitHubID(ctx context.Context, staffID uint, githubID int64) error {
+	res := s.db.WithContext(ctx).Model(&model.StaffMember{}).Where("id = ?", staffID).Update("git_hub_id", githubID)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return fmt.Errorf("%w: %d", ErrStaffMemberNotFound, staffID)
+	}
+	return nil
+}
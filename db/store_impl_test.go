@@ -22,6 +22,7 @@ func setupTestDB(t *testing.T) *gorm.DB {
 		&model.Company{},
 		&model.Project{},
 		&model.Maintainer{},
+		&model.MaintainerIdentity{},
 		&model.MaintainerProject{},
 		&model.Service{},
 		&model.ServiceTeam{},
@@ -88,7 +89,7 @@ func TestGetMaintainersByProject(t *testing.T) {
 	store := NewSQLStore(db)
 
 	t.Run("returns maintainers for project with multiple maintainers", func(t *testing.T) {
-		maintainers, err := store.GetMaintainersByProject(project1.ID)
+		maintainers, err := store.GetMaintainersByProject(t.Context(), project1.ID)
 		require.NoError(t, err)
 		require.Len(t, maintainers, 2)
 
@@ -106,7 +107,7 @@ func TestGetMaintainersByProject(t *testing.T) {
 	})
 
 	t.Run("returns different maintainers for different project", func(t *testing.T) {
-		maintainers, err := store.GetMaintainersByProject(project2.ID)
+		maintainers, err := store.GetMaintainersByProject(t.Context(), project2.ID)
 		require.NoError(t, err)
 		require.Len(t, maintainers, 2)
 
@@ -119,20 +120,20 @@ func TestGetMaintainersByProject(t *testing.T) {
 		emptyProject := model.Project{Name: "empty-project", Maturity: model.Sandbox}
 		require.NoError(t, db.Create(&emptyProject).Error)
 
-		maintainers, err := store.GetMaintainersByProject(emptyProject.ID)
+		maintainers, err := store.GetMaintainersByProject(t.Context(), emptyProject.ID)
 		require.NoError(t, err)
 		assert.Empty(t, maintainers)
 	})
 
 	t.Run("returns empty slice for non-existent project", func(t *testing.T) {
-		maintainers, err := store.GetMaintainersByProject(99999)
+		maintainers, err := store.GetMaintainersByProject(t.Context(), 99999)
 		require.Error(t, err)
 		assert.Equal(t, ErrProjectNotFound, err)
 		assert.Nil(t, maintainers)
 	})
 
 	t.Run("maintainers have correct fields populated", func(t *testing.T) {
-		maintainers, err := store.GetMaintainersByProject(project1.ID)
+		maintainers, err := store.GetMaintainersByProject(t.Context(), project1.ID)
 		require.NoError(t, err)
 		require.NotEmpty(t, maintainers)
 
@@ -0,0 +1,181 @@
+package db
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+)
+
+// errNoWorksheet is returned by readXLSXSheet when the workbook has no worksheet of that name.
+var errNoWorksheet = errors.New("no such worksheet")
+
+// The parts of SpreadsheetML readXLSXSheet reads. Cell values are read as the text the sheet stores; formulas are
+// not evaluated, their cached values are used.
+type (
+	xlsxWorkbook struct {
+		Sheets []struct {
+			Name string `xml:"name,attr"`
+			RID  string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+		} `xml:"sheets>sheet"`
+	}
+	xlsxRelationships struct {
+		Relationships []struct {
+			ID     string `xml:"Id,attr"`
+			Target string `xml:"Target,attr"`
+		} `xml:"Relationship"`
+	}
+	xlsxText struct {
+		T    string `xml:"t"`
+		Runs []struct {
+			T string `xml:"t"`
+		} `xml:"r"`
+	}
+	xlsxSharedStrings struct {
+		Items []xlsxText `xml:"si"`
+	}
+	xlsxWorksheet struct {
+		Rows []struct {
+			Ref   int `xml:"r,attr"`
+			Cells []struct {
+				Ref    string   `xml:"r,attr"`
+				Type   string   `xml:"t,attr"`
+				Value  string   `xml:"v"`
+				Inline xlsxText `xml:"is"`
+			} `xml:"c"`
+		} `xml:"sheetData>row"`
+	}
+)
+
+func (t xlsxText) String() string {
+	if len(t.Runs) == 0 {
+		return t.T
+	}
+	var b strings.Builder
+	for _, r := range t.Runs {
+		b.WriteString(r.T)
+	}
+	return b.String()
+}
+
+// readXLSXSheet returns the cell values of the worksheet called name in the workbook at filePath, row by row. Excel
+// leaves empty rows and cells out of the file, so rows and cells are placed by their references, with empty ones in
+// the gaps; one without a reference follows the one before it.
+func readXLSXSheet(filePath, name string) ([][]string, error) {
+	zr, err := zip.OpenReader(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("open %s: %w", filePath, err)
+	}
+	defer zr.Close() //nolint:errcheck
+	files := map[string]*zip.File{}
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+	decode := func(name string, v interface{}) error {
+		f, ok := files[name]
+		if !ok {
+			return fmt.Errorf("%s is missing", name)
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return err
+		}
+		defer rc.Close() //nolint:errcheck
+		if err := xml.NewDecoder(rc).Decode(v); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		return nil
+	}
+
+	var workbook xlsxWorkbook
+	if err := decode("xl/workbook.xml", &workbook); err != nil {
+		return nil, fmt.Errorf("read %s: %w", filePath, err)
+	}
+	var rels xlsxRelationships
+	if err := decode("xl/_rels/workbook.xml.rels", &rels); err != nil {
+		return nil, fmt.Errorf("read %s: %w", filePath, err)
+	}
+	var target string
+	for _, s := range workbook.Sheets {
+		if s.Name != name {
+			continue
+		}
+		for _, r := range rels.Relationships {
+			if r.ID == s.RID {
+				target = r.Target
+			}
+		}
+	}
+	if target == "" {
+		return nil, fmt.Errorf("%s: %w: %s", filePath, errNoWorksheet, name)
+	}
+	if strings.HasPrefix(target, "/") {
+		target = strings.TrimPrefix(target, "/")
+	} else {
+		target = path.Join("xl", target)
+	}
+
+	var shared xlsxSharedStrings
+	if _, ok := files["xl/sharedStrings.xml"]; ok {
+		if err := decode("xl/sharedStrings.xml", &shared); err != nil {
+			return nil, fmt.Errorf("read %s: %w", filePath, err)
+		}
+	}
+	var sheet xlsxWorksheet
+	if err := decode(target, &sheet); err != nil {
+		return nil, fmt.Errorf("read %s worksheet %s: %w", filePath, name, err)
+	}
+
+	values := make([][]string, 0, len(sheet.Rows))
+	for _, row := range sheet.Rows {
+		if row.Ref != 0 {
+			if row.Ref <= len(values) {
+				return nil, fmt.Errorf("read %s worksheet %s: row %d is out of order", filePath, name, row.Ref)
+			}
+			for len(values) < row.Ref-1 {
+				values = append(values, nil)
+			}
+		}
+		var cells []string
+		for _, c := range row.Cells {
+			col := len(cells)
+			if c.Ref != "" {
+				col = xlsxColumn(c.Ref)
+			}
+			if col < 0 {
+				return nil, fmt.Errorf("read %s worksheet %s: bad cell reference %q", filePath, name, c.Ref)
+			}
+			for len(cells) <= col {
+				cells = append(cells, "")
+			}
+			switch c.Type {
+			case "s":
+				var idx int
+				if _, err := fmt.Sscan(c.Value, &idx); err != nil || idx < 0 || idx >= len(shared.Items) {
+					return nil, fmt.Errorf("read %s worksheet %s: cell %s: bad shared string %q", filePath, name, c.Ref, c.Value)
+				}
+				cells[col] = shared.Items[idx].String()
+			case "inlineStr":
+				cells[col] = c.Inline.String()
+			default:
+				cells[col] = c.Value
+			}
+		}
+		values = append(values, cells)
+	}
+	return values, nil
+}
+
+// xlsxColumn returns the zero-based column of a cell reference such as "AB12".
+func xlsxColumn(ref string) int {
+	col := 0
+	for _, r := range ref {
+		if r < 'A' || r > 'Z' {
+			break
+		}
+		col = col*26 + int(r-'A'+1)
+	}
+	return col - 1
+}
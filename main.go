@@ -2,27 +2,33 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"log"
 	"os"
 	"strings"
 
+	"maintainerd/db"
 	"maintainerd/onboarding"
 )
 
 func main() {
 	// command‑line flags
 	var (
-		dbPath        = flag.String("db-path", "/data/onboarding.db", "Path to SQLite database file")
+		dbPath        = flag.String("db-path", "/data/onboarding.db", "SQLite database file or PostgreSQL DSN (overridden by $MD_DB_DSN)")
 		fossaEnvVar   = flag.String("fossa-token-env", "FOSSA_API_TOKEN", "Name of the env var holding the FOSSA API token")
 		webhookSecret = flag.String("webhook-secret", "", "GitHub webhook secret (raw string)")
 		addr          = flag.String("addr", "2525", "Address to listen on (e.g. :2525)")
+		internalAddr  = flag.String("internal-addr", "127.0.0.1:2526", "Address of the internal listener serving /findings, empty to disable it")
 		ghRep         = flag.String("repo", "sandbox", "Name of the repository (e.g. sandbox)")
 		ghOrg         = flag.String("org", "cncf", "Name of the GitHub org (e.g. cncf)")
 		ghToken       = flag.String("gh-api", "", "GitHub API token (raw string)")
 	)
 	flag.Parse()
 
+	if v := os.Getenv(db.DSNEnvVar); v != "" {
+		*dbPath = v
+	}
 	if *webhookSecret == "" {
 		*webhookSecret = os.Getenv("GITHUB_WEBHOOK_SECRET")
 	}
@@ -60,10 +66,18 @@ func main() {
 	listener := &onboarding.EventListener{
 		Secret: []byte(*webhookSecret),
 	}
-	if err := listener.Init(*dbPath, *fossaEnvVar, *ghToken, *ghOrg, *ghRep); err != nil {
+	if err := listener.Init(context.Background(), *dbPath, *fossaEnvVar, *ghToken, *ghOrg, *ghRep); err != nil {
 		log.Fatalf("maintainerd: ERR, failed to init EventListener: %v", err)
 	}
 
+	if *internalAddr != "" {
+		go func() {
+			log.Printf("maintainerd: DBG, Starting internal server on %s…", *internalAddr)
+			if err := listener.RunInternal(*internalAddr); err != nil {
+				log.Fatalf("maintainerd: ERR, internal server error: %v", err)
+			}
+		}()
+	}
 	log.Printf("maintainerd: DBG, Starting onboarding server on %s…", *addr)
 	if err := listener.Run(*addr); err != nil {
 		log.Fatalf("maintainerd: ERR, server error: %v", err)
@@ -4,7 +4,11 @@ import (
 	"database/sql/driver"
 	"fmt"
 	"net/url"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
+	"unicode"
 
 	"gorm.io/gorm"
 )
@@ -95,14 +99,138 @@ type Maintainer struct {
 	RegisteredAt     *time.Time
 	CompanyID        *uint
 	Company          Company
+	Identities       []MaintainerIdentity `gorm:"foreignKey:MaintainerID"`
 }
+
+// Emails returns the maintainer's email addresses, lower-cased and without duplicates: Email, GitHubEmail and any
+// email Identities that are loaded.
+func (m Maintainer) Emails() []string {
+	var emails []string
+	for _, id := range append(m.DeclaredIdentities(), m.Identities...) {
+		if id.Kind == IdentityEmail && !slices.Contains(emails, id.Normalize().Value) {
+			emails = append(emails, id.Normalize().Value)
+		}
+	}
+	return emails
+}
+
+// GitHubAccounts returns the maintainer's GitHub logins, lower-cased and without duplicates: GitHubAccount and any
+// GitHub Identities that are loaded.
+func (m Maintainer) GitHubAccounts() []string {
+	var logins []string
+	for _, id := range append(m.DeclaredIdentities(), m.Identities...) {
+		if id.Kind == IdentityGitHub && !slices.Contains(logins, id.Normalize().Value) {
+			logins = append(logins, id.Normalize().Value)
+		}
+	}
+	return logins
+}
+
+// GitHubID returns the maintainer's numeric GitHub user ID from their loaded Identities, and false if it has not been
+// resolved.
+func (m Maintainer) GitHubID() (int64, bool) {
+	for _, id := range m.Identities {
+		if id.Kind != IdentityGitHubID {
+			continue
+		}
+		if v, err := strconv.ParseInt(id.Value, 10, 64); err == nil && v > 0 {
+			return v, true
+		}
+	}
+	return 0, false
+}
+
+// DeclaredIdentities returns the identities held in the maintainer's own columns: Email and GitHubAccount as primary
+// identities and GitHubEmail as a further email. Empty and placeholder values are skipped.
+func (m Maintainer) DeclaredIdentities() []MaintainerIdentity {
+	var ids []MaintainerIdentity
+	add := func(kind IdentityKind, value string, primary bool) {
+		if IsMissing(value) {
+			return
+		}
+		ids = append(ids, MaintainerIdentity{MaintainerID: m.ID, Kind: kind, Value: value, IsPrimary: primary}.Normalize())
+	}
+	add(IdentityEmail, m.Email, true)
+	if !strings.EqualFold(m.GitHubEmail, m.Email) {
+		add(IdentityEmail, m.GitHubEmail, false)
+	}
+	add(IdentityGitHub, m.GitHubAccount, true)
+	return ids
+}
+
+// IsMissing returns true if value is empty or one of the placeholders the schema defaults missing emails and
+// GitHub logins to.
+func IsMissing(value string) bool {
+	switch strings.TrimSpace(value) {
+	case "", "EMAIL_MISSING", "GITHUB_MISSING", "GITHUB_EMAIL_MISSING":
+		return true
+	}
+	return false
+}
+
+// An IdentityKind says what a MaintainerIdentity's Value is.
+type IdentityKind string
+
+const (
+	IdentityEmail IdentityKind = "email"
+	// IdentityGitHub is a GitHub login.
+	IdentityGitHub IdentityKind = "github"
+	// IdentityGitHubID is a numeric GitHub user ID, which survives renames of the login.
+	IdentityGitHubID IdentityKind = "github-id"
+	// IdentityService is a handle on the Service named by MaintainerIdentity.Service, e.g. a FOSSA user ID.
+	IdentityService IdentityKind = "service"
+)
+
+// IsValid returns true if IdentityKind is known
+func (k IdentityKind) IsValid() bool {
+	switch k {
+	case IdentityEmail, IdentityGitHub, IdentityGitHubID, IdentityService:
+		return true
+	}
+	return false
+}
+
+// A MaintainerIdentity is one of the ways a Maintainer is known: an email address, a GitHub login or ID, or a handle
+// on a service. An identity belongs to one maintainer. Maintainer.Email and Maintainer.GitHubAccount hold the
+// maintainer's primary email and GitHub identities.
+type MaintainerIdentity struct {
+	ID           uint `gorm:"primaryKey"`
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	MaintainerID uint         `gorm:"index"`
+	Kind         IdentityKind `gorm:"size:20;uniqueIndex:idx_maintainer_identities_value"`
+	Service      string       `gorm:"size:50;uniqueIndex:idx_maintainer_identities_value"`
+	Value        string       `gorm:"size:254;uniqueIndex:idx_maintainer_identities_value"`
+	Verified     bool
+	IsPrimary    bool
+}
+
+// Normalize returns the identity with its Value in the form it is stored and looked up in: emails and GitHub logins
+// are matched case-insensitively, and a GitHub login may be given with a leading @.
+func (i MaintainerIdentity) Normalize() MaintainerIdentity {
+	i.Value = strings.TrimSpace(i.Value)
+	i.Service = strings.TrimSpace(i.Service)
+	switch i.Kind {
+	case IdentityEmail:
+		i.Value = strings.ToLower(i.Value)
+	case IdentityGitHub:
+		i.Value = strings.ToLower(strings.TrimPrefix(i.Value, "@"))
+	}
+	if i.Kind != IdentityService {
+		i.Service = ""
+	}
+	return i
+}
+
+// A Collaborator is a person who works on a Project without being one of its Maintainers. Their projects are kept
+// in collaborator_projects, apart from the maintainers' maintainer_projects.
 type Collaborator struct {
 	gorm.Model
 	Name          string
 	Email         string    `gorm:"size:254;default:EMAIL_MISSING"`
 	GitHubEmail   *string   `gorm:"size:254;default:GITHUB_EMAIL_MISSING"`
 	GitHubAccount *string   `gorm:"size:100;default:GITHUB_MISSING"`
-	Projects      []Project `gorm:"many2many:maintainer_projects;joinForeignKey:MaintainerID;joinReferences:ProjectID"`
+	Projects      []Project `gorm:"many2many:collaborator_projects;joinForeignKey:CollaboratorID;joinReferences:ProjectID"`
 	LastLogin     time.Time
 	RegisteredAt  time.Time
 }
@@ -118,19 +246,260 @@ type Project struct {
 	Services        []Service    `gorm:"many2many:service_projects;joinForeignKey:ProjectID;joinReferences:ServiceID"`
 }
 
+// MaintainerProject is the maintainer_projects join table. Its rows are soft deleted together with the Maintainer
+// or Project they belong to, so that restoring either brings the membership back. Roles defaults to maintainer.
 type MaintainerProject struct {
-	MaintainerID uint       `gorm:"primaryKey;index"` // FK + index
-	ProjectID    uint       `gorm:"primaryKey;index"` // FK + index
-	JoinedAt     time.Time  `gorm:"autoCreateTime"`
+	MaintainerID uint            `gorm:"primaryKey;index"` // FK + index
+	ProjectID    uint            `gorm:"primaryKey;index"` // FK + index
+	JoinedAt     time.Time       `gorm:"autoCreateTime"`
+	Roles        MembershipRoles `gorm:"type:text;default:'maintainer'"`
+	DeletedAt    gorm.DeletedAt  `gorm:"index"`
+	Maintainer   Maintainer      `gorm:"foreignKey:MaintainerID;constraint:OnDelete:CASCADE"`
+	Project      Project         `gorm:"foreignKey:ProjectID;constraint:OnDelete:CASCADE"`
+}
+
+// A MembershipRole is a part a Maintainer plays on a Project.
+type MembershipRole string
+
+const (
+	RoleMaintainer      MembershipRole = "maintainer"
+	RoleLead            MembershipRole = "lead"
+	RoleReviewer        MembershipRole = "reviewer"
+	RoleSecurityContact MembershipRole = "security-contact"
+)
+
+// membershipRoles lists the known roles in the order MembershipRoles keeps them.
+var membershipRoles = []MembershipRole{RoleLead, RoleMaintainer, RoleReviewer, RoleSecurityContact}
+
+// IsValid returns true if MembershipRole is known
+func (r MembershipRole) IsValid() bool {
+	for _, known := range membershipRoles {
+		if r == known {
+			return true
+		}
+	}
+	return false
+}
+
+// MembershipRoles is the set of roles a Maintainer holds on a Project. It is stored as a comma separated list.
+type MembershipRoles []MembershipRole
+
+// ParseMembershipRoles parses a comma separated list of roles, such as "lead, security-contact", ignoring case,
+// blanks and duplicates.
+func ParseMembershipRoles(s string) (MembershipRoles, error) {
+	var roles MembershipRoles
+	for _, field := range strings.Split(s, ",") {
+		role := MembershipRole(strings.ToLower(strings.TrimSpace(field)))
+		if role == "" {
+			continue
+		}
+		if !role.IsValid() {
+			return nil, fmt.Errorf("invalid MembershipRole %q", role)
+		}
+		roles = append(roles, role)
+	}
+	return roles.Normalize(), nil
+}
+
+// Normalize returns the roles without duplicates or unknown roles, in a fixed order.
+func (rs MembershipRoles) Normalize() MembershipRoles {
+	var out MembershipRoles
+	for _, known := range membershipRoles {
+		if rs.Has(known) {
+			out = append(out, known)
+		}
+	}
+	return out
+}
+
+// Has returns true if role is one of the roles.
+func (rs MembershipRoles) Has(role MembershipRole) bool {
+	for _, r := range rs {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// Strings returns the roles as strings, e.g. for Kubernetes resources.
+func (rs MembershipRoles) Strings() []string {
+	out := make([]string, 0, len(rs))
+	for _, r := range rs {
+		out = append(out, string(r))
+	}
+	return out
+}
+
+func (rs *MembershipRoles) Scan(value interface{ any }) error {
+	var s string
+	switch v := value.(type) {
+	case string:
+		s = v
+	case []byte:
+		s = string(v)
+	case nil:
+	default:
+		return fmt.Errorf("cannot scan %T into MembershipRoles", value)
+	}
+	roles, err := ParseMembershipRoles(s)
+	if err != nil {
+		return err
+	}
+	*rs = roles
+	return nil
+}
+
+func (rs MembershipRoles) Value() (driver.Value, error) {
+	for _, r := range rs {
+		if !r.IsValid() {
+			return nil, fmt.Errorf("invalid MembershipRole %q", r)
+		}
+	}
+	return strings.Join(rs.Normalize().Strings(), ","), nil
+}
+
+// A MembershipPeriod is a span of time during which a Maintainer held a Role on a Project. Periods outlive the
+// maintainer_projects rows they describe: a current membership has an open period, with no EndedAt, for each of its
+// Roles, and dropping a role or ending the membership closes the period with the Reason.
+type MembershipPeriod struct {
+	ID           uint           `gorm:"primaryKey"`
+	MaintainerID uint           `gorm:"index"`
+	ProjectID    uint           `gorm:"index"`
+	Role         MembershipRole `gorm:"size:50;default:maintainer"`
+	StartedAt    time.Time      `gorm:"index"`
+	EndedAt      *time.Time     `gorm:"index"`
+	Reason       string
+	CreatedAt    time.Time
 	Maintainer   Maintainer `gorm:"foreignKey:MaintainerID;constraint:OnDelete:CASCADE"`
 	Project      Project    `gorm:"foreignKey:ProjectID;constraint:OnDelete:CASCADE"`
 }
 
+// Duration returns how long the period lasted, or has lasted by now if it is still open.
+func (p MembershipPeriod) Duration(now time.Time) time.Duration {
+	if p.EndedAt != nil {
+		return p.EndedAt.Sub(p.StartedAt)
+	}
+	return now.Sub(p.StartedAt)
+}
+
+// A MaintainerStatusChange records a Maintainer's MaintainerStatus changing, e.g. from Active to Emeritus. From is
+// nil when the previous status was unknown.
+type MaintainerStatusChange struct {
+	ID           uint              `gorm:"primaryKey"`
+	MaintainerID uint              `gorm:"index"`
+	FromStatus   *MaintainerStatus `gorm:"type:text"`
+	ToStatus     MaintainerStatus  `gorm:"type:text"`
+	ChangedAt    time.Time         `gorm:"index"`
+	Reason       string
+}
+
+// A FindingSeverity says how much a Finding matters: errors are wrong data, warnings are gaps.
+type FindingSeverity string
+
+const (
+	SeverityError   FindingSeverity = "error"
+	SeverityWarning FindingSeverity = "warning"
+)
+
+// A Finding is a data quality problem the registry validator found in a maintainer or project, e.g. a maintainer
+// without an email. Each validation run replaces the findings of the one before.
+type Finding struct {
+	ID          uint            `gorm:"primaryKey" json:"id"`
+	CreatedAt   time.Time       `json:"createdAt"`
+	Rule        string          `gorm:"size:50;index" json:"rule"`
+	Severity    FindingSeverity `gorm:"size:20" json:"severity"`
+	SubjectKind string          `gorm:"size:20;index:idx_findings_subject" json:"subjectKind"` // maintainer or project
+	SubjectID   uint            `gorm:"index:idx_findings_subject" json:"subjectID"`
+	Subject     string          `json:"subject"` // the maintainer's GitHub login or the project's name, for reports
+	Message     string          `json:"message"`
+}
+
+// An ImportRun is a bootstrap run that read maintainers, projects and staff from a source. Report holds its
+// ImportReport as JSON: the outcome of every row, without emails.
+type ImportRun struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	RunID      string    `gorm:"size:40;uniqueIndex" json:"runID"`
+	Source     string    `json:"source"`
+	StartedAt  time.Time `gorm:"index" json:"startedAt"`
+	FinishedAt time.Time `json:"finishedAt"`
+	Created    int       `json:"created"`
+	Updated    int       `json:"updated"`
+	Unchanged  int       `json:"unchanged"`
+	Skipped    int       `json:"skipped"`
+	Failed     int       `json:"failed"`
+	Report     string    `json:"report"`
+}
+
+// An ImportCheckpoint is how far an import stage got, so an interrupted or failed run can resume after LastKey.
+type ImportCheckpoint struct {
+	Stage     string `gorm:"primaryKey;size:50"`
+	LastKey   string
+	UpdatedAt time.Time
+}
+
 type Company struct {
 	gorm.Model
 	Name string `gorm:"uniqueIndex"`
 }
 
+// companySuffixes are the legal-form words CompanyKey drops from the end of a company name.
+var companySuffixes = []string{
+	"ab", "ag", "bv", "co", "company", "corp", "corporation", "gmbh", "inc", "incorporated", "kk", "limited", "llc",
+	"ltd", "nv", "oy", "plc", "pte", "pty", "sa", "sarl", "se", "srl",
+}
+
+// CompanyKey canonicalizes a company name, so that the spellings of one company share a key: it is lower-cased,
+// dots and apostrophes are dropped, other punctuation separates words, and legal forms such as LLC, Inc. or GmbH are
+// removed from the end. "Google", "Google LLC" and "google" are all "google". A name made only of a legal form keeps it.
+func CompanyKey(name string) string {
+	name = strings.Map(func(r rune) rune {
+		switch {
+		case r == '.' || r == '\'' || r == '’':
+			return -1
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			return unicode.ToLower(r)
+		default:
+			return ' '
+		}
+	}, name)
+	words := strings.Fields(name)
+	for len(words) > 1 && slices.Contains(companySuffixes, words[len(words)-1]) {
+		words = words[:len(words)-1]
+	}
+	return strings.Join(words, " ")
+}
+
+// A CompanyAlias is a spelling of a Company's name, stored by its CompanyKey. Sheet rows and lookups find a company
+// by the key of any of its aliases, so each spelling does not become a company of its own.
+type CompanyAlias struct {
+	ID        uint   `gorm:"primaryKey"`
+	CompanyID uint   `gorm:"index"`
+	Key       string `gorm:"uniqueIndex"`
+	Name      string // the spelling the key was first seen as
+	CreatedAt time.Time
+	Company   Company `gorm:"foreignKey:CompanyID;constraint:OnDelete:CASCADE"`
+}
+
+// An AffiliationPeriod is a span of time during which a Maintainer worked for a Company. A maintainer's current
+// company has an open period, with no EndedAt; changing company closes it with the Reason and opens another.
+type AffiliationPeriod struct {
+	ID           uint       `gorm:"primaryKey"`
+	MaintainerID uint       `gorm:"index"`
+	CompanyID    uint       `gorm:"index"`
+	StartedAt    time.Time  `gorm:"index"`
+	EndedAt      *time.Time `gorm:"index"`
+	Reason       string
+	CreatedAt    time.Time
+	Maintainer   Maintainer `gorm:"foreignKey:MaintainerID;constraint:OnDelete:CASCADE"`
+	Company      Company    `gorm:"foreignKey:CompanyID;constraint:OnDelete:CASCADE"`
+}
+
+// Covers reports whether the period includes on.
+func (p AffiliationPeriod) Covers(on time.Time) bool {
+	return !p.StartedAt.After(on) && (p.EndedAt == nil || p.EndedAt.After(on))
+}
+
 // A Foundation represents an organization that employs Staff members working with
 // CNCF projects (e.g., CNCF and LF).
 type Foundation struct {
@@ -146,7 +515,10 @@ type StaffMember struct {
 	Email         string `gorm:"size:254;default:EMAIL_MISSING"`
 	GitHubAccount string `gorm:"size:100;default:GITHUB_MISSING"`
 	GitHubEmail   string `gorm:"size:254;default:GITHUB_EMAIL_MISSING"`
-	RegisteredAt  *time.Time
+	// GitHubID is the numeric GitHub user ID of GitHubAccount, once resolved. Staff with an ID are recognised by it
+	// alone, so a renamed account keeps its access and whoever takes over an old login gains none.
+	GitHubID     *int64 `gorm:"index"`
+	RegisteredAt *time.Time
 
 	FoundationID *uint `gorm:"index"`
 	Foundation   Foundation
@@ -179,6 +551,17 @@ type ServiceTeam struct {
 	ProjectName     *string // De-normalised for debugging purposes
 }
 
+// A ServiceTeamRolePolicy overrides the roles people associated with a Project hold on the Project's team in a
+// Service. A zero role keeps the service's default policy for that kind of person.
+type ServiceTeamRolePolicy struct {
+	gorm.Model
+	ProjectID        uint `gorm:"uniqueIndex:idx_role_policy_project_service"`
+	ServiceID        uint `gorm:"uniqueIndex:idx_role_policy_project_service"`
+	MaintainerRole   int
+	CollaboratorRole int
+	StaffRole        int
+}
+
 type ServiceUser struct {
 	gorm.Model
 	ServiceID         uint   `gorm:"index"` // FK to Service
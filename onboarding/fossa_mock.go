@@ -2,26 +2,34 @@ package onboarding
 
 import (
 	"errors"
+	"fmt"
 	"maintainerd/plugins/fossa"
 	"sync"
+	"time"
 )
 
 // MockFossaClient simulates FOSSA API behavior for testing
 type MockFossaClient struct {
 	mu            sync.Mutex
 	teams         map[string]*fossa.Team
-	invitations   map[string]bool  // email -> pending
-	teamMembers   map[int][]string // teamID -> emails
-	userExists    map[string]bool  // email -> exists
-	userIDs       map[string]int   // email -> userID
+	invitations   map[string]*fossa.Invitation      // email -> pending invitation
+	teamMembers   map[int][]string                  // teamID -> emails
+	teamRoles     map[int]map[string]fossa.TeamRole // teamID -> email -> role
+	userExists    map[string]bool                   // email -> exists
+	userIDs       map[string]int                    // email -> userID
 	nextTeamID    int
 	nextUserID    int
+	nextInviteID  int
 	importedRepos map[int]fossa.ImportedProjects // teamID -> imported projects
 
 	// Capture calls for verification
-	invitationsSent []string
-	teamsCreated    []string
-	membersAdded    map[int][]string // teamID -> emails added
+	invitationsSent    []string
+	invitationsResent  []string
+	invitationsRevoked []string
+	invitationLookups  int // calls to ListInvitations and GetInvitation
+	teamsCreated       []string
+	membersAdded       map[int][]string // teamID -> emails added
+	roleChanges        []string         // emails whose team role was updated
 
 	createTeamErr error
 }
@@ -30,14 +38,16 @@ type MockFossaClient struct {
 func NewMockFossaClient() *MockFossaClient {
 	return &MockFossaClient{
 		teams:         make(map[string]*fossa.Team),
-		invitations:   make(map[string]bool),
+		invitations:   make(map[string]*fossa.Invitation),
 		teamMembers:   make(map[int][]string),
+		teamRoles:     make(map[int]map[string]fossa.TeamRole),
 		userExists:    make(map[string]bool),
 		userIDs:       make(map[string]int),
 		membersAdded:  make(map[int][]string),
 		importedRepos: make(map[int]fossa.ImportedProjects),
 		nextTeamID:    1000,
 		nextUserID:    5000,
+		nextInviteID:  9000,
 	}
 }
 
@@ -77,11 +87,12 @@ func (m *MockFossaClient) SendUserInvitation(email string) error {
 		return fossa.ErrUserAlreadyMember
 	}
 
-	if m.invitations[email] {
+	if _, ok := m.invitations[email]; ok {
 		return fossa.ErrInviteAlreadyExists
 	}
 
-	m.invitations[email] = true
+	m.invitations[email] = &fossa.Invitation{ID: m.nextInviteID, Email: email, CreatedAt: time.Now()}
+	m.nextInviteID++
 	return nil
 }
 
@@ -89,7 +100,64 @@ func (m *MockFossaClient) SendUserInvitation(email string) error {
 func (m *MockFossaClient) HasPendingInvitation(email string) (bool, error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
-	return m.invitations[email], nil
+	inv, ok := m.invitations[email]
+	return ok && !inv.IsExpired(time.Now()), nil
+}
+
+// ListInvitations returns all pending invitations
+func (m *MockFossaClient) ListInvitations() ([]fossa.Invitation, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.invitationLookups++
+	invitations := make([]fossa.Invitation, 0, len(m.invitations))
+	for _, inv := range m.invitations {
+		invitations = append(invitations, *inv)
+	}
+	return invitations, nil
+}
+
+// GetInvitation returns the pending invitation for email
+func (m *MockFossaClient) GetInvitation(email string) (*fossa.Invitation, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.invitationLookups++
+	inv, ok := m.invitations[email]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", fossa.ErrInvitationNotFound, email)
+	}
+	cp := *inv
+	return &cp, nil
+}
+
+// ResendInvitation replaces the pending invitation for email with a fresh one
+func (m *MockFossaClient) ResendInvitation(email string) (*fossa.Invitation, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.invitations[email]; !ok {
+		return nil, fmt.Errorf("%w: %s", fossa.ErrInvitationNotFound, email)
+	}
+	inv := &fossa.Invitation{ID: m.nextInviteID, Email: email, CreatedAt: time.Now()}
+	m.nextInviteID++
+	m.invitations[email] = inv
+	m.invitationsResent = append(m.invitationsResent, email)
+	cp := *inv
+	return &cp, nil
+}
+
+// RevokeInvitation withdraws the pending invitation for email
+func (m *MockFossaClient) RevokeInvitation(email string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.invitations[email]; !ok {
+		return fmt.Errorf("%w: %s", fossa.ErrInvitationNotFound, email)
+	}
+	delete(m.invitations, email)
+	m.invitationsRevoked = append(m.invitationsRevoked, email)
+	return nil
 }
 
 // FetchTeamUserEmails returns all user emails for a team
@@ -122,9 +190,53 @@ func (m *MockFossaClient) AddUserToTeamByEmail(teamID int, email string, roleID
 
 	m.teamMembers[teamID] = append(members, email)
 	m.membersAdded[teamID] = append(m.membersAdded[teamID], email)
+	m.setRoleLocked(teamID, email, fossa.TeamRole(roleID))
 	return nil
 }
 
+// ListTeamMembersWithRoles returns all members of a team and their roles
+func (m *MockFossaClient) ListTeamMembersWithRoles(teamID int) ([]fossa.TeamMember, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	emails, ok := m.teamMembers[teamID]
+	if !ok {
+		return nil, errors.New("team not found")
+	}
+	members := make([]fossa.TeamMember, 0, len(emails))
+	for _, email := range emails {
+		members = append(members, fossa.TeamMember{
+			UserID:   m.userIDs[email],
+			RoleID:   m.teamRoles[teamID][email],
+			Username: email,
+			Email:    email,
+		})
+	}
+	return members, nil
+}
+
+// UpdateTeamMemberRole changes the role of an existing team member
+func (m *MockFossaClient) UpdateTeamMemberRole(teamID, userID int, role fossa.TeamRole) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, email := range m.teamMembers[teamID] {
+		if m.userIDs[email] == userID {
+			m.setRoleLocked(teamID, email, role)
+			m.roleChanges = append(m.roleChanges, email)
+			return nil
+		}
+	}
+	return errors.New("user is not a member of the team")
+}
+
+func (m *MockFossaClient) setRoleLocked(teamID int, email string, role fossa.TeamRole) {
+	if m.teamRoles[teamID] == nil {
+		m.teamRoles[teamID] = make(map[string]fossa.TeamRole)
+	}
+	m.teamRoles[teamID][email] = role
+}
+
 // FetchImportedRepos returns imported repos for a team
 func (m *MockFossaClient) FetchImportedRepos(teamID int) (int, fossa.ImportedProjects, error) {
 	m.mu.Lock()
@@ -133,10 +245,7 @@ func (m *MockFossaClient) FetchImportedRepos(teamID int) (int, fossa.ImportedPro
 	repos, ok := m.importedRepos[teamID]
 	if !ok {
 		// Return empty by default
-		return 0, fossa.ImportedProjects{Results: []struct {
-			Title   string `json:"title"`
-			Locator string `json:"locator"`
-		}{}}, nil
+		return 0, fossa.ImportedProjects{Results: []fossa.ImportedProject{}}, nil
 	}
 	return len(repos.Results), repos, nil
 }
@@ -203,6 +312,51 @@ func (m *MockFossaClient) AcceptInvitation(email string) {
 	}
 }
 
+// SetInvitationSentAt backdates the pending invitation for email, e.g. to make it stale
+func (m *MockFossaClient) SetInvitationSentAt(email string, sentAt time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if inv, ok := m.invitations[email]; ok {
+		inv.CreatedAt = sentAt
+	}
+}
+
+// SetTeamMember makes email an existing member of the team holding role
+func (m *MockFossaClient) SetTeamMember(teamID int, email string, role fossa.TeamRole) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.userExists[email] = true
+	if m.userIDs[email] == 0 {
+		m.userIDs[email] = m.nextUserID
+		m.nextUserID++
+	}
+	found := false
+	for _, e := range m.teamMembers[teamID] {
+		if e == email {
+			found = true
+			break
+		}
+	}
+	if !found {
+		m.teamMembers[teamID] = append(m.teamMembers[teamID], email)
+	}
+	m.setRoleLocked(teamID, email, role)
+}
+
+// GetTeamMemberRole returns the role email holds on the team
+func (m *MockFossaClient) GetTeamMemberRole(teamID int, email string) fossa.TeamRole {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.teamRoles[teamID][email]
+}
+
+// GetRoleChanges returns all emails whose team role was updated
+func (m *MockFossaClient) GetRoleChanges() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]string{}, m.roleChanges...)
+}
+
 // SetImportedRepos sets imported repos for a team
 func (m *MockFossaClient) SetImportedRepos(teamID int, repos fossa.ImportedProjects) {
 	m.mu.Lock()
@@ -217,6 +371,20 @@ func (m *MockFossaClient) GetInvitationsSent() []string {
 	return append([]string{}, m.invitationsSent...)
 }
 
+// GetInvitationsResent returns all emails whose invitations were resent
+func (m *MockFossaClient) GetInvitationsResent() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]string{}, m.invitationsResent...)
+}
+
+// GetInvitationLookups returns how many times the pending invitations were listed or looked up by email
+func (m *MockFossaClient) GetInvitationLookups() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.invitationLookups
+}
+
 // GetTeamsCreated returns all team names that were created
 func (m *MockFossaClient) GetTeamsCreated() []string {
 	m.mu.Lock()
@@ -237,11 +405,16 @@ func (m *MockFossaClient) Reset() {
 	defer m.mu.Unlock()
 
 	m.teams = make(map[string]*fossa.Team)
-	m.invitations = make(map[string]bool)
+	m.invitations = make(map[string]*fossa.Invitation)
 	m.teamMembers = make(map[int][]string)
+	m.teamRoles = make(map[int]map[string]fossa.TeamRole)
+	m.roleChanges = nil
 	m.userExists = make(map[string]bool)
 	m.userIDs = make(map[string]int)
 	m.invitationsSent = nil
+	m.invitationsResent = nil
+	m.invitationsRevoked = nil
+	m.invitationLookups = 0
 	m.teamsCreated = nil
 	m.membersAdded = make(map[int][]string)
 	m.importedRepos = make(map[int]fossa.ImportedProjects)
@@ -0,0 +1,130 @@
+package onboarding
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"maintainerd/model"
+	"maintainerd/plugins/fossa"
+)
+
+// fossaRolePolicy returns the FOSSA team role policy for project: fossa.DefaultTeamRolePolicy with any per-project
+// overrides stored in maintainer-d applied on top.
+func (s *EventListener) fossaRolePolicy(ctx context.Context, project model.Project) fossa.TeamRolePolicy {
+	policy := fossa.DefaultTeamRolePolicy
+	override, err := s.Store.GetServiceTeamRolePolicy(ctx, project.ID, "FOSSA")
+	if err != nil {
+		log.Printf("fossaRolePolicy: WRN, using default policy for %q: %v", project.Name, err)
+		return policy
+	}
+	if override == nil {
+		return policy
+	}
+	if override.MaintainerRole != 0 {
+		policy.Maintainer = fossa.TeamRole(override.MaintainerRole)
+	}
+	if override.CollaboratorRole != 0 {
+		policy.Collaborator = fossa.TeamRole(override.CollaboratorRole)
+	}
+	if override.StaffRole != 0 {
+		policy.Staff = fossa.TeamRole(override.StaffRole)
+	}
+	return policy
+}
+
+// enforceFossaRolePolicy brings the role of every member of the project's FOSSA team in line with the project's role
+// policy: registered maintainers, foundation staff and recorded collaborators each get the role the policy assigns
+// them. Members maintainer-d does not know are left alone. Returned actions reference maintainers by GitHub handle and
+// everyone else by FOSSA user ID, never by email. If staff or collaborators cannot be loaded, no role is changed:
+// treating them as unknown could demote staff.
+func (s *EventListener) enforceFossaRolePolicy(ctx context.Context, project model.Project, teamID int) ([]string, error) {
+	policy := s.fossaRolePolicy(ctx, project)
+
+	maintainers, err := s.Store.GetMaintainersByProject(ctx, project.ID)
+	if err != nil {
+		return nil, fmt.Errorf("GetMaintainersByProject: %w", err)
+	}
+	handleByEmail := make(map[string]string, len(maintainers)*2)
+	for _, m := range maintainers {
+		for _, email := range m.Emails() {
+			handleByEmail[email] = m.GitHubAccount
+		}
+	}
+	staffEmails, collaboratorEmails, err := s.staffAndCollaboratorEmails(ctx)
+	if err != nil {
+		log.Printf("enforceFossaRolePolicy: ERR, not enforcing the role policy of %q: %v", project.Name, err)
+		return nil, errors.New("enforceFossaRolePolicy: role policy not enforced, staff and collaborators could not be loaded")
+	}
+
+	members, err := s.FossaClient.ListTeamMembersWithRoles(teamID)
+	if err != nil {
+		return nil, fmt.Errorf("ListTeamMembersWithRoles: %w", err)
+	}
+
+	var actions []string
+	var errs []string
+	for _, member := range members {
+		name := fmt.Sprintf("FOSSA user %d", member.UserID)
+		want := fossa.TeamRoleDefault
+		if handle, ok := handleByEmail[strings.ToLower(member.Email)]; ok {
+			name = "@" + handle
+			want = policy.Maintainer
+		} else if staffEmails[strings.ToLower(member.Email)] {
+			want = policy.Staff
+		} else if collaboratorEmails[strings.ToLower(member.Email)] {
+			want = policy.Collaborator
+		}
+		if want == fossa.TeamRoleDefault || member.RoleID == want {
+			continue
+		}
+
+		if err := s.FossaClient.UpdateTeamMemberRole(teamID, member.UserID, want); err != nil {
+			log.Printf("enforceFossaRolePolicy: ERR, setting role %s for FOSSA user %d: %v", want, member.UserID, err)
+			errs = append(errs, name) // details are logged, error text may contain emails
+			continue
+		}
+		actions = append(actions, fmt.Sprintf("%s: role changed from %s to %s", name, member.RoleID, want))
+		s.Store.LogAuditEvent(ctx, zapNewNopSugar(), model.AuditLog{
+			ProjectID: project.ID,
+			Action:    "FOSSA_SET_ROLE",
+			Message:   fmt.Sprintf("Changed FOSSA user %d on team %s from %s to %s", member.UserID, project.Name, member.RoleID, want),
+		})
+	}
+	if len(errs) > 0 {
+		return actions, fmt.Errorf("enforceFossaRolePolicy: %d role changes failed for %s", len(errs), strings.Join(errs, ", "))
+	}
+	return actions, nil
+}
+
+// staffAndCollaboratorEmails returns the lower-cased emails and GitHub emails of staff members and of collaborators.
+func (s *EventListener) staffAndCollaboratorEmails(ctx context.Context) (staff, collaborators map[string]bool, err error) {
+	staffMembers, err := s.Store.ListStaffMembers(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("ListStaffMembers: %w", err)
+	}
+	staff = map[string]bool{}
+	for _, sm := range staffMembers {
+		for _, email := range []string{sm.Email, sm.GitHubEmail} {
+			if !model.IsMissing(email) {
+				staff[strings.ToLower(email)] = true
+			}
+		}
+	}
+	recorded, err := s.Store.ListCollaborators(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("ListCollaborators: %w", err)
+	}
+	collaborators = map[string]bool{}
+	for _, c := range recorded {
+		if !model.IsMissing(c.Email) {
+			collaborators[strings.ToLower(c.Email)] = true
+		}
+		if c.GitHubEmail != nil && !model.IsMissing(*c.GitHubEmail) {
+			collaborators[strings.ToLower(*c.GitHubEmail)] = true
+		}
+	}
+	return staff, collaborators, nil
+}
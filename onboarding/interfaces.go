@@ -7,8 +7,14 @@ type FossaClientInterface interface {
 	CreateTeam(name string) (*fossa.Team, error)
 	SendUserInvitation(email string) error
 	HasPendingInvitation(email string) (bool, error)
+	ListInvitations() ([]fossa.Invitation, error)
+	GetInvitation(email string) (*fossa.Invitation, error)
+	ResendInvitation(email string) (*fossa.Invitation, error)
+	RevokeInvitation(email string) error
 	FetchTeamUserEmails(teamID int) ([]string, error)
 	AddUserToTeamByEmail(teamID int, email string, roleID int) error
+	ListTeamMembersWithRoles(teamID int) ([]fossa.TeamMember, error)
+	UpdateTeamMemberRole(teamID, userID int, role fossa.TeamRole) error
 	FetchImportedRepos(teamID int) (int, fossa.ImportedProjects, error)
 	ImportedProjectLinks(projects fossa.ImportedProjects) string
 	FetchTeam(name string) (*fossa.Team, error)
@@ -2,19 +2,19 @@ package onboarding
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
 	"maintainerd/model"
 	"net/http"
 	"os"
+	"slices"
 	"strings"
 	"time"
 
 	"golang.org/x/oauth2"
 	"google.golang.org/api/sourcerepo/v1"
-	"gorm.io/driver/sqlite"
-	"gorm.io/gorm"
 
 	"github.com/google/go-github/v55/github"
 	"go.uber.org/zap"
@@ -26,7 +26,7 @@ import (
 // EventListener server that handles GitHub webhook events and triggers onboarding processes using the maintainerd db and
 // known services such as FOSSA.
 type EventListener struct {
-	Store        *db.SQLStore
+	Store        db.Store
 	FossaClient  FossaClientInterface
 	Secret       []byte
 	Projects     map[string]model.Project
@@ -34,15 +34,20 @@ type EventListener struct {
 	GitHubClient *github.Client
 }
 
-func (s *EventListener) Init(dbPath, fossaAPItokenEnvVar, ghToken, org, repo string) error {
-	dbConn, err := gorm.Open(sqlite.Open(dbPath))
+// Init connects to the SQLite or PostgreSQL database identified by dsn and to FOSSA and GitHub.
+func (s *EventListener) Init(ctx context.Context, dsn, fossaAPItokenEnvVar, ghToken, org, repo string) error {
+	dbConn, err := db.Open(dsn, nil)
 	if err != nil {
 		log.Printf("error: failed to connect to db: %v", err)
 		return fmt.Errorf("connect ``to db: %w", err)
 	}
+	if err := db.CheckSchemaVersion(dbConn); err != nil {
+		log.Printf("Init: ERR, refusing to start: %v", err)
+		return err
+	}
 	s.Store = db.NewSQLStore(dbConn)
 
-	projectMap, err := s.Store.GetProjectMapByName()
+	projectMap, err := s.Store.GetProjectMapByName(ctx)
 	if err != nil {
 		log.Printf("error: failed to get project map: %v", err)
 		return fmt.Errorf("get project map: %w", err)
@@ -65,52 +70,64 @@ func (s *EventListener) Init(dbPath, fossaAPItokenEnvVar, ghToken, org, repo str
 	}
 	s.FossaClient = fossa.NewClient(token)
 	ts := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: ghToken})
-	tc := oauth2.NewClient(context.Background(), ts)
+	tc := oauth2.NewClient(ctx, ts)
 	s.GitHubClient = github.NewClient(tc)
 
 	log.Printf("info: EventListener initialized successfully for org %q and repo %q", org, repo)
 	return nil
 }
 
-// Run starts an HTTP server listening on the given address.
+// Run starts an HTTP server listening on the given address for GitHub webhooks and health checks.
 func (s *EventListener) Run(addr string) error {
+	return newHTTPServer(addr, s.publicMux()).ListenAndServe()
+}
+
+// RunInternal starts an HTTP server listening on the given address for endpoints that must not be exposed with the
+// webhook, such as the findings, which name maintainers. addr should only be reachable from inside the cluster, e.g.
+// a loopback address reached with kubectl port-forward.
+func (s *EventListener) RunInternal(addr string) error {
+	return newHTTPServer(addr, s.internalMux()).ListenAndServe()
+}
+
+func (s *EventListener) publicMux() *http.ServeMux {
 	mux := http.NewServeMux()
 	mux.HandleFunc("/healthz", s.handleHealth)
 	mux.HandleFunc("/webhook", s.handleWebhook)
+	return mux
+}
 
-	server := &http.Server{
+func (s *EventListener) internalMux() *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", s.handleHealth)
+	mux.HandleFunc("/findings", s.handleFindings)
+	return mux
+}
+
+func newHTTPServer(addr string, handler http.Handler) *http.Server {
+	return &http.Server{
 		Addr:         addr,
-		Handler:      mux,
+		Handler:      handler,
 		ReadTimeout:  15 * time.Second,
 		WriteTimeout: 120 * time.Second,
 		IdleTimeout:  60 * time.Second,
 	}
-	return server.ListenAndServe()
 }
 
-func (s *EventListener) isAuthorizedForProjectAction(actor string, project model.Project, issue *github.Issue) bool {
-	// Check if actor is a registered maintainer for this project.
-	if maintainers, err := s.Store.GetMaintainersByProject(project.ID); err == nil {
-		for _, m := range maintainers {
-			if m.GitHubAccount == actor {
-				return true
-			}
-		}
-	}
-
-	// CNCF/LF staff may also execute onboarding commands.
-	if ok, err := s.Store.IsStaffGitHubAccount(actor); err == nil && ok {
+// isAuthorizedForProjectAction reports whether actor, the sender of a webhook event, may run onboarding commands for
+// the project: its maintainers, CNCF/LF staff and the assignees of the onboarding issue may.
+func (s *EventListener) isAuthorizedForProjectAction(ctx context.Context, actor *github.User, project model.Project, issue *github.Issue) bool {
+	if s.isMaintainerOrStaff(ctx, actor, project) {
 		return true
 	}
 
-	// Also allow any GitHub handle assigned to the onboarding issue.
+	// Also allow any GitHub user assigned to the onboarding issue.
 	if issue != nil {
 		for _, u := range issue.Assignees {
-			if u.GetLogin() == actor {
+			if sameGitHubUser(u, actor) {
 				return true
 			}
 		}
-		if a := issue.GetAssignee(); a != nil && a.GetLogin() == actor {
+		if a := issue.GetAssignee(); a != nil && sameGitHubUser(a, actor) {
 			return true
 		}
 	}
@@ -118,7 +135,86 @@ func (s *EventListener) isAuthorizedForProjectAction(actor string, project model
 	return false
 }
 
+// isMaintainerOrStaff reports whether actor is a registered maintainer of the project or a CNCF/LF staff member, both
+// matched on their GitHub user ID once it is recorded. Commands that provision services, such as /label, are limited
+// to them; issue assignees may not run them.
+func (s *EventListener) isMaintainerOrStaff(ctx context.Context, actor *github.User, project model.Project) bool {
+	if maintainers, err := s.Store.GetMaintainersByProject(ctx, project.ID); err == nil {
+		for _, m := range maintainers {
+			if isActor(m, actor) {
+				return true
+			}
+		}
+	}
+	return s.isStaff(ctx, actor)
+}
+
+// isAuthorizedForDestructiveAction reports whether actor may run commands that take something away from other people,
+// such as enforcing a role policy that demotes FOSSA team members. When the project has leads only they, and CNCF/LF
+// staff, may; projects without leads fall back to isAuthorizedForProjectAction.
+func (s *EventListener) isAuthorizedForDestructiveAction(ctx context.Context, actor *github.User, project model.Project, issue *github.Issue) bool {
+	memberships, err := s.Store.GetProjectMemberships(ctx, project.ID)
+	if err != nil {
+		log.Printf("isAuthorizedForDestructiveAction: WRN, could not list memberships of %q: %v", project.Name, err)
+		return false
+	}
+	hasLeads := false
+	for _, ms := range memberships {
+		if !ms.Roles.Has(model.RoleLead) {
+			continue
+		}
+		hasLeads = true
+		if isActor(ms.Maintainer, actor) {
+			return true
+		}
+	}
+	if !hasLeads {
+		return s.isAuthorizedForProjectAction(ctx, actor, project, issue)
+	}
+	return s.isStaff(ctx, actor)
+}
+
+// isStaff reports whether actor is a CNCF/LF staff member, recognised by their GitHub user ID once it is recorded and
+// by their login until then.
+func (s *EventListener) isStaff(ctx context.Context, actor *github.User) bool {
+	ok, err := s.Store.IsStaffGitHubUser(ctx, actor.GetID(), actor.GetLogin())
+	if err != nil {
+		log.Printf("isStaff: WRN, failed to check staff authorization for @%s: %v", actor.GetLogin(), err)
+	}
+	return err == nil && ok
+}
+
+// isActor reports whether the maintainer is the GitHub user actor. A maintainer linked to a GitHub user ID is matched
+// on the ID alone, so a renamed account keeps its access and whoever takes over an old login gains none. Maintainers
+// whose ID has not been resolved yet are matched on their logins, ignoring case.
+func isActor(m model.Maintainer, actor *github.User) bool {
+	if id, ok := m.GitHubID(); ok {
+		return actor.GetID() == id
+	}
+	login := strings.ToLower(actor.GetLogin())
+	return login != "" && slices.Contains(m.GitHubAccounts(), login)
+}
+
+// sameGitHubUser reports whether a and b are the same GitHub user: by ID when both carry one, by login otherwise.
+func sameGitHubUser(a, b *github.User) bool {
+	if a.GetID() != 0 && b.GetID() != 0 {
+		return a.GetID() == b.GetID()
+	}
+	return a.GetLogin() != "" && strings.EqualFold(a.GetLogin(), b.GetLogin())
+}
+
+// eventActor returns the user who sent the comment event. The sender is the authenticated user GitHub delivers the
+// event for; the comment author is the fallback for payloads without one.
+func eventActor(e *github.IssueCommentEvent) *github.User {
+	if e.GetSender() != nil {
+		return e.GetSender()
+	}
+	return e.GetComment().GetUser()
+}
+
 func (s *EventListener) handleWebhook(w http.ResponseWriter, r *http.Request) {
+	// Store queries made for this event are cancelled along with the request.
+	ctx := r.Context()
 	payload, err := github.ValidatePayload(r, s.Secret)
 	if err != nil {
 		log.Printf("handleWebhook: ERR github.ValidatePayload: %v", err)
@@ -162,10 +258,12 @@ func (s *EventListener) handleWebhook(w http.ResponseWriter, r *http.Request) {
 			break
 		}
 
-		// Authorization: allow project maintainers or CNCF Project Team handles (via env var list)
-		actor := e.GetComment().GetUser().GetLogin()
+		// Authorization: allow project maintainers, CNCF staff and the issue's assignees, matched on the sender's
+		// GitHub user ID where it is known.
+		sender := eventActor(e)
+		actor := sender.GetLogin()
 
-		if !s.isAuthorizedForProjectAction(actor, project, e.GetIssue()) {
+		if !s.isAuthorizedForProjectAction(ctx, sender, project, e.GetIssue()) {
 			// Post an authorization failure comment and return
 			comment := "You are not authorized to perform this action."
 			if err := s.updateIssue(r.Context(), e.GetRepo().GetOwner().GetLogin(), e.GetRepo().GetName(), e.GetIssue().GetNumber(), comment); err != nil {
@@ -177,7 +275,7 @@ func (s *EventListener) handleWebhook(w http.ResponseWriter, r *http.Request) {
 		log.Printf("handleWebhook: INF, /fossa-invite accepted by @%s for project %q", actor, project.Name)
 
 		// Ensure a FOSSA ServiceTeam exists for this project
-		stMap, err := s.Store.GetProjectServiceTeamMap("FOSSA")
+		stMap, err := s.Store.GetProjectServiceTeamMap(ctx, "FOSSA")
 		if err != nil {
 			log.Printf("handleWebhook: ERR, could not get FOSSA team map: %v", err)
 			break
@@ -192,11 +290,24 @@ func (s *EventListener) handleWebhook(w http.ResponseWriter, r *http.Request) {
 			break
 		}
 
-		// Process all maintainers: verify acceptance, check membership, add as Team Admin if needed
-		actions, err := s.addProjectMaintainersToFossaTeam(project, st.ServiceTeamID)
+		// Process all maintainers: verify acceptance, check membership, add with the policy's maintainer role if needed
+		actions, err := s.addProjectMaintainersToFossaTeam(ctx, project, st.ServiceTeamID)
 		if err != nil {
 			log.Printf("handleWebhook: ERR, addProjectMaintainersToFossaTeam: %v", err)
 		}
+		// Then make sure every team member, including anyone added through the FOSSA UI, holds the role the policy
+		// gives them, e.g. collaborators who were made Team Admins by mistake are demoted. Demoting people is left
+		// to project leads where the project has them.
+		if s.isAuthorizedForDestructiveAction(ctx, sender, project, e.GetIssue()) {
+			roleActions, roleErr := s.enforceFossaRolePolicy(ctx, project, st.ServiceTeamID)
+			if roleErr != nil {
+				log.Printf("handleWebhook: ERR, enforceFossaRolePolicy: %v", roleErr)
+				err = errors.Join(err, roleErr)
+			}
+			actions = append(actions, roleActions...)
+		} else {
+			actions = append(actions, fmt.Sprintf("@%s: team roles were not changed, only project leads or CNCF staff may enforce the FOSSA role policy", actor))
+		}
 		// Build and post summary comment (using GitHub handles only)
 		var comment string
 		comment += "### maintainer-d - CNCF FOSSA Team Membership Update\n\n"
@@ -238,7 +349,7 @@ func (s *EventListener) fossaChosen(projectName string, r *http.Request, e *gith
 
 	log.Printf("fossaChosen: DBG by %s", projectName)
 	project := s.Projects[projectName]
-	actions, err := s.signProjectUpForFOSSA(project)
+	actions, err := s.signProjectUpForFOSSA(r.Context(), project)
 	if err != nil {
 		log.Printf("fossaChosen: ERR, failed to send FOSSA invitations: %v", err)
 	}
@@ -268,6 +379,7 @@ func (s *EventListener) fossaChosen(projectName string, r *http.Request, e *gith
 
 // handleLabelCommand processes /label commands from issue comments
 func (s *EventListener) handleLabelCommand(r *http.Request, e *github.IssueCommentEvent) {
+	ctx := r.Context()
 	body := e.GetComment().GetBody()
 	parts := strings.Fields(body)
 
@@ -310,30 +422,9 @@ func (s *EventListener) handleLabelCommand(r *http.Request, e *github.IssueComme
 		return
 	}
 
-	// Authorization: check if actor is a registered maintainer for this project
-	actor := e.GetComment().GetUser().GetLogin()
-	isAuthorized := false
-
-	maintainers, err := s.Store.GetMaintainersByProject(project.ID)
-	if err == nil {
-		for _, m := range maintainers {
-			if m.GitHubAccount == actor {
-				isAuthorized = true
-				break
-			}
-		}
-	}
-
-	// CNCF/LF staff may also execute onboarding commands.
-	if !isAuthorized {
-		if ok, err := s.Store.IsStaffGitHubAccount(actor); err != nil {
-			log.Printf("handleLabelCommand: WRN, failed to check staff authorization for @%s: %v", actor, err)
-		} else if ok {
-			isAuthorized = true
-		}
-	}
-
-	if !isAuthorized {
+	sender := eventActor(e)
+	actor := sender.GetLogin()
+	if !s.isMaintainerOrStaff(ctx, sender, project) {
 		log.Printf("handleLabelCommand: WRN, @%s is not authorized for project %q", actor, projectName)
 		comment := fmt.Sprintf("@%s, looks like you have not yet been registered in maintainer-d. A CNCF Projects Team member will be in touch to assist you further.", actor)
 		if err := s.updateIssue(r.Context(), e.GetRepo().GetOwner().GetLogin(), e.GetRepo().GetName(), e.GetIssue().GetNumber(), comment); err != nil {
@@ -383,15 +474,41 @@ func (s *EventListener) handleHealth(w http.ResponseWriter, r *http.Request) {
 	_, _ = w.Write([]byte(`{"status":"ok"}`)) //nolint:errcheck
 }
 
+// handleFindings serves the data quality findings recorded by the last validate run as JSON. ?severity= and ?rule=
+// narrow them down. It is only served by RunInternal.
+func (s *EventListener) handleFindings(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	findings, err := s.Store.ListFindings(r.Context())
+	if err != nil {
+		log.Printf("handleFindings: ERR, failed to list findings: %v", err)
+		http.Error(w, "failed to list findings", http.StatusInternalServerError)
+		return
+	}
+	severity, rule := r.URL.Query().Get("severity"), r.URL.Query().Get("rule")
+	filtered := []model.Finding{}
+	for _, f := range findings {
+		if (severity == "" || string(f.Severity) == severity) && (rule == "" || f.Rule == rule) {
+			filtered = append(filtered, f)
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(filtered); err != nil {
+		log.Printf("handleFindings: WRN, failed to write response: %v", err)
+	}
+}
+
 // signProjectUpForFOSSA using @s.store, gets the maintainers registered for @project, uses the @s.fc to email FOSSA
 // invites to their registered email addresses. As invitations are sent, we build up a list of actions that were taken by the
 // process so that the client can report steps taken and their results; in actions we reference maintainers using their
 // public GitHub account keeping their registered email addresses private.
-func (s *EventListener) signProjectUpForFOSSA(project model.Project) ([]string, error) {
+func (s *EventListener) signProjectUpForFOSSA(ctx context.Context, project model.Project) ([]string, error) {
 	var actions []string
 
 	// Check for maintainers registered for this project
-	maintainers, err := s.Store.GetMaintainersByProject(project.ID)
+	maintainers, err := s.Store.GetMaintainersByProject(ctx, project.ID)
 	if err != nil {
 		actions = append(actions, fmt.Sprintf(":x: %s maintainers not present in db, @cncf-projects-team check maintainer-d db", project.Name))
 		return actions, fmt.Errorf("signProjectUpForFOSSA: maintainers not found in db for project %s (ID: %d)", project.Name, project.ID)
@@ -400,7 +517,7 @@ func (s *EventListener) signProjectUpForFOSSA(project model.Project) ([]string,
 	actions = append(actions, fmt.Sprintf("✅  %s has %d maintainers registered in maintainer-d", project.Name, len(maintainers)))
 
 	// Do we have a team already in FOSSA for @project?
-	serviceTeams, err := s.Store.GetProjectServiceTeamMap("FOSSA")
+	serviceTeams, err := s.Store.GetProjectServiceTeamMap(ctx, "FOSSA")
 	if err != nil {
 		actions = append(actions, fmt.Sprintf(":warning: Problem retrieving serviceTeams.  %v", err))
 	}
@@ -423,7 +540,7 @@ func (s *EventListener) signProjectUpForFOSSA(project model.Project) ([]string,
 		actions = append(actions,
 			fmt.Sprintf("👥  [%s team](https://app.fossa.com/account/settings/organization/teams/%d) has been created in FOSSA",
 				team.Name, team.ID))
-		_, err = s.Store.CreateServiceTeam(project.ID, project.Name, team.ID, team.Name)
+		_, err = s.Store.CreateServiceTeam(ctx, project.ID, project.Name, team.ID, team.Name)
 		if err != nil {
 			log.Printf("handleWebhook: WRN, failed to create service team: %v", err)
 		}
@@ -433,14 +550,24 @@ func (s *EventListener) signProjectUpForFOSSA(project model.Project) ([]string,
 		actions = append(actions, fmt.Sprintf("Maintainers not yet registered, for project %s", project.Name))
 		return actions, fmt.Errorf(":x: no maintainers found for project %d", project.ID)
 	}
+	policy := s.fossaRolePolicy(ctx, project)
 	var invitedMaintainers []string  // track who we've invited so we can mention them in a single line comment
 	var existingMaintainers []string // track who is already a member over on CNCF FOSSA
+	var invited []model.Maintainer   // track invitees so we can tell them when their invitation expires
+	invitations := s.listInvitations()
 	for _, maintainer := range maintainers {
+		key := strings.ToLower(maintainer.Email)
 		err := s.FossaClient.SendUserInvitation(maintainer.Email) // TODO See if I can Name the User on FOSSA!
 		if errors.Is(err, fossa.ErrInviteAlreadyExists) {
+			if inv, ok := invitations[key]; ok {
+				if refreshed := s.refreshStaleInvitation(maintainer, inv); refreshed != nil {
+					invitations[key] = *refreshed
+				}
+			}
 			invitedMaintainers = append(invitedMaintainers, maintainer.GitHubAccount) // invited already
+			invited = append(invited, maintainer)
 		} else if errors.Is(err, fossa.ErrUserAlreadyMember) {
-			err := s.FossaClient.AddUserToTeamByEmail(st.ServiceTeamID, maintainer.Email, 3)
+			err := s.FossaClient.AddUserToTeamByEmail(st.ServiceTeamID, maintainer.Email, int(policy.Maintainer))
 			if err != nil {
 				actions = append(actions, fmt.Sprintf("@%s : error adding you to your team on CNCF FOSSA", maintainer.GitHubAccount))
 			} else {
@@ -451,19 +578,22 @@ func (s *EventListener) signProjectUpForFOSSA(project model.Project) ([]string,
 			actions = append(actions, fmt.Sprintf("@%s there was a problem sending you a CNCF FOSSA invitation. A CNCF Staff member will contact you.", maintainer.GitHubAccount))
 		} else {
 			invitedMaintainers = append(invitedMaintainers, maintainer.GitHubAccount) // invited just now
+			invited = append(invited, maintainer)
+			invitations[key] = fossa.Invitation{Email: maintainer.Email, CreatedAt: time.Now()}
 		}
 	}
 
 	if len(invitedMaintainers) > 0 {
 		actions = append(actions, fmt.Sprintf("✅ Invitation(s) to join CNCF FOSSA sent to %s", formatHandles(invitedMaintainers)))
+		actions = append(actions, invitationExpiryNotes(invited, invitations)...)
 	}
 	if len(existingMaintainers) != 0 {
-		actions = append(actions, fmt.Sprintf("✅ CNCF FOSSA Users added to the team as Team Admins %s", formatHandles(existingMaintainers)))
+		actions = append(actions, fmt.Sprintf("✅ CNCF FOSSA Users added to the team as %ss %s", policy.Maintainer, formatHandles(existingMaintainers)))
 	}
 
 	// check if the project team has imported their repos. If we label an onboarding issue with 'fossa' and the project
 	// has been manually setup in the past, better to report that repos have been imported into FOSSA.
-	teamMap, err := s.Store.GetProjectServiceTeamMap("FOSSA")
+	teamMap, err := s.Store.GetProjectServiceTeamMap(ctx, "FOSSA")
 	if err != nil {
 		return nil, err
 	}
@@ -482,6 +612,55 @@ func (s *EventListener) signProjectUpForFOSSA(project model.Project) ([]string,
 	return actions, nil
 }
 
+// staleInvitationAge is how old a pending FOSSA invitation may get before onboarding replaces it with a fresh one.
+const staleInvitationAge = 5 * 24 * time.Hour
+
+// listInvitations returns FOSSA's pending invitations keyed by lower-cased email. Listing pages through every
+// invitation in the organization, so callers fetch the map once per run and keep it current as they send invitations.
+// A failure is logged and yields an empty map.
+func (s *EventListener) listInvitations() map[string]fossa.Invitation {
+	invitations, err := s.FossaClient.ListInvitations()
+	if err != nil {
+		log.Printf("listInvitations: WRN, listing invitations: %v", err)
+	}
+	byEmail := make(map[string]fossa.Invitation, len(invitations))
+	for _, inv := range invitations {
+		byEmail[strings.ToLower(inv.Email)] = inv
+	}
+	return byEmail
+}
+
+// refreshStaleInvitation resends inv, the FOSSA invitation for maintainer, if it is older than staleInvitationAge. It
+// returns the new invitation, or nil if nothing was resent.
+func (s *EventListener) refreshStaleInvitation(maintainer model.Maintainer, inv fossa.Invitation) *fossa.Invitation {
+	if inv.Age(time.Now()) < staleInvitationAge {
+		return nil
+	}
+	refreshed, err := s.FossaClient.ResendInvitation(maintainer.Email)
+	if err != nil {
+		log.Printf("refreshStaleInvitation: ERR, resending invitation for @%s: %v", maintainer.GitHubAccount, err)
+		return nil
+	}
+	log.Printf("refreshStaleInvitation: INF, resent stale invitation for @%s", maintainer.GitHubAccount)
+	return refreshed
+}
+
+// invitationExpiryNotes returns one action line per invited maintainer telling them when their invitation, looked up
+// in invitations by lower-cased email, expires.
+func invitationExpiryNotes(invited []model.Maintainer, invitations map[string]fossa.Invitation) []string {
+	var notes []string
+	for _, m := range invited {
+		if inv, ok := invitations[strings.ToLower(m.Email)]; ok {
+			notes = append(notes, fmt.Sprintf("⏳ @%s your invitation expires on %s", m.GitHubAccount, formatExpiry(inv.Expiry())))
+		}
+	}
+	return notes
+}
+
+func formatExpiry(t time.Time) string {
+	return t.UTC().Format("Jan 2, 2006")
+}
+
 func formatHandles(handles []string) string {
 	if len(handles) == 0 {
 		return ""
@@ -502,11 +681,11 @@ func (s *EventListener) updateIssue(ctx context.Context, owner, repo string, iss
 
 // addProjectMaintainersToFossaTeam processes all registered maintainers for a project against the given FOSSA team.
 // It does not include email addresses in returned action strings; only GitHub handles.
-func (s *EventListener) addProjectMaintainersToFossaTeam(project model.Project, teamID int) ([]string, error) {
+func (s *EventListener) addProjectMaintainersToFossaTeam(ctx context.Context, project model.Project, teamID int) ([]string, error) {
 	log.Printf("addProjectMaintainersToFossaTeam: project=%q projectID=%d teamID=%d", project.Name, project.ID, teamID)
 	var actions []string
 
-	maintainers, err := s.Store.GetMaintainersByProject(project.ID)
+	maintainers, err := s.Store.GetMaintainersByProject(ctx, project.ID)
 	if err != nil {
 		return nil, fmt.Errorf("GetMaintainersByProject: %w", err)
 	}
@@ -521,20 +700,21 @@ func (s *EventListener) addProjectMaintainersToFossaTeam(project model.Project,
 		return actions, fmt.Errorf("FetchTeamUserEmails: %w", err)
 	}
 
-	const FossaTeamAdmin = 3
-	roleId := FossaTeamAdmin
+	role := s.fossaRolePolicy(ctx, project).Maintainer
+	invitations := s.listInvitations()
 
 	// Iterate maintainers
 	for _, m := range maintainers {
 		handle := m.GitHubAccount
 		email := m.Email
 		// Verify acceptance: ensure no pending invitation for email
-		pending, pendErr := s.FossaClient.HasPendingInvitation(email)
-		if pendErr != nil {
-			log.Printf("addProjectMaintainersToFossaTeam: WRN, checking pending invite for %s: %v", handle, pendErr)
-		}
-		if pending {
-			actions = append(actions, fmt.Sprintf("@%s: invitation still pending; skipped", handle))
+		if inv, ok := invitations[strings.ToLower(email)]; ok && !inv.IsExpired(time.Now()) {
+			if refreshed := s.refreshStaleInvitation(m, inv); refreshed != nil {
+				actions = append(actions, fmt.Sprintf("@%s: invitation still pending; a new invitation was sent and expires on %s",
+					handle, formatExpiry(refreshed.Expiry())))
+				continue
+			}
+			actions = append(actions, fmt.Sprintf("@%s: invitation still pending, it expires on %s; skipped", handle, formatExpiry(inv.Expiry())))
 			continue
 		}
 		// Check membership
@@ -542,8 +722,8 @@ func (s *EventListener) addProjectMaintainersToFossaTeam(project model.Project,
 			actions = append(actions, fmt.Sprintf("@%s: already a member; no action", handle))
 			continue
 		}
-		// Attempt to add to team as Team Admin
-		if err := s.FossaClient.AddUserToTeamByEmail(teamID, email, roleId); err != nil {
+		// Attempt to add to team with the role the project's policy gives maintainers
+		if err := s.FossaClient.AddUserToTeamByEmail(teamID, email, int(role)); err != nil {
 			if errors.Is(err, fossa.ErrUserAlreadyMember) {
 				actions = append(actions, fmt.Sprintf("@%s: already a member; no action", handle))
 				continue
@@ -552,12 +732,12 @@ func (s *EventListener) addProjectMaintainersToFossaTeam(project model.Project,
 			log.Printf("addProjectMaintainersToFossaTeam: ERR, add user @%s: %v", handle, err)
 			continue
 		}
-		actions = append(actions, fmt.Sprintf("@%s: added to FOSSA team %s as Team Admin", handle, project.Name))
+		actions = append(actions, fmt.Sprintf("@%s: added to FOSSA team %s as %s", handle, project.Name, role))
 		// Write audit log (best-effort)
 		// NOTE: ServiceID is optional; we omit or could set to FOSSA ID if available.
 		if s.Store != nil {
 			lg := zapNewNopSugar()
-			s.Store.LogAuditEvent(lg, model.AuditLog{
+			s.Store.LogAuditEvent(ctx, lg, model.AuditLog{
 				ProjectID:    project.ID,
 				MaintainerID: &m.ID,
 				Action:       "FOSSA_ADD_MEMBER",
@@ -1,15 +1,22 @@
 package onboarding
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/google/go-github/v55/github"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"maintainerd/db"
+	"maintainerd/db/dbtest"
 	"maintainerd/model"
+	"maintainerd/plugins/fossa"
 )
 
 func TestMockInfrastructure(t *testing.T) {
@@ -173,6 +180,31 @@ func TestFossaChosen_Basic(t *testing.T) {
 		assert.Contains(t, comments[0].Body, "Invitation(s) to join CNCF FOSSA sent to")
 	})
 
+	t.Run("stale pending invitation is resent", func(t *testing.T) {
+		db := setupTestDB(t)
+		project, _ := seedProjectData(t, db)
+
+		mockFossa := NewMockFossaClient()
+		require.NoError(t, mockFossa.SendUserInvitation("alice@example.com"))
+		mockFossa.SetInvitationSentAt("alice@example.com", time.Now().Add(-6*24*time.Hour))
+
+		mockGitHub := NewMockGitHubTransport()
+		server := createTestServer(t, db, mockFossa, mockGitHub)
+
+		issueEvent := createIssueLabeledEvent(project.Name, "fossa", 42)
+		req, _ := http.NewRequest("POST", "/webhook", nil)
+
+		server.fossaChosen(project.Name, req, issueEvent)
+
+		comments := mockGitHub.GetCreatedComments()
+		require.Len(t, comments, 1)
+		expiry := formatExpiry(time.Now().Add(fossa.DefaultInvitationTTL))
+		assert.Contains(t, comments[0].Body, "@alice your invitation expires on "+expiry)
+		assert.Contains(t, comments[0].Body, "@bob your invitation expires on "+expiry)
+		assert.Equal(t, []string{"alice@example.com"}, mockFossa.GetInvitationsResent())
+		assert.Equal(t, 1, mockFossa.GetInvitationLookups(), "invitations are listed once per run")
+	})
+
 	t.Run("maintainer already exists in FOSSA", func(t *testing.T) {
 		// Setup
 		db := setupTestDB(t)
@@ -214,7 +246,7 @@ func TestSignProjectUpForFOSSA_CreateTeamFailure(t *testing.T) {
 	server := createTestServer(t, db, mockFossa, mockGitHub)
 
 	assert.NotPanics(t, func() {
-		_, err := server.signProjectUpForFOSSA(project)
+		_, err := server.signProjectUpForFOSSA(t.Context(), project)
 		assert.Error(t, err)
 	})
 }
@@ -305,6 +337,42 @@ func TestLabelCommand(t *testing.T) {
 		assert.Contains(t, comments[0].Body, "not yet been registered")
 	})
 
+	t.Run("issue assignee who is not registered", func(t *testing.T) {
+		database := setupTestDB(t)
+		project, _ := seedProjectData(t, database)
+		mockGitHub := NewMockGitHubTransport()
+		server := createTestServer(t, database, NewMockFossaClient(), mockGitHub)
+
+		event := createIssueCommentEvent(project.Name, "/label fossa", "dave", 104, []string{"dave"})
+		req, _ := http.NewRequest("POST", "/webhook", nil)
+
+		server.handleLabelCommand(req, event)
+
+		assert.Empty(t, mockGitHub.GetAddedLabels(), "/label provisions services, so assignees may not run it")
+		comments := mockGitHub.GetCreatedComments()
+		require.Len(t, comments, 1)
+		assert.Contains(t, comments[0].Body, "not yet been registered")
+	})
+
+	t.Run("staff member matched by GitHub user ID", func(t *testing.T) {
+		database := setupTestDB(t)
+		project, _ := seedProjectData(t, database)
+		id := int64(303)
+		require.NoError(t, database.Create(&model.StaffMember{Name: "Carol Staff", GitHubAccount: "carol", GitHubID: &id}).Error)
+		mockGitHub := NewMockGitHubTransport()
+		server := createTestServer(t, database, NewMockFossaClient(), mockGitHub)
+
+		event := createIssueCommentEvent(project.Name, "/label fossa", "carol-renamed", 105, nil)
+		event.Sender.ID = github.Int64(303)
+		req, _ := http.NewRequest("POST", "/webhook", nil)
+
+		server.handleLabelCommand(req, event)
+
+		labels := mockGitHub.GetAddedLabels()
+		require.Len(t, labels, 1)
+		assert.Contains(t, labels[0].Labels, "fossa")
+	})
+
 	t.Run("invalid label name", func(t *testing.T) {
 		// Setup
 		database := setupTestDB(t)
@@ -431,16 +499,249 @@ func TestFossaInviteAcceptedAuthorization(t *testing.T) {
 
 		server := createTestServer(t, database, NewMockFossaClient(), NewMockGitHubTransport())
 
-		ok := server.isAuthorizedForProjectAction("carol", project, &github.Issue{})
+		ok := server.isAuthorizedForProjectAction(t.Context(), ghUser("carol", 0), project, &github.Issue{})
 		assert.True(t, ok)
 	})
 
+	t.Run("staff member linked to a GitHub user ID is matched on it", func(t *testing.T) {
+		database := setupTestDB(t)
+		project, _ := seedProjectData(t, database)
+		id := int64(303)
+		require.NoError(t, database.Create(&model.StaffMember{Name: "Carol Staff", GitHubAccount: "carol", GitHubID: &id}).Error)
+
+		server := createTestServer(t, database, NewMockFossaClient(), NewMockGitHubTransport())
+
+		assert.True(t, server.isAuthorizedForProjectAction(t.Context(), ghUser("carol-renamed", 303), project, &github.Issue{}))
+		assert.False(t, server.isAuthorizedForProjectAction(t.Context(), ghUser("carol", 304), project, &github.Issue{}),
+			"whoever takes over a staff member's old login is not staff")
+	})
+
 	t.Run("non staff/non maintainer/non assignee is not authorized", func(t *testing.T) {
 		database := setupTestDB(t)
 		project, _ := seedProjectData(t, database)
 
 		server := createTestServer(t, database, NewMockFossaClient(), NewMockGitHubTransport())
-		ok := server.isAuthorizedForProjectAction("mallory", project, &github.Issue{})
+		ok := server.isAuthorizedForProjectAction(t.Context(), ghUser("mallory", 0), project, &github.Issue{})
 		assert.False(t, ok)
 	})
 }
+
+func TestDestructiveActionAuthorization(t *testing.T) {
+	store := dbtest.NewMemStore()
+	project := store.AddProject(model.Project{Name: "memproject", Maturity: model.Sandbox})
+	alice := store.AddMaintainer(model.Maintainer{Name: "Alice", Email: "alice@example.com", GitHubAccount: "alice",
+		MaintainerStatus: model.ActiveMaintainer}, project.ID)
+	store.AddMaintainer(model.Maintainer{Name: "Bob", Email: "bob@example.com", GitHubAccount: "bob",
+		MaintainerStatus: model.ActiveMaintainer}, project.ID)
+	store.AddStaffMember(model.StaffMember{Name: "Carol", Email: "carol@cncf.io", GitHubAccount: "carol"})
+	server := createTestServerWithStore(t, store, NewMockFossaClient(), NewMockGitHubTransport())
+
+	// Without leads every maintainer may.
+	assert.True(t, server.isAuthorizedForDestructiveAction(t.Context(), ghUser("bob", 0), project, &github.Issue{}))
+
+	roles := model.MembershipRoles{model.RoleLead, model.RoleMaintainer}
+	require.NoError(t, store.SetMembershipRoles(t.Context(), alice.ID, project.ID, roles, time.Now()))
+	assert.True(t, server.isAuthorizedForDestructiveAction(t.Context(), ghUser("alice", 0), project, &github.Issue{}))
+	assert.False(t, server.isAuthorizedForDestructiveAction(t.Context(), ghUser("bob", 0), project, &github.Issue{}))
+	assert.True(t, server.isAuthorizedForDestructiveAction(t.Context(), ghUser("carol", 0), project, &github.Issue{}))
+	assigned := &github.Issue{Assignees: []*github.User{{Login: github.String("mallory")}}}
+	assert.False(t, server.isAuthorizedForDestructiveAction(t.Context(), ghUser("mallory", 0), project, assigned))
+}
+
+func TestAuthorizationByGitHubID(t *testing.T) {
+	store := dbtest.NewMemStore()
+	project := store.AddProject(model.Project{Name: "memproject", Maturity: model.Sandbox})
+	alice := store.AddMaintainer(model.Maintainer{Name: "Alice", Email: "alice@example.com", GitHubAccount: "alice",
+		MaintainerStatus: model.ActiveMaintainer}, project.ID)
+	store.AddMaintainer(model.Maintainer{Name: "Bob", Email: "bob@example.com", GitHubAccount: "bob",
+		MaintainerStatus: model.ActiveMaintainer}, project.ID)
+	_, err := store.AddMaintainerIdentity(t.Context(), model.MaintainerIdentity{MaintainerID: alice.ID,
+		Kind: model.IdentityGitHubID, Value: "101", Verified: true})
+	require.NoError(t, err)
+	server := createTestServerWithStore(t, store, NewMockFossaClient(), NewMockGitHubTransport())
+
+	assert.True(t, server.isAuthorizedForProjectAction(t.Context(), ghUser("alice", 101), project, nil))
+	assert.True(t, server.isAuthorizedForProjectAction(t.Context(), ghUser("alice-renamed", 101), project, nil),
+		"a renamed account keeps access")
+	assert.False(t, server.isAuthorizedForProjectAction(t.Context(), ghUser("alice", 999), project, nil),
+		"whoever takes over a linked login gains nothing")
+	assert.True(t, server.isAuthorizedForProjectAction(t.Context(), ghUser("BOB", 5), project, nil),
+		"unlinked maintainers are matched on their login, ignoring case")
+
+	assigned := &github.Issue{Assignees: []*github.User{ghUser("dave", 7)}}
+	assert.True(t, server.isAuthorizedForProjectAction(t.Context(), ghUser("dave-renamed", 7), project, assigned))
+	assert.False(t, server.isAuthorizedForProjectAction(t.Context(), ghUser("dave", 8), project, assigned))
+}
+
+func TestAddProjectMaintainersToFossaTeam_PendingInvitations(t *testing.T) {
+	database := setupTestDB(t)
+	project, _ := seedProjectData(t, database)
+
+	mockFossa := NewMockFossaClient()
+	team, err := mockFossa.CreateTeam(project.Name)
+	require.NoError(t, err)
+
+	// alice was invited six days ago and has not accepted; bob was invited just now.
+	require.NoError(t, mockFossa.SendUserInvitation("alice@example.com"))
+	mockFossa.SetInvitationSentAt("alice@example.com", time.Now().Add(-6*24*time.Hour))
+	require.NoError(t, mockFossa.SendUserInvitation("bob@example.com"))
+
+	server := createTestServer(t, database, mockFossa, NewMockGitHubTransport())
+	actions, err := server.addProjectMaintainersToFossaTeam(t.Context(), project, team.ID)
+	require.NoError(t, err)
+	require.Len(t, actions, 2)
+
+	assert.Contains(t, actions[0], "@alice: invitation still pending; a new invitation was sent and expires on")
+	assert.Contains(t, actions[1], "@bob: invitation still pending, it expires on")
+	assert.Equal(t, []string{"alice@example.com"}, mockFossa.GetInvitationsResent())
+	assert.Empty(t, mockFossa.GetMembersAdded(team.ID))
+	assert.Equal(t, 1, mockFossa.GetInvitationLookups(), "invitations are listed once per run")
+}
+
+func TestEnforceFossaRolePolicy(t *testing.T) {
+	t.Run("default policy demotes collaborators and promotes maintainers", func(t *testing.T) {
+		database := setupTestDB(t)
+		project, _ := seedProjectData(t, database)
+		require.NoError(t, database.Create(&model.Collaborator{Name: "Contributor", Email: "contributor@example.com"}).Error)
+
+		mockFossa := NewMockFossaClient()
+		team, err := mockFossa.CreateTeam(project.Name)
+		require.NoError(t, err)
+		mockFossa.SetTeamMember(team.ID, "alice@example.com", fossa.TeamRoleAdmin)
+		mockFossa.SetTeamMember(team.ID, "bob@example.com", fossa.TeamRoleViewer)
+		mockFossa.SetTeamMember(team.ID, "contributor@example.com", fossa.TeamRoleAdmin)
+		mockFossa.SetTeamMember(team.ID, "stranger@example.com", fossa.TeamRoleAdmin)
+
+		server := createTestServer(t, database, mockFossa, NewMockGitHubTransport())
+		actions, err := server.enforceFossaRolePolicy(t.Context(), project, team.ID)
+		require.NoError(t, err)
+		require.Len(t, actions, 2)
+
+		assert.Equal(t, fossa.TeamRoleAdmin, mockFossa.GetTeamMemberRole(team.ID, "alice@example.com"))
+		assert.Equal(t, fossa.TeamRoleAdmin, mockFossa.GetTeamMemberRole(team.ID, "bob@example.com"))
+		assert.Equal(t, fossa.TeamRoleEditor, mockFossa.GetTeamMemberRole(team.ID, "contributor@example.com"))
+		assert.Equal(t, fossa.TeamRoleAdmin, mockFossa.GetTeamMemberRole(team.ID, "stranger@example.com"),
+			"members maintainer-d does not know are left alone")
+		assert.ElementsMatch(t, []string{"bob@example.com", "contributor@example.com"}, mockFossa.GetRoleChanges())
+		for _, a := range actions {
+			assert.NotContains(t, a, "@example.com", "actions must not leak email addresses")
+		}
+	})
+
+	t.Run("project policy overrides the default", func(t *testing.T) {
+		database := setupTestDB(t)
+		project, _ := seedProjectData(t, database)
+
+		require.NoError(t, database.Create(&model.Collaborator{Name: "Contributor", Email: "contributor@example.com"}).Error)
+		require.NoError(t, db.NewSQLStore(database).SetServiceTeamRolePolicy(t.Context(), "FOSSA", model.ServiceTeamRolePolicy{
+			ProjectID:        project.ID,
+			MaintainerRole:   int(fossa.TeamRoleEditor),
+			CollaboratorRole: int(fossa.TeamRoleViewer),
+		}))
+
+		mockFossa := NewMockFossaClient()
+		team, err := mockFossa.CreateTeam(project.Name)
+		require.NoError(t, err)
+		mockFossa.SetTeamMember(team.ID, "alice@example.com", fossa.TeamRoleAdmin)
+		mockFossa.SetTeamMember(team.ID, "contributor@example.com", fossa.TeamRoleEditor)
+
+		server := createTestServer(t, database, mockFossa, NewMockGitHubTransport())
+		_, err = server.enforceFossaRolePolicy(t.Context(), project, team.ID)
+		require.NoError(t, err)
+
+		assert.Equal(t, fossa.TeamRoleEditor, mockFossa.GetTeamMemberRole(team.ID, "alice@example.com"))
+		assert.Equal(t, fossa.TeamRoleViewer, mockFossa.GetTeamMemberRole(team.ID, "contributor@example.com"))
+	})
+}
+
+func TestEnforceFossaRolePolicy_MemStore(t *testing.T) {
+	store := dbtest.NewMemStore()
+	fossaService := store.AddService("FOSSA")
+	project := store.AddProject(model.Project{Name: "memproject", Maturity: model.Sandbox})
+	store.AddMaintainer(model.Maintainer{Name: "Alice", Email: "alice@example.com", GitHubAccount: "alice",
+		MaintainerStatus: model.ActiveMaintainer}, project.ID)
+	store.AddStaffMember(model.StaffMember{Name: "Staff", Email: "staff@cncf.io"})
+	store.AddServiceTeamRolePolicy(model.ServiceTeamRolePolicy{
+		ProjectID: project.ID, ServiceID: fossaService.ID, StaffRole: int(fossa.TeamRoleViewer),
+	})
+
+	mockFossa := NewMockFossaClient()
+	team, err := mockFossa.CreateTeam(project.Name)
+	require.NoError(t, err)
+	mockFossa.SetTeamMember(team.ID, "alice@example.com", fossa.TeamRoleViewer)
+	mockFossa.SetTeamMember(team.ID, "staff@cncf.io", fossa.TeamRoleAdmin)
+
+	server := createTestServerWithStore(t, store, mockFossa, NewMockGitHubTransport())
+	actions, err := server.enforceFossaRolePolicy(t.Context(), project, team.ID)
+	require.NoError(t, err)
+	assert.Len(t, actions, 2)
+	assert.Equal(t, fossa.TeamRoleAdmin, mockFossa.GetTeamMemberRole(team.ID, "alice@example.com"))
+	assert.Equal(t, fossa.TeamRoleViewer, mockFossa.GetTeamMemberRole(team.ID, "staff@cncf.io"))
+	require.Len(t, store.AuditEvents(), 2)
+	assert.Equal(t, "FOSSA_SET_ROLE", store.AuditEvents()[0].Action)
+}
+
+// collaboratorsUnavailable is a store whose collaborators cannot be listed.
+type collaboratorsUnavailable struct {
+	db.Store
+}
+
+func (collaboratorsUnavailable) ListCollaborators(context.Context) ([]model.Collaborator, error) {
+	return nil, errors.New("database is locked")
+}
+
+func TestEnforceFossaRolePolicy_StoreFailure(t *testing.T) {
+	store := dbtest.NewMemStore()
+	project := store.AddProject(model.Project{Name: "memproject", Maturity: model.Sandbox})
+	store.AddMaintainer(model.Maintainer{Name: "Alice", Email: "alice@example.com", GitHubAccount: "alice",
+		MaintainerStatus: model.ActiveMaintainer}, project.ID)
+	store.AddStaffMember(model.StaffMember{Name: "Staff", Email: "staff@cncf.io"})
+
+	mockFossa := NewMockFossaClient()
+	team, err := mockFossa.CreateTeam(project.Name)
+	require.NoError(t, err)
+	mockFossa.SetTeamMember(team.ID, "alice@example.com", fossa.TeamRoleViewer)
+	mockFossa.SetTeamMember(team.ID, "staff@cncf.io", fossa.TeamRoleAdmin)
+
+	server := createTestServerWithStore(t, collaboratorsUnavailable{store}, mockFossa, NewMockGitHubTransport())
+	actions, err := server.enforceFossaRolePolicy(t.Context(), project, team.ID)
+	require.Error(t, err)
+	assert.NotContains(t, err.Error(), "database is locked", "store errors are logged, not posted")
+	assert.Empty(t, actions)
+	assert.Empty(t, mockFossa.GetRoleChanges(), "no role is changed when staff and collaborators are unknown")
+}
+
+func TestHandleFindings(t *testing.T) {
+	store := dbtest.NewMemStore()
+	require.NoError(t, store.ReplaceFindings(t.Context(), []model.Finding{
+		{Rule: "missing-email", Severity: model.SeverityWarning, SubjectKind: "maintainer", SubjectID: 1, Subject: "@alice", Message: "no email address"},
+		{Rule: "invalid-maturity", Severity: model.SeverityError, SubjectKind: "project", SubjectID: 2, Subject: "proj", Message: "maturity \"Beta\" is not valid"},
+	}))
+	server := createTestServerWithStore(t, store, NewMockFossaClient(), NewMockGitHubTransport())
+
+	get := func(url string) []model.Finding {
+		rec := httptest.NewRecorder()
+		server.handleFindings(rec, httptest.NewRequest(http.MethodGet, url, nil))
+		require.Equal(t, http.StatusOK, rec.Code)
+		var findings []model.Finding
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &findings))
+		return findings
+	}
+	assert.Len(t, get("/findings"), 2)
+	errs := get("/findings?severity=error")
+	require.Len(t, errs, 1)
+	assert.Equal(t, "proj", errs[0].Subject)
+	assert.Empty(t, get("/findings?rule=orphan-parent-project"))
+
+	rec := httptest.NewRecorder()
+	server.handleFindings(rec, httptest.NewRequest(http.MethodPost, "/findings", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+
+	// The findings name maintainers, so only the internal listener serves them.
+	rec = httptest.NewRecorder()
+	server.publicMux().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/findings", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	rec = httptest.NewRecorder()
+	server.internalMux().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/findings", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
@@ -1,6 +1,7 @@
 package onboarding
 
 import (
+	"context"
 	"net/http"
 	"testing"
 
@@ -21,17 +22,7 @@ func setupTestDB(t *testing.T) *gorm.DB {
 	})
 	require.NoError(t, err)
 
-	err = database.AutoMigrate(
-		&model.Company{},
-		&model.Foundation{},
-		&model.Project{},
-		&model.Maintainer{},
-		&model.MaintainerProject{},
-		&model.StaffMember{},
-		&model.Service{},
-		&model.ServiceTeam{},
-		&model.AuditLog{},
-	)
+	err = db.Migrate(database)
 	require.NoError(t, err)
 
 	// Create FOSSA service by default since most tests need it
@@ -98,10 +89,13 @@ func seedProjectWithService(t *testing.T, database *gorm.DB, project model.Proje
 
 // createTestServer creates a test EventListener with mocked dependencies
 func createTestServer(t *testing.T, database *gorm.DB, mockFossa *MockFossaClient, mockGitHub *MockGitHubTransport) *EventListener {
-	store := db.NewSQLStore(database)
+	return createTestServerWithStore(t, db.NewSQLStore(database), mockFossa, mockGitHub)
+}
 
+// createTestServerWithStore creates a test EventListener backed by store, e.g. a dbtest.MemStore
+func createTestServerWithStore(t *testing.T, store db.Store, mockFossa *MockFossaClient, mockGitHub *MockGitHubTransport) *EventListener {
 	// Build projects map
-	projectMap, err := store.GetProjectMapByName()
+	projectMap, err := store.GetProjectMapByName(context.Background())
 	require.NoError(t, err)
 
 	httpClient := &http.Client{Transport: mockGitHub}
@@ -163,7 +157,8 @@ func createIssueCommentEvent(projectName, body, author string, issueNum int, ass
 			Body: github.String(body),
 			User: &github.User{Login: github.String(author)},
 		},
-		Issue: issue,
+		Sender: &github.User{Login: github.String(author)},
+		Issue:  issue,
 		Repo: &github.Repository{
 			Owner: &github.User{Login: github.String("cncf")},
 			Name:  github.String("onboarding"),
@@ -171,6 +166,15 @@ func createIssueCommentEvent(projectName, body, author string, issueNum int, ass
 	}
 }
 
+// ghUser returns a GitHub user as webhook payloads carry them; an id of 0 leaves the ID out.
+func ghUser(login string, id int64) *github.User {
+	u := &github.User{Login: github.String(login)}
+	if id != 0 {
+		u.ID = github.Int64(id)
+	}
+	return u
+}
+
 // createWebhookRequest creates a mock HTTP request for webhook testing.
 // TODO: This will be used when testing handleWebhook authorization logic.
 //
@@ -9,7 +9,6 @@ import (
 	"log"
 	"net/http"
 	"net/url"
-	"os"
 	"strconv"
 	"strings"
 	"time"
@@ -39,119 +38,14 @@ func NewClient(token string) *Client {
 	}
 }
 
-// FetchFirstPageOfUsers returns an array of User or an error
-func (c *Client) FetchFirstPageOfUsers() ([]User, error) {
-	req, _ := http.NewRequest("GET", c.APIBase+"/users", nil)
-	req.Header.Set("Authorization", "Bearer "+c.APIKey)
-	req.Header.Set("Accept", "application/json")
-
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer func(Body io.ReadCloser) {
-		err := Body.Close()
-		if err != nil {
-			return
-		}
-	}(resp.Body)
-	body, _ := io.ReadAll(resp.Body)
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("FetchUsers failed: called $s\n\t\t%s – %s", resp.Status, string(body))
-	}
-	var users []User
-	if err := json.NewDecoder(resp.Body).Decode(&users); err != nil {
-		return nil, err
-	}
-	return users, nil
-}
+// FetchUsers returns every user in the organization, reading all pages of GET /api/users.
 func (c *Client) FetchUsers() ([]User, error) {
-	var allUsers []User
-	page := 0
-	count := 100 // Adjust this value as per FOSSA API limits
-	fmt.Printf("")
-	for {
-		// Construct paginated URL
-		usersEndpoint := fmt.Sprintf("%s/users?count=%d&page=%d", c.APIBase, count, page)
-
-		req, err := http.NewRequest("GET", usersEndpoint, nil)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create request: %w", err)
-		}
-		req.Header.Set("Authorization", "Bearer "+c.APIKey)
-		req.Header.Set("Accept", "application/json")
-
-		resp, err := http.DefaultClient.Do(req)
-		if err != nil {
-			return nil, fmt.Errorf("request failed: %w", err)
-		}
-		defer resp.Body.Close()
-
-		// Read body early for error handling/logging
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return nil, fmt.Errorf("failed to read response body: %w", err)
-		}
-
-		if resp.StatusCode != http.StatusOK {
-			return nil, fmt.Errorf("FetchUsers failed: %s\n\t\t%s", resp.Status, string(body))
-		}
-
-		var users []User
-		if err := json.Unmarshal(body, &users); err != nil {
-			return nil, fmt.Errorf("failed to decode response: %w", err)
-		}
-
-		allUsers = append(allUsers, users...)
-
-		// If we got fewer users than count, we’re done
-		if len(users) < count {
-			break
-		}
-		page++
-	}
-	fmt.Printf("FetchUsers page: %d Found %d FOSSA Users\n", page, len(allUsers))
-	return allUsers, nil
-}
-
-// FetchUserInvitations GETs /api/user-invitations - Retrieves all active (non-expired) user invitations for an
-// organization
-func (c *Client) FetchUserInvitations() (string, error) {
-	req, _ := http.NewRequest("GET", c.APIBase+"/user-invitations", nil)
-	req.Header.Set("Authorization", "Bearer "+c.APIKey)
-	req.Header.Set("Accept", "application/json")
-
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return fmt.Sprintf("FetchUserInvitations failed %s\n", err), err
-	}
-	defer func(Body io.ReadCloser) {
-		err := Body.Close()
-		if err != nil {
-			return
-		}
-	}(resp.Body)
-	body, _ := io.ReadAll(resp.Body)
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("FetchUserInvitations failed: called $s\n\t\t%s – %s", resp.Status, string(body))
-	}
-
-	return string(body), nil
-}
-
-// HasPendingInvitation performs a check to see if an active invitation exists for the email.
-// It relies on FetchUserInvitations and searches for the email within the response body to avoid
-// coupling to an unstable API schema.
-func (c *Client) HasPendingInvitation(email string) (bool, error) {
-	log.Printf("HasPendingInvitation: email=%q", email)
-	body, err := c.FetchUserInvitations()
+	users, err := c.Users().Collect()
 	if err != nil {
-		log.Printf("HasPendingInvitation: err=%q", err)
-		return false, err
+		return nil, fmt.Errorf("FetchUsers failed: %w", err)
 	}
-	log.Printf("HasPendingInvitation: body=%q", body)
-	// Case-insensitive substring search; avoids schema assumptions.
-	return strings.Contains(strings.ToLower(body), strings.ToLower(email)), nil
+	log.Printf("FetchUsers: found %d FOSSA users", len(users))
+	return users, nil
 }
 
 // SendUserInvitation uses email to send an invitation to join this org of FOSSA
@@ -173,7 +67,7 @@ func (c *Client) SendUserInvitation(email string) error {
 
 	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
-		return fmt.Errorf("FetchUserInvitations failed %w", err)
+		return fmt.Errorf("SendUserInvitation failed %w", err)
 	}
 	defer func(Body io.ReadCloser) {
 		err := Body.Close()
@@ -216,66 +110,23 @@ func (c *Client) FetchTeam(name string) (*Team, error) {
 	return nil, fmt.Errorf("failed to find team with name %s", name)
 }
 
-// FetchTeams calls GET /api/teams
+// FetchTeams calls GET /api/teams, reading every page
 func (c *Client) FetchTeams() ([]Team, error) {
-	req, _ := http.NewRequest("GET", c.APIBase+"/teams", nil)
-	req.Header.Set("Authorization", "Bearer "+c.APIKey)
-	req.Header.Set("Accept", "application/json")
-
-	resp, err := http.DefaultClient.Do(req)
+	teams, err := c.Teams().Collect()
 	if err != nil {
-		return nil, err
-	}
-	defer func(Body io.ReadCloser) {
-		err := Body.Close()
-		if err != nil {
-			return
-		}
-	}(resp.Body)
-
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("list teams failed: %s – %s", resp.Status, string(body))
-	}
-
-	var teams []Team
-	if err := json.NewDecoder(resp.Body).Decode(&teams); err != nil {
-		return nil, err
+		return nil, fmt.Errorf("list teams failed: %w", err)
 	}
 	return teams, nil
 }
 
-// FetchTeamUserEmails calls GET /api/teams/{id}/members
+// FetchTeamUserEmails calls GET /api/teams/{id}/members, reading every page
 func (c *Client) FetchTeamUserEmails(teamID int) ([]string, error) {
-	var teamMemberEndpoint = fmt.Sprintf("%s/teams/%d/members", c.APIBase, teamID)
-	req, _ := http.NewRequest("GET", teamMemberEndpoint, nil)
-	req.Header.Set("Authorization", "Bearer "+c.APIKey)
-	req.Header.Set("Accept", "application/json")
-
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return nil, err
-	}
-
-	defer func(Body io.ReadCloser) {
-		if err := Body.Close(); err != nil {
-			fmt.Fprintf(os.Stderr, "error closing response body: %v\n", err)
-		}
-	}(resp.Body)
-
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("list team users failed: %s – %s", resp.Status, string(body))
-	}
 	var emails []string
-	var members TeamMembers
-	if err := json.NewDecoder(resp.Body).Decode(&members); err != nil {
-		return nil, fmt.Errorf("list team users failed json.NewDecoder returned: %s\nwhen trying to decode %s", err, resp.Body)
-	}
-	if members.TotalCount > 0 {
-		for _, result := range members.Results {
-			emails = append(emails, result.Email)
+	for member, err := range c.TeamMembers(teamID).All() {
+		if err != nil {
+			return nil, fmt.Errorf("list team users failed: %w", err)
 		}
+		emails = append(emails, member.Email)
 	}
 	return emails, nil
 }
@@ -302,10 +153,10 @@ func (c *Client) AddUserToTeamByEmail(teamID int, email string, roleID int) erro
 		},
 		"action": "add",
 	}
-	// Let's try defaulting the role
-	// if roleID != 0 {
-	//	bodyPayload["users"].([]map[string]interface{})[0]["roleId"] = roleID
-	//}
+	// Without a role the member takes the team's default role.
+	if roleID != 0 {
+		bodyPayload["users"].([]map[string]interface{})[0]["roleId"] = roleID
+	}
 	jsonBody, err := json.Marshal(bodyPayload)
 	if err != nil {
 		return fmt.Errorf("failed to encode body: %w", err)
@@ -345,19 +196,65 @@ func (c *Client) AddUserToTeamByEmail(teamID int, email string, roleID int) erro
 	return fmt.Errorf("AddUserToTeamByEmail failed: %s – %s", resp.Status, string(body))
 }
 
-// findUserIDByEmail searches the user list for a matching email and returns the user ID.
-func (c *Client) findUserIDByEmail(email string) (int, error) {
-	log.Printf("findUserIDByEmail: email=%q", email)
-	users, err := c.FetchUsers()
+// RemoveUserFromTeamByEmail removes the user with the given email from a FOSSA team. The user remains a member of the
+// organization.
+func (c *Client) RemoveUserFromTeamByEmail(teamID int, email string) error {
+	uid, err := c.findUserIDByEmail(email)
+	if err != nil {
+		return fmt.Errorf("resolve user by email: %w", err)
+	}
+	return c.putTeamUsers("RemoveUserFromTeamByEmail", teamID, map[string]interface{}{"id": uid}, "remove")
+}
+
+// putTeamUsers applies action (add, update or remove) to a single user via PUT /api/teams/{id}/users. op names the
+// caller in returned errors.
+func (c *Client) putTeamUsers(op string, teamID int, user map[string]interface{}, action string) error {
+	bodyPayload := map[string]interface{}{
+		"users":  []map[string]interface{}{user},
+		"action": action,
+	}
+	jsonBody, err := json.Marshal(bodyPayload)
 	if err != nil {
-		return 0, err
+		return fmt.Errorf("failed to encode body: %w", err)
+	}
+	req, err := http.NewRequest("PUT", fmt.Sprintf("%s/teams/%d/users", c.APIBase, teamID), bytes.NewBuffer(jsonBody))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
 	}
+	req.Header.Set("Authorization", "Bearer "+c.APIKey)
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+
+	if resp.StatusCode == http.StatusOK || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	var fossaErr Error
+	if err := json.Unmarshal(body, &fossaErr); err == nil && fossaErr.Code != 0 {
+		return fmt.Errorf("%s failed (code %d): %s – %s", op, fossaErr.Code, resp.Status, fossaErr.Message)
+	}
+	return fmt.Errorf("%s failed: %s – %s", op, resp.Status, string(body))
+}
+
+// findUserIDByEmail pages through the user list for a matching email and returns the user ID.
+func (c *Client) findUserIDByEmail(email string) (int, error) {
+	log.Printf("findUserIDByEmail: email=%q", email)
 	target := normalizeEmail(email)
 	if target == "" {
 		return 0, fmt.Errorf("user not found by email: %s", email)
 	}
 
-	for _, u := range users {
+	// Stop paging as soon as the user is found.
+	for u, err := range c.Users().All() {
+		if err != nil {
+			return 0, err
+		}
 		if normalizeEmail(u.Email) == target {
 			return u.ID, nil
 		}
@@ -488,58 +385,45 @@ func (c *Client) CreateTeam(name string) (*Team, error) {
 }
 
 // FetchImportedRepos is a function that returns an ImportedProjects struct for the FOSSA Team associated with teamID.
-// returns the number of repos imported and the only first page of imported project records.
+// returns the number of repos imported and every imported project record, reading all pages.
 func (c *Client) FetchImportedRepos(teamID int) (int, ImportedProjects, error) {
 	team, err := c.GetTeam(teamID)
-	repoCount := 0
 	if err != nil {
 		return 0, ImportedProjects{}, fmt.Errorf("call to c.GetTeam(%d) returned %w", teamID, err)
 	}
 	if team == nil {
 		return 0, ImportedProjects{}, fmt.Errorf("team not found %d", teamID)
 	}
-	req, err := http.NewRequest("GET", c.APIBase+"/teams/"+strconv.Itoa(teamID)+"/projects", nil)
 
+	pager := c.TeamProjects(teamID)
+	projects, err := pager.Collect()
 	if err != nil {
-		return 0, ImportedProjects{}, fmt.Errorf("failed to create request: %w", err)
+		return 0, ImportedProjects{}, fmt.Errorf("FetchImportedRepos failed %w", err)
 	}
-	req.Header.Set("Authorization", "Bearer "+c.APIKey)
-	req.Header.Set("Accept", "application/json")
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return repoCount, ImportedProjects{}, fmt.Errorf("FetchImportedRepos failed %w", err)
+	repoCount := pager.TotalCount()
+	if repoCount < len(projects) {
+		repoCount = len(projects)
 	}
-	defer func(Body io.ReadCloser) {
-		err := Body.Close()
-		if err != nil {
-			return
-		}
-	}(resp.Body)
+	return repoCount, ImportedProjects{
+		Results:    projects,
+		PageSize:   len(projects),
+		TotalCount: repoCount,
+	}, nil
+}
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return repoCount, ImportedProjects{}, fmt.Errorf("failed to read response body: %w", err)
-	}
-	var repos ImportedProjects
-	if err := json.Unmarshal(body, &repos); err != nil {
-		return repoCount, ImportedProjects{}, fmt.Errorf("failed to decode response: %w", err)
-	}
-	repoCount = repos.TotalCount
-	return repoCount, repos, nil
+// TeamMember models a single record from GET /api/teams/{id}/members
+type TeamMember struct {
+	UserID   int      `json:"userId"`
+	RoleID   TeamRole `json:"roleId"`
+	Username string   `json:"username"`
+	Email    string   `json:"email"`
 }
 
 type TeamMembers struct {
-	Results []struct {
-		UserID   int    `json:"userId"`
-		RoleID   int    `json:"roleId"`
-		Username string `json:"username"`
-		Email    string `json:"email"`
-	} `json:"results"`
-	PageSize   int `json:"pageSize"`
-	Page       int `json:"page"`
-	TotalCount int `json:"totalCount"`
+	Results    []TeamMember `json:"results"`
+	PageSize   int          `json:"pageSize"`
+	Page       int          `json:"page"`
+	TotalCount int          `json:"totalCount"`
 }
 
 // Team models a single team object from GET /api/teams
@@ -616,14 +500,17 @@ type User struct {
 	} `json:"organization"`
 }
 
+// ImportedProject models a single record from GET /api/teams/{id}/projects
+type ImportedProject struct {
+	Title   string `json:"title"`
+	Locator string `json:"locator"`
+}
+
 type ImportedProjects struct {
-	Results []struct {
-		Title   string `json:"title"`
-		Locator string `json:"locator"`
-	} `json:"results"`
-	PageSize   int `json:"pageSize"`
-	Page       int `json:"page"`
-	TotalCount int `json:"totalCount"`
+	Results    []ImportedProject `json:"results"`
+	PageSize   int               `json:"pageSize"`
+	Page       int               `json:"page"`
+	TotalCount int               `json:"totalCount"`
 }
 
 type Error struct {
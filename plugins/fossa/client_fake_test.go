@@ -0,0 +1,183 @@
+package fossa_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"maintainerd/plugins/fossa"
+	"maintainerd/plugins/fossa/fossatest"
+)
+
+func TestClientAgainstFakeServer_Teams(t *testing.T) {
+	server := fossatest.NewServer(t)
+	client := server.NewClient()
+
+	team, err := client.CreateTeam("Keycloak")
+	require.NoError(t, err)
+	assert.Equal(t, "Keycloak", team.Name)
+
+	again, err := client.CreateTeam("Keycloak")
+	require.NoError(t, err, "team-already-exists (2003) resolves to the existing team")
+	assert.Equal(t, team.ID, again.ID)
+
+	server.AddTeamProject(team.ID, "keycloak", "git+github.com/keycloak/keycloak")
+	server.AddTeamProject(team.ID, "keycloak-operator", "git+github.com/keycloak/keycloak-operator")
+	count, projects, err := client.FetchImportedRepos(team.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+	assert.Equal(t, "keycloak-operator", projects.Results[1].Title)
+
+	teams, err := client.FetchTeamsMap()
+	require.NoError(t, err)
+	assert.Contains(t, teams, "Keycloak")
+}
+
+func TestClientAgainstFakeServer_Members(t *testing.T) {
+	server := fossatest.NewServer(t)
+	client := server.NewClient()
+	team := server.AddTeam("Argo")
+	alice := server.AddUser("alice@example.com", "alice")
+
+	require.NoError(t, client.AddUserToTeamByEmail(team.ID, "Alice@Example.com", 0))
+	err := client.AddUserToTeamByEmail(team.ID, "alice@example.com", 0)
+	assert.ErrorIs(t, err, fossa.ErrUserAlreadyMember)
+
+	err = client.AddUserToTeamByEmail(team.ID, "nobody@example.com", 0)
+	assert.ErrorContains(t, err, "user not found by email")
+
+	// A role given to the add is set by the add itself.
+	bob := server.AddUser("bob@example.com", "bob")
+	require.NoError(t, client.AddUserToTeamByEmail(team.ID, "bob@example.com", int(fossa.TeamRoleEditor)))
+	assert.Contains(t, server.TeamMembers(team.ID),
+		fossa.TeamMember{UserID: bob.ID, RoleID: fossa.TeamRoleEditor, Username: "bob", Email: "bob@example.com"})
+	require.NoError(t, client.RemoveUserFromTeamByEmail(team.ID, "bob@example.com"))
+
+	require.NoError(t, client.UpdateTeamMemberRoleByEmail(team.ID, "alice@example.com", fossa.TeamRoleViewer))
+	members, err := client.ListTeamMembersWithRoles(team.ID)
+	require.NoError(t, err)
+	require.Len(t, members, 1)
+	assert.Equal(t, alice.ID, members[0].UserID)
+	assert.Equal(t, fossa.TeamRoleViewer, members[0].RoleID)
+
+	emails, err := client.FetchTeamUserEmails(team.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"alice@example.com"}, emails)
+
+	require.NoError(t, client.RemoveUserFromTeamByEmail(team.ID, "alice@example.com"))
+	assert.Empty(t, server.TeamMembers(team.ID))
+}
+
+func TestClientAgainstFakeServer_Invitations(t *testing.T) {
+	server := fossatest.NewServer(t)
+	client := server.NewClient()
+	server.AddUser("member@example.com", "member")
+
+	require.NoError(t, client.SendUserInvitation("new@example.com"))
+	assert.ErrorIs(t, client.SendUserInvitation("new@example.com"), fossa.ErrInviteAlreadyExists)
+	assert.ErrorIs(t, client.SendUserInvitation("member@example.com"), fossa.ErrUserAlreadyMember)
+
+	pending, err := client.HasPendingInvitation("new@example.com")
+	require.NoError(t, err)
+	assert.True(t, pending)
+
+	sentAt := time.Now().Add(-10 * 24 * time.Hour)
+	server.AddInvitation("stale@example.com", sentAt)
+	pending, err = client.HasPendingInvitation("stale@example.com")
+	require.NoError(t, err)
+	assert.False(t, pending, "an invitation past its TTL is not pending")
+
+	fresh, err := client.ResendInvitation("stale@example.com")
+	require.NoError(t, err)
+	assert.True(t, fresh.CreatedAt.After(sentAt))
+	assert.Len(t, server.Invitations(), 2)
+
+	require.NoError(t, client.RevokeInvitation("new@example.com"))
+	_, err = client.GetInvitation("new@example.com")
+	assert.ErrorIs(t, err, fossa.ErrInvitationNotFound)
+
+	_, ok := server.AcceptInvitation("stale@example.com")
+	require.True(t, ok)
+	assert.Empty(t, server.Invitations())
+}
+
+func TestClientAgainstFakeServer_Faults(t *testing.T) {
+	t.Run("transient server error", func(t *testing.T) {
+		server := fossatest.NewServer(t)
+		client := server.NewClient()
+		server.AddUser("alice@example.com", "alice")
+		server.Fail(http.MethodGet, "/users", fossatest.Fault{Status: http.StatusBadGateway, Times: 1})
+
+		_, err := client.FetchUsers()
+		assert.ErrorContains(t, err, "502")
+		users, err := client.FetchUsers()
+		require.NoError(t, err)
+		assert.Len(t, users, 1)
+	})
+
+	t.Run("unknown FOSSA error code", func(t *testing.T) {
+		server := fossatest.NewServer(t)
+		client := server.NewClient()
+		server.Fail(http.MethodPost, "/organizations/*", fossatest.Fault{
+			Status: http.StatusForbidden, Code: 1234, Message: "invitations disabled",
+		})
+
+		err := client.SendUserInvitation("new@example.com")
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, fossa.ErrInviteAlreadyExists)
+		assert.ErrorContains(t, err, "code 1234")
+	})
+
+	t.Run("resend keeps the old invitation when sending fails", func(t *testing.T) {
+		server := fossatest.NewServer(t)
+		client := server.NewClient()
+		server.AddInvitation("stale@example.com", time.Now().Add(-6*24*time.Hour))
+		server.Fail(http.MethodPost, "/organizations/*", fossatest.Fault{Status: http.StatusBadGateway, Times: 1})
+
+		_, err := client.ResendInvitation("stale@example.com")
+		require.Error(t, err)
+		assert.Len(t, server.Invitations(), 1, "the invitation is only revoked once FOSSA reports it exists")
+		for _, r := range server.Requests() {
+			assert.NotEqual(t, http.MethodDelete, r.Method)
+		}
+
+		fresh, err := client.ResendInvitation("stale@example.com")
+		require.NoError(t, err)
+		assert.WithinDuration(t, time.Now(), fresh.CreatedAt, time.Minute)
+		assert.Len(t, server.Invitations(), 1)
+	})
+
+	t.Run("malformed body", func(t *testing.T) {
+		server := fossatest.NewServer(t)
+		client := server.NewClient()
+		server.Fail(http.MethodGet, "/teams", fossatest.Fault{Status: http.StatusOK, Body: `{"results": [`})
+
+		_, err := client.FetchTeams()
+		assert.ErrorContains(t, err, "failed to decode")
+	})
+
+	t.Run("bad token", func(t *testing.T) {
+		server := fossatest.NewServer(t)
+		client := fossa.NewClient("wrong")
+		client.APIBase = server.URL
+
+		_, err := client.FetchTeams()
+		assert.ErrorContains(t, err, "401")
+	})
+
+	t.Run("requests are recorded", func(t *testing.T) {
+		server := fossatest.NewServer(t)
+		client := server.NewClient()
+		team := server.AddTeam("Argo")
+		server.AddUser("alice@example.com", "alice")
+
+		require.NoError(t, client.AddUserToTeamByEmail(team.ID, "alice@example.com", 0))
+		requests := server.Requests()
+		last := requests[len(requests)-1]
+		assert.Equal(t, http.MethodPut, last.Method)
+		assert.JSONEq(t, `{"action":"add","users":[{"id":2}]}`, last.Body)
+	})
+}
@@ -6,7 +6,7 @@ import (
 	"testing"
 )
 
-func TestFetchUserInvitations_Live(t *testing.T) {
+func TestListInvitations_Live(t *testing.T) {
 	apiKey := os.Getenv("FOSSA_API_TOKEN")
 	if apiKey == "" {
 		t.Skip("FOSSA_API_TOKEN not set; skipping live API test")
@@ -14,10 +14,12 @@ func TestFetchUserInvitations_Live(t *testing.T) {
 
 	client := fossa.NewClient(apiKey)
 
-	body, err := client.FetchUserInvitations()
+	invitations, err := client.ListInvitations()
 	if err != nil {
-		t.Fatalf("FetchUserInvitations returned error: %v", err)
+		t.Fatalf("ListInvitations returned error: %v", err)
 	}
 
-	t.Logf("FetchUserInvitations response: %s", body)
+	for _, inv := range invitations {
+		t.Logf("invitation %d sent %s, expires %s", inv.ID, inv.CreatedAt, inv.Expiry())
+	}
 }
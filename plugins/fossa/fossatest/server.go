@@ -0,0 +1,650 @@
+// Package fossatest provides an in-memory stand-in for the FOSSA REST API, built on net/http/httptest, so that
+// fossa.Client can be exercised end to end without a FOSSA token or network access.
+//
+// The server implements the endpoints maintainer-d uses: users, teams, team members, team projects and user
+// invitations. State is seeded with the Add* helpers and inspected with the matching getters. Faults (HTTP status
+// codes, FOSSA error codes, malformed bodies and latency) can be injected per endpoint with Fail.
+package fossatest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"maintainerd/plugins/fossa"
+)
+
+// DefaultToken is the API token the server accepts unless Server.Token is changed.
+const DefaultToken = "fossatest-token"
+
+// DefaultOrganizationID is the organization every seeded user, team and invitation belongs to. It matches the
+// organization ID fossa.Client uses when sending invitations.
+const DefaultOrganizationID = 162
+
+// FOSSA error codes returned by the server in addition to those exported by package fossa.
+const (
+	ErrCodeTeamAlreadyExists = 2003
+	ErrCodeNotFound          = 1004
+)
+
+// Fault describes an error the server returns instead of handling a request normally.
+type Fault struct {
+	// Status is the HTTP status code to return. Defaults to 500.
+	Status int
+	// Code and Message, when Code is non-zero, are returned as a FOSSA error body.
+	Code    int
+	Message string
+	// Body, when set, is written verbatim instead of a FOSSA error body, e.g. to return malformed JSON.
+	Body string
+	// Delay is slept before responding.
+	Delay time.Duration
+	// Times limits how many requests the fault applies to. Zero means every matching request.
+	Times int
+}
+
+type fault struct {
+	method string
+	path   string
+	Fault
+	hits int
+}
+
+// Request records a request received by the server.
+type Request struct {
+	Method string
+	Path   string
+	Query  string
+	Body   string
+}
+
+type teamState struct {
+	team     fossa.Team
+	members  map[int]fossa.TeamRole // keyed by user ID
+	projects []fossa.ImportedProject
+}
+
+// Server is an in-memory fake of the FOSSA API. Point a fossa.Client at it by setting APIBase to URL, or use
+// NewClient.
+type Server struct {
+	*httptest.Server
+
+	// Token is the bearer token requests must present.
+	Token string
+
+	mu          sync.Mutex
+	nextID      int
+	users       map[int]*fossa.User
+	teams       map[int]*teamState
+	invitations map[int]*fossa.Invitation
+	faults      []*fault
+	requests    []Request
+	now         func() time.Time
+}
+
+// NewServer starts a fake FOSSA server. It is shut down when the test finishes.
+func NewServer(t interface{ Cleanup(func()) }) *Server {
+	s := &Server{
+		Token:       DefaultToken,
+		nextID:      1,
+		users:       map[int]*fossa.User{},
+		teams:       map[int]*teamState{},
+		invitations: map[int]*fossa.Invitation{},
+		now:         time.Now,
+	}
+	s.Server = httptest.NewServer(s.routes())
+	t.Cleanup(s.Close)
+	return s
+}
+
+// NewClient returns a fossa.Client talking to the server.
+func (s *Server) NewClient() *fossa.Client {
+	client := fossa.NewClient(s.Token)
+	client.APIBase = s.URL
+	return client
+}
+
+// SetClock replaces the clock used to timestamp users, teams and invitations.
+func (s *Server) SetClock(now func() time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.now = now
+}
+
+// Fail makes requests matching method and path return f. path is matched against the request path exactly, or as a
+// prefix when it ends in "*". An empty method matches every method.
+func (s *Server) Fail(method, path string, f Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = append(s.faults, &fault{method: method, path: path, Fault: f})
+}
+
+// ClearFaults removes every injected fault.
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = nil
+}
+
+// Requests returns every request the server has received, oldest first.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+// AddUser adds a member of the organization and returns it.
+func (s *Server) AddUser(email, username string) fossa.User {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return *s.addUserLocked(email, username)
+}
+
+func (s *Server) addUserLocked(email, username string) *fossa.User {
+	now := s.now()
+	u := &fossa.User{
+		ID:             s.id(),
+		Email:          email,
+		Username:       username,
+		OrganizationID: DefaultOrganizationID,
+		Enabled:        true,
+		Joined:         now,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	s.users[u.ID] = u
+	return u
+}
+
+// AddTeam creates a team and returns it.
+func (s *Server) AddTeam(name string) fossa.Team {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.addTeamLocked(name).team
+}
+
+func (s *Server) addTeamLocked(name string) *teamState {
+	now := s.now()
+	ts := &teamState{
+		team: fossa.Team{
+			ID:             s.id(),
+			OrganizationID: DefaultOrganizationID,
+			Name:           name,
+			CreatedAt:      now,
+			UpdatedAt:      now,
+		},
+		members: map[int]fossa.TeamRole{},
+	}
+	s.teams[ts.team.ID] = ts
+	return ts
+}
+
+// AddTeamMember puts the user on the team with role.
+func (s *Server) AddTeamMember(teamID, userID int, role fossa.TeamRole) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if ts, ok := s.teams[teamID]; ok {
+		ts.members[userID] = role
+	}
+}
+
+// AddTeamProject imports a project into the team.
+func (s *Server) AddTeamProject(teamID int, title, locator string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if ts, ok := s.teams[teamID]; ok {
+		ts.projects = append(ts.projects, fossa.ImportedProject{Title: title, Locator: locator})
+	}
+}
+
+// AddInvitation records a pending invitation to email sent at sentAt and returns it.
+func (s *Server) AddInvitation(email string, sentAt time.Time) fossa.Invitation {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return *s.addInvitationLocked(email, sentAt)
+}
+
+func (s *Server) addInvitationLocked(email string, sentAt time.Time) *fossa.Invitation {
+	inv := &fossa.Invitation{
+		ID:             s.id(),
+		Email:          email,
+		OrganizationID: DefaultOrganizationID,
+		CreatedAt:      sentAt,
+		UpdatedAt:      sentAt,
+	}
+	s.invitations[inv.ID] = inv
+	return inv
+}
+
+// AcceptInvitation simulates the invitee signing up: the invitation is removed and a user is created for email.
+func (s *Server) AcceptInvitation(email string) (fossa.User, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	inv := s.invitationByEmailLocked(email)
+	if inv == nil {
+		return fossa.User{}, false
+	}
+	delete(s.invitations, inv.ID)
+	return *s.addUserLocked(inv.Email, strings.Split(inv.Email, "@")[0]), true
+}
+
+// Invitations returns every pending invitation ordered by ID.
+func (s *Server) Invitations() []fossa.Invitation {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.invitationsLocked()
+}
+
+// TeamMembers returns the members of the team, with their roles, ordered by user ID.
+func (s *Server) TeamMembers(teamID int) []fossa.TeamMember {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.teamMembersLocked(teamID)
+}
+
+// Teams returns every team ordered by ID.
+func (s *Server) Teams() []fossa.Team {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.teamsLocked()
+}
+
+func (s *Server) id() int {
+	id := s.nextID
+	s.nextID++
+	return id
+}
+
+func (s *Server) routes() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /users", s.handleListUsers)
+	mux.HandleFunc("GET /teams", s.handleListTeams)
+	mux.HandleFunc("POST /teams", s.handleCreateTeam)
+	mux.HandleFunc("GET /teams/{id}", s.handleGetTeam)
+	mux.HandleFunc("GET /teams/{id}/members", s.handleListTeamMembers)
+	mux.HandleFunc("PUT /teams/{id}/users", s.handleUpdateTeamUsers)
+	mux.HandleFunc("GET /teams/{id}/projects", s.handleListTeamProjects)
+	mux.HandleFunc("GET /user-invitations", s.handleListInvitations)
+	mux.HandleFunc("DELETE /user-invitations/{key}", s.handleDeleteInvitation)
+	mux.HandleFunc("POST /organizations/{org}/invite", s.handleInvite)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body := readBody(r)
+		s.mu.Lock()
+		s.requests = append(s.requests, Request{Method: r.Method, Path: r.URL.Path, Query: r.URL.RawQuery, Body: body})
+		f := s.matchFaultLocked(r)
+		token := s.Token
+		s.mu.Unlock()
+
+		if f != nil {
+			writeFault(w, f)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer "+token {
+			writeError(w, http.StatusUnauthorized, 0, "Unauthorized", "invalid API token")
+			return
+		}
+		mux.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) matchFaultLocked(r *http.Request) *Fault {
+	for _, f := range s.faults {
+		if f.method != "" && f.method != r.Method {
+			continue
+		}
+		if prefix, ok := strings.CutSuffix(f.path, "*"); ok {
+			if !strings.HasPrefix(r.URL.Path, prefix) {
+				continue
+			}
+		} else if f.path != r.URL.Path {
+			continue
+		}
+		if f.Times > 0 && f.hits >= f.Times {
+			continue
+		}
+		f.hits++
+		out := f.Fault
+		return &out
+	}
+	return nil
+}
+
+func (s *Server) handleListUsers(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	users := make([]fossa.User, 0, len(s.users))
+	for _, u := range s.users {
+		users = append(users, *u)
+	}
+	s.mu.Unlock()
+	sort.Slice(users, func(i, j int) bool { return users[i].ID < users[j].ID })
+	writeJSON(w, http.StatusOK, page(r, users))
+}
+
+func (s *Server) handleListTeams(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, page(r, s.Teams()))
+}
+
+func (s *Server) handleCreateTeam(w http.ResponseWriter, r *http.Request) {
+	var payload struct {
+		Name string `json:"name"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil || payload.Name == "" {
+		writeError(w, http.StatusBadRequest, 0, "BadRequest", "a team name is required")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, ts := range s.teams {
+		if ts.team.Name == payload.Name {
+			writeError(w, http.StatusConflict, ErrCodeTeamAlreadyExists, "TeamAlreadyExists",
+				fmt.Sprintf("a team named %s already exists", payload.Name))
+			return
+		}
+	}
+	writeJSON(w, http.StatusCreated, s.addTeamLocked(payload.Name).team)
+}
+
+func (s *Server) handleGetTeam(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ts := s.teamLocked(w, r)
+	if ts == nil {
+		return
+	}
+	team := ts.team
+	team.TeamProjectsCount = len(ts.projects)
+	for _, m := range s.teamMembersLocked(team.ID) {
+		team.TeamUsers = append(team.TeamUsers, struct {
+			UserID int `json:"userId"`
+			RoleID int `json:"roleId"`
+		}{UserID: m.UserID, RoleID: int(m.RoleID)})
+	}
+	writeJSON(w, http.StatusOK, team)
+}
+
+func (s *Server) handleListTeamMembers(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	ts := s.teamLocked(w, r)
+	if ts == nil {
+		s.mu.Unlock()
+		return
+	}
+	members := s.teamMembersLocked(ts.team.ID)
+	s.mu.Unlock()
+	writeJSON(w, http.StatusOK, envelope(r, members))
+}
+
+func (s *Server) handleUpdateTeamUsers(w http.ResponseWriter, r *http.Request) {
+	var payload struct {
+		Action string `json:"action"`
+		Users  []struct {
+			ID     int `json:"id"`
+			RoleID int `json:"roleId"`
+		} `json:"users"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		writeError(w, http.StatusBadRequest, 0, "BadRequest", "malformed request body")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ts := s.teamLocked(w, r)
+	if ts == nil {
+		return
+	}
+	for _, u := range payload.Users {
+		if _, ok := s.users[u.ID]; !ok {
+			writeError(w, http.StatusNotFound, ErrCodeNotFound, "NotFound", fmt.Sprintf("user %d not found", u.ID))
+			return
+		}
+		_, member := ts.members[u.ID]
+		switch payload.Action {
+		case "add":
+			if member {
+				writeError(w, http.StatusConflict, fossa.ErrCodeUserAlreadyMember, "UserAlreadyMember",
+					fmt.Sprintf("user %d is already a member of team %d", u.ID, ts.team.ID))
+				return
+			}
+			role := fossa.TeamRole(u.RoleID)
+			if role == fossa.TeamRoleDefault {
+				role = fossa.TeamRole(ts.team.DefaultRoleID)
+			}
+			ts.members[u.ID] = role
+		case "update":
+			if !member {
+				writeError(w, http.StatusNotFound, ErrCodeNotFound, "NotFound",
+					fmt.Sprintf("user %d is not a member of team %d", u.ID, ts.team.ID))
+				return
+			}
+			ts.members[u.ID] = fossa.TeamRole(u.RoleID)
+		case "remove":
+			delete(ts.members, u.ID)
+		default:
+			writeError(w, http.StatusBadRequest, 0, "BadRequest", fmt.Sprintf("unknown action %q", payload.Action))
+			return
+		}
+	}
+	ts.team.UpdatedAt = s.now()
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) handleListTeamProjects(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	ts := s.teamLocked(w, r)
+	if ts == nil {
+		s.mu.Unlock()
+		return
+	}
+	projects := append([]fossa.ImportedProject(nil), ts.projects...)
+	s.mu.Unlock()
+	writeJSON(w, http.StatusOK, envelope(r, projects))
+}
+
+func (s *Server) handleListInvitations(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, page(r, s.Invitations()))
+}
+
+// handleDeleteInvitation accepts either the invitation ID or the invitee email, FOSSA documents the latter but
+// fossa.Client uses the former.
+func (s *Server) handleDeleteInvitation(w http.ResponseWriter, r *http.Request) {
+	key := r.PathValue("key")
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var inv *fossa.Invitation
+	if id, err := strconv.Atoi(key); err == nil {
+		inv = s.invitations[id]
+	} else {
+		inv = s.invitationByEmailLocked(key)
+	}
+	if inv == nil {
+		writeError(w, http.StatusNotFound, ErrCodeNotFound, "NotFound", "invitation not found")
+		return
+	}
+	delete(s.invitations, inv.ID)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleInvite(w http.ResponseWriter, r *http.Request) {
+	var payload struct {
+		Email string `json:"email"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil || payload.Email == "" {
+		writeError(w, http.StatusBadRequest, 0, "BadRequest", "an email is required")
+		return
+	}
+	if r.PathValue("org") != strconv.Itoa(DefaultOrganizationID) {
+		writeError(w, http.StatusNotFound, ErrCodeNotFound, "NotFound", "organization not found")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, u := range s.users {
+		if strings.EqualFold(u.Email, payload.Email) {
+			writeError(w, http.StatusConflict, fossa.ErrCodeUserAlreadyMember, "UserAlreadyMember",
+				"user is already a member of the organization")
+			return
+		}
+	}
+	if s.invitationByEmailLocked(payload.Email) != nil {
+		writeError(w, http.StatusConflict, fossa.ErrCodeInviteAlreadyExists, "InviteAlreadyExists",
+			"an invitation has already been sent to this address")
+		return
+	}
+	s.addInvitationLocked(payload.Email, s.now())
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) teamLocked(w http.ResponseWriter, r *http.Request) *teamState {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, 0, "BadRequest", "team id must be numeric")
+		return nil
+	}
+	ts, ok := s.teams[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, ErrCodeNotFound, "NotFound", fmt.Sprintf("team %d not found", id))
+		return nil
+	}
+	return ts
+}
+
+func (s *Server) teamsLocked() []fossa.Team {
+	teams := make([]fossa.Team, 0, len(s.teams))
+	for _, ts := range s.teams {
+		t := ts.team
+		t.TeamProjectsCount = len(ts.projects)
+		teams = append(teams, t)
+	}
+	sort.Slice(teams, func(i, j int) bool { return teams[i].ID < teams[j].ID })
+	return teams
+}
+
+func (s *Server) teamMembersLocked(teamID int) []fossa.TeamMember {
+	ts, ok := s.teams[teamID]
+	if !ok {
+		return nil
+	}
+	members := make([]fossa.TeamMember, 0, len(ts.members))
+	for uid, role := range ts.members {
+		m := fossa.TeamMember{UserID: uid, RoleID: role}
+		if u, ok := s.users[uid]; ok {
+			m.Email = u.Email
+			m.Username = u.Username
+		}
+		members = append(members, m)
+	}
+	sort.Slice(members, func(i, j int) bool { return members[i].UserID < members[j].UserID })
+	return members
+}
+
+func (s *Server) invitationsLocked() []fossa.Invitation {
+	invitations := make([]fossa.Invitation, 0, len(s.invitations))
+	for _, inv := range s.invitations {
+		invitations = append(invitations, *inv)
+	}
+	sort.Slice(invitations, func(i, j int) bool { return invitations[i].ID < invitations[j].ID })
+	return invitations
+}
+
+func (s *Server) invitationByEmailLocked(email string) *fossa.Invitation {
+	for _, inv := range s.invitations {
+		if strings.EqualFold(inv.Email, email) {
+			return inv
+		}
+	}
+	return nil
+}
+
+// window returns the slice bounds of the page requested with the count and page query parameters. Without a
+// count parameter the whole collection is returned, as FOSSA does.
+func window(r *http.Request, total int) (int, int) {
+	count, err := strconv.Atoi(r.URL.Query().Get("count"))
+	if err != nil || count <= 0 {
+		return 0, total
+	}
+	p, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	start := min(max(p, 0)*count, total)
+	return start, min(start+count, total)
+}
+
+// page returns the requested page of items as a bare array, the shape of /users, /teams and /user-invitations.
+func page[T any](r *http.Request, items []T) []T {
+	start, end := window(r, len(items))
+	return append([]T{}, items[start:end]...)
+}
+
+type pageEnvelope[T any] struct {
+	Results    []T `json:"results"`
+	PageSize   int `json:"pageSize"`
+	Page       int `json:"page"`
+	TotalCount int `json:"totalCount"`
+}
+
+// envelope returns the requested page of items wrapped the way /teams/{id}/members and /teams/{id}/projects are.
+func envelope[T any](r *http.Request, items []T) pageEnvelope[T] {
+	start, end := window(r, len(items))
+	p, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	return pageEnvelope[T]{
+		Results:    append([]T{}, items[start:end]...),
+		PageSize:   end - start,
+		Page:       p,
+		TotalCount: len(items),
+	}
+}
+
+func readBody(r *http.Request) string {
+	if r.Body == nil {
+		return ""
+	}
+	body, _ := io.ReadAll(r.Body)
+	_ = r.Body.Close()
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	return string(body)
+}
+
+func writeFault(w http.ResponseWriter, f *Fault) {
+	if f.Delay > 0 {
+		time.Sleep(f.Delay)
+	}
+	status := f.Status
+	if status == 0 {
+		status = http.StatusInternalServerError
+	}
+	switch {
+	case f.Body != "":
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(f.Body))
+	case f.Code != 0:
+		writeError(w, status, f.Code, "InjectedFault", f.Message)
+	default:
+		w.WriteHeader(status)
+	}
+}
+
+func writeError(w http.ResponseWriter, status, code int, name, message string) {
+	writeJSON(w, status, fossa.Error{
+		UUID:           fmt.Sprintf("fossatest-%d", time.Now().UnixNano()),
+		Code:           code,
+		Name:           name,
+		Message:        message,
+		HTTPStatusCode: status,
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
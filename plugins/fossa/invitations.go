@@ -0,0 +1,139 @@
+package fossa
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+)
+
+// DefaultInvitationTTL is how long a FOSSA invitation stays valid when the API does not report an explicit expiry.
+const DefaultInvitationTTL = 7 * 24 * time.Hour
+
+var ErrInvitationNotFound = errors.New("fossa: invitation not found")
+
+// Invitation models a single record from GET /api/user-invitations
+type Invitation struct {
+	ID             int        `json:"id"`
+	Email          string     `json:"email"`
+	OrganizationID int        `json:"organizationId"`
+	InviterID      *int       `json:"inviterId"`
+	CreatedAt      time.Time  `json:"createdAt"`
+	UpdatedAt      time.Time  `json:"updatedAt"`
+	ExpiresAt      *time.Time `json:"expiresAt"`
+}
+
+// Expiry returns when the invitation lapses, falling back to CreatedAt plus DefaultInvitationTTL.
+func (i Invitation) Expiry() time.Time {
+	if i.ExpiresAt != nil && !i.ExpiresAt.IsZero() {
+		return *i.ExpiresAt
+	}
+	return i.CreatedAt.Add(DefaultInvitationTTL)
+}
+
+// Age returns how long ago the invitation was sent, relative to now.
+func (i Invitation) Age(now time.Time) time.Duration {
+	return now.Sub(i.CreatedAt)
+}
+
+// IsExpired reports whether the invitation has lapsed at now.
+func (i Invitation) IsExpired(now time.Time) bool {
+	return !now.Before(i.Expiry())
+}
+
+// Invitations returns a Paginator over GET /api/user-invitations.
+func (c *Client) Invitations() *Paginator[Invitation] {
+	return newPaginator[Invitation](c, "/user-invitations", nil)
+}
+
+// ListInvitations GETs /api/user-invitations - Retrieves all active (non-expired) user invitations for an
+// organization, reading every page.
+func (c *Client) ListInvitations() ([]Invitation, error) {
+	invitations, err := c.Invitations().Collect()
+	if err != nil {
+		return nil, fmt.Errorf("ListInvitations failed: %w", err)
+	}
+	return invitations, nil
+}
+
+// GetInvitation returns the invitation sent to email, or ErrInvitationNotFound if there is none.
+func (c *Client) GetInvitation(email string) (*Invitation, error) {
+	target := normalizeEmail(email)
+	for inv, err := range c.Invitations().All() {
+		if err != nil {
+			return nil, fmt.Errorf("GetInvitation failed: %w", err)
+		}
+		if normalizeEmail(inv.Email) == target {
+			return &inv, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrInvitationNotFound, email)
+}
+
+// HasPendingInvitation performs a check to see if an unexpired invitation exists for the email.
+func (c *Client) HasPendingInvitation(email string) (bool, error) {
+	inv, err := c.GetInvitation(email)
+	if errors.Is(err, ErrInvitationNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		log.Printf("HasPendingInvitation: err=%q", err)
+		return false, err
+	}
+	return !inv.IsExpired(time.Now()), nil
+}
+
+// RevokeInvitation DELETEs /api/user-invitations/{id}, withdrawing the invitation sent to email.
+func (c *Client) RevokeInvitation(email string) error {
+	inv, err := c.GetInvitation(email)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("DELETE", fmt.Sprintf("%s/user-invitations/%d", c.APIBase, inv.ID), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+c.APIKey)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("RevokeInvitation failed %w", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusNoContent:
+		return nil
+	case http.StatusNotFound:
+		return fmt.Errorf("%w: %s", ErrInvitationNotFound, email)
+	default:
+		return fmt.Errorf("RevokeInvitation failed for invitation %d: %s – %s", inv.ID, resp.Status, string(body))
+	}
+}
+
+// ResendInvitation replaces the invitation sent to email with a fresh one, resetting its expiry. A new invitation is
+// sent first; only when FOSSA rejects it because one already exists is the old invitation revoked and the new one sent
+// again, retried once so that a transient failure does not leave the address without any invitation.
+func (c *Client) ResendInvitation(email string) (*Invitation, error) {
+	err := c.SendUserInvitation(email)
+	if errors.Is(err, ErrInviteAlreadyExists) {
+		if err := c.RevokeInvitation(email); err != nil && !errors.Is(err, ErrInvitationNotFound) {
+			return nil, fmt.Errorf("ResendInvitation: %w", err)
+		}
+		if err = c.SendUserInvitation(email); err != nil {
+			log.Printf("ResendInvitation: WRN, sending after revoke failed, retrying: %v", err)
+			err = c.SendUserInvitation(email)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("ResendInvitation: invitation for %s revoked but not replaced: %w", email, err)
+		}
+	} else if err != nil {
+		return nil, fmt.Errorf("ResendInvitation: %w", err)
+	}
+	return c.GetInvitation(email)
+}
@@ -0,0 +1,59 @@
+package fossa_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"maintainerd/plugins/fossa"
+)
+
+func TestInvitationExpiry(t *testing.T) {
+	sent := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	inv := fossa.Invitation{CreatedAt: sent}
+	assert.Equal(t, sent.Add(fossa.DefaultInvitationTTL), inv.Expiry())
+	assert.False(t, inv.IsExpired(sent.Add(time.Hour)))
+	assert.True(t, inv.IsExpired(sent.Add(fossa.DefaultInvitationTTL)))
+	assert.Equal(t, 48*time.Hour, inv.Age(sent.Add(48*time.Hour)))
+
+	explicit := sent.Add(time.Hour)
+	inv.ExpiresAt = &explicit
+	assert.Equal(t, explicit, inv.Expiry())
+}
+
+func TestGetAndRevokeInvitation(t *testing.T) {
+	var deleted string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/user-invitations":
+			_, _ = w.Write([]byte(`[{"id":11,"email":"Alice@Example.com","createdAt":"2025-01-01T00:00:00Z"}]`))
+		case r.Method == http.MethodDelete:
+			deleted = r.URL.Path
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	client := fossa.NewClient("token")
+	client.APIBase = server.URL
+
+	inv, err := client.GetInvitation("alice@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, 11, inv.ID)
+
+	_, err = client.GetInvitation("bob@example.com")
+	assert.ErrorIs(t, err, fossa.ErrInvitationNotFound)
+
+	pending, err := client.HasPendingInvitation("alice@example.com")
+	require.NoError(t, err)
+	assert.False(t, pending, "an invitation sent in 2025 has expired")
+
+	require.NoError(t, client.RevokeInvitation("alice@example.com"))
+	assert.Equal(t, "/user-invitations/11", deleted)
+}
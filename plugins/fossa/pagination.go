@@ -0,0 +1,216 @@
+package fossa
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"iter"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// DefaultPageSize is the number of records requested per page from FOSSA list endpoints.
+const DefaultPageSize = 100
+
+// pageEnvelope is the wrapper FOSSA puts around most paginated list responses. Some endpoints (/users, /teams)
+// return a bare JSON array instead; decodePage accepts both shapes.
+type pageEnvelope[T any] struct {
+	Results    []T `json:"results"`
+	PageSize   int `json:"pageSize"`
+	Page       int `json:"page"`
+	TotalCount int `json:"totalCount"`
+}
+
+// Paginator walks a paginated FOSSA list endpoint one page at a time. Pages are requested with the count and page
+// query parameters, page numbers start at zero.
+//
+// A Paginator stops when FOSSA returns an empty or short page, when the reported totalCount has been reached, or
+// when the server returns the same page twice (i.e. the endpoint ignores the paging parameters).
+type Paginator[T any] struct {
+	client   *Client
+	path     string
+	query    url.Values
+	pageSize int
+
+	page     int
+	seen     int
+	total    int
+	lastBody []byte
+	done     bool
+}
+
+func newPaginator[T any](c *Client, path string, query url.Values) *Paginator[T] {
+	if query == nil {
+		query = url.Values{}
+	}
+	return &Paginator[T]{
+		client:   c,
+		path:     path,
+		query:    query,
+		pageSize: DefaultPageSize,
+		total:    -1,
+	}
+}
+
+// WithPageSize sets the number of records requested per page. Values below 1 are ignored.
+func (p *Paginator[T]) WithPageSize(size int) *Paginator[T] {
+	if size > 0 {
+		p.pageSize = size
+	}
+	return p
+}
+
+// Done reports whether every page has been read.
+func (p *Paginator[T]) Done() bool {
+	return p.done
+}
+
+// TotalCount returns the total number of records reported by FOSSA, or -1 if the endpoint does not report one or
+// no page has been fetched yet.
+func (p *Paginator[T]) TotalCount() int {
+	return p.total
+}
+
+// NextPage fetches and returns the next page of records. Once Done reports true, NextPage returns nil, nil.
+func (p *Paginator[T]) NextPage() ([]T, error) {
+	if p.done {
+		return nil, nil
+	}
+
+	q := url.Values{}
+	for k, v := range p.query {
+		q[k] = v
+	}
+	q.Set("count", strconv.Itoa(p.pageSize))
+	q.Set("page", strconv.Itoa(p.page))
+
+	body, err := p.client.get(p.path, q)
+	if err != nil {
+		p.done = true
+		return nil, err
+	}
+	if p.lastBody != nil && bytes.Equal(body, p.lastBody) {
+		p.done = true
+		return nil, nil
+	}
+	p.lastBody = body
+
+	items, total, err := decodePage[T](body)
+	if err != nil {
+		p.done = true
+		return nil, fmt.Errorf("failed to decode %s page %d: %w", p.path, p.page, err)
+	}
+	if total >= 0 {
+		p.total = total
+	}
+
+	p.page++
+	p.seen += len(items)
+	if len(items) == 0 || len(items) < p.pageSize || (p.total >= 0 && p.seen >= p.total) {
+		p.done = true
+	}
+	return items, nil
+}
+
+// All returns an iterator over every record on every remaining page. Iteration stops after yielding the first
+// error.
+func (p *Paginator[T]) All() iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for !p.done {
+			items, err := p.NextPage()
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+			for _, item := range items {
+				if !yield(item, nil) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Collect reads every remaining page and returns the records in the order FOSSA returned them.
+func (p *Paginator[T]) Collect() ([]T, error) {
+	var all []T
+	for item, err := range p.All() {
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, item)
+	}
+	return all, nil
+}
+
+// decodePage decodes a single page that is either a bare JSON array or a pageEnvelope. total is -1 when the
+// response does not carry a totalCount.
+func decodePage[T any](body []byte) ([]T, int, error) {
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) > 0 && trimmed[0] == '[' {
+		var items []T
+		if err := json.Unmarshal(trimmed, &items); err != nil {
+			return nil, -1, err
+		}
+		return items, -1, nil
+	}
+
+	var env pageEnvelope[T]
+	if err := json.Unmarshal(trimmed, &env); err != nil {
+		return nil, -1, err
+	}
+	return env.Results, env.TotalCount, nil
+}
+
+// get performs an authenticated GET against path (relative to APIBase) and returns the response body, or an error
+// if FOSSA did not answer 200 OK.
+func (c *Client) get(path string, query url.Values) ([]byte, error) {
+	endpoint := c.APIBase + path
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+	req, err := http.NewRequest("GET", endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+c.APIKey)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s failed: %s – %s", path, resp.Status, string(body))
+	}
+	return body, nil
+}
+
+// Users returns a Paginator over GET /api/users.
+func (c *Client) Users() *Paginator[User] {
+	return newPaginator[User](c, "/users", nil)
+}
+
+// Teams returns a Paginator over GET /api/teams.
+func (c *Client) Teams() *Paginator[Team] {
+	return newPaginator[Team](c, "/teams", nil)
+}
+
+// TeamMembers returns a Paginator over GET /api/teams/{id}/members.
+func (c *Client) TeamMembers(teamID int) *Paginator[TeamMember] {
+	return newPaginator[TeamMember](c, fmt.Sprintf("/teams/%d/members", teamID), nil)
+}
+
+// TeamProjects returns a Paginator over GET /api/teams/{id}/projects.
+func (c *Client) TeamProjects(teamID int) *Paginator[ImportedProject] {
+	return newPaginator[ImportedProject](c, fmt.Sprintf("/teams/%d/projects", teamID), nil)
+}
@@ -0,0 +1,126 @@
+package fossa_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"maintainerd/plugins/fossa"
+)
+
+// newPagedServer serves total users from /users as bare arrays and total members from /teams/7/members as
+// envelopes, honouring the count and page query parameters.
+func newPagedServer(t *testing.T, total int) (*httptest.Server, *int) {
+	t.Helper()
+	requests := 0
+	mux := http.NewServeMux()
+	window := func(r *http.Request) (int, int) {
+		count, _ := strconv.Atoi(r.URL.Query().Get("count"))
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		start := page * count
+		end := start + count
+		if start > total {
+			start = total
+		}
+		if end > total {
+			end = total
+		}
+		return start, end
+	}
+	mux.HandleFunc("/users", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		start, end := window(r)
+		users := make([]fossa.User, 0, end-start)
+		for i := start; i < end; i++ {
+			users = append(users, fossa.User{ID: i + 1, Email: fmt.Sprintf("user%d@example.com", i+1)})
+		}
+		require.NoError(t, json.NewEncoder(w).Encode(users))
+	})
+	mux.HandleFunc("/teams/7/members", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		start, end := window(r)
+		members := fossa.TeamMembers{TotalCount: total}
+		for i := start; i < end; i++ {
+			members.Results = append(members.Results, fossa.TeamMember{UserID: i + 1, Email: fmt.Sprintf("member%d@example.com", i+1)})
+		}
+		require.NoError(t, json.NewEncoder(w).Encode(members))
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+func TestPaginatorCollectsEveryPage(t *testing.T) {
+	server, requests := newPagedServer(t, 250)
+	client := fossa.NewClient("token")
+	client.APIBase = server.URL
+
+	users, err := client.FetchUsers()
+	require.NoError(t, err)
+	assert.Len(t, users, 250)
+	assert.Equal(t, 250, users[249].ID)
+	assert.Equal(t, 3, *requests, "a short third page should end pagination")
+}
+
+func TestPaginatorStopsAtTotalCount(t *testing.T) {
+	server, requests := newPagedServer(t, 20)
+	client := fossa.NewClient("token")
+	client.APIBase = server.URL
+
+	pager := client.TeamMembers(7).WithPageSize(10)
+	members, err := pager.Collect()
+	require.NoError(t, err)
+	assert.Len(t, members, 20)
+	assert.Equal(t, 20, pager.TotalCount())
+	assert.True(t, pager.Done())
+	assert.Equal(t, 2, *requests, "totalCount should avoid fetching an empty trailing page")
+}
+
+func TestPaginatorAllStopsEarly(t *testing.T) {
+	server, requests := newPagedServer(t, 500)
+	client := fossa.NewClient("token")
+	client.APIBase = server.URL
+
+	for u, err := range client.Users().WithPageSize(50).All() {
+		require.NoError(t, err)
+		if u.ID == 60 {
+			break
+		}
+	}
+	assert.Equal(t, 2, *requests)
+}
+
+func TestPaginatorStopsWhenServerIgnoresPaging(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		_, _ = w.Write([]byte(`[{"id":1,"name":"a"},{"id":2,"name":"b"}]`))
+	}))
+	defer server.Close()
+	client := fossa.NewClient("token")
+	client.APIBase = server.URL
+
+	teams, err := client.Teams().WithPageSize(2).Collect()
+	require.NoError(t, err)
+	assert.Len(t, teams, 2)
+	assert.Equal(t, 2, calls)
+}
+
+func TestPaginatorReturnsHTTPErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"code":1}`, http.StatusInternalServerError)
+	}))
+	defer server.Close()
+	client := fossa.NewClient("token")
+	client.APIBase = server.URL
+
+	_, err := client.FetchTeamUserEmails(7)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "500")
+}
@@ -0,0 +1,96 @@
+package fossa
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TeamRole is the ID of one of FOSSA's built-in team roles
+// (https://docs.fossa.com/docs/role-based-access-control#team-roles).
+type TeamRole int
+
+const (
+	// TeamRoleDefault leaves the choice of role to the team's default role.
+	TeamRoleDefault TeamRole = 0
+	TeamRoleAdmin   TeamRole = 3
+	TeamRoleEditor  TeamRole = 4
+	TeamRoleViewer  TeamRole = 5
+)
+
+// String returns the role name as shown in the FOSSA UI.
+func (r TeamRole) String() string {
+	switch r {
+	case TeamRoleDefault:
+		return "Team Default"
+	case TeamRoleAdmin:
+		return "Team Admin"
+	case TeamRoleEditor:
+		return "Team Editor"
+	case TeamRoleViewer:
+		return "Team Viewer"
+	}
+	return fmt.Sprintf("Team Role %d", int(r))
+}
+
+// ParseTeamRole accepts a role name (admin, editor, viewer, optionally prefixed with "team") and returns its
+// TeamRole.
+func ParseTeamRole(name string) (TeamRole, error) {
+	n := strings.ToLower(strings.TrimSpace(name))
+	n = strings.TrimPrefix(strings.TrimPrefix(n, "team"), " ")
+	n = strings.TrimPrefix(n, "-")
+	switch n {
+	case "admin":
+		return TeamRoleAdmin, nil
+	case "editor":
+		return TeamRoleEditor, nil
+	case "viewer":
+		return TeamRoleViewer, nil
+	case "default", "":
+		return TeamRoleDefault, nil
+	}
+	return TeamRoleDefault, fmt.Errorf("unknown FOSSA team role %q, expected admin, editor or viewer", name)
+}
+
+// TeamRolePolicy is the role each kind of person associated with a project should hold on the project's FOSSA team.
+type TeamRolePolicy struct {
+	Maintainer   TeamRole
+	Collaborator TeamRole
+	Staff        TeamRole
+}
+
+// DefaultTeamRolePolicy makes registered maintainers and foundation staff Team Admins and everyone else a Team Editor.
+var DefaultTeamRolePolicy = TeamRolePolicy{
+	Maintainer:   TeamRoleAdmin,
+	Collaborator: TeamRoleEditor,
+	Staff:        TeamRoleAdmin,
+}
+
+// ListTeamMembersWithRoles returns every member of the team together with the role they hold.
+func (c *Client) ListTeamMembersWithRoles(teamID int) ([]TeamMember, error) {
+	members, err := c.TeamMembers(teamID).Collect()
+	if err != nil {
+		return nil, fmt.Errorf("ListTeamMembersWithRoles failed for team %d: %w", teamID, err)
+	}
+	return members, nil
+}
+
+// UpdateTeamMemberRole changes the role the member of the team with FOSSA user ID userID holds.
+func (c *Client) UpdateTeamMemberRole(teamID, userID int, role TeamRole) error {
+	if role == TeamRoleDefault {
+		return fmt.Errorf("UpdateTeamMemberRole: a concrete role is required for user %d", userID)
+	}
+	return c.putTeamUsers("UpdateTeamMemberRole", teamID, map[string]interface{}{"id": userID, "roleId": int(role)}, "update")
+}
+
+// UpdateTeamMemberRoleByEmail is UpdateTeamMemberRole for the member with email, which is looked up among the
+// organization's users. Prefer UpdateTeamMemberRole when the user ID is known, e.g. from ListTeamMembersWithRoles.
+func (c *Client) UpdateTeamMemberRoleByEmail(teamID int, email string, role TeamRole) error {
+	if role == TeamRoleDefault {
+		return fmt.Errorf("UpdateTeamMemberRole: a concrete role is required for %s", email)
+	}
+	uid, err := c.findUserIDByEmail(email)
+	if err != nil {
+		return fmt.Errorf("resolve user by email: %w", err)
+	}
+	return c.UpdateTeamMemberRole(teamID, uid, role)
+}
@@ -0,0 +1,64 @@
+package fossa_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"maintainerd/plugins/fossa"
+)
+
+func TestParseTeamRole(t *testing.T) {
+	for name, want := range map[string]fossa.TeamRole{
+		"admin":       fossa.TeamRoleAdmin,
+		"Team Admin":  fossa.TeamRoleAdmin,
+		"team-editor": fossa.TeamRoleEditor,
+		"VIEWER":      fossa.TeamRoleViewer,
+	} {
+		got, err := fossa.ParseTeamRole(name)
+		require.NoError(t, err, name)
+		assert.Equal(t, want, got, name)
+	}
+	_, err := fossa.ParseTeamRole("owner")
+	assert.Error(t, err)
+	assert.Equal(t, "Team Admin", fossa.TeamRoleAdmin.String())
+}
+
+func TestUpdateTeamMemberRole(t *testing.T) {
+	var payload map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/users":
+			_, _ = w.Write([]byte(`[{"id":42,"email":"carol@example.com"}]`))
+		case r.Method == http.MethodPut && r.URL.Path == "/teams/7/users":
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+			w.WriteHeader(http.StatusOK)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	client := fossa.NewClient("token")
+	client.APIBase = server.URL
+
+	require.NoError(t, client.UpdateTeamMemberRoleByEmail(7, "carol@example.com", fossa.TeamRoleViewer))
+	assert.Equal(t, "update", payload["action"])
+	users := payload["users"].([]interface{})
+	require.Len(t, users, 1)
+	user := users[0].(map[string]interface{})
+	assert.EqualValues(t, 42, user["id"])
+	assert.EqualValues(t, fossa.TeamRoleViewer, user["roleId"])
+
+	assert.Error(t, client.UpdateTeamMemberRoleByEmail(7, "carol@example.com", fossa.TeamRoleDefault))
+
+	// By user ID, the organization's users are not paged through.
+	payload = nil
+	require.NoError(t, client.UpdateTeamMemberRole(7, 43, fossa.TeamRoleAdmin))
+	user = payload["users"].([]interface{})[0].(map[string]interface{})
+	assert.EqualValues(t, 43, user["id"])
+	assert.EqualValues(t, fossa.TeamRoleAdmin, user["roleId"])
+}
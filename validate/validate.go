@@ -0,0 +1,237 @@
+// Package validate checks the maintainer registry for data quality problems: missing or malformed contact details,
+// GitHub logins that do not exist, projects without maintainers and broken project fields. Findings are stored with
+// db.Store.ReplaceFindings, which also fills in Maintainer.ImportWarnings.
+package validate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/mail"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/google/go-github/v55/github"
+
+	"maintainerd/db"
+	"maintainerd/model"
+)
+
+// Registry is the data the rules check.
+type Registry struct {
+	Maintainers []model.Maintainer
+	// Projects hold their live maintainers.
+	Projects []model.Project
+}
+
+// Load reads the live maintainers and projects from store.
+func Load(ctx context.Context, store db.Store) (Registry, error) {
+	maintainers, err := store.ListMaintainers(ctx)
+	if err != nil {
+		return Registry{}, fmt.Errorf("list maintainers: %w", err)
+	}
+	byName, err := store.GetProjectMapByName(ctx)
+	if err != nil {
+		return Registry{}, fmt.Errorf("list projects: %w", err)
+	}
+	reg := Registry{Maintainers: maintainers}
+	for _, p := range byName {
+		reg.Projects = append(reg.Projects, p)
+	}
+	sort.Slice(reg.Projects, func(i, j int) bool { return reg.Projects[i].ID < reg.Projects[j].ID })
+	return reg, nil
+}
+
+// A Rule checks the registry for one kind of problem.
+type Rule struct {
+	Name     string
+	Severity model.FindingSeverity
+	// Check returns a message for each subject that breaks the rule.
+	Check func(ctx context.Context, reg Registry) ([]Violation, error)
+}
+
+// A Violation is a maintainer or project that breaks a rule.
+type Violation struct {
+	Kind    db.PersonKind // db.PersonMaintainer, or "project"
+	ID      uint
+	Subject string
+	Message string
+}
+
+// SubjectProject is the Violation.Kind of projects.
+const SubjectProject db.PersonKind = "project"
+
+// GitHubUsers is the part of the GitHub users API the unknown-github-login rule uses.
+type GitHubUsers interface {
+	Get(ctx context.Context, user string) (*github.User, *github.Response, error)
+}
+
+// Rules returns the registry's rules. With users nil the unknown-github-login rule only checks login syntax, so the
+// rules run without GitHub access.
+func Rules(users GitHubUsers) []Rule {
+	return []Rule{
+		{Name: "missing-email", Severity: model.SeverityWarning, Check: missingEmail},
+		{Name: "invalid-email", Severity: model.SeverityError, Check: invalidEmail},
+		{Name: "missing-github", Severity: model.SeverityWarning, Check: missingGitHub},
+		{Name: "unknown-github-login", Severity: model.SeverityError, Check: unknownGitHubLogin(users)},
+		{Name: "project-without-maintainers", Severity: model.SeverityWarning, Check: projectWithoutMaintainers},
+		{Name: "invalid-maturity", Severity: model.SeverityError, Check: invalidMaturity},
+		{Name: "orphan-parent-project", Severity: model.SeverityError, Check: orphanParentProject},
+		{Name: "missing-mailing-list", Severity: model.SeverityWarning, Check: missingMailingList},
+	}
+}
+
+// Run checks reg against rules and returns their findings, in rule order.
+func Run(ctx context.Context, reg Registry, rules []Rule) ([]model.Finding, error) {
+	var findings []model.Finding
+	for _, r := range rules {
+		violations, err := r.Check(ctx, reg)
+		if err != nil {
+			return findings, fmt.Errorf("rule %s: %w", r.Name, err)
+		}
+		for _, v := range violations {
+			findings = append(findings, model.Finding{
+				Rule:        r.Name,
+				Severity:    r.Severity,
+				SubjectKind: string(v.Kind),
+				SubjectID:   v.ID,
+				Subject:     v.Subject,
+				Message:     v.Message,
+			})
+		}
+	}
+	return findings, nil
+}
+
+// maintainerViolation describes m by their GitHub login where they have one; messages never include email addresses,
+// since reports are shared.
+func maintainerViolation(m model.Maintainer, message string) Violation {
+	subject := m.Name
+	if !model.IsMissing(m.GitHubAccount) {
+		subject = "@" + m.GitHubAccount
+	}
+	return Violation{Kind: db.PersonMaintainer, ID: m.ID, Subject: subject, Message: message}
+}
+
+func projectViolation(p model.Project, message string) Violation {
+	return Violation{Kind: SubjectProject, ID: p.ID, Subject: p.Name, Message: message}
+}
+
+func missingEmail(_ context.Context, reg Registry) ([]Violation, error) {
+	var vs []Violation
+	for _, m := range reg.Maintainers {
+		if len(m.Emails()) == 0 {
+			vs = append(vs, maintainerViolation(m, "no email address"))
+		}
+	}
+	return vs, nil
+}
+
+func invalidEmail(_ context.Context, reg Registry) ([]Violation, error) {
+	var vs []Violation
+	for _, m := range reg.Maintainers {
+		invalid := 0
+		for _, e := range m.Emails() {
+			if addr, err := mail.ParseAddress(e); err != nil || addr.Address != e {
+				invalid++
+			}
+		}
+		if invalid > 0 {
+			vs = append(vs, maintainerViolation(m, fmt.Sprintf("%d of %d email addresses are not valid", invalid, len(m.Emails()))))
+		}
+	}
+	return vs, nil
+}
+
+func missingGitHub(_ context.Context, reg Registry) ([]Violation, error) {
+	var vs []Violation
+	for _, m := range reg.Maintainers {
+		if len(m.GitHubAccounts()) == 0 {
+			vs = append(vs, maintainerViolation(m, "no GitHub account"))
+		}
+	}
+	return vs, nil
+}
+
+// gitHubLogin matches the logins GitHub allows: up to 39 letters, digits and single hyphens, not at either end.
+var gitHubLogin = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// unknownGitHubLogin flags logins GitHub would not accept and, with users, logins GitHub does not know. Maintainers
+// already linked to a GitHub user ID are known; only their current login is looked up, older ones may have been
+// renamed away.
+func unknownGitHubLogin(users GitHubUsers) func(context.Context, Registry) ([]Violation, error) {
+	return func(ctx context.Context, reg Registry) ([]Violation, error) {
+		var vs []Violation
+		for _, m := range reg.Maintainers {
+			for _, login := range m.GitHubAccounts() {
+				if len(login) > 39 || !gitHubLogin.MatchString(login) {
+					vs = append(vs, maintainerViolation(m, fmt.Sprintf("@%s is not a valid GitHub login", login)))
+					continue
+				}
+				if _, linked := m.GitHubID(); linked || users == nil || !strings.EqualFold(login, m.GitHubAccount) {
+					continue
+				}
+				_, _, err := users.Get(ctx, login)
+				var resp *github.ErrorResponse
+				if errors.As(err, &resp) && resp.Response != nil && resp.Response.StatusCode == http.StatusNotFound {
+					vs = append(vs, maintainerViolation(m, fmt.Sprintf("@%s is not a GitHub user, it may have been renamed or deleted", login)))
+					continue
+				}
+				if err != nil {
+					return vs, fmt.Errorf("look up GitHub user @%s: %w", login, err)
+				}
+			}
+		}
+		return vs, nil
+	}
+}
+
+func projectWithoutMaintainers(_ context.Context, reg Registry) ([]Violation, error) {
+	var vs []Violation
+	for _, p := range reg.Projects {
+		if len(p.Maintainers) == 0 {
+			vs = append(vs, projectViolation(p, "no maintainers"))
+		}
+	}
+	return vs, nil
+}
+
+func invalidMaturity(_ context.Context, reg Registry) ([]Violation, error) {
+	var vs []Violation
+	for _, p := range reg.Projects {
+		if !p.Maturity.IsValid() {
+			vs = append(vs, projectViolation(p, fmt.Sprintf("maturity %q is not one of Sandbox, Incubating, Graduated or Archived", p.Maturity)))
+		}
+	}
+	return vs, nil
+}
+
+func orphanParentProject(_ context.Context, reg Registry) ([]Violation, error) {
+	ids := map[uint]bool{}
+	for _, p := range reg.Projects {
+		ids[p.ID] = true
+	}
+	var vs []Violation
+	for _, p := range reg.Projects {
+		switch {
+		case p.ParentProjectID == nil:
+		case *p.ParentProjectID == p.ID:
+			vs = append(vs, projectViolation(p, "the project is its own parent"))
+		case !ids[*p.ParentProjectID]:
+			vs = append(vs, projectViolation(p, fmt.Sprintf("parent project %d does not exist or is archived", *p.ParentProjectID)))
+		}
+	}
+	return vs, nil
+}
+
+func missingMailingList(_ context.Context, reg Registry) ([]Violation, error) {
+	var vs []Violation
+	for _, p := range reg.Projects {
+		if p.MailingList == nil || *p.MailingList == "" || *p.MailingList == "MML_MISSING" {
+			vs = append(vs, projectViolation(p, "no maintainer mailing list"))
+		}
+	}
+	return vs, nil
+}
@@ -0,0 +1,90 @@
+package validate
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-github/v55/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"maintainerd/model"
+)
+
+// fakeUsers knows the logins in ids.
+type fakeUsers struct {
+	ids    map[string]int64
+	looked []string
+}
+
+func (f *fakeUsers) Get(_ context.Context, login string) (*github.User, *github.Response, error) {
+	f.looked = append(f.looked, login)
+	id, ok := f.ids[login]
+	if !ok {
+		resp := &http.Response{StatusCode: http.StatusNotFound, Request: &http.Request{}}
+		return nil, &github.Response{Response: resp}, &github.ErrorResponse{Response: resp, Message: "Not Found"}
+	}
+	return &github.User{Login: github.String(login), ID: github.Int64(id)}, nil, nil
+}
+
+func ptr[T any](v T) *T { return &v }
+
+func TestRules(t *testing.T) {
+	reg := Registry{
+		Maintainers: []model.Maintainer{
+			{Name: "Alice", Email: "alice@example.com", GitHubAccount: "alice"},
+			{Name: "Bob", Email: "EMAIL_MISSING", GitHubAccount: "GITHUB_MISSING"},
+			{Name: "Carol", Email: "carol at example.com", GitHubAccount: "carol_"},
+			{Name: "Dave", Email: "dave@example.com", GitHubAccount: "gone-dave"},
+			{Name: "Erin", Email: "erin@example.com", GitHubAccount: "erin",
+				Identities: []model.MaintainerIdentity{{Kind: model.IdentityGitHubID, Value: "5"}}},
+		},
+		Projects: []model.Project{
+			{Name: "argo", Maturity: model.Graduated, MailingList: ptr("argo@lists.cncf.io"),
+				Maintainers: []model.Maintainer{{Name: "Alice"}}},
+			{Name: "beta", Maturity: "Beta", MailingList: ptr("MML_MISSING"), ParentProjectID: ptr(uint(99))},
+		},
+	}
+	for i := range reg.Maintainers {
+		reg.Maintainers[i].ID = uint(i + 1)
+	}
+	reg.Projects[0].ID, reg.Projects[1].ID = 1, 2
+	users := &fakeUsers{ids: map[string]int64{"alice": 1}}
+
+	findings, err := Run(t.Context(), reg, Rules(users))
+	require.NoError(t, err)
+	type result struct{ rule, subject string }
+	var got []result
+	for _, f := range findings {
+		got = append(got, result{f.Rule, f.Subject})
+		assert.NotContains(t, f.Message, "@example.com", "messages must not leak email addresses")
+	}
+	assert.Equal(t, []result{
+		{"missing-email", "Bob"},
+		{"invalid-email", "@carol_"},
+		{"missing-github", "Bob"},
+		{"unknown-github-login", "@carol_"},
+		{"unknown-github-login", "@gone-dave"},
+		{"project-without-maintainers", "beta"},
+		{"invalid-maturity", "beta"},
+		{"orphan-parent-project", "beta"},
+		{"missing-mailing-list", "beta"},
+	}, got)
+	assert.Equal(t, model.SeverityError, findings[1].Severity)
+	assert.Equal(t, "maintainer", findings[0].SubjectKind)
+	assert.Equal(t, "project", findings[5].SubjectKind)
+	// Erin is linked to a GitHub user ID and Carol's login is not valid, so neither is looked up.
+	assert.Equal(t, []string{"alice", "gone-dave"}, users.looked)
+
+	// Without GitHub access only the login syntax is checked.
+	findings, err = Run(t.Context(), reg, Rules(nil))
+	require.NoError(t, err)
+	var unknown []string
+	for _, f := range findings {
+		if f.Rule == "unknown-github-login" {
+			unknown = append(unknown, f.Subject)
+		}
+	}
+	assert.Equal(t, []string{"@carol_"}, unknown)
+}